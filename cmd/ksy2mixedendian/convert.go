@@ -0,0 +1,487 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// Integer types, optionally suffixed with their endianness
+	intType = regexp.MustCompile(`^([us])([1248])(le|be)?$`)
+
+	// Plain attribute or enum references, anything else is an expression
+	identifier = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+)
+
+// lineError is an error pinned to a line of the .ksy file
+type lineError struct {
+	line int
+	msg  string
+}
+
+func (e *lineError) Error() string {
+	return fmt.Sprintf("%d: %s", e.line, e.msg)
+}
+
+func errorf(n *yaml.Node, format string, a ...any) error {
+	return &lineError{line: n.Line, msg: fmt.Sprintf(format, a...)}
+}
+
+// ksyType is a root or user type being converted
+type ksyType struct {
+	name   string
+	doc    string
+	endian string // "le", "be", or "" if inherited
+	node   *yaml.Node
+	parent *ksyType
+	enums  map[string]*yaml.Node
+}
+
+// converter accumulates generated declarations
+type converter struct {
+	rootEndian string
+	types      map[string]*ksyType
+	structs    bytes.Buffer
+	enums      bytes.Buffer
+	usedEnums  map[enumKey]bool
+}
+
+// enumKey identifies an enum by the type declaring it as well as its name, as types may each
+// declare their own enums of the same name
+type enumKey struct {
+	owner, name string
+}
+
+// convert translates a .ksy document into formatted Go source for package pkg
+func convert(src []byte, pkg string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(src, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, errorf(&doc, "expected a mapping at the top level")
+	}
+
+	c := &converter{
+		types:     map[string]*ksyType{},
+		usedEnums: map[enumKey]bool{},
+	}
+
+	root, err := c.declare(doc.Content[0], nil, "")
+	if err != nil {
+		return nil, err
+	}
+	c.rootEndian = root.endian
+
+	if err = c.emit(root); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by ksy2mixedendian from the %s Kaitai Struct description. DO NOT EDIT.\n\n", root.name)
+	fmt.Fprintf(&out, "package %s\n\n", pkg)
+	out.Write(c.structs.Bytes())
+	out.Write(c.enums.Bytes())
+
+	return format.Source(out.Bytes())
+}
+
+// declare registers a type and, recursively, the types nested within it
+func (c *converter) declare(n *yaml.Node, parent *ksyType, name string) (t *ksyType, err error) {
+	t = &ksyType{name: name, node: n, parent: parent, enums: map[string]*yaml.Node{}}
+
+	for i := 0; i < len(n.Content); i += 2 {
+		key, val := n.Content[i], n.Content[i+1]
+		switch key.Value {
+		case "meta":
+			if err = t.meta(val, parent == nil); err != nil {
+				return
+			}
+		case "doc":
+			t.doc = val.Value
+		case "seq", "doc-ref":
+		case "types":
+			if val.Kind != yaml.MappingNode {
+				return nil, errorf(val, "types must be a mapping")
+			}
+			for j := 0; j < len(val.Content); j += 2 {
+				if _, err = c.declare(val.Content[j+1], t, val.Content[j].Value); err != nil {
+					return
+				}
+			}
+		case "enums":
+			if val.Kind != yaml.MappingNode {
+				return nil, errorf(val, "enums must be a mapping")
+			}
+			for j := 0; j < len(val.Content); j += 2 {
+				t.enums[val.Content[j].Value] = val.Content[j+1]
+			}
+		default:
+			// instances, params, and anything newer
+			return nil, errorf(key, "%s are not supported", key.Value)
+		}
+	}
+
+	if t.name == "" {
+		return nil, errorf(n, "meta/id is required")
+	}
+	if _, ok := c.types[t.name]; ok {
+		return nil, errorf(n, "type %s is declared more than once", t.name)
+	}
+	c.types[t.name] = t
+	return
+}
+
+// meta reads the parts of a meta section which affect layout
+func (t *ksyType) meta(n *yaml.Node, root bool) error {
+	if n.Kind != yaml.MappingNode {
+		return errorf(n, "meta must be a mapping")
+	}
+	for i := 0; i < len(n.Content); i += 2 {
+		key, val := n.Content[i], n.Content[i+1]
+		switch key.Value {
+		case "id":
+			if root {
+				t.name = val.Value
+			}
+		case "endian":
+			if val.Kind != yaml.ScalarNode || (val.Value != "le" && val.Value != "be") {
+				return errorf(val, "only le or be endianness is supported")
+			}
+			t.endian = val.Value
+		case "imports", "bit-endian":
+			return errorf(key, "meta/%s is not supported", key.Value)
+		}
+	}
+	return nil
+}
+
+// resolvedEndian is the byte order fields of t default to
+func (t *ksyType) resolvedEndian() string {
+	for ; t != nil; t = t.parent {
+		if t.endian != "" {
+			return t.endian
+		}
+	}
+	return ""
+}
+
+// lookupEnum finds an enum visible from t, and the type declaring it
+func (t *ksyType) lookupEnum(name string) (*yaml.Node, *ksyType) {
+	for ; t != nil; t = t.parent {
+		if e, ok := t.enums[name]; ok {
+			return e, t
+		}
+	}
+	return nil, nil
+}
+
+// emit writes the struct for t followed by those of its nested types
+func (c *converter) emit(t *ksyType) error {
+	var seq *yaml.Node
+	for i := 0; i < len(t.node.Content); i += 2 {
+		if t.node.Content[i].Value == "seq" {
+			seq = t.node.Content[i+1]
+		}
+	}
+
+	name := goName(t.name)
+	if t.doc != "" {
+		comment(&c.structs, t.doc)
+	} else {
+		fmt.Fprintf(&c.structs, "// %s is generated from %s.\n", name, t.name)
+	}
+	if t.parent == nil && c.rootEndian != "" {
+		c.structs.WriteString("//\n")
+		fmt.Fprintf(&c.structs, "// Read and write with mixedEndian.%s.\n", orderName(c.rootEndian))
+	}
+	fmt.Fprintf(&c.structs, "type %s struct {\n", name)
+
+	seen := map[string]bool{}
+	if seq != nil {
+		if seq.Kind != yaml.SequenceNode {
+			return errorf(seq, "seq must be a list")
+		}
+		for _, attr := range seq.Content {
+			id, err := c.field(t, attr, seen)
+			if err != nil {
+				return err
+			}
+			seen[id] = true
+		}
+	}
+	c.structs.WriteString("}\n\n")
+
+	// Nested types follow in declaration order
+	for i := 0; i < len(t.node.Content); i += 2 {
+		if t.node.Content[i].Value != "types" {
+			continue
+		}
+		types := t.node.Content[i+1]
+		for j := 0; j < len(types.Content); j += 2 {
+			if err := c.emit(c.types[types.Content[j].Value]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// field writes a single seq attribute, returning its id
+func (c *converter) field(t *ksyType, attr *yaml.Node, seen map[string]bool) (id string, err error) {
+	if attr.Kind != yaml.MappingNode {
+		return "", errorf(attr, "seq entries must be mappings")
+	}
+
+	keys := map[string]*yaml.Node{}
+	for i := 0; i < len(attr.Content); i += 2 {
+		key, val := attr.Content[i], attr.Content[i+1]
+		switch key.Value {
+		case "id", "type", "size", "contents", "repeat", "repeat-expr", "enum", "doc", "doc-ref", "encoding":
+			if val.Kind != yaml.ScalarNode && key.Value != "contents" {
+				return "", errorf(val, "%s must be a plain value", key.Value)
+			}
+			keys[key.Value] = val
+		default:
+			return "", errorf(key, "%s is not supported", key.Value)
+		}
+	}
+
+	idNode, ok := keys["id"]
+	if !ok {
+		return "", errorf(attr, "attribute has no id")
+	}
+	id = idNode.Value
+
+	var (
+		goType string
+		tags   []string
+	)
+
+	// Resolve the element type
+	endian := t.resolvedEndian()
+	typeNode, hasType := keys["type"]
+	switch {
+	case keys["contents"] != nil:
+		if hasType || keys["size"] != nil {
+			return "", errorf(keys["contents"], "contents can't be combined with type or size")
+		}
+		var contents []string
+		if contents, err = contentBytes(keys["contents"]); err != nil {
+			return
+		}
+		goType = fmt.Sprintf("[%d]byte", len(contents))
+		tags = append(tags, fmt.Sprintf(`const:"%s"`, strings.Join(contents, ",")))
+
+	case !hasType, typeNode.Value == "str":
+		size, ok := keys["size"]
+		if !ok {
+			return "", errorf(attr, "%s has neither type nor size", id)
+		}
+		if size.Tag == "!!int" {
+			goType = fmt.Sprintf("[%s]byte", size.Value)
+		} else if identifier.MatchString(size.Value) {
+			if !seen[size.Value] {
+				return "", errorf(size, "size %s must refer to an earlier attribute", size.Value)
+			}
+			goType = "[]byte"
+			tags = append(tags, fmt.Sprintf(`len:"%s"`, goName(size.Value)))
+		} else {
+			return "", errorf(size, "size expressions are not supported")
+		}
+
+	default:
+		if keys["size"] != nil {
+			return "", errorf(keys["size"], "size on typed attributes (substreams) is not supported")
+		}
+		if m := intType.FindStringSubmatch(typeNode.Value); m != nil {
+			goType = map[string]string{"u": "uint", "s": "int"}[m[1]] + map[string]string{"1": "8", "2": "16", "4": "32", "8": "64"}[m[2]]
+			if m[2] != "1" {
+				fieldEndian := endian
+				if m[3] != "" {
+					fieldEndian = m[3]
+				}
+				if fieldEndian == "" {
+					return "", errorf(typeNode, "%s has no endianness", typeNode.Value)
+				}
+				if fieldEndian != c.rootEndian {
+					tags = append(tags, fmt.Sprintf(`endian:"%s"`, map[string]string{"le": "little", "be": "big"}[fieldEndian]))
+				}
+			}
+		} else if ut, ok := c.types[typeNode.Value]; ok {
+			if ut.resolvedEndian() != c.rootEndian && ut.resolvedEndian() != "" {
+				return "", errorf(typeNode, "user type %s changes endianness, which can't be tagged on a struct field", typeNode.Value)
+			}
+			goType = goName(ut.name)
+		} else {
+			return "", errorf(typeNode, "type %s is not supported", typeNode.Value)
+		}
+
+		if e, ok := keys["enum"]; ok {
+			if !strings.Contains(goType, "int") {
+				return "", errorf(e, "enum on non-integer type")
+			}
+			var values string
+			if values, err = c.enum(t, e); err != nil {
+				return
+			}
+			tags = append(tags, fmt.Sprintf(`enum:"%s"`, values))
+		}
+	}
+
+	// Repetition wraps the element type
+	if r, ok := keys["repeat"]; ok {
+		if r.Value != "expr" {
+			return "", errorf(r, "repeat: %s is not supported", r.Value)
+		}
+		count, ok := keys["repeat-expr"]
+		switch {
+		case !ok:
+			return "", errorf(r, "repeat: expr without repeat-expr")
+		case count.Tag == "!!int":
+			goType = fmt.Sprintf("[%s]%s", count.Value, goType)
+		case identifier.MatchString(count.Value) && seen[count.Value]:
+			if strings.HasPrefix(goType, "[]") {
+				return "", errorf(count, "repeated variable sizes are not supported")
+			}
+			goType = "[]" + goType
+			tags = append(tags, fmt.Sprintf(`len:"%s"`, goName(count.Value)))
+		default:
+			return "", errorf(count, "repeat-expr expressions are not supported")
+		}
+	}
+
+	if d, ok := keys["doc"]; ok {
+		comment(&c.structs, d.Value)
+	}
+	fmt.Fprintf(&c.structs, "%s %s", goName(id), goType)
+	if len(tags) > 0 {
+		fmt.Fprintf(&c.structs, " `%s`", strings.Join(tags, " "))
+	}
+	c.structs.WriteString("\n")
+	return
+}
+
+// enum returns the values of the referenced enum, emitting its constants the first time around
+func (c *converter) enum(t *ksyType, ref *yaml.Node) (string, error) {
+	if !identifier.MatchString(ref.Value) {
+		return "", errorf(ref, "enum %s must be a plain name", ref.Value)
+	}
+	e, owner := t.lookupEnum(ref.Value)
+	if e == nil {
+		return "", errorf(ref, "no enum named %s", ref.Value)
+	}
+	if e.Kind != yaml.MappingNode {
+		return "", errorf(e, "enum %s must be a mapping", ref.Value)
+	}
+
+	type entry struct {
+		value int64
+		name  string
+	}
+	var entries []entry
+	for i := 0; i < len(e.Content); i += 2 {
+		v, err := strconv.ParseInt(e.Content[i].Value, 0, 64)
+		if err != nil {
+			return "", errorf(e.Content[i], "enum value %s is not an integer", e.Content[i].Value)
+		}
+		name := e.Content[i+1].Value
+		if e.Content[i+1].Kind == yaml.MappingNode {
+			// Verbose form with id and doc
+			for j := 0; j < len(e.Content[i+1].Content); j += 2 {
+				if e.Content[i+1].Content[j].Value == "id" {
+					name = e.Content[i+1].Content[j+1].Value
+				}
+			}
+		}
+		entries = append(entries, entry{v, name})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].value < entries[j].value })
+
+	values := make([]string, len(entries))
+	for i, en := range entries {
+		values[i] = strconv.FormatInt(en.value, 10)
+	}
+
+	if key := (enumKey{owner.name, ref.Value}); !c.usedEnums[key] {
+		c.usedEnums[key] = true
+
+		// Enums of nested types are prefixed with their type's name, so can't collide
+		prefix, name := goName(ref.Value), ref.Value
+		if owner.parent != nil {
+			prefix, name = goName(owner.name)+prefix, owner.name+"::"+name
+		}
+		fmt.Fprintf(&c.enums, "// Values of the %s enum.\nconst (\n", name)
+		for _, en := range entries {
+			fmt.Fprintf(&c.enums, "%s%s = %d\n", prefix, goName(en.name), en.value)
+		}
+		c.enums.WriteString(")\n\n")
+	}
+
+	return strings.Join(values, ","), nil
+}
+
+// contentBytes expands a contents value into byte literals
+func contentBytes(n *yaml.Node) (bs []string, err error) {
+	var items []*yaml.Node
+	switch n.Kind {
+	case yaml.ScalarNode:
+		items = []*yaml.Node{n}
+	case yaml.SequenceNode:
+		items = n.Content
+	default:
+		return nil, errorf(n, "contents must be a string or a list")
+	}
+
+	for _, item := range items {
+		if item.Kind != yaml.ScalarNode {
+			return nil, errorf(item, "contents entries must be plain values")
+		}
+		if item.Tag == "!!int" {
+			v, err := strconv.ParseUint(item.Value, 0, 8)
+			if err != nil {
+				return nil, errorf(item, "contents byte %s is out of range", item.Value)
+			}
+			bs = append(bs, fmt.Sprintf("0x%02X", v))
+			continue
+		}
+		for _, b := range []byte(item.Value) {
+			bs = append(bs, fmt.Sprintf("0x%02X", b))
+		}
+	}
+	return
+}
+
+// goName turns a snake_case Kaitai identifier into an exported Go one
+func goName(id string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(id, "_") {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// orderName is the mixedEndian variable for a Kaitai endianness
+func orderName(endian string) string {
+	if endian == "le" {
+		return "LittleEndian"
+	}
+	return "BigEndian"
+}
+
+// comment writes text as a line comment
+func comment(b *bytes.Buffer, text string) {
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		fmt.Fprintf(b, "// %s\n", strings.TrimSpace(line))
+	}
+}
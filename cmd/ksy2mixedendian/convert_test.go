@@ -0,0 +1,154 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestConvertGolden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "*.ksy"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, input := range inputs {
+		t.Run(filepath.Base(input), func(t *testing.T) {
+			src, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := convert(src, "formats")
+			if err != nil {
+				t.Fatalf("convert() error = %v", err)
+			}
+
+			golden := strings.TrimSuffix(input, ".ksy") + ".go.golden"
+			if *update {
+				if err = os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("convert() =\n%s\nwanted\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestConvertUnsupported(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		wantLine int
+		wantMsg  string
+	}{
+		{
+			name: "instances",
+			src: `meta:
+  id: a
+seq:
+  - id: b
+    type: u1
+instances:
+  c:
+    pos: 4
+    type: u1
+`,
+			wantLine: 6,
+			wantMsg:  "instances are not supported",
+		},
+		{
+			name: "size expression",
+			src: `meta:
+  id: a
+seq:
+  - id: len
+    type: u1
+  - id: body
+    size: len - 2
+`,
+			wantLine: 7,
+			wantMsg:  "size expressions are not supported",
+		},
+		{
+			name: "forward size reference",
+			src: `meta:
+  id: a
+seq:
+  - id: body
+    size: len
+  - id: len
+    type: u1
+`,
+			wantLine: 5,
+			wantMsg:  "must refer to an earlier attribute",
+		},
+		{
+			name: "repeat eos",
+			src: `meta:
+  id: a
+seq:
+  - id: b
+    type: u1
+    repeat: eos
+`,
+			wantLine: 6,
+			wantMsg:  "repeat: eos is not supported",
+		},
+		{
+			name: "conditional",
+			src: `meta:
+  id: a
+seq:
+  - id: b
+    type: u1
+    if: _root.c == 0
+`,
+			wantLine: 6,
+			wantMsg:  "if is not supported",
+		},
+		{
+			name: "missing endianness",
+			src: `meta:
+  id: a
+seq:
+  - id: b
+    type: u2
+`,
+			wantLine: 5,
+			wantMsg:  "u2 has no endianness",
+		},
+		{
+			name: "bit fields",
+			src: `meta:
+  id: a
+seq:
+  - id: b
+    type: b3
+`,
+			wantLine: 5,
+			wantMsg:  "type b3 is not supported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := convert([]byte(tt.src), "formats")
+			var le *lineError
+			if !errors.As(err, &le) {
+				t.Fatalf("convert() error = %v, wanted a line error", err)
+			}
+			if le.line != tt.wantLine || !strings.Contains(le.msg, tt.wantMsg) {
+				t.Errorf("convert() error = %v, wanted %d: %s", err, tt.wantLine, tt.wantMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+module github.com/AV-IO/mixedEndian/cmd/ksy2mixedendian
+
+go 1.19
+
+require gopkg.in/yaml.v3 v3.0.1
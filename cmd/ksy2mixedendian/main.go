@@ -0,0 +1,55 @@
+// ksy2mixedendian converts a Kaitai Struct (.ksy) format description into Go structs tagged for
+// the mixedEndian package.
+//
+// Only the subset of Kaitai that mixedEndian can express is supported: integer and byte array
+// attributes with literal or field-referenced sizes, repeat-expr counts, endianness (including
+// per-attribute overrides), enums (as enum tags), and contents (as const tags).
+// Anything else, such as expressions, instances, or bit fields, is reported with its line number.
+//
+// Usage:
+//
+//	ksy2mixedendian [-pkg name] [-o output.go] format.ksy
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	pkg := flag.String("pkg", "main", "package name of the generated file")
+	out := flag.String("o", "", "output file (default stdout)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-pkg name] [-o output.go] format.ksy\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	src, err := os.ReadFile(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	gen, err := convert(src, *pkg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s:%v\n", flag.Arg(0), err)
+		os.Exit(1)
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(gen)
+	} else {
+		err = os.WriteFile(*out, gen, 0o644)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
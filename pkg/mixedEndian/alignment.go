@@ -0,0 +1,142 @@
+package mixedEndian
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ValidateAlignment checks that t, a struct type or pointer to one, is laid out on the wire as C
+// would lay it out in memory, with each field at a multiple of its natural alignment and the
+// struct padded to a multiple of its largest. The Go struct's own field offsets must agree too,
+// so the struct can be shared with C code as is.
+//
+// Structs tagged `encoding:"require_alignment"` on a blank marker field are checked as they're
+// registered with a TypeRegistry in builds with the debug tag, panicking if they fail.
+func ValidateAlignment(t reflect.Type) error {
+	sl, err := LayoutOf(t)
+	if err != nil {
+		return err
+	}
+	_, _, err = checkCLayout(sl)
+	return err
+}
+
+// checkCLayout compares sl against C's layout rules, giving its C size and alignment
+func checkCLayout(sl *StructLayout) (size, align int, err error) {
+	align = 1
+	for _, fl := range sl.Fields {
+		fsize, falign, err := cField(fl)
+		if err != nil {
+			return 0, 0, fmt.Errorf("%s: %w", fl.Path, err)
+		}
+
+		size = (size + falign - 1) / falign * falign
+		if fl.Offset != size {
+			return 0, 0, fmt.Errorf("%w %s is at wire offset %d, but C puts it at %d", ErrLayout, fl.Path, fl.Offset, size)
+		}
+		if sf, _ := sl.Type.FieldByName(fl.Name); int(sf.Offset) != size {
+			return 0, 0, fmt.Errorf("%w %s is at Go offset %d, but C puts it at %d", ErrLayout, fl.Path, sf.Offset, size)
+		}
+
+		size += fsize
+		if falign > align {
+			align = falign
+		}
+	}
+
+	size = (size + align - 1) / align * align
+	if sl.Size != size {
+		return 0, 0, fmt.Errorf("%w %s is %d bytes on the wire, but %d in C", ErrLayout, sl.Type.String(), sl.Size, size)
+	}
+	if int(sl.Type.Size()) != size {
+		return 0, 0, fmt.Errorf("%w %s is %d bytes in Go, but %d in C", ErrLayout, sl.Type.String(), sl.Type.Size(), size)
+	}
+	return size, align, nil
+}
+
+// cField gives the C size and alignment of a field
+func cField(fl FieldLayout) (size, align int, err error) {
+	if fl.Size < 0 {
+		return 0, 0, fmt.Errorf("%w Variable sized fields have no C equivalent", ErrLayout)
+	}
+
+	if fl.Elem != nil {
+		if size, align, err = checkCLayout(fl.Elem); err != nil {
+			return
+		}
+		if k := fl.Type.Kind(); k == reflect.Array || k == reflect.Slice {
+			size *= fl.Count
+		}
+		return size, align, nil
+	}
+
+	return fl.Size, naturalAlign(reflect.StructField{Type: fl.Type, Tag: fl.Tag}), nil
+}
+
+// naturalAlign is the alignment C would give field sf
+func naturalAlign(sf reflect.StructField) int {
+	switch {
+	case isDecimal(sf):
+		// Stored as plain integers
+		df, _ := decimalFormatOf(sf)
+		return df.bits / 8
+	case isDuration(sf):
+		if _, wt, err := durationFormat(sf); err == nil {
+			return typeSize(wt)
+		}
+	case isEpoch(sf):
+		if _, _, wt, err := epochFormat(sf); err == nil {
+			return typeSize(wt)
+		}
+	case isNorm(sf):
+		if wt, _, err := normFormat(sf); err == nil {
+			return typeSize(wt)
+		}
+	case isPCM(sf), sf.Type == serializableType:
+		return 1
+	}
+	return typeAlign(sf.Type)
+}
+
+// typeAlign is the alignment C would give a field of type t
+func typeAlign(t reflect.Type) int {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == hardwareAddrType || t == uint24Type || t == int24Type || t == uint48Type || t == int48Type:
+		// Only byte arrays in C
+		return 1
+	case t == uint128Type || t == int128Type:
+		// As __int128
+		return 16
+	case t == timecodeType:
+		// As the uint32 holding its digits
+		return 4
+	case t.Kind() == reflect.Array || t.Kind() == reflect.Slice:
+		return typeAlign(t.Elem())
+	case t.Kind() == reflect.Struct:
+		so, err := optionsOf(t)
+		if err != nil || so.packed {
+			return 1
+		}
+		align := 1
+		for i := 0; i < t.NumField(); i++ {
+			if sf := t.Field(i); sf.IsExported() && sf.Name != "_" {
+				if a := naturalAlign(sf); a > align {
+					align = a
+				}
+			}
+		}
+		if so.align > 0 && align > so.align {
+			align = so.align
+		}
+		return align
+	case typeSize(t) > 0:
+		return typeSize(t)
+	default:
+		// Strings are byte arrays
+		return 1
+	}
+}
@@ -0,0 +1,74 @@
+package mixedEndian
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// AlignedStruct is laid out as C would, with explicit padding
+type AlignedStruct struct {
+	_     struct{} `encoding:"require_alignment"`
+	A     uint8
+	Pad   [3]uint8
+	B     uint32
+	C     uint16
+	Inner struct {
+		D uint16
+		E uint8
+		F uint8
+	}
+	Pad2 [2]uint8
+	G    [2]uint32
+}
+
+// MisalignedStruct packs B at offset 1 on the wire, where C puts it at 4
+type MisalignedStruct struct {
+	_ struct{} `encoding:"require_alignment"`
+	A uint8
+	B uint32
+}
+
+func TestValidateAlignment(t *testing.T) {
+	tests := []struct {
+		name    string
+		t       reflect.Type
+		wantErr error
+	}{
+		{name: "aligned", t: reflect.TypeOf(AlignedStruct{})},
+		{name: "pointer", t: reflect.TypeOf(&AlignedStruct{})},
+		{name: "misaligned", t: reflect.TypeOf(MisalignedStruct{}), wantErr: ErrLayout},
+		{
+			name: "trailing padding",
+			t: reflect.TypeOf(struct {
+				A uint32
+				B uint8
+			}{}),
+			wantErr: ErrLayout,
+		},
+		{
+			name: "odd width",
+			t: reflect.TypeOf(struct {
+				A Uint24
+				B uint8
+			}{}),
+			wantErr: ErrLayout,
+		},
+		{
+			name: "variable size",
+			t: reflect.TypeOf(struct {
+				N uint32
+				A []uint32 `len:"N"`
+			}{}),
+			wantErr: ErrLayout,
+		},
+		{name: "not a struct", t: reflect.TypeOf(uint8(0)), wantErr: ErrUnexpectedType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateAlignment(tt.t); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateAlignment() error = %v, wanted %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package mixedEndian
+
+import "reflect"
+
+// Allocator makes the slices and maps a Decoder reads into, so they can come from an arena or
+// pool rather than the Go heap
+type Allocator interface {
+	// MakeSlice returns a slice of type t holding n zero values. Not every element is
+	// necessarily read into, sparse slices for one.
+	MakeSlice(t reflect.Type, n int) reflect.Value
+
+	// MakeMap returns an empty map of type t, which n entries are about to be read into
+	MakeMap(t reflect.Type, n int) reflect.Value
+}
+
+// makeSlice makes a slice of type t with length n to read into, with r's Allocator if it has one
+func (r *reader) makeSlice(t reflect.Type, n int) reflect.Value {
+	if r.alloc != nil {
+		return r.alloc.MakeSlice(t, n)
+	}
+	return reflect.MakeSlice(t, n, n)
+}
+
+// makeMap makes a map of type t to read n entries into, with r's Allocator if it has one.
+// Counts come off the wire, so the Go heap isn't asked to size a map for them up front.
+func (r *reader) makeMap(t reflect.Type, n int) reflect.Value {
+	if r.alloc != nil {
+		return r.alloc.MakeMap(t, n)
+	}
+	return reflect.MakeMap(t)
+}
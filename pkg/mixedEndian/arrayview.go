@@ -0,0 +1,210 @@
+package mixedEndian
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// ArrayView gives random access to count fixed size records of type T, held one after another
+// from an offset in an io.ReaderAt, decoding only those asked for. It suits indexes too large to
+// decode whole: record i is found at its offset and decoded alone.
+//
+// An ArrayView is safe for concurrent use should its io.ReaderAt be.
+type ArrayView[T any] struct {
+	r     io.ReaderAt
+	base  int64
+	count int
+	size  int
+	order binary.ByteOrder
+
+	// cache holds recently decoded records, record i in slot i modulo its length
+	mu    sync.Mutex
+	cache []cachedRecord[T]
+}
+
+// cachedRecord is a record an ArrayView decoded, and its index, -1 for none
+type cachedRecord[T any] struct {
+	index int
+	value T
+}
+
+// NewArrayView returns an ArrayView of count records of type T, the first baseOffset bytes into r,
+// decoded with default byte order order. T must be of a fixed size on the wire, as SizeOf gives it.
+func NewArrayView[T any](r io.ReaderAt, baseOffset int64, count int, order binary.ByteOrder) (*ArrayView[T], error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	size := typeSize(t)
+	if t.Kind() == reflect.Struct {
+		sl, err := LayoutOf(t)
+		if err != nil {
+			return nil, err
+		}
+		size = sl.Size
+	}
+	switch {
+	case size <= 0:
+		return nil, fmt.Errorf("%w ArrayView needs a fixed size; Got %s", ErrLength, t.String())
+	case baseOffset < 0 || count < 0:
+		return nil, fmt.Errorf("%w ArrayView of %d records at %d", ErrLength, count, baseOffset)
+	}
+	return &ArrayView[T]{r: r, base: baseOffset, count: count, size: size, order: order}, nil
+}
+
+// Len is the number of records in the view
+func (a *ArrayView[T]) Len() int {
+	return a.count
+}
+
+// SetCacheSize keeps up to n recently decoded records, so reading them again doesn't touch the
+// io.ReaderAt. It suits access that keeps returning to nearby records. A size of 0, the
+// default, caches nothing. Records are cached as decoded, so slices within them are shared.
+func (a *ArrayView[T]) SetCacheSize(n int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.cache = nil
+	if n > 0 {
+		a.cache = make([]cachedRecord[T], n)
+		for i := range a.cache {
+			a.cache[i].index = -1
+		}
+	}
+}
+
+// At decodes record i. Indices outside [0, Len()) give ErrLength.
+func (a *ArrayView[T]) At(i int) (v T, err error) {
+	if i < 0 || i >= a.count {
+		return v, fmt.Errorf("%w Index %d out of range [0, %d)", ErrLength, i, a.count)
+	}
+	if v, ok := a.cached(i); ok {
+		return v, nil
+	}
+
+	bs := make([]byte, a.size)
+	if _, err = a.readAt(bs, i); err != nil {
+		return v, err
+	}
+	if err = Unmarshal(a.order, bs, &v); err != nil {
+		return v, fmt.Errorf("record %d: %w", i, err)
+	}
+	a.store(i, v)
+	return v, nil
+}
+
+// readAt fills bs with the records from index i, returning how many whole records it read
+func (a *ArrayView[T]) readAt(bs []byte, i int) (int, error) {
+	n, err := a.r.ReadAt(bs, a.base+int64(i)*int64(a.size))
+	switch {
+	case n == len(bs):
+		return n / a.size, nil
+	case err == io.EOF:
+		return n / a.size, fmt.Errorf("record %d: %w", i+n/a.size, io.ErrUnexpectedEOF)
+	default:
+		return n / a.size, fmt.Errorf("record %d: %w", i+n/a.size, err)
+	}
+}
+
+// cached returns record i should it be cached
+func (a *ArrayView[T]) cached(i int) (v T, ok bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.cache) == 0 {
+		return v, false
+	}
+	if c := a.cache[i%len(a.cache)]; c.index == i {
+		return c.value, true
+	}
+	return v, false
+}
+
+// store caches v as record i
+func (a *ArrayView[T]) store(i int, v T) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.cache) > 0 {
+		a.cache[i%len(a.cache)] = cachedRecord[T]{index: i, value: v}
+	}
+}
+
+// arrayIterChunk is about how many bytes an ArrayIter reads at a time
+const arrayIterChunk = 64 << 10
+
+// Iter returns an ArrayIter over the records from index from to the end of the view
+func (a *ArrayView[T]) Iter(from int) *ArrayIter[T] {
+	if from < 0 {
+		from = 0
+	}
+	return &ArrayIter[T]{a: a, next: from, index: from - 1}
+}
+
+// ArrayIter steps through the records of an ArrayView in order, reading many at a time:
+//
+//	it := view.Iter(0)
+//	for it.Next() {
+//		use(it.Index(), it.Value())
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type ArrayIter[T any] struct {
+	a *ArrayView[T]
+
+	// buf holds the records read from index next on, and pending the error that ended them
+	buf     []byte
+	next    int
+	pending error
+
+	index int
+	value T
+	err   error
+}
+
+// Next decodes the next record, returning false at the end of the view or on an error
+func (it *ArrayIter[T]) Next() bool {
+	if it.err != nil || it.next >= it.a.count {
+		return false
+	}
+	if len(it.buf) == 0 {
+		n := arrayIterChunk / it.a.size
+		if n < 1 {
+			n = 1
+		}
+		if left := it.a.count - it.next; n > left {
+			n = left
+		}
+		it.buf = make([]byte, n*it.a.size)
+		n, it.pending = it.a.readAt(it.buf, it.next)
+		it.buf = it.buf[:n*it.a.size]
+	}
+	if len(it.buf) == 0 {
+		// The records read before an error are decoded before it's reported
+		it.err = it.pending
+		return false
+	}
+
+	var v T
+	if it.err = Unmarshal(it.a.order, it.buf[:it.a.size], &v); it.err != nil {
+		it.err = fmt.Errorf("record %d: %w", it.next, it.err)
+		return false
+	}
+	it.buf = it.buf[it.a.size:]
+	it.index, it.value = it.next, v
+	it.next++
+	return true
+}
+
+// Index is the index of the record Next last decoded
+func (it *ArrayIter[T]) Index() int {
+	return it.index
+}
+
+// Value is the record Next last decoded
+func (it *ArrayIter[T]) Value() T {
+	return it.value
+}
+
+// Err is the error that ended iteration, if any
+func (it *ArrayIter[T]) Err() error {
+	return it.err
+}
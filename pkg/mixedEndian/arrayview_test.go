@@ -0,0 +1,125 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type IndexEntry struct {
+	Key    uint64
+	Offset Uint48
+	Flags  uint8
+	Kind   uint8 `endian:"little"`
+	Name   [4]byte
+}
+
+func TestArrayView(t *testing.T) {
+	const count = 20000
+	rng := rand.New(rand.NewSource(3))
+	want := make([]IndexEntry, count)
+	for i := range want {
+		want[i] = IndexEntry{Key: rng.Uint64(), Offset: Uint48(rng.Int63n(1 << 48)), Flags: uint8(i), Kind: uint8(i >> 8)}
+		rng.Read(want[i].Name[:])
+	}
+
+	// Records follow a header the view skips
+	header := []byte("IDX\x00\x00\x00\x00\x01")
+	path := filepath.Join(t.TempDir(), "index")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	enc := NewEncoder(f, BigEndian)
+	f.Write(header)
+	for _, e := range want {
+		if err = enc.Encode(e); err != nil {
+			t.Fatalf("Encode() error = %v", err)
+		}
+	}
+	if err = f.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if f, err = os.Open(path); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer f.Close()
+
+	// A full decode to check against
+	if _, err = f.Seek(int64(len(header)), io.SeekStart); err != nil {
+		t.Fatalf("Seek() error = %v", err)
+	}
+	var all []IndexEntry
+	if _, err = NewDecoder(f, BigEndian).DecodeAll(&all); err != nil || len(all) != count {
+		t.Fatalf("DecodeAll() = %d records, %v", len(all), err)
+	}
+
+	view, err := NewArrayView[IndexEntry](f, int64(len(header)), count, BigEndian)
+	if err != nil {
+		t.Fatalf("NewArrayView() error = %v", err)
+	}
+	if view.Len() != count {
+		t.Errorf("Len() = %d, wanted %d", view.Len(), count)
+	}
+	for _, cache := range []int{0, 16} {
+		view.SetCacheSize(cache)
+		for _, i := range append(rng.Perm(count)[:500], 0, count-1, 5, 5, 6) {
+			got, err := view.At(i)
+			if err != nil {
+				t.Fatalf("At(%d) error = %v", i, err)
+			}
+			if got != all[i] {
+				t.Errorf("At(%d) = %+v, wanted %+v", i, got, all[i])
+			}
+		}
+	}
+
+	for _, i := range []int{-1, count} {
+		if _, err = view.At(i); !errors.Is(err, ErrLength) {
+			t.Errorf("At(%d) error = %v, wanted %v", i, err, ErrLength)
+		}
+	}
+
+	it := view.Iter(count - 3000)
+	n := count - 3000
+	for it.Next() {
+		if it.Index() != n || it.Value() != all[n] {
+			t.Fatalf("Next() = %d, %+v, wanted %d, %+v", it.Index(), it.Value(), n, all[n])
+		}
+		n++
+	}
+	if it.Err() != nil || n != count {
+		t.Errorf("Iter() ended at %d, %v; wanted %d", n, it.Err(), count)
+	}
+}
+
+func TestArrayViewErrors(t *testing.T) {
+	if _, err := NewArrayView[TranscodeCounted](bytes.NewReader(nil), 0, 1, BigEndian); !errors.Is(err, ErrLength) {
+		t.Errorf("NewArrayView() error = %v, wanted %v", err, ErrLength)
+	}
+
+	// A view claiming more records than there are fails reading the missing ones
+	view, err := NewArrayView[uint32](bytes.NewReader([]byte{0, 0, 0, 1, 0, 0}), 0, 2, BigEndian)
+	if err != nil {
+		t.Fatalf("NewArrayView() error = %v", err)
+	}
+	if v, err := view.At(0); v != 1 || err != nil {
+		t.Errorf("At(0) = %d, %v; wanted 1", v, err)
+	}
+	if _, err = view.At(1); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("At(1) error = %v, wanted %v", err, io.ErrUnexpectedEOF)
+	}
+	it := view.Iter(0)
+	n := 0
+	for it.Next() {
+		n++
+	}
+	if n != 1 || !errors.Is(it.Err(), io.ErrUnexpectedEOF) {
+		t.Errorf("Iter() gave %d records then %v, wanted 1 then %v", n, it.Err(), io.ErrUnexpectedEOF)
+	}
+}
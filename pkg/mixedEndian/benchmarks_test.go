@@ -0,0 +1,106 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+)
+
+type LargeByteSliceStruct struct {
+	N    uint32
+	Data []byte `len:"N"`
+}
+
+var largeByteSliceSizes = []int{1 << 20, 10 << 20, 100 << 20}
+
+// largeByteSliceWire encodes a LargeByteSliceStruct holding n bytes
+func largeByteSliceWire(n int) []byte {
+	wire := make([]byte, 4+n)
+	binary.BigEndian.PutUint32(wire, uint32(n))
+	for i := range wire[4:] {
+		wire[4+i] = byte(i)
+	}
+	return wire
+}
+
+func BenchmarkReadLargeByteSlice(b *testing.B) {
+	for _, n := range largeByteSliceSizes {
+		wire := largeByteSliceWire(n)
+		b.Run(fmt.Sprintf("%dMB", n>>20), func(b *testing.B) {
+			b.SetBytes(int64(n))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var data any = &LargeByteSliceStruct{}
+				if err := Read(bytes.NewReader(wire), BigEndian, &data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkReadLargeByteSliceBaseline reads the same as BenchmarkReadLargeByteSlice by hand,
+// with io.ReadFull, giving the cost of the copy alone
+func BenchmarkReadLargeByteSliceBaseline(b *testing.B) {
+	for _, n := range largeByteSliceSizes {
+		wire := largeByteSliceWire(n)
+		b.Run(fmt.Sprintf("%dMB", n>>20), func(b *testing.B) {
+			b.SetBytes(int64(n))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				r := bytes.NewReader(wire)
+				var header [4]byte
+				if _, err := io.ReadFull(r, header[:]); err != nil {
+					b.Fatal(err)
+				}
+				data := LargeByteSliceStruct{N: binary.BigEndian.Uint32(header[:])}
+				data.Data = make([]byte, data.N)
+				if _, err := io.ReadFull(r, data.Data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// transcodeInput is 16MB of AlignedStruct8 records
+var transcodeInput = make([]byte, 32<<19)
+
+func BenchmarkTranscode(b *testing.B) {
+	b.SetBytes(int64(len(transcodeInput)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := Transcode(io.Discard, bytes.NewReader(transcodeInput), AlignedStruct8{}, BigEndian, LittleEndian); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkTranscodeBaseline copies the same input as BenchmarkTranscode, untouched, with io.Copy.
+// The reader and writer are wrapped so the copy goes through a buffer, as it would between files.
+func BenchmarkTranscodeBaseline(b *testing.B) {
+	b.SetBytes(int64(len(transcodeInput)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := io.Copy(struct{ io.Writer }{io.Discard}, struct{ io.Reader }{bytes.NewReader(transcodeInput)}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkTranscodeRecoded transcodes the first megabyte of BenchmarkTranscode's input by
+// decoding and encoding each record
+func BenchmarkTranscodeRecoded(b *testing.B) {
+	input := transcodeInput[:1<<20]
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, err := recodeRecords(io.Discard, bytes.NewReader(input), reflect.TypeOf(AlignedStruct8{}), "AlignedStruct8", BigEndian, LittleEndian)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package mixedEndian
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ExportCHeader writes C declarations of the structs samples lay out as, so C code can share
+// their formats.
+//
+// Each struct, and each struct within one, becomes a typedef of stdint.h types under
+// #pragma pack(1), fields being laid out back to back, with padding members where an align option
+// calls for padding. C can't give a field's byte order, so fields wider than a byte are commented
+// with theirs. Arrays, strings, and slices of a fixed count become arrays, and overlay groups
+// unions. A slice counted by another field, or led by a lenprefix, becomes a flexible array member
+// when it's the last field of one of samples. Fields with no C equivalent, such as varints, maps,
+// optional fields, and other variable length slices, fail with ErrLayout.
+func ExportCHeader(w io.Writer, samples ...any) error {
+	h := &cHeader{names: map[reflect.Type]string{}, flexible: map[reflect.Type]bool{}}
+
+	var names []string
+	for _, sample := range samples {
+		sl, err := Describe(sample)
+		if err != nil {
+			return err
+		}
+		name, err := h.declare(sl, true)
+		if err != nil {
+			return err
+		}
+		names = append(names, name)
+	}
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "// C declarations for %s, generated by mixedEndian\n\n", strings.Join(names, ", "))
+	fmt.Fprintf(b, "#include <stdint.h>\n\n#pragma pack(push, 1)\n\n%s#pragma pack(pop)\n", h.b.String())
+
+	// Nothing's written unless it all could be
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type cHeader struct {
+	b     strings.Builder
+	names map[reflect.Type]string
+
+	// flexible notes the structs ending in a flexible array member, which C won't nest
+	flexible map[reflect.Type]bool
+}
+
+// declare writes typedefs for sl and the structs it contains, returning its C name. Only
+// outermost structs may end in a flexible array member.
+func (h *cHeader) declare(sl *StructLayout, outermost bool) (string, error) {
+	if name, ok := h.names[sl.Type]; ok {
+		if !outermost && h.flexible[sl.Type] {
+			return "", fmt.Errorf("%w %s ends in a flexible array member, so can't be nested", ErrLayout, name)
+		}
+		return name, nil
+	}
+
+	name := sl.Type.Name()
+	if name == "" {
+		name = fmt.Sprintf("anon%d", len(h.names))
+	}
+	h.names[sl.Type] = name
+
+	// Nested structs are declared first
+	for _, f := range sl.Fields {
+		if f.Elem != nil {
+			if _, err := h.declare(f.Elem, false); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	overlay := func(j int) string {
+		if j < 0 || j >= len(sl.Fields) {
+			return ""
+		}
+		g, _, _ := strings.Cut(sl.Fields[j].Tag.Get("overlay"), ",")
+		return g
+	}
+
+	var lines []string
+	indent, end, pads := "\t", 0, 0
+	pad := func(to int) {
+		if end >= 0 && to > end {
+			lines = append(lines, fmt.Sprintf("\tuint8_t _pad%d[%d];", pads, to-end))
+			pads++
+		}
+	}
+	for j, f := range sl.Fields {
+		if g := overlay(j); g != "" && g != overlay(j-1) {
+			pad(f.Offset)
+			lines = append(lines, "\tunion {")
+			indent = "\t\t"
+		} else if g == "" {
+			pad(f.Offset)
+		}
+
+		decls, flexible, err := h.field(f, outermost && j == len(sl.Fields)-1)
+		if err != nil {
+			return "", err
+		}
+		for _, d := range decls {
+			lines = append(lines, indent+d)
+		}
+		h.flexible[sl.Type] = h.flexible[sl.Type] || flexible
+
+		switch {
+		case end < 0 || f.Offset < 0 || f.Size < 0:
+			end = -1
+		case f.Offset+f.Size > end:
+			end = f.Offset + f.Size
+		}
+		if g := overlay(j); g != "" && g != overlay(j+1) {
+			lines = append(lines, fmt.Sprintf("\t} %s;", g))
+			indent = "\t"
+		}
+	}
+	if sl.Size >= 0 {
+		pad(sl.Size)
+	}
+
+	fmt.Fprintf(&h.b, "typedef struct %s {\n", name)
+	for _, l := range lines {
+		fmt.Fprintf(&h.b, "%s\n", l)
+	}
+	fmt.Fprintf(&h.b, "} %s;\n\n", name)
+	return name, nil
+}
+
+// field renders the declarations of f, usually one, but two for a length prefixed slice.
+// A slice without a fixed count is only allowed when last, as a flexible array member.
+func (h *cHeader) field(f FieldLayout, last bool) (decls []string, flexible bool, err error) {
+	sf := reflect.StructField{Name: f.Name, Type: f.Type, Tag: f.Tag}
+	t := f.Type
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	noC := func(what string) error {
+		return fmt.Errorf("%w %s is %s, which has no C equivalent", ErrLayout, f.Path, what)
+	}
+
+	switch {
+	case isRaw(sf) || f.Type == serializableType:
+		return []string{fmt.Sprintf("// %s: not on the wire", f.Name)}, false, nil
+	case isVarint(sf):
+		return nil, false, noC(f.Tag.Get("encoding") + " encoded")
+	case f.Tag.Get("rle") != "":
+		return nil, false, noC("run length encoded")
+	case f.Tag.Get("sparse") != "":
+		return nil, false, noC("sparse")
+	case f.Tag.Get("ssh") != "":
+		return nil, false, noC("an ssh " + f.Tag.Get("ssh"))
+	case f.Tag.Get("compress") != "":
+		return nil, false, noC(f.Tag.Get("compress") + " compressed")
+	case f.Tag.Get("crcblocks") != "":
+		return nil, false, noC("split into CRC blocks")
+	case f.Tag.Get("presentif") != "" || isVersioned(sf) || f.Tag.Get("de") != "":
+		return nil, false, noC("optional")
+	case f.Tag.Get("dims") != "":
+		return nil, false, noC("a nested slice")
+	case t.Kind() == reflect.Map:
+		return nil, false, noC("a map")
+	case t.Kind() == reflect.String && f.Tag.Get("string") != "":
+		return nil, false, noC("packed text")
+	}
+
+	var (
+		elem    = t
+		dims    string
+		notes   []string
+		prefix  string
+		counted = f.Count
+	)
+	switch {
+	case isDuration(sf):
+		// Durations are stored as plain integers
+		_, elem, _ = durationFormat(sf)
+		notes = append(notes, f.Tag.Get("dur"))
+	case isEpoch(sf):
+		// Times are stored as plain integers
+		epoch, unit, wt, _ := epochFormat(sf)
+		elem = wt
+		notes = append(notes, fmt.Sprintf("%s since %s", unit, epoch.Format(time.RFC3339)))
+	case isDecimal(sf):
+		// Decimals have no C type, so are given as their bits
+		df, _ := decimalFormatOf(sf)
+		elem = uintTypes[fmt.Sprintf("uint%d", df.bits)]
+		notes = append(notes, f.Tag.Get("floatfmt"))
+	case t == hardwareAddrType:
+		elem, dims = t.Elem(), fmt.Sprintf("[%d]", f.Count)
+	case t.Kind() == reflect.String:
+		dims = fmt.Sprintf("[%d]", f.Count)
+	case t.Kind() == reflect.Array || t.Kind() == reflect.Slice:
+		elem = t.Elem()
+		switch p, ok, _ := lenPrefixOf(sf); {
+		case counted >= 0:
+			dims = fmt.Sprintf("[%d]", counted)
+		case !last:
+			return nil, false, noC("a variable length slice not ending the outermost struct")
+		case ok:
+			name, _, _ := cType(p.t)
+			prefix = fmt.Sprintf("%s %sLen; // length prefix of %s%s", name, f.Name, f.Name, cOrder(f.Tag.Get("lenprefix"), p.t))
+			dims, flexible = "[]", true
+		case f.CountRef != "":
+			dims, flexible = "[]", true
+			notes = append(notes, "count in "+f.CountRef)
+		default:
+			dims, flexible = "[]", true
+			notes = append(notes, "to the end of input")
+		}
+		for ; elem.Kind() == reflect.Array; elem = elem.Elem() {
+			dims += fmt.Sprintf("[%d]", elem.Len())
+		}
+	}
+
+	if isNorm(sf) {
+		// Normalized floats are stored as plain integers
+		elem, _, _ = normFormat(sf)
+		notes = append(notes, f.Tag.Get("norm"))
+	} else if isPCM(sf) {
+		elem = reflect.TypeOf(uint8(0))
+		notes = append(notes, f.Tag.Get("pcm"))
+	} else if elem == timecodeType {
+		// Timecodes are BCD digits in a plain integer
+		elem = reflect.TypeOf(uint32(0))
+		notes = append(notes, "BCD timecode")
+	}
+
+	name, raw, ok := cType(elem)
+	if elem.Kind() == reflect.Struct && raw == 0 {
+		name, ok = h.names[elem], true
+		if e := f.Tag.Get("endian"); e == "big" || e == "little" {
+			notes = append(notes, "fields "+e+" endian unless tagged")
+		}
+	} else if typeSize(elem) > 1 || raw > 0 {
+		notes = append(notes, strings.TrimPrefix(cOrder(f.Tag.Get("endian"), elem), ", "))
+	}
+	if !ok {
+		return nil, false, noC(elem.String())
+	}
+	if raw > 0 {
+		dims += fmt.Sprintf("[%d]", raw)
+		notes = append(notes, elem.Name()+" as raw bytes")
+	}
+	if w := f.Tag.Get("bitwidth"); w != "" {
+		notes = append(notes, "low "+w+" bits")
+	}
+
+	decl := fmt.Sprintf("%s %s%s;", name, f.Name, dims)
+	if len(notes) > 0 {
+		decl += " // " + strings.Join(notes, ", ")
+	}
+	if prefix != "" {
+		decls = append(decls, prefix)
+	}
+	return append(decls, decl), flexible, nil
+}
+
+// cType maps a base type to its stdint.h equivalent. Types without one are given as uint8_t
+// along with their width in bytes. Structs, and types with no C equivalent at all, aren't ok.
+func cType(t reflect.Type) (name string, raw int, ok bool) {
+	switch t {
+	case uint24Type, int24Type, uint48Type, int48Type, uint128Type, int128Type:
+		return "uint8_t", typeSize(t), true
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "char", 0, true
+	case reflect.Bool, reflect.Uint8:
+		return "uint8_t", 0, true
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fmt.Sprintf("int%d_t", t.Bits()), 0, true
+	case reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fmt.Sprintf("uint%d_t", t.Bits()), 0, true
+	default:
+		return "", 0, false
+	}
+}
+
+// cOrder comments on the byte order given by an endian tag, or the lenprefix tag s, of a t,
+// should t be wider than a byte
+func cOrder(s string, t reflect.Type) string {
+	if typeSize(t) <= 1 {
+		return ""
+	}
+	if _, o, ok := strings.Cut(s, ","); ok {
+		s = o
+	}
+	switch s {
+	case "big", "little":
+		return ", " + s + " endian"
+	}
+	return ", default byte order"
+}
@@ -0,0 +1,80 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestExportCHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []any
+	}{
+		{name: "NoTagStruct", samples: []any{NoTagStruct{}}},
+		{name: "NestedStruct", samples: []any{NestedStruct{}}},
+		{name: "OddWidthStruct", samples: []any{&OddWidthStruct{}}},
+		{name: "OddWidthSliceStruct", samples: []any{OddWidthSliceStruct{}}},
+		{name: "EthernetHeader", samples: []any{EthernetHeader{}, ValidatedStruct{}}},
+		{name: "AlignedStruct8", samples: []any{AlignedStruct8{}}},
+		{name: "OverlayRecord", samples: []any{OverlayRecord{}}},
+		{name: "PaddedRecord", samples: []any{struct {
+			Kind   uint8  `padding_after:"1"`
+			Length uint16 `padding_before:"2,0xFF" padding_after:"2,0xAA"`
+		}{}}},
+		{name: "NestedArrayStruct", samples: []any{NestedArrayStruct{}}},
+		{name: "LenPrefixed", samples: []any{struct {
+			Count uint8
+			Pad   [3]uint16  `endian:"little"`
+			Items []GenInner `lenprefix:"uint16,big"`
+		}{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			if err := ExportCHeader(buf, tt.samples...); err != nil {
+				t.Fatalf("ExportCHeader() error = %v", err)
+			}
+			checkGolden(t, tt.name+".h", buf.Bytes())
+		})
+	}
+}
+
+func TestExportCHeaderErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample any
+	}{
+		{name: "varint", sample: struct {
+			A uint32 `encoding:"uvarint"`
+		}{}},
+		{name: "map", sample: struct {
+			N uint8
+			M map[uint8]uint8 `len:"N"`
+		}{}},
+		{name: "optional", sample: struct {
+			Has bool
+			A   uint16 `presentif:"Has"`
+		}{}},
+		{name: "slice not last", sample: struct {
+			N uint8
+			D []uint8 `len:"N"`
+			E uint8
+		}{}},
+		{name: "nested flexible", sample: struct {
+			A OddWidthSliceStruct
+		}{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			err := ExportCHeader(buf, tt.sample)
+			if !errors.Is(err, ErrLayout) {
+				t.Errorf("ExportCHeader() error = %v, wanted %v", err, ErrLayout)
+			}
+			if buf.Len() > 0 {
+				t.Errorf("ExportCHeader() wrote %q, wanted nothing on error", buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// checksumField is a field tagged network_checksum, or crc and crcrange, holding the checksum of
+// fields from through to
+type checksumField struct {
+	field, from, to int
+
+	// crc is the field's CRC, nil for an RFC 1071 checksum
+	crc *CRC
+}
+
+// checksumsOf finds the fields of t tagged network_checksum, or crc and crcrange
+func checksumsOf(t reflect.Type) (cks []checksumField, err error) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		s, tag := sf.Tag.Get("network_checksum"), "network_checksum"
+		var crc *CRC
+		if sf.Tag.Get("crc") != "" {
+			c, err := crcOf(sf)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", sf.Name, err)
+			}
+			if sf.Type.Kind() != uintKinds[c.Width/8] {
+				return nil, fmt.Errorf("%s: %w crc of width %d needs a uint%d; Got %s", sf.Name, ErrUnexpectedType, c.Width, c.Width, sf.Type.String())
+			}
+			if _, s, err = crcRange(sf); err != nil {
+				return nil, fmt.Errorf("%s: %w", sf.Name, err)
+			}
+			tag, crc = "crcrange", &c
+			if sf.Tag.Get("crcrange") == "" {
+				tag = "crc"
+			}
+		} else if s == "" {
+			continue
+		} else if sf.Type.Kind() != reflect.Uint16 {
+			return nil, fmt.Errorf("%s: %w network_checksum needs a uint16; Got %s", sf.Name, ErrUnexpectedType, sf.Type.String())
+		}
+		if !sf.IsExported() {
+			return nil, fmt.Errorf("%s: %w %s needs an exported field", sf.Name, ErrTag, tag)
+		}
+
+		from, to, ok := strings.Cut(s, ":")
+		a, aok := t.FieldByName(from)
+		c, cok := t.FieldByName(to)
+		if !ok || !aok || !cok || len(a.Index) != 1 || len(c.Index) != 1 || a.Index[0] > c.Index[0] {
+			return nil, fmt.Errorf("%s: %w %s %q is not a range of fields", sf.Name, ErrTag, tag, s)
+		}
+		cks = append(cks, checksumField{field: i, from: a.Index[0], to: c.Index[0], crc: crc})
+	}
+	return
+}
+
+// uintKinds are the unsigned kinds by size
+var uintKinds = map[int]reflect.Kind{1: reflect.Uint8, 2: reflect.Uint16, 4: reflect.Uint32, 8: reflect.Uint64}
+
+// internetChecksum is the RFC 1071 checksum of bs: the one's complement of the one's complement
+// sum of its big endian 16 bit words, an odd byte out padded with zero
+func internetChecksum(bs []byte) uint16 {
+	var sum uint32
+	for ; len(bs) >= 2; bs = bs[2:] {
+		sum += uint32(bs[0])<<8 | uint32(bs[1])
+	}
+	if len(bs) == 1 {
+		sum += uint32(bs[0]) << 8
+	}
+	for sum > 0xFFFF {
+		sum = sum>>16 + sum&0xFFFF
+	}
+	return ^uint16(sum)
+}
+
+// sumRange is the checksum ck should hold over encoded struct bs, whose fields are at spans
+func (ck checksumField) sumRange(bs []byte, spans [][2]int64) (uint64, error) {
+	at, n := spans[ck.field], int64(2)
+	if ck.crc != nil {
+		n = int64(ck.crc.Width / 8)
+	}
+	if at[1]-at[0] != n {
+		return 0, fmt.Errorf("%w Checksum field takes %d bytes, not %d", ErrLength, at[1]-at[0], n)
+	}
+
+	// The checksum's computed as though it were zero, should it fall in its own range
+	saved := append([]byte(nil), bs[at[0]:at[1]]...)
+	for i := at[0]; i < at[1]; i++ {
+		bs[i] = 0
+	}
+	defer copy(bs[at[0]:], saved)
+
+	data := bs[spans[ck.from][0]:spans[ck.to][1]]
+	if ck.crc != nil {
+		return ck.crc.Checksum(data), nil
+	}
+	return uint64(internetChecksum(data)), nil
+}
+
+// readCaptured reads struct v keeping the bytes read, then checks each of its checksums against
+// them and fills in its raw fields
+func (r *reader) readCaptured(v reflect.Value, so structOptions, cks []checksumField, raws []rawField, o binary.ByteOrder) error {
+	buf := &bytes.Buffer{}
+	sub := *r
+	sub.r = io.TeeReader(r.r, buf)
+
+	spans := make([][2]int64, v.NumField())
+	if err := sub.readCounted(v, so, o, spans); err != nil {
+		return err
+	}
+
+	bs := buf.Bytes()
+	for _, ck := range cks {
+		sum, err := ck.sumRange(bs, spans)
+		if err != nil {
+			return fmt.Errorf("%s: %w", v.Type().Field(ck.field).Name, err)
+		}
+
+		// CRCs are read as their field, RFC 1071 sums in network order whatever the field's
+		got := v.Field(ck.field).Uint()
+		if ck.crc == nil {
+			got = uint64(binary.BigEndian.Uint16(bs[spans[ck.field][0]:]))
+		}
+		if got != sum {
+			return fmt.Errorf("%s: %w Read %#x, computed %#x", v.Type().Field(ck.field).Name, ErrChecksum, got, sum)
+		}
+	}
+	r.capture(v, raws, bs, spans)
+	return nil
+}
+
+// writeChecksummed encodes struct v, fills in each of its checksums, then writes it
+func (w *writer) writeChecksummed(v reflect.Value, so structOptions, cks []checksumField, o binary.ByteOrder) error {
+	bs, _, err := w.encodeChecksummed(v, so, cks, o)
+	if err != nil {
+		return err
+	}
+	_, err = w.w.Write(bs)
+	return err
+}
+
+// encodeChecksummed encodes struct v with each of its checksums filled in, giving where each
+// field starts and ends
+func (w *writer) encodeChecksummed(v reflect.Value, so structOptions, cks []checksumField, o binary.ByteOrder) ([]byte, [][2]int64, error) {
+	buf := &bytes.Buffer{}
+	sub := *w
+	sub.w = buf
+
+	spans := make([][2]int64, v.NumField())
+	if err := sub.writeCounted(v, so, o, spans); err != nil {
+		return nil, nil, err
+	}
+
+	// RFC 1071 sums come out the same in either byte order, so are patched in network order
+	// whatever the field's. CRCs are patched in the field's.
+	bs := buf.Bytes()
+	for _, ck := range cks {
+		sf := v.Type().Field(ck.field)
+		sum, err := ck.sumRange(bs, spans)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		at := bs[spans[ck.field][0]:spans[ck.field][1]]
+		if ck.crc == nil {
+			binary.BigEndian.PutUint16(at, uint16(sum))
+			continue
+		}
+		fo := o
+		switch sf.Tag.Get("endian") {
+		case "big":
+			fo = BigEndian
+		case "little":
+			fo = LittleEndian
+		}
+		if w.overrides != nil {
+			fo = w.overrides.order(sf.Name, fo)
+		}
+		f := reflect.New(sf.Type).Elem()
+		f.SetUint(sum)
+		if err = encode(f, at, fo); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", sf.Name, err)
+		}
+	}
+
+	return bs, spans, nil
+}
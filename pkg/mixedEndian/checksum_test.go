@@ -0,0 +1,106 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type IPv4Header struct {
+	VersionIHL  uint8
+	TOS         uint8
+	TotalLength uint16
+	ID          uint16
+	FlagsFrag   uint16
+	TTL         uint8
+	Protocol    uint8
+	Checksum    uint16 `network_checksum:"VersionIHL:Dst"`
+	Src         [4]byte
+	Dst         [4]byte
+}
+
+type OddChecksumStruct struct {
+	Checksum uint16 `network_checksum:"A:B" endian:"little"`
+	A        uint8
+	B        [2]uint8
+}
+
+type BadChecksumStruct struct {
+	Checksum uint16 `network_checksum:"B:A"`
+	A, B     uint8
+}
+
+func TestNetworkChecksum(t *testing.T) {
+	wire := []byte{
+		0x45, 0x00, 0x00, 0x73, 0x00, 0x00, 0x40, 0x00, 0x40, 0x11, 0xB8, 0x61,
+		0xC0, 0xA8, 0x00, 0x01, 0xC0, 0xA8, 0x00, 0xC7,
+	}
+	want := &IPv4Header{
+		VersionIHL: 0x45, TotalLength: 0x73, FlagsFrag: 0x4000, TTL: 0x40, Protocol: 0x11,
+		Checksum: 0xB861, Src: [4]byte{192, 168, 0, 1}, Dst: [4]byte{192, 168, 0, 199},
+	}
+
+	unsummed := *want
+	unsummed.Checksum = 0x1234
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, unsummed); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+
+	var data any = &IPv4Header{}
+	if err := Read(bytes.NewReader(wire), BigEndian, &data); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("Read() data = %v, wanted %v", data, want)
+	}
+
+	corrupt := append([]byte(nil), wire...)
+	corrupt[15] ^= 0x01
+	data = &IPv4Header{}
+	if err := Read(bytes.NewReader(corrupt), BigEndian, &data); !errors.Is(err, ErrChecksum) {
+		t.Errorf("Read() error = %v, wanted %v", err, ErrChecksum)
+	}
+}
+
+func TestNetworkChecksumOdd(t *testing.T) {
+	// 0x0102 + 0x0300 = 0x0402, complemented to 0xFBFD
+	wire := []byte{0xFB, 0xFD, 0x01, 0x02, 0x03}
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, OddChecksumStruct{A: 1, B: [2]uint8{2, 3}}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+
+	var data any = &OddChecksumStruct{}
+	if err := Read(bytes.NewReader(wire), BigEndian, &data); err != nil {
+		t.Errorf("Read() error = %v", err)
+	}
+}
+
+func TestNetworkChecksumErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    any
+		wantErr error
+	}{
+		{"backwards range", BadChecksumStruct{}, ErrTag},
+		{"wrong type", struct {
+			Sum uint32 `network_checksum:"Sum:Sum"`
+		}{}, ErrUnexpectedType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Write(&bytes.Buffer{}, BigEndian, tt.data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Write() error = %v, wanted %v", err, tt.wantErr)
+			}
+		})
+	}
+}
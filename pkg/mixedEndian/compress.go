@@ -0,0 +1,214 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Compressor compresses the fields tagged with the name it's registered under, as
+// `compress:"gzip"`. Compress must give the same output for the same input, for encodings to be
+// deterministic. What Decompress returns is checked against the Decoder's WithMaxDecompressed cap
+// once it's done, where the built in Compressors stop as soon as they pass it.
+type Compressor interface {
+	Compress(bs []byte) ([]byte, error)
+	Decompress(bs []byte) ([]byte, error)
+}
+
+// defaultMaxDecompressed is the most a compress tagged field decompresses to without
+// WithMaxDecompressed
+const defaultMaxDecompressed = 64 << 20
+
+// streamDecompressor is a Compressor that decompresses as it's read, so can be stopped partway
+type streamDecompressor interface {
+	decompressor(bs []byte) (io.ReadCloser, error)
+}
+
+// decompress is c's decompression of bs, failing with ErrLength should it pass max bytes
+func decompress(c Compressor, bs []byte, max int64) ([]byte, error) {
+	var out []byte
+	if sd, ok := c.(streamDecompressor); ok {
+		rc, err := sd.decompressor(bs)
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		if out, err = io.ReadAll(io.LimitReader(rc, max+1)); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if out, err = c.Decompress(bs); err != nil {
+			return nil, err
+		}
+	}
+	if int64(len(out)) > max {
+		return nil, fmt.Errorf("%w Decompresses to over %d bytes", ErrLength, max)
+	}
+	return out, nil
+}
+
+// compressors are the Compressors a compress tag may name, those of RegisterCompressor included
+var compressors = struct {
+	sync.RWMutex
+	m map[string]Compressor
+}{m: map[string]Compressor{
+	"gzip": gzipCompressor{},
+	"zlib": zlibCompressor{},
+}}
+
+// RegisterCompressor names c, so compress tags can give name to use it. Registering a name
+// again replaces the Compressor it names.
+func RegisterCompressor(name string, c Compressor) error {
+	if c == nil {
+		return fmt.Errorf("%w Compressor %q is nil", ErrUnexpectedType, name)
+	}
+	if name == "" || strings.ContainsAny(name, ",=:\"") {
+		return fmt.Errorf("%w Compressor name %q can't appear in a tag", ErrTag, name)
+	}
+	compressors.Lock()
+	defer compressors.Unlock()
+	compressors.m[name] = c
+	return nil
+}
+
+// compressorOf is the Compressor named by sf's compress tag, checked against sf's type
+func compressorOf(sf reflect.StructField) (Compressor, error) {
+	name := sf.Tag.Get("compress")
+	if t := sf.Type; t.Kind() != reflect.String && (t.Kind() != reflect.Slice || t.Elem().Kind() != reflect.Uint8) {
+		return nil, fmt.Errorf("%w compress needs a string or []byte; Got %s", ErrUnexpectedType, t.String())
+	}
+	compressors.RLock()
+	defer compressors.RUnlock()
+	c, ok := compressors.m[name]
+	if !ok {
+		return nil, fmt.Errorf("%w Unknown compressor %q", ErrTag, name)
+	}
+	return c, nil
+}
+
+// readCompressed reads f as a uint32 length in byte order o, then that many bytes compressed
+// as sf's compress tag names
+func (r *reader) readCompressed(sf reflect.StructField, f reflect.Value, o binary.ByteOrder) error {
+	c, err := compressorOf(sf)
+	if err != nil {
+		return err
+	}
+	bs, err := r.next(4)
+	if err != nil {
+		return err
+	}
+	n := int64(wireOrder(o).Uint32(bs))
+
+	// Read as it arrives, so a corrupt length can't force a huge allocation
+	buf := &bytes.Buffer{}
+	if m, err := io.CopyN(buf, r.r, n); err == io.EOF {
+		return fmt.Errorf("%w Compressed block of %d bytes ends after %d", io.ErrUnexpectedEOF, n, m)
+	} else if err != nil {
+		return err
+	}
+
+	max := r.maxDecompressed
+	if max <= 0 {
+		max = defaultMaxDecompressed
+	}
+	if bs, err = decompress(c, buf.Bytes(), max); err != nil {
+		return fmt.Errorf("compress %s: %w", sf.Tag.Get("compress"), err)
+	}
+	if f.Kind() == reflect.String {
+		f.SetString(string(bs))
+	} else {
+		f.SetBytes(bs)
+	}
+	return nil
+}
+
+// writeCompressed writes f compressed as sf's compress tag names, led by its compressed length as
+// a uint32 in byte order o
+func (w *writer) writeCompressed(sf reflect.StructField, f reflect.Value, o binary.ByteOrder) error {
+	c, err := compressorOf(sf)
+	if err != nil {
+		return err
+	}
+	var bs []byte
+	if f.Kind() == reflect.String {
+		bs = []byte(f.String())
+	} else {
+		bs = f.Bytes()
+	}
+
+	if bs, err = c.Compress(bs); err != nil {
+		return fmt.Errorf("compress %s: %w", sf.Tag.Get("compress"), err)
+	}
+	if uint64(len(bs)) > 1<<32-1 {
+		return fmt.Errorf("%w Compressed block of %d bytes is too long", ErrLength, len(bs))
+	}
+	wireOrder(o).PutUint32(w.scratch[:4], uint32(len(bs)))
+	if _, err = w.w.Write(w.scratch[:4]); err != nil {
+		return err
+	}
+	_, err = w.w.Write(bs)
+	return err
+}
+
+// gzipCompressor is gzip, as RFC 1952 gives it, with no name or time in its header
+type gzipCompressor struct{}
+
+func (gzipCompressor) Compress(bs []byte) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	zw := gzip.NewWriter(buf)
+	if _, err := zw.Write(bs); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (c gzipCompressor) Decompress(bs []byte) ([]byte, error) {
+	zr, err := c.decompressor(bs)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
+
+func (gzipCompressor) decompressor(bs []byte) (io.ReadCloser, error) {
+	return gzip.NewReader(bytes.NewReader(bs))
+}
+
+// zlibCompressor is zlib, as RFC 1950 gives it
+type zlibCompressor struct{}
+
+func (zlibCompressor) Compress(bs []byte) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	zw := zlib.NewWriter(buf)
+	if _, err := zw.Write(bs); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (c zlibCompressor) Decompress(bs []byte) ([]byte, error) {
+	zr, err := c.decompressor(bs)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
+
+func (zlibCompressor) decompressor(bs []byte) (io.ReadCloser, error) {
+	return zlib.NewReader(bytes.NewReader(bs))
+}
@@ -0,0 +1,184 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type CompressedStruct struct {
+	Kind uint8
+	Body []byte `compress:"gzip"`
+	Note string `compress:"zlib" endian:"little"`
+}
+
+// bestGzip is gzip at its best compression, registered as a Compressor of its own
+type bestGzip struct{}
+
+func (bestGzip) Compress(bs []byte) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	zw, err := gzip.NewWriterLevel(buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = zw.Write(bs); err != nil {
+		return nil, err
+	}
+	if err = zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (bestGzip) Decompress(bs []byte) ([]byte, error) {
+	return gzipCompressor{}.Decompress(bs)
+}
+
+func TestCompressRoundTrip(t *testing.T) {
+	if err := RegisterCompressor("gzip-best", bestGzip{}); err != nil {
+		t.Fatalf("RegisterCompressor() error = %v", err)
+	}
+	payload := bytes.Repeat([]byte("mixedEndian compresses fields "), 200)
+
+	tests := []struct {
+		name string
+		data any
+	}{
+		{name: "built in", data: &CompressedStruct{Kind: 1, Body: payload, Note: strings.Repeat("note ", 50)}},
+		{name: "registered", data: &struct {
+			Body []byte `compress:"gzip-best"`
+		}{Body: payload}},
+		{name: "empty", data: &CompressedStruct{Body: []byte{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			if err := Write(buf, BigEndian, tt.data); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if tt.name != "empty" && buf.Len() >= len(payload)/4 {
+				t.Errorf("Write() wrote %d bytes of a %d byte payload, wanted it compressed", buf.Len(), len(payload))
+			}
+
+			got := reflect.New(reflect.TypeOf(tt.data).Elem()).Interface()
+			if err := NewDecoder(buf, BigEndian, WithStrict(true)).Decode(got); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.data) {
+				t.Errorf("Decode() differs from what was written")
+			}
+		})
+	}
+}
+
+func TestCompressWire(t *testing.T) {
+	data := CompressedStruct{Kind: 2, Body: []byte("abc"), Note: "xyz"}
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, data); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	wire := buf.Bytes()
+
+	// Body's length is big endian, as the struct is, and Note's little endian, as tagged
+	body, _ := gzipCompressor{}.Compress(data.Body)
+	if n := binary.BigEndian.Uint32(wire[1:]); int(n) != len(body) || !bytes.Equal(wire[5:5+n], body) {
+		t.Errorf("Write() Body = % X, wanted %d bytes of % X", wire[1:], len(body), body)
+	}
+	note := wire[5+len(body):]
+	if n := binary.LittleEndian.Uint32(note); int(n) != len(note)-4 {
+		t.Errorf("Write() Note length = %d, wanted %d", n, len(note)-4)
+	}
+
+	// Ending partway through a block, or a block that won't decompress, fails
+	var got any = &CompressedStruct{}
+	if err := Read(bytes.NewReader(wire[:len(wire)-2]), BigEndian, &got); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Read() error = %v, wanted %v", err, io.ErrUnexpectedEOF)
+	}
+	corrupt := append([]byte(nil), wire...)
+	corrupt[5] ^= 0xFF
+	if err := Read(bytes.NewReader(corrupt), BigEndian, &got); !errors.Is(err, gzip.ErrHeader) || !strings.HasPrefix(err.Error(), "Body: ") {
+		t.Errorf("Read() error = %v, wanted %v reading Body", err, gzip.ErrHeader)
+	}
+}
+
+func TestCompressBadTags(t *testing.T) {
+	tests := []struct {
+		name string
+		data any
+		want error
+	}{
+		{name: "unknown", data: &struct {
+			A []byte `compress:"lzma"`
+		}{}, want: ErrTag},
+		{name: "not bytes", data: &struct {
+			A []uint16 `compress:"gzip"`
+		}{}, want: ErrUnexpectedType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Write(io.Discard, BigEndian, tt.data); !errors.Is(err, tt.want) {
+				t.Errorf("Write() error = %v, wanted %v", err, tt.want)
+			}
+			err := NewDecoder(bytes.NewReader(make([]byte, 8)), BigEndian, WithStrict(true)).Decode(tt.data)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Decode() error = %v, wanted %v", err, tt.want)
+			}
+		})
+	}
+
+	if err := RegisterCompressor("a,b", bestGzip{}); !errors.Is(err, ErrTag) {
+		t.Errorf("RegisterCompressor() error = %v, wanted %v", err, ErrTag)
+	}
+}
+
+func TestCompressMaxDecompressed(t *testing.T) {
+	payload := bytes.Repeat([]byte{0}, 6000)
+	data := &CompressedStruct{Body: payload}
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, data); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	wire := buf.Bytes()
+
+	tests := []struct {
+		name string
+		max  int64
+		want error
+	}{
+		{name: "default", max: 0},
+		{name: "at the cap", max: int64(len(payload))},
+		{name: "over the cap", max: int64(len(payload)) - 1, want: ErrLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &CompressedStruct{}
+			err := NewDecoder(bytes.NewReader(wire), BigEndian, WithMaxDecompressed(tt.max)).Decode(got)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Decode() error = %v, wanted %v", err, tt.want)
+			}
+			if err == nil && !bytes.Equal(got.Body, payload) {
+				t.Errorf("Decode() Body = %d bytes, wanted %d", len(got.Body), len(payload))
+			}
+		})
+	}
+
+	// Registered Compressors are held to the cap once they're done
+	if err := RegisterCompressor("gzip-best", bestGzip{}); err != nil {
+		t.Fatalf("RegisterCompressor() error = %v", err)
+	}
+	registered := &struct {
+		Body []byte `compress:"gzip-best"`
+	}{Body: payload}
+	buf.Reset()
+	if err := Write(buf, BigEndian, registered); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := NewDecoder(buf, BigEndian, WithMaxDecompressed(100)).Decode(registered); !errors.Is(err, ErrLength) {
+		t.Errorf("Decode() error = %v, wanted %v", err, ErrLength)
+	}
+}
@@ -0,0 +1,237 @@
+package mixedEndian
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CRC is a CRC in the Rocksoft model, as the CRC catalogue gives them: a Width bit register
+// starting at Init, fed through generator polynomial Poly a byte at a time, each byte taken least
+// significant bit first when RefIn, the result reversed when RefOut, then xored with XorOut.
+// Width is 8, 16, 32, or 64, and Poly is given without its top bit, as the catalogue does.
+type CRC struct {
+	Width  int
+	Poly   uint64
+	Init   uint64
+	RefIn  bool
+	RefOut bool
+	XorOut uint64
+}
+
+// crcTables caches the lookup table of each CRC, by its parameters
+var crcTables sync.Map
+
+// namedCRCs are the CRCs a tag may name, those of RegisterCRC included
+var namedCRCs = struct {
+	sync.RWMutex
+	m map[string]CRC
+}{m: map[string]CRC{
+	"crc8":          {Width: 8, Poly: 0x07},
+	"crc8-maxim":    {Width: 8, Poly: 0x31, RefIn: true, RefOut: true},
+	"crc16":         {Width: 16, Poly: 0x8005, RefIn: true, RefOut: true},
+	"crc16-arc":     {Width: 16, Poly: 0x8005, RefIn: true, RefOut: true},
+	"crc16-ccitt":   {Width: 16, Poly: 0x1021, Init: 0xFFFF},
+	"crc16-dnp":     {Width: 16, Poly: 0x3D65, RefIn: true, RefOut: true, XorOut: 0xFFFF},
+	"crc16-kermit":  {Width: 16, Poly: 0x1021, RefIn: true, RefOut: true},
+	"crc16-modbus":  {Width: 16, Poly: 0x8005, Init: 0xFFFF, RefIn: true, RefOut: true},
+	"crc16-x25":     {Width: 16, Poly: 0x1021, Init: 0xFFFF, RefIn: true, RefOut: true, XorOut: 0xFFFF},
+	"crc16-xmodem":  {Width: 16, Poly: 0x1021},
+	"crc32":         {Width: 32, Poly: 0x04C11DB7, Init: 0xFFFFFFFF, RefIn: true, RefOut: true, XorOut: 0xFFFFFFFF},
+	"crc32c":        {Width: 32, Poly: 0x1EDC6F41, Init: 0xFFFFFFFF, RefIn: true, RefOut: true, XorOut: 0xFFFFFFFF},
+	"crc32-bzip2":   {Width: 32, Poly: 0x04C11DB7, Init: 0xFFFFFFFF, XorOut: 0xFFFFFFFF},
+	"crc32-mpeg2":   {Width: 32, Poly: 0x04C11DB7, Init: 0xFFFFFFFF},
+	"crc64-ecma182": {Width: 64, Poly: 0x42F0E1EBA9EA3693},
+	"crc64-xz":      {Width: 64, Poly: 0x42F0E1EBA9EA3693, Init: 1<<64 - 1, RefIn: true, RefOut: true, XorOut: 1<<64 - 1},
+}}
+
+// RegisterCRC names c, so tags can give name in place of c's parameters
+func RegisterCRC(name string, c CRC) error {
+	if err := c.check(); err != nil {
+		return err
+	}
+	if name == "" || strings.ContainsAny(name, ",=:") {
+		return fmt.Errorf("%w CRC name %q can't appear in a tag", ErrTag, name)
+	}
+	namedCRCs.Lock()
+	defer namedCRCs.Unlock()
+	namedCRCs.m[name] = c
+	return nil
+}
+
+// parseCRC resolves a CRC named by spec, either a registered name or its parameters, such as
+// "width=16,poly=0x1021,init=0xFFFF,refin=true,refout=true,xorout=0". Parameters left out are 0
+// or false, but width and poly must be given.
+func parseCRC(spec string) (c CRC, err error) {
+	if !strings.Contains(spec, "=") {
+		namedCRCs.RLock()
+		defer namedCRCs.RUnlock()
+		var ok bool
+		if c, ok = namedCRCs.m[spec]; !ok {
+			return c, fmt.Errorf("%w Unknown CRC %q", ErrTag, spec)
+		}
+		return c, nil
+	}
+
+	seen := map[string]bool{}
+	for _, param := range strings.Split(spec, ",") {
+		k, v, _ := strings.Cut(param, "=")
+		if seen[k] {
+			return c, fmt.Errorf("%w CRC %q gives %s twice", ErrTag, spec, k)
+		}
+		seen[k] = true
+
+		switch k {
+		case "width":
+			c.Width, err = strconv.Atoi(v)
+		case "poly":
+			c.Poly, err = strconv.ParseUint(v, 0, 64)
+		case "init":
+			c.Init, err = strconv.ParseUint(v, 0, 64)
+		case "xorout":
+			c.XorOut, err = strconv.ParseUint(v, 0, 64)
+		case "refin":
+			c.RefIn, err = strconv.ParseBool(v)
+		case "refout":
+			c.RefOut, err = strconv.ParseBool(v)
+		default:
+			return c, fmt.Errorf("%w CRC %q has unknown parameter %q", ErrTag, spec, k)
+		}
+		if err != nil {
+			return c, fmt.Errorf("%w CRC %q has a bad %s", ErrTag, spec, k)
+		}
+	}
+	if !seen["width"] || !seen["poly"] {
+		return c, fmt.Errorf("%w CRC %q needs a width and poly", ErrTag, spec)
+	}
+	return c, c.check()
+}
+
+// crcRange splits sf's crc tag into the CRC it names and the range of fields it covers, given
+// either after the CRC, as in "crc16,A..B", or by a crcrange tag, as in "A:B". The range is
+// returned in the latter form.
+func crcRange(sf reflect.StructField) (spec, rng string, err error) {
+	spec, rng = sf.Tag.Get("crc"), sf.Tag.Get("crcrange")
+	if i := strings.LastIndexByte(spec, ','); i >= 0 && strings.Contains(spec[i:], "..") {
+		if rng != "" {
+			return spec, rng, fmt.Errorf("%w crc %q gives a range as well as crcrange", ErrTag, spec)
+		}
+		spec, rng = spec[:i], strings.Replace(spec[i+1:], "..", ":", 1)
+	}
+	return
+}
+
+// crcOf resolves the CRC of field sf, named by its crc tag, with the initial value and polynomial
+// overridden by any crc_seed and crc_poly tags, given in hex
+func crcOf(sf reflect.StructField) (c CRC, err error) {
+	spec, _, err := crcRange(sf)
+	if err != nil {
+		return
+	}
+	if c, err = parseCRC(spec); err != nil {
+		return
+	}
+	for _, o := range []struct {
+		key string
+		u   *uint64
+	}{{"crc_seed", &c.Init}, {"crc_poly", &c.Poly}} {
+		if s := sf.Tag.Get(o.key); s != "" {
+			s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+			if *o.u, err = strconv.ParseUint(s, 16, 64); err != nil {
+				return c, fmt.Errorf("%w %s %q is not a hex number", ErrTag, o.key, sf.Tag.Get(o.key))
+			}
+		}
+	}
+	return c, c.check()
+}
+
+// check errors unless c's parameters fit its width
+func (c CRC) check() error {
+	switch c.Width {
+	case 8, 16, 32, 64:
+	default:
+		return fmt.Errorf("%w CRC width %d is not 8, 16, 32, or 64", ErrTag, c.Width)
+	}
+	if m := c.mask(); c.Poly&^m != 0 || c.Init&^m != 0 || c.XorOut&^m != 0 || c.Poly&1 == 0 {
+		return fmt.Errorf("%w CRC parameters %+v don't fit width %d", ErrTag, c, c.Width)
+	}
+	return nil
+}
+
+// mask has c's low Width bits set
+func (c CRC) mask() uint64 {
+	return 1<<(c.Width-1)<<1 - 1
+}
+
+// table is c's lookup table, built on first use
+func (c CRC) table() *[256]uint64 {
+	if t, ok := crcTables.Load(c); ok {
+		return t.(*[256]uint64)
+	}
+
+	t := new([256]uint64)
+	if c.RefIn {
+		// Reflected CRCs shift right through the reversed polynomial
+		poly := reverseBits(c.Poly, c.Width)
+		for i := range t {
+			r := uint64(i)
+			for j := 0; j < 8; j++ {
+				if r&1 != 0 {
+					r = r>>1 ^ poly
+				} else {
+					r >>= 1
+				}
+			}
+			t[i] = r
+		}
+	} else {
+		top := uint64(1) << (c.Width - 1)
+		for i := range t {
+			r := uint64(i) << (c.Width - 8)
+			for j := 0; j < 8; j++ {
+				if r&top != 0 {
+					r = r<<1 ^ c.Poly
+				} else {
+					r <<= 1
+				}
+			}
+			t[i] = r & c.mask()
+		}
+	}
+	actual, _ := crcTables.LoadOrStore(c, t)
+	return actual.(*[256]uint64)
+}
+
+// Checksum is the CRC of bs
+func (c CRC) Checksum(bs []byte) uint64 {
+	t := c.table()
+	r := c.Init
+	if c.RefIn {
+		r = reverseBits(r, c.Width)
+		for _, b := range bs {
+			r = r>>8 ^ t[byte(r)^b]
+		}
+	} else {
+		shift := c.Width - 8
+		for _, b := range bs {
+			r = (r<<8 ^ t[byte(r>>shift)^b]) & c.mask()
+		}
+	}
+
+	// The register's reflected by now exactly when the input was
+	if c.RefIn != c.RefOut {
+		r = reverseBits(r, c.Width)
+	}
+	return r ^ c.XorOut
+}
+
+// reverseBits reverses the low width bits of u
+func reverseBits(u uint64, width int) (r uint64) {
+	for i := 0; i < width; i++ {
+		r = r<<1 | u&1
+		u >>= 1
+	}
+	return
+}
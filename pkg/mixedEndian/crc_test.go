@@ -0,0 +1,343 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"hash/crc32"
+	"hash/crc64"
+	"io"
+	"reflect"
+	"testing"
+)
+
+type ModbusFrame struct {
+	Address  uint8
+	Function uint8
+	Data     [4]byte
+	CRC      uint16 `crc:"crc16-modbus" crcrange:"Address:Data" endian:"little"`
+}
+
+// RangedCRCFrame's CRC covers only its middle two fields, leaving out its header and trailer
+type RangedCRCFrame struct {
+	Header  uint16
+	Payload [4]byte
+	Seq     uint16
+	Trailer uint8
+	CRC     uint16 `crc:"crc16,Payload..Seq"`
+}
+
+type CustomCRCStruct struct {
+	CRC  uint16 `crc:"width=16,poly=0x1021,init=0x1D0F,refin=false,refout=false,xorout=0" crcrange:"CRC:Body"`
+	Body [9]byte
+}
+
+func TestCRCCatalogue(t *testing.T) {
+	// Check values from the CRC catalogue, each the CRC of "123456789"
+	tests := []struct {
+		name string
+		spec string
+		want uint64
+	}{
+		{"CRC-8", "crc8", 0xF4},
+		{"CRC-8/MAXIM", "crc8-maxim", 0xA1},
+		{"CRC-8/SAE-J1850", "width=8,poly=0x1D,init=0xFF,xorout=0xFF", 0x4B},
+		{"CRC-16/ARC", "crc16-arc", 0xBB3D},
+		{"CRC-16", "crc16", 0xBB3D},
+		{"CRC-16/CCITT-FALSE", "crc16-ccitt", 0x29B1},
+		{"CRC-16/DNP", "crc16-dnp", 0xEA82},
+		{"CRC-16/KERMIT", "crc16-kermit", 0x2189},
+		{"CRC-16/MODBUS", "crc16-modbus", 0x4B37},
+		{"CRC-16/X-25", "crc16-x25", 0x906E},
+		{"CRC-16/XMODEM", "crc16-xmodem", 0x31C3},
+		{"CRC-16/AUG-CCITT", "width=16,poly=0x1021,init=0x1D0F", 0xE5CC},
+		{"CRC-32", "crc32", 0xCBF43926},
+		{"CRC-32C", "crc32c", 0xE3069283},
+		{"CRC-32/BZIP2", "crc32-bzip2", 0xFC891918},
+		{"CRC-32/MPEG-2", "crc32-mpeg2", 0x0376E6E7},
+		{"CRC-64/ECMA-182", "crc64-ecma182", 0x6C40DF5F0B497347},
+		{"CRC-64/XZ", "crc64-xz", 0x995DC9BBDF1939FA},
+		{"CRC-64/GO-ISO", "width=64,poly=0x1B,init=0xFFFFFFFFFFFFFFFF,refin=true,refout=true,xorout=0xFFFFFFFFFFFFFFFF", 0xB90956C775A41001},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := parseCRC(tt.spec)
+			if err != nil {
+				t.Fatalf("parseCRC() error = %v", err)
+			}
+			if got := c.Checksum([]byte("123456789")); got != tt.want {
+				t.Errorf("Checksum() = %#x, wanted %#x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCRCStandardLibrary(t *testing.T) {
+	data := []byte("The quick brown fox jumps over the lazy dog")
+	c32, _ := parseCRC("crc32")
+	if got, want := c32.Checksum(data), uint64(crc32.ChecksumIEEE(data)); got != want {
+		t.Errorf("crc32 Checksum() = %#x, wanted %#x", got, want)
+	}
+	c32c, _ := parseCRC("crc32c")
+	if got, want := c32c.Checksum(data), uint64(crc32.Checksum(data, crc32.MakeTable(crc32.Castagnoli))); got != want {
+		t.Errorf("crc32c Checksum() = %#x, wanted %#x", got, want)
+	}
+	// hash/crc64 is the reflected, inverted form of ECMA-182, as XZ uses
+	c64, _ := parseCRC("crc64-xz")
+	if got, want := c64.Checksum(data), crc64.Checksum(data, crc64.MakeTable(crc64.ECMA)); got != want {
+		t.Errorf("crc64-xz Checksum() = %#x, wanted %#x", got, want)
+	}
+}
+
+func TestRegisterCRC(t *testing.T) {
+	custom := CRC{Width: 16, Poly: 0x8005, Init: 0x800D}
+	if err := RegisterCRC("crc16-test", custom); err != nil {
+		t.Fatalf("RegisterCRC() error = %v", err)
+	}
+	got, err := parseCRC("crc16-test")
+	if err != nil || got != custom {
+		t.Errorf("parseCRC() = %+v, %v, wanted %+v", got, err, custom)
+	}
+
+	tests := []struct {
+		name string
+		crc  CRC
+	}{
+		{"bad width", CRC{Width: 24, Poly: 0x864CFB}},
+		{"poly too wide", CRC{Width: 8, Poly: 0x107}},
+		{"even poly", CRC{Width: 8, Poly: 0x06}},
+		{"init too wide", CRC{Width: 16, Poly: 0x1021, Init: 0x10000}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RegisterCRC("crc-bad", tt.crc); !errors.Is(err, ErrTag) {
+				t.Errorf("RegisterCRC() error = %v, wanted %v", err, ErrTag)
+			}
+		})
+	}
+	if err := RegisterCRC("crc,bad", custom); !errors.Is(err, ErrTag) {
+		t.Errorf("RegisterCRC() error = %v, wanted %v", err, ErrTag)
+	}
+}
+
+func TestParseCRCErrors(t *testing.T) {
+	for _, spec := range []string{
+		"crc-unknown",
+		"poly=0x1021",
+		"width=16",
+		"width=16,poly=0x1021,refin=maybe",
+		"width=16,poly=0x1021,poly=0x8005",
+		"width=16,poly=0x1021,reflect=true",
+	} {
+		t.Run(spec, func(t *testing.T) {
+			if _, err := parseCRC(spec); !errors.Is(err, ErrTag) {
+				t.Errorf("parseCRC() error = %v, wanted %v", err, ErrTag)
+			}
+		})
+	}
+}
+
+func TestCRCField(t *testing.T) {
+	// A Modbus RTU read holding registers request, whose CRC is given as C5 CD
+	want := &ModbusFrame{Address: 0x01, Function: 0x03, Data: [4]byte{0x00, 0x00, 0x00, 0x0A}, CRC: 0xCDC5}
+	wire := []byte{0x01, 0x03, 0x00, 0x00, 0x00, 0x0A, 0xC5, 0xCD}
+
+	unsummed := *want
+	unsummed.CRC = 0
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, unsummed); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+
+	var data any = &ModbusFrame{}
+	if err := Read(bytes.NewReader(wire), BigEndian, &data); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("Read() data = %v, wanted %v", data, want)
+	}
+
+	corrupt := append([]byte(nil), wire...)
+	corrupt[3] ^= 0x01
+	data = &ModbusFrame{}
+	if err := Read(bytes.NewReader(corrupt), BigEndian, &data); !errors.Is(err, ErrChecksum) {
+		t.Errorf("Read() error = %v, wanted %v", err, ErrChecksum)
+	}
+}
+
+func TestCRCRange(t *testing.T) {
+	covered := []byte{'1', '2', '3', '4', '5', '6'}
+	sum := namedCRCs.m["crc16"].Checksum(covered)
+	want := &RangedCRCFrame{Header: 0xAAAA, Payload: [4]byte{'1', '2', '3', '4'}, Seq: 0x3536, Trailer: 0xEE, CRC: uint16(sum)}
+	wire := append(append([]byte{0xAA, 0xAA}, covered...), 0xEE, byte(sum>>8), byte(sum))
+
+	unsummed := *want
+	unsummed.CRC = 0
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, unsummed); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+
+	var data any = &RangedCRCFrame{}
+	if err := Read(bytes.NewReader(wire), BigEndian, &data); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("Read() data = %v, wanted %v", data, want)
+	}
+
+	// Bytes outside the range aren't covered, those within are
+	for _, tt := range []struct {
+		name    string
+		at      int
+		wantErr error
+	}{
+		{"header", 0, nil},
+		{"payload", 3, ErrChecksum},
+		{"seq", 7, ErrChecksum},
+		{"trailer", 8, nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			corrupt := append([]byte(nil), wire...)
+			corrupt[tt.at] ^= 0x01
+			data = &RangedCRCFrame{}
+			if err := Read(bytes.NewReader(corrupt), BigEndian, &data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Read() error = %v, wanted %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCRCFieldInRange(t *testing.T) {
+	// A CRC within its own range is computed over zeros in its place
+	body := [9]byte{'1', '2', '3', '4', '5', '6', '7', '8', '9'}
+	c := CRC{Width: 16, Poly: 0x1021, Init: 0x1D0F}
+	sum := c.Checksum(append([]byte{0, 0}, body[:]...))
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, CustomCRCStruct{Body: body}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	var data any = &CustomCRCStruct{}
+	if err := Read(bytes.NewReader(buf.Bytes()), BigEndian, &data); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if got := data.(*CustomCRCStruct).CRC; uint64(got) != sum {
+		t.Errorf("Read() CRC = %#04x, wanted %#04x", got, sum)
+	}
+}
+
+func TestCRCSeedAndPoly(t *testing.T) {
+	body := []byte("123456789")
+	tests := []struct {
+		name string
+		data any
+		want uint64
+	}{
+		{"seed", &struct {
+			Body [9]byte
+			Sum  uint32 `crc:"crc32" crcrange:"Body:Body" crc_seed:"0x00000000"`
+		}{}, CRC{Width: 32, Poly: 0x04C11DB7, RefIn: true, RefOut: true, XorOut: 0xFFFFFFFF}.Checksum(body)},
+		{"poly", &struct {
+			Body [9]byte
+			Sum  uint32 `crc:"crc32" crcrange:"Body:Body" crc_poly:"1EDC6F41"`
+		}{}, 0xE3069283},
+		{"both", &struct {
+			Body [9]byte
+			Sum  uint16 `crc:"crc16-xmodem" crcrange:"Body:Body" crc_seed:"FFFF" crc_poly:"0x1021"`
+		}{}, 0x29B1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := reflect.ValueOf(tt.data).Elem()
+			reflect.Copy(v.Field(0), reflect.ValueOf(body))
+			buf := &bytes.Buffer{}
+			if err := Write(buf, LittleEndian, tt.data); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if err := Read(bytes.NewReader(buf.Bytes()), LittleEndian, &tt.data); err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if got := v.Field(1).Uint(); got != tt.want {
+				t.Errorf("Write() CRC = %#x, wanted %#x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCRCFieldErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    any
+		wantErr error
+	}{
+		{"wrong width", struct {
+			A   uint8
+			Sum uint16 `crc:"crc32" crcrange:"A:A"`
+		}{}, ErrUnexpectedType},
+		{"no range", struct {
+			A   uint8
+			Sum uint16 `crc:"crc16-arc"`
+		}{}, ErrTag},
+		{"unknown crc", struct {
+			A   uint8
+			Sum uint16 `crc:"crc16-nope" crcrange:"A:A"`
+		}{}, ErrTag},
+		{"two ranges", struct {
+			A   uint8
+			Sum uint16 `crc:"crc16,A..A" crcrange:"A:A"`
+		}{}, ErrTag},
+		{"bad inline range", struct {
+			A   uint8
+			Sum uint16 `crc:"crc16,A..B"`
+		}{}, ErrTag},
+		{"seed not hex", struct {
+			A   uint8
+			Sum uint16 `crc:"crc16-arc" crcrange:"A:A" crc_seed:"0xFFFG"`
+		}{}, ErrTag},
+		{"seed too wide", struct {
+			A   uint8
+			Sum uint16 `crc:"crc16-arc" crcrange:"A:A" crc_seed:"0x10000"`
+		}{}, ErrTag},
+		{"even poly", struct {
+			A   uint8
+			Sum uint16 `crc:"crc16-arc" crcrange:"A:A" crc_poly:"8004"`
+		}{}, ErrTag},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Write(&bytes.Buffer{}, BigEndian, tt.data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Write() error = %v, wanted %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCRCBlocksWide(t *testing.T) {
+	payload := []byte("123456789")
+	buf := &bytes.Buffer{}
+	w, err := NewCRCBlockWriter(buf, 9, "crc32")
+	if err != nil {
+		t.Fatalf("NewCRCBlockWriter() error = %v", err)
+	}
+	if _, err = w.Write(payload); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	want := append(append([]byte(nil), payload...), 0x26, 0x39, 0xF4, 0xCB)
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("CRCBlockWriter wrote % X, wanted % X", buf.Bytes(), want)
+	}
+
+	r, err := NewCRCBlockReader(bytes.NewReader(want), 9, "crc32")
+	if err != nil {
+		t.Fatalf("NewCRCBlockReader() error = %v", err)
+	}
+	if got, err := io.ReadAll(r); err != nil || !bytes.Equal(got, payload) {
+		t.Errorf("CRCBlockReader read %q, %v, wanted %q", got, err, payload)
+	}
+}
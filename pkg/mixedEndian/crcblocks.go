@@ -0,0 +1,210 @@
+package mixedEndian
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// parseCRCBlocks parses a crcblocks tag, such as "16,crc16-dnp", the CRC named as a crc tag names it
+func parseCRCBlocks(tag string) (size int, crc CRC, err error) {
+	s, spec, _ := strings.Cut(tag, ",")
+	if size, err = strconv.Atoi(s); err != nil || size < 1 {
+		return 0, crc, fmt.Errorf("%w crcblocks %q needs a block size", ErrTag, tag)
+	}
+	if crc, err = parseCRC(spec); err != nil {
+		return 0, crc, fmt.Errorf("crcblocks: %w", err)
+	}
+	return
+}
+
+// appendCRC appends the low n bytes of sum to bs, little endian
+func appendCRC(bs []byte, sum uint64, n int) []byte {
+	for i := 0; i < n; i++ {
+		bs = append(bs, byte(sum>>(8*i)))
+	}
+	return bs
+}
+
+// getCRC is the little endian CRC filling bs
+func getCRC(bs []byte) (sum uint64) {
+	for i := len(bs) - 1; i >= 0; i-- {
+		sum = sum<<8 | uint64(bs[i])
+	}
+	return
+}
+
+// CRCBlockReader reads a payload stored in blocks, each followed by its little endian CRC,
+// checking and stripping the CRCs. The last block may be short, and carries its own CRC.
+type CRCBlockReader struct {
+	r     io.Reader
+	size  int
+	crc   CRC
+	block []byte
+
+	// buf holds the payload of the current block, index counting blocks from 0
+	buf   []byte
+	index int
+	err   error
+}
+
+// NewCRCBlockReader returns a CRCBlockReader of r, in blocks of size bytes with CRCs named as a
+// crcblocks tag names them. The payload ends with r.
+func NewCRCBlockReader(r io.Reader, size int, crc string) (*CRCBlockReader, error) {
+	s, c, err := parseCRCBlocks(strconv.Itoa(size) + "," + crc)
+	if err != nil {
+		return nil, err
+	}
+	return &CRCBlockReader{r: guardProgress(r), size: s, crc: c, block: make([]byte, s+c.Width/8), index: -1}, nil
+}
+
+func (c *CRCBlockReader) Read(bs []byte) (int, error) {
+	for len(c.buf) == 0 {
+		if c.err != nil {
+			return 0, c.err
+		}
+		c.fill()
+	}
+	n := copy(bs, c.buf)
+	c.buf = c.buf[n:]
+	return n, nil
+}
+
+// fill reads and checks the next block
+func (c *CRCBlockReader) fill() {
+	n, err := io.ReadFull(c.r, c.block)
+	k := c.crc.Width / 8
+	switch {
+	case err == io.EOF:
+		c.err = io.EOF
+		return
+	case err == io.ErrUnexpectedEOF && n > k:
+		// A short last block
+	case err == io.ErrUnexpectedEOF:
+		c.err = fmt.Errorf("%w Block %d has no room for its CRC", ErrLength, c.index+1)
+		return
+	case err != nil:
+		c.err = err
+		return
+	}
+
+	c.index++
+	data := c.block[:n-k]
+	if got, want := getCRC(c.block[n-k:n]), c.crc.Checksum(data); got != want {
+		c.err = fmt.Errorf("%w Block %d has CRC %#0*x, computed %#0*x", ErrChecksum, c.index, 2*k, got, 2*k, want)
+		return
+	}
+	c.buf = data
+	if n < len(c.block) {
+		c.err = io.EOF
+	}
+}
+
+// CRCBlockWriter writes a payload in blocks, each followed by its little endian CRC.
+// Close writes the last, short block.
+type CRCBlockWriter struct {
+	w    io.Writer
+	size int
+	crc  CRC
+	buf  []byte
+}
+
+// NewCRCBlockWriter returns a CRCBlockWriter to w, in blocks of size bytes with CRCs named as a
+// crcblocks tag names them
+func NewCRCBlockWriter(w io.Writer, size int, crc string) (*CRCBlockWriter, error) {
+	s, c, err := parseCRCBlocks(strconv.Itoa(size) + "," + crc)
+	if err != nil {
+		return nil, err
+	}
+	return &CRCBlockWriter{w: w, size: s, crc: c, buf: make([]byte, 0, s+c.Width/8)}, nil
+}
+
+func (c *CRCBlockWriter) Write(bs []byte) (n int, err error) {
+	for len(bs) > 0 {
+		m := copy(c.buf[len(c.buf):c.size], bs)
+		c.buf = c.buf[:len(c.buf)+m]
+		bs, n = bs[m:], n+m
+		if len(c.buf) == c.size {
+			if err = c.flush(); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// Close writes whatever's left as a short block. It doesn't close the underlying io.Writer.
+func (c *CRCBlockWriter) Close() error {
+	if len(c.buf) == 0 {
+		return nil
+	}
+	return c.flush()
+}
+
+// flush writes the buffered block and its CRC
+func (c *CRCBlockWriter) flush() error {
+	block := appendCRC(c.buf, c.crc.Checksum(c.buf), c.crc.Width/8)
+	c.buf = c.buf[:0]
+	_, err := writeFull(c.w, block)
+	return err
+}
+
+// payloadSize is the encoded size of f, which a crcblocks tag needs to know before reading
+func payloadSize(f reflect.Value) (int, error) {
+	t := f.Type()
+	n := 1
+	if k := t.Kind(); k == reflect.Array || k == reflect.Slice {
+		n, t = f.Len(), t.Elem()
+	}
+
+	size := typeSize(t)
+	if t.Kind() == reflect.Struct {
+		sl, err := LayoutOf(t)
+		if err != nil {
+			return 0, err
+		}
+		size = sl.Size
+	}
+	if size <= 0 {
+		return 0, fmt.Errorf("%w crcblocks needs a fixed size; Got %s", ErrUnexpectedType, f.Type().String())
+	}
+	return n * size, nil
+}
+
+// readCRCBlocks reads f, sized already, from blocks as given by sf's crcblocks tag
+func (r *reader) readCRCBlocks(sf reflect.StructField, f reflect.Value, o binary.ByteOrder) error {
+	size, crc, err := parseCRCBlocks(sf.Tag.Get("crcblocks"))
+	if err != nil {
+		return err
+	}
+	n, err := payloadSize(f)
+	if err != nil {
+		return err
+	}
+
+	// The payload's followed by a CRC for each block, the last possibly short
+	k := crc.Width / 8
+	wire := int64(n + k*((n+size-1)/size))
+	cr := &CRCBlockReader{r: io.LimitReader(r.r, wire), size: size, crc: crc, block: make([]byte, size+k), index: -1}
+	sub := *r
+	sub.r = cr
+	return sub.readOrdered(f, o)
+}
+
+// writeCRCBlocks writes f into blocks as given by sf's crcblocks tag
+func (w *writer) writeCRCBlocks(sf reflect.StructField, f reflect.Value, o binary.ByteOrder) error {
+	size, crc, err := parseCRCBlocks(sf.Tag.Get("crcblocks"))
+	if err != nil {
+		return err
+	}
+	cw := &CRCBlockWriter{w: w.w, size: size, crc: crc, buf: make([]byte, 0, size+crc.Width/8)}
+	sub := *w
+	sub.w = cw
+	if err = sub.writeOrdered(f, o); err != nil {
+		return err
+	}
+	return cw.Close()
+}
@@ -0,0 +1,111 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type DNP3Struct struct {
+	N    uint8
+	Data []byte `len:"N" crcblocks:"8,crc16-dnp"`
+}
+
+func TestCRC16DNP(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want uint16
+	}{
+		{"check", []byte("123456789"), 0xEA82},
+		// The link header of a DNP3 frame, whose CRC is given as E9 21
+		{"link header", []byte{0x05, 0x64, 0x05, 0xC0, 0x01, 0x00, 0x00, 0x04}, 0x21E9},
+	}
+	dnp, err := parseCRC("crc16-dnp")
+	if err != nil {
+		t.Fatalf("parseCRC() error = %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dnp.Checksum(tt.data); got != uint64(tt.want) {
+				t.Errorf("Checksum() = %#04x, wanted %#04x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCRCBlocks(t *testing.T) {
+	header := []byte{0x05, 0x64, 0x05, 0xC0, 0x01, 0x00, 0x00, 0x04}
+	want := &DNP3Struct{N: 17, Data: append(append([]byte(nil), header...), "123456789"...)}
+	wire := []byte{0x11}
+	wire = append(wire, header...)
+	wire = append(wire, 0xE9, 0x21)
+	wire = append(wire, "12345678"...)
+	wire = append(wire, 0x2F, 0x18)
+	wire = append(wire, '9', 0xEC, 0xAD)
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, want); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+
+	var data any = &DNP3Struct{}
+	r := bytes.NewReader(append(wire, 0xFF))
+	if err := Read(r, BigEndian, &data); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("Read() data = %v, wanted %v", data, want)
+	}
+	if r.Len() != 1 {
+		t.Errorf("Read() left %d bytes, wanted 1", r.Len())
+	}
+
+	// Corrupting the second block's payload is reported against it
+	corrupt := append([]byte(nil), wire...)
+	corrupt[12] ^= 0x01
+	data = &DNP3Struct{}
+	if err := Read(bytes.NewReader(corrupt), BigEndian, &data); !errors.Is(err, ErrChecksum) || !strings.Contains(err.Error(), "Block 1") {
+		t.Errorf("Read() error = %v, wanted %v in block 1", err, ErrChecksum)
+	}
+}
+
+func TestCRCBlockStreams(t *testing.T) {
+	payload := []byte("a payload longer than a couple of blocks")
+
+	buf := &bytes.Buffer{}
+	w, err := NewCRCBlockWriter(buf, 16, "crc16-dnp")
+	if err != nil {
+		t.Fatalf("NewCRCBlockWriter() error = %v", err)
+	}
+	for _, chunk := range [][]byte{payload[:5], payload[5:30], payload[30:]} {
+		if _, err = w.Write(chunk); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if want := len(payload) + 6; buf.Len() != want {
+		t.Errorf("CRCBlockWriter wrote %d bytes, wanted %d", buf.Len(), want)
+	}
+
+	r, err := NewCRCBlockReader(bytes.NewReader(buf.Bytes()), 16, "crc16-dnp")
+	if err != nil {
+		t.Fatalf("NewCRCBlockReader() error = %v", err)
+	}
+	got, err := io.ReadAll(r)
+	if err != nil || !bytes.Equal(got, payload) {
+		t.Errorf("CRCBlockReader read %q, %v, wanted %q", got, err, payload)
+	}
+
+	if _, err = NewCRCBlockReader(nil, 16, "crc24"); !errors.Is(err, ErrTag) {
+		t.Errorf("NewCRCBlockReader() error = %v, wanted %v", err, ErrTag)
+	}
+}
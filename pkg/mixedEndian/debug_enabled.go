@@ -0,0 +1,27 @@
+//go:build debug
+
+package mixedEndian
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Debug builds check structs requiring alignment as they're registered, so misaligned structs
+// shared with C fail at startup rather than corrupting data later
+func init() {
+	onRegister = func(name string, t reflect.Type) {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return
+		}
+		if so, err := optionsOf(t); err != nil || !so.requireAlignment {
+			return
+		}
+		if err := ValidateAlignment(t); err != nil {
+			panic(fmt.Sprintf("mixedEndian: registering %s: %v", name, err))
+		}
+	}
+}
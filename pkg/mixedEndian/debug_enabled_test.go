@@ -0,0 +1,23 @@
+//go:build debug
+
+package mixedEndian
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRegisterRequiresAlignment(t *testing.T) {
+	tr := NewTypeRegistry()
+	tr.Register("Aligned", reflect.TypeOf(AlignedStruct{}))
+
+	// Misaligned structs without the marker are left alone
+	tr.Register("Nested", reflect.TypeOf(NestedStruct{}))
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Register() didn't panic on a misaligned struct")
+		}
+	}()
+	tr.Register("Misaligned", reflect.TypeOf(MisalignedStruct{}))
+}
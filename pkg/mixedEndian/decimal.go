@@ -0,0 +1,525 @@
+package mixedEndian
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an IEEE 754 decimal floating point number, as held by fields tagged with a decimal
+// floatfmt. Finite values are (-1)^Neg × Coefficient × 10^Exponent. Zeros keep their sign and
+// exponent, so 1.50 and 1.5 are distinct, as they are on the wire.
+type Decimal struct {
+	Neg         bool
+	Coefficient uint64
+	Exponent    int
+
+	// Kind marks infinities and NaNs, whose Exponent is ignored.
+	// A NaN's Coefficient holds its diagnostic payload.
+	Kind DecimalKind
+}
+
+// DecimalKind distinguishes finite Decimals from infinities and NaNs
+type DecimalKind uint8
+
+const (
+	DecimalFinite DecimalKind = iota
+	DecimalInf
+	DecimalNaN
+	DecimalSNaN
+)
+
+var (
+	decimalType = reflect.TypeOf(Decimal{})
+	ratType     = reflect.TypeOf(big.Rat{})
+	ratPtrType  = reflect.TypeOf(&big.Rat{})
+)
+
+// decimalFormat describes one of the IEEE 754 decimal interchange formats
+type decimalFormat struct {
+	// Width of the format in bits, and its precision in digits
+	bits      int
+	precision int
+
+	// Bits of exponent continuation, and the exponent's bias
+	expBits int
+	bias    int
+
+	// dpd selects the densely packed decimal encoding of the coefficient over binary integer
+	dpd bool
+}
+
+// decimalFormats are the formats a floatfmt tag may name
+var decimalFormats = map[string]decimalFormat{
+	"decimal32-bid": {bits: 32, precision: 7, expBits: 6, bias: 101},
+	"decimal32-dpd": {bits: 32, precision: 7, expBits: 6, bias: 101, dpd: true},
+	"decimal64-bid": {bits: 64, precision: 16, expBits: 8, bias: 398},
+	"decimal64-dpd": {bits: 64, precision: 16, expBits: 8, bias: 398, dpd: true},
+}
+
+// pow10 holds every power of 10 which fits a uint64
+var pow10 = func() (p [20]uint64) {
+	p[0] = 1
+	for i := 1; i < len(p); i++ {
+		p[i] = p[i-1] * 10
+	}
+	return
+}()
+
+// isDecimal reports whether sf is tagged with a floatfmt
+func isDecimal(sf reflect.StructField) bool {
+	return sf.Tag.Get("floatfmt") != ""
+}
+
+// decimalFormatOf resolves the format named by a field's floatfmt tag,
+// checking the field is of a type which can hold it
+func decimalFormatOf(sf reflect.StructField) (df decimalFormat, err error) {
+	df, ok := decimalFormats[sf.Tag.Get("floatfmt")]
+	if !ok {
+		return df, fmt.Errorf("%w Unknown floatfmt %q", ErrTag, sf.Tag.Get("floatfmt"))
+	}
+	switch sf.Type {
+	case decimalType, ratType, ratPtrType:
+	default:
+		if sf.Type.Kind() != reflect.String {
+			return df, fmt.Errorf("%w floatfmt needs a Decimal, string, or big.Rat; Got %s", ErrUnexpectedType, sf.Type.String())
+		}
+	}
+	return df, nil
+}
+
+// trailing is the width of the trailing significand field
+func (df decimalFormat) trailing() int {
+	return df.bits - 6 - df.expBits
+}
+
+// decode unpacks the decimal encoded in u
+func (df decimalFormat) decode(u uint64) (d Decimal) {
+	t := uint(df.trailing())
+	w := uint(df.expBits)
+
+	d.Neg = u>>(df.bits-1)&1 == 1
+	g := u >> t & (1<<(w+5) - 1)
+
+	// Infinities and NaNs have a combination field starting 1111
+	if g>>(w+1) == 0b1111 {
+		switch {
+		case g>>w&1 == 0:
+			d.Kind = DecimalInf
+			return
+		case g>>(w-1)&1 == 1:
+			d.Kind = DecimalSNaN
+		default:
+			d.Kind = DecimalNaN
+		}
+		d.Coefficient = df.coefficient(u & (1<<t - 1))
+		if d.Coefficient >= pow10[df.precision-1] {
+			// Non-canonical payloads are zero
+			d.Coefficient = 0
+		}
+		return
+	}
+
+	var e uint64
+	switch {
+	case df.dpd:
+		// The combination field holds the top two bits of the exponent and the leading digit
+		msb, lead := g>>(w+3), g>>w&7
+		if msb == 0b11 {
+			msb, lead = g>>(w+1)&3, 8+g>>w&1
+		}
+		e = msb<<w | g&(1<<w-1)
+		d.Coefficient = lead*pow10[df.precision-1] + df.coefficient(u&(1<<t-1))
+
+	case g>>(w+3) != 0b11:
+		e = u >> (t + 3) & (1<<(w+2) - 1)
+		d.Coefficient = u & (1<<(t+3) - 1)
+
+	default:
+		// Large coefficients have an implicit leading 100
+		e = u >> (t + 1) & (1<<(w+2) - 1)
+		d.Coefficient = 0b100<<(t+1) | u&(1<<(t+1)-1)
+	}
+	if d.Coefficient >= pow10[df.precision] {
+		// Non-canonical coefficients are zero
+		d.Coefficient = 0
+	}
+	d.Exponent = int(e) - df.bias
+	return
+}
+
+// coefficient reads a trailing significand field, which is binary or declets as per the format
+func (df decimalFormat) coefficient(bs uint64) (c uint64) {
+	if !df.dpd {
+		return bs
+	}
+	for i := df.trailing() - 10; i >= 0; i -= 10 {
+		c = c*1000 + uint64(dpdDecode(uint16(bs>>i&0x3FF)))
+	}
+	return
+}
+
+// significand writes a trailing significand field of c, which must fit
+func (df decimalFormat) significand(c uint64) (bs uint64) {
+	if !df.dpd {
+		return bs | c
+	}
+	for i := 0; i < df.trailing(); i += 10 {
+		bs |= uint64(dpdEncode[c%1000]) << i
+		c /= 1000
+	}
+	return
+}
+
+// encode packs d in its canonical encoding
+func (df decimalFormat) encode(d Decimal) (u uint64, err error) {
+	t := uint(df.trailing())
+	w := uint(df.expBits)
+
+	if d.Neg {
+		u = 1 << (df.bits - 1)
+	}
+
+	switch d.Kind {
+	case DecimalInf:
+		return u | 0b11110<<(t+w), nil
+
+	case DecimalNaN, DecimalSNaN:
+		if d.Coefficient >= pow10[df.precision-1] {
+			return 0, fmt.Errorf("%w NaN payload %d has more than %d digits", ErrRange, d.Coefficient, df.precision-1)
+		}
+		u |= 0b11111<<(t+w) | df.significand(d.Coefficient)
+		if d.Kind == DecimalSNaN {
+			u |= 1 << (t + w - 1)
+		}
+		return u, nil
+
+	case DecimalFinite:
+
+	default:
+		return 0, fmt.Errorf("%w Unknown DecimalKind %d", ErrRange, d.Kind)
+	}
+
+	c, e, err := df.fit(d.Coefficient, d.Exponent)
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case df.dpd:
+		lead, msb := c/pow10[df.precision-1], e>>w
+		g := msb<<3 | lead
+		if lead >= 8 {
+			g = 0b11000 | msb<<1 | (lead - 8)
+		}
+		u |= g<<(t+w) | (e&(1<<w-1))<<t | df.significand(c%pow10[df.precision-1])
+
+	case c < 1<<(t+3):
+		u |= e<<(t+3) | c
+
+	default:
+		u |= 0b11<<(t+w+3) | e<<(t+1) | c&(1<<(t+1)-1)
+	}
+	return u, nil
+}
+
+// fit brings coefficient c and exponent q within the format, giving c and the biased exponent.
+// Exponents out of range are brought in by adding or removing trailing zeros, which doesn't
+// change the value.
+func (df decimalFormat) fit(c uint64, q int) (uint64, uint64, error) {
+	minQ := -df.bias
+	maxQ := 3<<df.expBits - 1 - df.bias
+
+	if c == 0 {
+		// Zeros just take the nearest exponent
+		if q < minQ {
+			q = minQ
+		} else if q > maxQ {
+			q = maxQ
+		}
+	}
+	for q > maxQ && c < pow10[df.precision-1] {
+		c, q = c*10, q-1
+	}
+	for q < minQ && c%10 == 0 {
+		c, q = c/10, q+1
+	}
+	for c >= pow10[df.precision] && c%10 == 0 && q < maxQ {
+		c, q = c/10, q+1
+	}
+
+	if c >= pow10[df.precision] {
+		return 0, 0, fmt.Errorf("%w %d has more than %d digits", ErrRange, c, df.precision)
+	}
+	if q < minQ || q > maxQ {
+		return 0, 0, fmt.Errorf("%w Exponent %d is outside %d to %d", ErrRange, q, minQ, maxQ)
+	}
+	return c, uint64(q + df.bias), nil
+}
+
+// dpdDecode converts a densely packed decimal declet to its value, 0 to 999
+func dpdDecode(b uint16) uint16 {
+	// Digits are named by the bits of the declet they come from
+	bit := func(n uint) uint16 { return b >> n & 1 }
+	abc, def, ghi := b>>7, b>>4&7, b&7
+	c, f, i := bit(7), bit(4), bit(0)
+
+	var d2, d1, d0 uint16
+	switch {
+	case bit(3) == 0:
+		d2, d1, d0 = abc, def, ghi
+	case b>>1&7 == 0b100:
+		d2, d1, d0 = abc, def, 8+i
+	case b>>1&7 == 0b101:
+		d2, d1, d0 = abc, 8+f, b>>5&3<<1|i
+	case b>>1&7 == 0b110:
+		d2, d1, d0 = 8+c, def, b>>8<<1|i
+	case b>>5&3 == 0b00:
+		d2, d1, d0 = 8+c, 8+f, b>>8<<1|i
+	case b>>5&3 == 0b01:
+		d2, d1, d0 = 8+c, b>>8<<1|f, 8+i
+	case b>>5&3 == 0b10:
+		d2, d1, d0 = abc, 8+f, 8+i
+	default:
+		d2, d1, d0 = 8+c, 8+f, 8+i
+	}
+	return d2*100 + d1*10 + d0
+}
+
+// dpdEncode maps 0 to 999 to their canonical declets.
+// Of the declets sharing a value, the canonical one is the lowest.
+var dpdEncode = func() (enc [1000]uint16) {
+	var seen [1000]bool
+	for b := uint16(0); b < 1024; b++ {
+		if v := dpdDecode(b); !seen[v] {
+			enc[v], seen[v] = b, true
+		}
+	}
+	return
+}()
+
+// readDecimal reads decimal field f
+func (r *reader) readDecimal(sf reflect.StructField, f reflect.Value, o binary.ByteOrder) error {
+	df, err := decimalFormatOf(sf)
+	if err != nil {
+		return err
+	}
+
+	u, err := r.readUint(uintTypes[fmt.Sprintf("uint%d", df.bits)], o)
+	if err != nil {
+		return err
+	}
+	d := df.decode(u)
+
+	// Formats forbidding NaNs and infinities tag their fields finite
+	if d.Kind != DecimalFinite && sf.Tag.Get("finite") == "true" {
+		return fmt.Errorf("%w Read %s where only finite values are allowed", ErrValidation, d.String())
+	}
+
+	switch {
+	case f.Type() == decimalType:
+		f.Set(reflect.ValueOf(d))
+	case f.Kind() == reflect.String:
+		f.SetString(d.String())
+	default:
+		if d.Kind != DecimalFinite {
+			return fmt.Errorf("%w %s can't be held by big.Rat", ErrRange, d.String())
+		}
+		if f.Kind() == reflect.Pointer {
+			f.Set(reflect.ValueOf(d.rat()))
+		} else {
+			f.Set(reflect.ValueOf(*d.rat()))
+		}
+	}
+	return nil
+}
+
+// writeDecimal writes decimal field f in its canonical encoding
+func (w *writer) writeDecimal(sf reflect.StructField, f reflect.Value, o binary.ByteOrder) error {
+	df, err := decimalFormatOf(sf)
+	if err != nil {
+		return err
+	}
+
+	var d Decimal
+	switch {
+	case f.Type() == decimalType:
+		d = f.Interface().(Decimal)
+	case f.Kind() == reflect.String:
+		if d, err = ParseDecimal(f.String()); err != nil {
+			return err
+		}
+	case f.Kind() == reflect.Pointer:
+		if f.IsNil() {
+			return fmt.Errorf("%w Got nil %s", ErrUnexpectedType, f.Type().String())
+		}
+		d, err = ratDecimal(f.Interface().(*big.Rat))
+	default:
+		rat := f.Interface().(big.Rat)
+		d, err = ratDecimal(&rat)
+	}
+	if err != nil {
+		return err
+	}
+
+	u, err := df.encode(d)
+	if err != nil {
+		return err
+	}
+	return w.writeUint(uintTypes[fmt.Sprintf("uint%d", df.bits)], u, o)
+}
+
+// rat is the value of finite d
+func (d Decimal) rat() *big.Rat {
+	r := new(big.Rat).SetInt(new(big.Int).SetUint64(d.Coefficient))
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(d.Exponent))), nil))
+	if d.Exponent < 0 {
+		r.Quo(r, scale)
+	} else {
+		r.Mul(r, scale)
+	}
+	if d.Neg {
+		r.Neg(r)
+	}
+	return r
+}
+
+// ratDecimal finds the Decimal with the fewest digits exactly equal to r
+func ratDecimal(r *big.Rat) (d Decimal, err error) {
+	d.Neg = r.Sign() < 0
+
+	// Only denominators of the form 2^a × 5^b terminate in decimal, needing max(a, b) places
+	num := new(big.Int).Abs(r.Num())
+	den := new(big.Int).Set(r.Denom())
+	ten := big.NewInt(10)
+	for den.Cmp(big.NewInt(1)) != 0 {
+		switch {
+		case den.Bit(0) == 0:
+			den.Rsh(den, 1)
+			num.Mul(num, big.NewInt(5))
+		case new(big.Int).Mod(den, big.NewInt(5)).Sign() == 0:
+			den.Quo(den, big.NewInt(5))
+			num.Lsh(num, 1)
+		default:
+			return d, fmt.Errorf("%w %s has no exact decimal representation", ErrRange, r.String())
+		}
+		d.Exponent--
+	}
+
+	// Then trailing zeros are dropped
+	mod := new(big.Int)
+	for num.Sign() != 0 {
+		if _, mod = new(big.Int).QuoRem(num, ten, mod); mod.Sign() != 0 {
+			break
+		}
+		num.Quo(num, ten)
+		d.Exponent++
+	}
+	if !num.IsUint64() {
+		return d, fmt.Errorf("%w %s has too many digits", ErrRange, r.String())
+	}
+	d.Coefficient = num.Uint64()
+	return
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
+
+// String formats d in scientific notation as the General Decimal Arithmetic specification does:
+// plainly for moderate exponents, as in "7.50", and otherwise with an exponent, as in "1.2E+7".
+// Infinities and NaNs are "Infinity", "NaN", and "sNaN", NaNs followed by any payload.
+func (d Decimal) String() string {
+	sign := ""
+	if d.Neg {
+		sign = "-"
+	}
+
+	switch d.Kind {
+	case DecimalInf:
+		return sign + "Infinity"
+	case DecimalNaN, DecimalSNaN:
+		s := sign + "NaN"
+		if d.Kind == DecimalSNaN {
+			s = sign + "sNaN"
+		}
+		if d.Coefficient != 0 {
+			s += strconv.FormatUint(d.Coefficient, 10)
+		}
+		return s
+	}
+
+	digits := strconv.FormatUint(d.Coefficient, 10)
+	adjusted := d.Exponent + len(digits) - 1
+	switch {
+	case d.Exponent == 0:
+		return sign + digits
+	case d.Exponent < 0 && adjusted >= -6:
+		if point := len(digits) + d.Exponent; point > 0 {
+			return sign + digits[:point] + "." + digits[point:]
+		}
+		return sign + "0." + strings.Repeat("0", -d.Exponent-len(digits)) + digits
+	default:
+		s := sign + digits[:1]
+		if len(digits) > 1 {
+			s += "." + digits[1:]
+		}
+		return s + fmt.Sprintf("E%+d", adjusted)
+	}
+}
+
+// ParseDecimal parses s, as written by Decimal.String, keeping its exponent.
+// Infinities may be given as "Inf" or "Infinity", and any letter case is accepted.
+func ParseDecimal(s string) (d Decimal, err error) {
+	in := s
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		d.Neg = s[0] == '-'
+		s = s[1:]
+	}
+
+	bad := fmt.Errorf("%w %q is not a decimal number", ErrRange, in)
+	switch lower := strings.ToLower(s); {
+	case lower == "inf" || lower == "infinity":
+		d.Kind = DecimalInf
+		return
+	case strings.HasPrefix(lower, "nan") || strings.HasPrefix(lower, "snan"):
+		d.Kind = DecimalNaN
+		if lower[0] == 's' {
+			d.Kind = DecimalSNaN
+		}
+		if payload := strings.TrimLeft(lower, "sna"); payload != "" {
+			if d.Coefficient, err = strconv.ParseUint(payload, 10, 64); err != nil {
+				return d, bad
+			}
+		}
+		return
+	}
+
+	mantissa, exp, hasExp := strings.Cut(strings.ToUpper(s), "E")
+	if hasExp {
+		if d.Exponent, err = strconv.Atoi(exp); err != nil {
+			return d, bad
+		}
+	}
+	whole, frac, _ := strings.Cut(mantissa, ".")
+	digits := strings.TrimLeft(whole+frac, "0")
+	if whole+frac == "" || strings.ContainsAny(whole+frac, "+-") {
+		return d, bad
+	}
+	if digits != "" {
+		if d.Coefficient, err = strconv.ParseUint(digits, 10, 64); errors.Is(err, strconv.ErrRange) {
+			return d, fmt.Errorf("%w %q has too many digits", ErrRange, in)
+		} else if err != nil {
+			return d, bad
+		}
+	}
+	d.Exponent -= len(frac)
+	return
+}
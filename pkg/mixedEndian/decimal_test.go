@@ -0,0 +1,243 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Encodings from the General Decimal Arithmetic specification and its decTest suite
+func TestDecimalEncodings(t *testing.T) {
+	tests := []struct {
+		format string
+		value  string
+		bits   uint64
+	}{
+		{"decimal32-dpd", "-7.50", 0xA23003D0},
+		{"decimal32-dpd", "1", 0x22500001},
+		{"decimal32-dpd", "9.999999E+96", 0x77F3FCFF},
+		{"decimal64-dpd", "-7.50", 0xA2300000000003D0},
+		{"decimal64-dpd", "0", 0x2238000000000000},
+		{"decimal64-dpd", "1", 0x2238000000000001},
+		{"decimal64-dpd", "9.999999999999999E+384", 0x77FCFF3FCFF3FCFF},
+		{"decimal64-dpd", "-Infinity", 0xF800000000000000},
+		{"decimal32-bid", "1", 0x32800001},
+		{"decimal32-bid", "9.999999E+96", 0x77F8967F},
+		{"decimal64-bid", "1", 0x31C0000000000001},
+		{"decimal64-bid", "9.999999999999999E+384", 0x77FB86F26FC0FFFF},
+		{"decimal64-bid", "1E-398", 0x0000000000000001},
+		{"decimal64-bid", "Infinity", 0x7800000000000000},
+		{"decimal64-bid", "NaN", 0x7C00000000000000},
+		{"decimal64-bid", "sNaN", 0x7E00000000000000},
+		{"decimal64-bid", "-NaN12", 0xFC0000000000000C},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format+" "+tt.value, func(t *testing.T) {
+			df := decimalFormats[tt.format]
+			if got := df.decode(tt.bits).String(); got != tt.value {
+				t.Errorf("decode(%X) = %s, wanted %s", tt.bits, got, tt.value)
+			}
+
+			d, err := ParseDecimal(tt.value)
+			if err != nil {
+				t.Fatalf("ParseDecimal(%s) error = %v", tt.value, err)
+			}
+			if got, err := df.encode(d); err != nil || got != tt.bits {
+				t.Errorf("encode(%s) = %X, %v, wanted %X", tt.value, got, err, tt.bits)
+			}
+		})
+	}
+}
+
+func TestDecimalNonCanonical(t *testing.T) {
+	tests := []struct {
+		format    string
+		bits      uint64
+		value     string
+		canonical uint64
+	}{
+		// Declets with unused bits set
+		{"decimal32-dpd", 0x225003FF, "999", 0x225000FF},
+		// Coefficients past 10^16-1 are zero
+		{"decimal64-bid", 0x77FB86F26FC10000, "0E+369", 0x5FE0000000000000},
+		// As are NaN payloads past 10^15-1
+		{"decimal64-bid", 0x7C038D7EA4C68000, "NaN", 0x7C00000000000000},
+		// Infinities ignore everything but their sign
+		{"decimal64-dpd", 0x7A00000000001234, "Infinity", 0x7800000000000000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			df := decimalFormats[tt.format]
+			d := df.decode(tt.bits)
+			if d.String() != tt.value {
+				t.Errorf("decode(%X) = %s, wanted %s", tt.bits, d.String(), tt.value)
+			}
+			if got, err := df.encode(d); err != nil || got != tt.canonical {
+				t.Errorf("encode(%s) = %X, %v, wanted %X", tt.value, got, err, tt.canonical)
+			}
+		})
+	}
+}
+
+func TestDPDDeclets(t *testing.T) {
+	for v := uint16(0); v < 1000; v++ {
+		if got := dpdDecode(dpdEncode[v]); got != v {
+			t.Errorf("dpdDecode(dpdEncode[%d]) = %d", v, got)
+		}
+	}
+}
+
+type DecimalStruct struct {
+	Price  Decimal  `floatfmt:"decimal64-bid"`
+	Text   string   `floatfmt:"decimal32-dpd" endian:"little"`
+	Ratio  big.Rat  `floatfmt:"decimal64-dpd"`
+	Volume *big.Rat `floatfmt:"decimal32-bid"`
+}
+
+func TestDecimalFields(t *testing.T) {
+	data := DecimalStruct{
+		Price:  Decimal{Coefficient: 12345, Exponent: -2},
+		Text:   "-7.50",
+		Ratio:  *big.NewRat(1, 8),
+		Volume: big.NewRat(1000, 1),
+	}
+	wire := []byte{
+		0x31, 0x80, 0x00, 0x00, 0x00, 0x00, 0x30, 0x39,
+		0xD0, 0x03, 0x30, 0xA2,
+		0x22, 0x2C, 0x00, 0x00, 0x00, 0x00, 0x00, 0xA5,
+		// Written with the fewest digits, as 1E+3
+		0x34, 0x00, 0x00, 0x01,
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, data); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+
+	var got any = &DecimalStruct{}
+	if err := Read(bytes.NewReader(wire), BigEndian, &got); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	g := got.(*DecimalStruct)
+	if !reflect.DeepEqual(g.Price, data.Price) || g.Text != data.Text || g.Ratio.Cmp(&data.Ratio) != 0 || g.Volume.Cmp(data.Volume) != 0 {
+		t.Errorf("Read() = %v, wanted %v", g, data)
+	}
+}
+
+func TestDecimalErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    any
+		wantErr error
+	}{
+		{
+			name: "too many digits",
+			data: struct {
+				D string `floatfmt:"decimal32-bid"`
+			}{D: "12345678"},
+			wantErr: ErrRange,
+		},
+		{
+			name: "exponent out of range",
+			data: struct {
+				D Decimal `floatfmt:"decimal32-bid"`
+			}{D: Decimal{Coefficient: 1234567, Exponent: 91}},
+			wantErr: ErrRange,
+		},
+		{
+			name: "no exact decimal",
+			data: struct {
+				D *big.Rat `floatfmt:"decimal64-bid"`
+			}{D: big.NewRat(1, 3)},
+			wantErr: ErrRange,
+		},
+		{
+			name: "unknown format",
+			data: struct {
+				D Decimal `floatfmt:"decimal128-bid"`
+			}{},
+			wantErr: ErrTag,
+		},
+		{
+			name: "wrong type",
+			data: struct {
+				D float64 `floatfmt:"decimal64-bid"`
+			}{},
+			wantErr: ErrUnexpectedType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Write(&bytes.Buffer{}, BigEndian, tt.data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Write() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Rats can't hold infinities
+	var got any = &struct {
+		D big.Rat `floatfmt:"decimal32-bid"`
+	}{}
+	if err := Read(bytes.NewReader([]byte{0x78, 0, 0, 0}), BigEndian, &got); !errors.Is(err, ErrRange) {
+		t.Errorf("Read() error = %v, wantErr %v", err, ErrRange)
+	}
+}
+
+type FiniteStruct struct {
+	A Decimal `floatfmt:"decimal64-bid" finite:"true"`
+	B string  `floatfmt:"decimal32-bid" finite:"true"`
+}
+
+func TestDecimalFinite(t *testing.T) {
+	one64 := []byte{0x31, 0xC0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01}
+	one32 := []byte{0x32, 0x80, 0x00, 0x01}
+	tests := []struct {
+		name      string
+		wire      []byte
+		wantErr   error
+		wantField string
+	}{
+		{name: "finite", wire: append(append([]byte{}, one64...), one32...)},
+		{
+			name:      "NaN",
+			wire:      append([]byte{0x7C, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, one32...),
+			wantErr:   ErrValidation,
+			wantField: "A",
+		},
+		{
+			name:      "infinity",
+			wire:      append(append([]byte{}, one64...), 0x78, 0x00, 0x00, 0x00),
+			wantErr:   ErrValidation,
+			wantField: "B",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data any = &FiniteStruct{}
+			err := Read(bytes.NewReader(tt.wire), BigEndian, &data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Read() error = %v, wanted %v", err, tt.wantErr)
+			}
+			if err == nil && (data.(*FiniteStruct).A.Coefficient != 1 || data.(*FiniteStruct).B != "1") {
+				t.Errorf("Read() = %+v, wanted ones", data)
+			}
+			if err != nil && !strings.HasPrefix(err.Error(), tt.wantField+": ") {
+				t.Errorf("Read() error = %v, wanted it to name %s", err, tt.wantField)
+			}
+		})
+	}
+
+	// Without the tag, NaNs are read
+	var data any = &struct {
+		A Decimal `floatfmt:"decimal64-bid"`
+	}{}
+	if err := Read(bytes.NewReader([]byte{0x7C, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}), BigEndian, &data); err != nil {
+		t.Errorf("Read() error = %v", err)
+	}
+}
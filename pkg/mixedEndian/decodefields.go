@@ -0,0 +1,77 @@
+package mixedEndian
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// DecodeFields reads only the named fields of a struct laid out as layout, from where they sit in
+// ra, without reading anything else but the fields before them in the structs holding them, which
+// their tags may refer to. Nested fields are named by their dotted Path. Fields are read with their
+// tags applied, those without a byte order of their own in defaultEndian.
+//
+// Only fields at a fixed offset and of a fixed size can be found this way; others, such as slices
+// sized when read or fields following them, give ErrLength.
+func DecodeFields(ra io.ReaderAt, defaultEndian binary.ByteOrder, layout *StructLayout, fieldNames ...string) (map[string]any, error) {
+	fields := make(map[string]any, len(fieldNames))
+	for _, name := range fieldNames {
+		parent, fl, off, err := findField(layout, name)
+		if err != nil {
+			return nil, err
+		}
+		if off < 0 || fl.Size < 0 {
+			return nil, fmt.Errorf("%s: %w Not at a fixed offset and size", name, ErrLength)
+		}
+
+		// Earlier siblings are read first, so the lengths, flags, and mirrored fields tags name
+		// hold what they do on the wire. Being before a field at a fixed offset, so are they.
+		v := reflect.New(parent.Type).Elem()
+		base := off - fl.Offset
+		for _, sib := range parent.Fields {
+			o := defaultEndian
+			if sib.Order != nil {
+				o = sib.Order
+			}
+			r := reader{r: io.NewSectionReader(ra, int64(base+sib.Offset), int64(sib.Size)), o: o, ctx: context.Background()}
+			sf, _ := parent.Type.FieldByName(sib.Name)
+			if err = r.readField(v, sf, v.FieldByIndex(sf.Index), o); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			if sib.Name == fl.Name {
+				fields[name] = v.FieldByIndex(sf.Index).Interface()
+				break
+			}
+		}
+	}
+	return fields, nil
+}
+
+// findField finds the field of sl at dotted path, with the layout of the struct holding it and its
+// offset from the start of sl, -1 if not fixed
+func findField(sl *StructLayout, path string) (parent *StructLayout, fl FieldLayout, off int, err error) {
+	name, rest, nested := strings.Cut(path, ".")
+	for _, f := range sl.Fields {
+		if f.Name != name {
+			continue
+		}
+		if !nested {
+			return sl, f, f.Offset, nil
+		}
+
+		// Only struct fields, not arrays of them, have fields at fixed offsets
+		if f.Elem == nil || f.Type.Kind() == reflect.Array || f.Type.Kind() == reflect.Slice {
+			break
+		}
+		if parent, fl, off, err = findField(f.Elem, rest); err == nil && (off < 0 || f.Offset < 0) {
+			off = -1
+		} else if err == nil {
+			off += f.Offset
+		}
+		return
+	}
+	return nil, fl, 0, fmt.Errorf("%w %s has no field %q", ErrLayout, sl.Type.String(), path)
+}
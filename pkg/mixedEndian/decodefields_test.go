@@ -0,0 +1,93 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type VertexRecord struct {
+	ID     uint32
+	Pos    [3]int16
+	Flags  TaggedStruct
+	Scale  uint16 `endian:"big"`
+	Count  uint8
+	Extras []byte `len:"Count"`
+	After  uint8
+}
+
+func TestDecodeFields(t *testing.T) {
+	data := VertexRecord{
+		ID: 0x01020304, Pos: [3]int16{-1, 2, 3}, Flags: TaggedStruct{A: 0x0A0B, B: 0x0C0D},
+		Scale: 0x1122, Count: 2, Extras: []byte{7, 8}, After: 9,
+	}
+	wire, err := Marshal(LittleEndian, data)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	layout, err := Describe(data)
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+
+	got, err := DecodeFields(bytes.NewReader(wire), LittleEndian, layout, "Scale", "Pos", "Flags.B", "ID")
+	if err != nil {
+		t.Fatalf("DecodeFields() error = %v", err)
+	}
+	want := map[string]any{
+		"ID":      uint32(0x01020304),
+		"Pos":     [3]int16{-1, 2, 3},
+		"Flags.B": uint16(0x0C0D),
+		"Scale":   uint16(0x1122),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeFields() = %v, wanted %v", got, want)
+	}
+
+	tests := []struct {
+		name    string
+		field   string
+		wantErr error
+	}{
+		{"slice", "Extras", ErrLength},
+		{"after slice", "After", ErrLength},
+		{"unknown", "Missing", ErrLayout},
+		{"unknown nested", "Flags.C", ErrLayout},
+		{"not a struct", "ID.A", ErrLayout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeFields(bytes.NewReader(wire), LittleEndian, layout, tt.field); !errors.Is(err, tt.wantErr) {
+				t.Errorf("DecodeFields() error = %v, wanted %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecodeFieldsInContext(t *testing.T) {
+	// Copy is checked against Length, so Length has to be read with it
+	data := MirrorStruct{Length: 0x0102, Name: [2]byte{'h', 'i'}, Copy: 0x0102}
+	wire, err := Marshal(BigEndian, data)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	layout, err := Describe(data)
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+
+	got, err := DecodeFields(bytes.NewReader(wire), BigEndian, layout, "Copy")
+	if err != nil {
+		t.Fatalf("DecodeFields() error = %v", err)
+	}
+	if want := map[string]any{"Copy": uint16(0x0102)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeFields() = %v, wanted %v", got, want)
+	}
+
+	// And a mismatch on the wire is caught, as Read would catch it
+	wire[len(wire)-1] ^= 0xFF
+	if _, err = DecodeFields(bytes.NewReader(wire), BigEndian, layout, "Copy"); !errors.Is(err, ErrValidation) {
+		t.Errorf("DecodeFields() error = %v, wanted %v", err, ErrValidation)
+	}
+}
@@ -0,0 +1,255 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Decoder reads values from an io.Reader as Read does.
+//
+// A strict Decoder, made with WithStrict(true), checks the type of each value before reading
+// anything into it, and errors on unexported fields, tags with values it doesn't recognise, and
+// field types outside those Read handles. Parsers can then rely on every field being filled
+// from the wire as declared, and never silently left alone.
+type Decoder struct {
+	dec    reader
+	in     countingReader
+	strict bool
+
+	// seeker is the io.Reader as an io.Seeker, if it is one, for the positions in ReadErrors
+	seeker io.Seeker
+
+	// recordSize and resync recover DecodeAll from bad records
+	recordSize int
+	resync     []byte
+
+	// stage is where DecodeGroup and SkipGroup are within the struct they're reading
+	stage groupStage
+}
+
+// RecordError notes a record DecodeAll skipped. Index counts every record, good or bad,
+// from 0, and Offset is where the record started in the Decoder's input.
+type RecordError struct {
+	Index  int
+	Offset int64
+	Err    error
+}
+
+func (e RecordError) Error() string {
+	return fmt.Sprintf("record %d at offset %d: %v", e.Index, e.Offset, e.Err)
+}
+
+func (e RecordError) Unwrap() error {
+	return e.Err
+}
+
+// NewDecoder returns a Decoder reading from r with default byte order defaultEndian
+func NewDecoder(r io.Reader, defaultEndian binary.ByteOrder, opts ...Option) *Decoder {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	d := &Decoder{
+		in:         countingReader{r: guardProgress(r)},
+		strict:     o.strict,
+		recordSize: o.recordSize,
+		resync:     o.resync,
+	}
+	d.seeker, _ = r.(io.Seeker)
+	d.dec = reader{r: &d.in, o: defaultEndian, ctx: o.context(), alloc: o.alloc, lenient: o.lenient, maxDecompressed: o.maxDecompressed}
+	d.dec.versions.vn = o.negotiator
+	d.dec.overrides = newOrderOverrides(o.overrides)
+	d.dec.presence = newFieldPresence(o.presence)
+	return d
+}
+
+// SetVersion makes the Decoder read fields as protocol version v has them. Fields tagged
+// `added_in:"N"` are skipped, left as their zero values, while v is below N, and those tagged
+// `removed_in:"N"` once v reaches N. A version of 0 reads every field, as before any is set.
+func (d *Decoder) SetVersion(v int) {
+	d.dec.versions.version = v
+}
+
+// Decode reads the next value from the Decoder's io.Reader into data, which must be a pointer.
+// Failing partway through, the error is a *ReadError saying where.
+func (d *Decoder) Decode(data any) error {
+	return d.decode(data, d.dec.o)
+}
+
+// ReadIf reads into data as Decode does, with default byte order defaultEndian, but only if
+// condition holds. Otherwise nothing's consumed and data is left alone.
+func (d *Decoder) ReadIf(condition bool, defaultEndian binary.ByteOrder, data any) error {
+	if !condition {
+		return nil
+	}
+	return d.decode(data, defaultEndian)
+}
+
+// decode reads into data with default byte order o
+func (d *Decoder) decode(data any, o binary.ByteOrder) error {
+	v := reflect.ValueOf(data)
+	if d.strict {
+		if err := checkStrict(v.Type()); err != nil {
+			return err
+		}
+	}
+	if d.dec.overrides != nil && v.IsValid() {
+		if err := d.dec.overrides.check(v.Type()); err != nil {
+			return err
+		}
+	}
+	start := d.in.n
+	err := d.dec.readOrdered(v, o)
+	return readError(d.seeker, start, d.in.n, err)
+}
+
+// DecodeAll reads values until the Decoder's io.Reader is exhausted, appending them to the slice
+// records points to. Ending partway through a value is an error.
+//
+// By default the first value that fails to decode ends DecodeAll with its error. Decoders made
+// with WithRecordRecovery or WithResync instead skip the bad record, note it in the returned
+// RecordErrors, and carry on:
+//
+//   - WithRecordRecovery(n) reads records of exactly n bytes, decoding each on its own, so a bad
+//     record is simply dropped. Bytes a value doesn't use are ignored.
+//   - WithResync(pattern) reads the rest of the input into memory, and on a bad record searches
+//     from just past its start for the next occurrence of pattern, resuming there.
+//
+// WithRecordRecovery takes precedence over WithResync.
+func (d *Decoder) DecodeAll(records any) ([]RecordError, error) {
+	out := reflect.ValueOf(records)
+	if out.Kind() != reflect.Pointer || out.Elem().Kind() != reflect.Slice {
+		return nil, fmt.Errorf("%w Expected pointer to slice; Got %T", ErrUnexpectedType, records)
+	}
+	out = out.Elem()
+	elem := out.Type().Elem()
+	if d.strict {
+		if err := checkStrict(elem); err != nil {
+			return nil, err
+		}
+	}
+	if d.dec.overrides != nil {
+		if err := d.dec.overrides.check(elem); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case d.recordSize > 0:
+		return d.decodeFixed(out, elem)
+	case len(d.resync) > 0:
+		return d.decodeResync(out, elem)
+	}
+
+	for {
+		start := d.in.n
+		v := reflect.New(elem)
+		if err := d.dec.readOrdered(v, d.dec.o); errors.Is(err, io.EOF) && d.in.n == start {
+			return nil, nil
+		} else if errors.Is(err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		} else if err != nil {
+			return nil, readError(d.seeker, start, d.in.n, err)
+		} else if d.in.n == start {
+			return nil, fmt.Errorf("%w %s takes no bytes, so there's no end of them", ErrLength, elem.String())
+		}
+		out.Set(reflect.Append(out, v.Elem()))
+	}
+}
+
+// decodeFixed is DecodeAll over records of d.recordSize bytes
+func (d *Decoder) decodeFixed(out reflect.Value, elem reflect.Type) (bad []RecordError, err error) {
+	buf := make([]byte, d.recordSize)
+	for i := 0; ; i++ {
+		start := d.in.n
+		if _, err = io.ReadFull(&d.in, buf); err == io.EOF {
+			return bad, nil
+		} else if err != nil {
+			return bad, err
+		}
+
+		v := reflect.New(elem)
+		sub := d.dec
+		sub.r = bytes.NewReader(buf)
+		if err = sub.readOrdered(v, sub.o); err != nil {
+			bad = append(bad, RecordError{Index: i, Offset: start, Err: err})
+			continue
+		}
+		out.Set(reflect.Append(out, v.Elem()))
+	}
+}
+
+// decodeResync is DecodeAll, resyncing on d.resync after bad records
+func (d *Decoder) decodeResync(out reflect.Value, elem reflect.Type) (bad []RecordError, err error) {
+	base := d.in.n
+	data, err := io.ReadAll(&d.in)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, pos := 0, 0; pos < len(data); i++ {
+		v := reflect.New(elem)
+		br := bytes.NewReader(data[pos:])
+		sub := d.dec
+		sub.r = br
+		if err = sub.readOrdered(v, sub.o); err == nil {
+			if br.Len() == len(data)-pos {
+				return bad, fmt.Errorf("%w %s takes no bytes, so there's no end of them", ErrLength, elem.String())
+			}
+			out.Set(reflect.Append(out, v.Elem()))
+			pos = len(data) - br.Len()
+			continue
+		}
+
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		bad = append(bad, RecordError{Index: i, Offset: base + int64(pos), Err: err})
+
+		next := bytes.Index(data[pos+1:], d.resync)
+		if next < 0 {
+			break
+		}
+		pos += 1 + next
+	}
+	return bad, nil
+}
+
+// DetectOrder reads a magic number, comparing it with magic, a constant such as uint32(0xA1B2C3D4)
+// for pcap or uint16(42) following a TIFF's byte order mark, encoded in both byte orders. The
+// order it matches becomes the Decoder's default, which it returns, so reading continues in the
+// order the file's written in. Matching neither is ErrValidation, and magics that read the same
+// either way, such as 0x7F7F, are ErrAmbiguousFormat.
+func (d *Decoder) DetectOrder(magic any) (binary.ByteOrder, error) {
+	big, err := Marshal(BigEndian, magic)
+	if err != nil {
+		return nil, err
+	}
+	little, err := Marshal(LittleEndian, magic)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(big, little) {
+		return nil, fmt.Errorf("%w Magic % X is the same in either byte order", ErrAmbiguousFormat, big)
+	}
+
+	start := d.in.n
+	got, err := d.dec.next(len(big))
+	if err != nil {
+		return nil, readError(d.seeker, start, d.in.n, err)
+	}
+	switch {
+	case bytes.Equal(got, big):
+		d.dec.o = BigEndian
+	case bytes.Equal(got, little):
+		d.dec.o = LittleEndian
+	default:
+		return nil, fmt.Errorf("%w Got magic % X, expected % X or % X", ErrValidation, got, big, little)
+	}
+	return d.dec.o, nil
+}
@@ -0,0 +1,275 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestDecoder(t *testing.T) {
+	d := NewDecoder(bytes.NewReader([]byte{0x01, 0x02, 0x03, 0x04, 0x06, 0x05, 0x08, 0x07, 0x09, 0x0A}), BigEndian)
+
+	got := &NestedStruct{}
+	if err := d.Decode(got); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	want := &NestedStruct{A: 0x0102, B: TaggedStruct{A: 0x0304, B: 0x0506}, C: 0x0708}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Decode() = %v, wanted %v", got, want)
+	}
+
+	var u uint16
+	if err := d.Decode(&u); err != nil || u != 0x090A {
+		t.Errorf("Decode() = %X, %v, wanted 90A", u, err)
+	}
+}
+
+func TestDecoderReadIf(t *testing.T) {
+	const hasExtension = 0x01
+	d := NewDecoder(bytes.NewReader([]byte{0x01, 0x02, 0x01, 0x00}), BigEndian)
+
+	var got []uint16
+	for i := 0; i < 2; i++ {
+		var flags uint8
+		if err := d.Decode(&flags); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		ext := uint16(0xFFFF)
+		if err := d.ReadIf(flags&hasExtension != 0, LittleEndian, &ext); err != nil {
+			t.Fatalf("ReadIf() error = %v", err)
+		}
+		got = append(got, ext)
+	}
+	if want := []uint16{0x0102, 0xFFFF}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadIf() = %X, wanted %X", got, want)
+	}
+
+	var u uint8
+	if err := d.ReadIf(true, BigEndian, &u); !errors.Is(err, io.EOF) {
+		t.Errorf("ReadIf() error = %v, wanted %v", err, io.EOF)
+	}
+}
+
+func TestStrictDecoder(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    any
+		wantErr error
+	}{
+		{
+			name: "fully specified",
+			data: &struct {
+				_ struct{} `encoding:"packed"`
+				N uint8
+				A []uint16 `len:"N" endian:"little"`
+				S string   `size:"2" trim:"space"`
+			}{},
+		},
+		{
+			name: "unexported",
+			data: &struct {
+				A uint8
+				b uint8
+			}{},
+			wantErr: ErrUnexpectedType,
+		},
+		{
+			name: "unknown endian",
+			data: &struct {
+				A uint16 `endian:"middle"`
+			}{},
+			wantErr: ErrTag,
+		},
+		{
+			name: "len names no field",
+			data: &struct {
+				A []uint8 `len:"N"`
+			}{},
+			wantErr: ErrTag,
+		},
+		{
+			name: "bad enum",
+			data: &struct {
+				A uint8 `enum:"1,x"`
+			}{},
+			wantErr: ErrTag,
+		},
+		{
+			name: "unsupported type",
+			data: &struct {
+				A uint8
+				B struct{ F float32 }
+			}{},
+			wantErr: ErrUnexpectedType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := bytes.NewReader([]byte{1, 0xAA, 0xBB, 'h', ' '})
+			err := NewDecoder(in, BigEndian, WithStrict(true)).Decode(tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Decode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			// Nothing is read from rejected types
+			if err != nil && in.Len() != 5 {
+				t.Errorf("Decode() read %d bytes before failing", 5-in.Len())
+			}
+		})
+	}
+
+	// Without strict, unexported fields are skipped
+	lax := &struct {
+		A uint8
+		b uint8
+	}{}
+	if err := NewDecoder(bytes.NewReader([]byte{1}), BigEndian).Decode(lax); err != nil {
+		t.Errorf("Decode() error = %v", err)
+	}
+}
+
+type RecordStruct struct {
+	Sync  [2]byte `const:"0xAA,0x55"`
+	Kind  uint8   `enum:"1,2"`
+	Value uint16
+}
+
+// records encodes a RecordStruct for each value, with kinds of 0 where bad
+func records(values []uint16, bad map[int]bool) []byte {
+	var bs []byte
+	for i, v := range values {
+		kind := byte(1)
+		if bad[i] {
+			kind = 0
+		}
+		bs = append(bs, 0xAA, 0x55, kind, byte(v>>8), byte(v))
+	}
+	return bs
+}
+
+func TestDecodeAll(t *testing.T) {
+	d := NewDecoder(bytes.NewReader(records([]uint16{1, 2, 3}, nil)), BigEndian)
+	var got []RecordStruct
+	if bad, err := d.DecodeAll(&got); err != nil || bad != nil {
+		t.Fatalf("DecodeAll() = %v, %v", bad, err)
+	}
+	if len(got) != 3 || got[2].Value != 3 {
+		t.Errorf("DecodeAll() = %v, wanted 3 records", got)
+	}
+
+	wire := records([]uint16{1, 2, 3}, map[int]bool{1: true})
+	d = NewDecoder(bytes.NewReader(wire), BigEndian)
+	got = nil
+	if _, err := d.DecodeAll(&got); !errors.Is(err, ErrValidation) || len(got) != 1 {
+		t.Errorf("DecodeAll() = %v, %v, wanted 1 record and %v", got, err, ErrValidation)
+	}
+
+	d = NewDecoder(bytes.NewReader(wire[:12]), BigEndian, WithRecordRecovery(5))
+	got = nil
+	if _, err := d.DecodeAll(&got); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("DecodeAll() error = %v, wanted %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestDecodeAllRecovery(t *testing.T) {
+	values := []uint16{10, 11, 12, 13, 14, 15}
+	wire := records(values, map[int]bool{1: true, 4: true})
+
+	// A corrupt sync in record 2 hides it from resyncing
+	resyncWire := append([]byte(nil), wire...)
+	resyncWire[11] = 0x00
+
+	tests := []struct {
+		name    string
+		wire    []byte
+		opt     Option
+		want    []uint16
+		wantBad []RecordError
+	}{
+		{
+			name:    "fixed size",
+			wire:    wire,
+			opt:     WithRecordRecovery(5),
+			want:    []uint16{10, 12, 13, 15},
+			wantBad: []RecordError{{Index: 1, Offset: 5}, {Index: 4, Offset: 20}},
+		},
+		{
+			name:    "resync",
+			wire:    resyncWire,
+			opt:     WithResync([]byte{0xAA, 0x55}),
+			want:    []uint16{10, 13, 15},
+			wantBad: []RecordError{{Index: 1, Offset: 5}, {Index: 3, Offset: 20}},
+		},
+		{
+			name:    "resync truncated",
+			wire:    wire[:len(wire)-2],
+			opt:     WithResync([]byte{0xAA, 0x55}),
+			want:    []uint16{10, 12, 13},
+			wantBad: []RecordError{{Index: 1, Offset: 5}, {Index: 4, Offset: 20}, {Index: 5, Offset: 25}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []RecordStruct
+			bad, err := NewDecoder(bytes.NewReader(tt.wire), BigEndian, tt.opt).DecodeAll(&got)
+			if err != nil {
+				t.Fatalf("DecodeAll() error = %v", err)
+			}
+
+			var values []uint16
+			for _, r := range got {
+				values = append(values, r.Value)
+			}
+			if !reflect.DeepEqual(values, tt.want) {
+				t.Errorf("DecodeAll() = %v, wanted %v", values, tt.want)
+			}
+
+			if len(bad) != len(tt.wantBad) {
+				t.Fatalf("DecodeAll() reported %v, wanted %v", bad, tt.wantBad)
+			}
+			for i, b := range bad {
+				if b.Index != tt.wantBad[i].Index || b.Offset != tt.wantBad[i].Offset || b.Err == nil {
+					t.Errorf("DecodeAll() reported %v, wanted record %d at %d", b, tt.wantBad[i].Index, tt.wantBad[i].Offset)
+				}
+			}
+		})
+	}
+}
+
+// recordingAllocator notes each slice and map it's asked to make
+type recordingAllocator struct {
+	made []string
+}
+
+func (a *recordingAllocator) MakeSlice(t reflect.Type, n int) reflect.Value {
+	a.made = append(a.made, fmt.Sprintf("%s[%d]", t, n))
+	return reflect.MakeSlice(t, n, n)
+}
+
+func (a *recordingAllocator) MakeMap(t reflect.Type, n int) reflect.Value {
+	a.made = append(a.made, fmt.Sprintf("%s[%d]", t, n))
+	return reflect.MakeMapWithSize(t, n)
+}
+
+func TestDecoderAllocator(t *testing.T) {
+	a := &recordingAllocator{}
+	d := NewDecoder(bytes.NewReader([]byte{0x02, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06}), BigEndian, WithAllocator(a))
+
+	got := &struct {
+		N    uint8
+		Data []uint16        `len:"N"`
+		Tags map[uint8]uint8 `count:"1"`
+	}{}
+	if err := d.Decode(got); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if !reflect.DeepEqual(got.Data, []uint16{0x0102, 0x0304}) || !reflect.DeepEqual(got.Tags, map[uint8]uint8{5: 6}) {
+		t.Errorf("Decode() = %v", got)
+	}
+	if want := []string{"[]uint16[2]", "map[uint8]uint8[1]"}; !reflect.DeepEqual(a.made, want) {
+		t.Errorf("Allocator made %v, wanted %v", a.made, want)
+	}
+}
@@ -0,0 +1,81 @@
+package mixedEndian
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// deltaDecode replaces each element of integer array or slice f, as read, with the running sum
+// of the elements up to it
+func deltaDecode(f reflect.Value) error {
+	if err := checkDelta(f); err != nil {
+		return err
+	}
+
+	for i := 1; i < f.Len(); i++ {
+		prev, cur := f.Index(i-1), f.Index(i)
+		switch cur.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			sum := prev.Int() + cur.Int()
+			if (cur.Int() > 0 && sum < prev.Int()) || (cur.Int() < 0 && sum > prev.Int()) || cur.OverflowInt(sum) {
+				return fmt.Errorf("%w element %d overflows %s", ErrRange, i, cur.Type().String())
+			}
+			cur.SetInt(sum)
+		default:
+			sum := prev.Uint() + cur.Uint()
+			if sum < prev.Uint() || cur.OverflowUint(sum) {
+				return fmt.Errorf("%w element %d overflows %s", ErrRange, i, cur.Type().String())
+			}
+			cur.SetUint(sum)
+		}
+	}
+	return nil
+}
+
+// deltaEncode returns a copy of integer array or slice f holding its first element followed by
+// the difference of each element from the one before
+func deltaEncode(f reflect.Value) (reflect.Value, error) {
+	if err := checkDelta(f); err != nil {
+		return f, err
+	}
+
+	dst := blankCopy(f)
+	for i := 0; i < f.Len(); i++ {
+		cur := f.Index(i)
+		switch cur.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			d := cur.Int()
+			if i > 0 {
+				prev := f.Index(i - 1).Int()
+				d -= prev
+				if (prev < 0 && d < cur.Int()) || (prev > 0 && d > cur.Int()) || cur.OverflowInt(d) {
+					return f, fmt.Errorf("%w difference at element %d overflows %s", ErrRange, i, cur.Type().String())
+				}
+			}
+			dst.Index(i).SetInt(d)
+		default:
+			d := cur.Uint()
+			if i > 0 {
+				prev := f.Index(i - 1).Uint()
+				if d < prev {
+					return f, fmt.Errorf("%w element %d decreases, which %s can't hold as a difference", ErrRange, i, cur.Type().String())
+				}
+				d -= prev
+			}
+			dst.Index(i).SetUint(d)
+		}
+	}
+	return dst, nil
+}
+
+// checkDelta errors unless f is an array or slice of integers
+func checkDelta(f reflect.Value) error {
+	if f.Kind() == reflect.Array || f.Kind() == reflect.Slice {
+		switch f.Type().Elem().Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return nil
+		}
+	}
+	return fmt.Errorf("%w delta requires integer arrays or slices; Got %s", ErrUnexpectedType, f.Type().String())
+}
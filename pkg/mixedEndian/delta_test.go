@@ -0,0 +1,109 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type DeltaStruct struct {
+	N      uint8
+	Fixed  []int32   `len:"N" delta:"true"`
+	Varint []int32   `len:"N" delta:"true" encoding:"varint"`
+	Times  [3]uint16 `delta:"true" encoding:"uvarint"`
+}
+
+func TestDeltaRoundTrip(t *testing.T) {
+	data := DeltaStruct{
+		N:      5,
+		Fixed:  []int32{100, 90, 95, -5, 2000},
+		Varint: []int32{-64, 63, 63, 0, -2147483648},
+		Times:  [3]uint16{1000, 1001, 65535},
+	}
+	wire := []byte{
+		5,
+		// 100, -10, 5, -100, 2005
+		0x00, 0x00, 0x00, 0x64, 0xFF, 0xFF, 0xFF, 0xF6, 0x00, 0x00, 0x00, 0x05, 0xFF, 0xFF, 0xFF, 0x9C, 0x00, 0x00, 0x07, 0xD5,
+		// Zigzag varints of -64, 127, 0, -63, -2147483648
+		0x7F, 0xFE, 0x01, 0x00, 0x7D, 0xFF, 0xFF, 0xFF, 0xFF, 0x0F,
+		// Uvarints of 1000, 1, 64534
+		0xE8, 0x07, 0x01, 0x96, 0xF8, 0x03,
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, data); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+
+	var got any = &DeltaStruct{}
+	if err := Read(bytes.NewReader(wire), BigEndian, &got); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, &data) {
+		t.Errorf("Read() = %v, wanted %v", got, data)
+	}
+}
+
+func TestDeltaWriteErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    any
+		wantErr error
+	}{
+		{
+			name: "signed difference overflows",
+			data: struct {
+				A [2]int8 `delta:"true"`
+			}{A: [2]int8{-100, 100}},
+			wantErr: ErrRange,
+		},
+		{
+			name: "unsigned decrease",
+			data: struct {
+				A [3]uint16 `delta:"true"`
+			}{A: [3]uint16{1, 5, 4}},
+			wantErr: ErrRange,
+		},
+		{
+			name: "not integers",
+			data: struct {
+				A [2]bool `delta:"true"`
+			}{},
+			wantErr: ErrUnexpectedType,
+		},
+		{
+			name: "varint on unsigned",
+			data: struct {
+				A uint32 `encoding:"varint"`
+			}{},
+			wantErr: ErrUnexpectedType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Write(&bytes.Buffer{}, BigEndian, tt.data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Write() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDeltaReadOverflow(t *testing.T) {
+	var got any = &struct {
+		A [2]int8 `delta:"true"`
+	}{}
+	if err := Read(bytes.NewReader([]byte{100, 100}), BigEndian, &got); !errors.Is(err, ErrRange) {
+		t.Errorf("Read() error = %v, wantErr %v", err, ErrRange)
+	}
+
+	got = &struct {
+		A uint8 `encoding:"uvarint"`
+	}{}
+	if err := Read(bytes.NewReader([]byte{0x80, 0x02}), BigEndian, &got); !errors.Is(err, ErrRange) {
+		t.Errorf("Read() error = %v, wantErr %v", err, ErrRange)
+	}
+}
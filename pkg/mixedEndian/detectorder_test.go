@@ -0,0 +1,71 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+)
+
+// PcapHeader is the global header of a pcap file, following its magic
+type PcapHeader struct {
+	Major, Minor uint16
+	Zone         int32
+	SigFigs      uint32
+	SnapLen      uint32
+	LinkType     uint32
+}
+
+func TestDetectOrder(t *testing.T) {
+	want := PcapHeader{Major: 2, Minor: 4, SnapLen: 65535, LinkType: 1}
+	for name, o := range map[string]binary.ByteOrder{"little": LittleEndian, "big": BigEndian} {
+		t.Run(name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			if err := Write(buf, o, uint32(0xA1B2C3D4)); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if err := Write(buf, o, want); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+
+			// The Decoder starts out big endian, switching should the magic say otherwise
+			dec := NewDecoder(buf, BigEndian)
+			got, err := dec.DetectOrder(uint32(0xA1B2C3D4))
+			if err != nil {
+				t.Fatalf("DetectOrder() error = %v", err)
+			}
+			if got != o {
+				t.Errorf("DetectOrder() = %v, wanted %v", got, o)
+			}
+			var header PcapHeader
+			if err = dec.Decode(&header); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if header != want {
+				t.Errorf("Decode() = %+v, wanted %+v", header, want)
+			}
+		})
+	}
+}
+
+func TestDetectOrderErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		wire  []byte
+		magic any
+		want  error
+	}{
+		{"neither", []byte{0xA1, 0xB2, 0xC3, 0xD5}, uint32(0xA1B2C3D4), ErrValidation},
+		{"palindrome", []byte{0x7F, 0x7F}, uint16(0x7F7F), ErrAmbiguousFormat},
+		{"short", []byte{0xD4, 0xC3}, uint32(0xA1B2C3D4), io.ErrUnexpectedEOF},
+		{"not fixed", []byte{0x00}, "magic", ErrUnexpectedType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewDecoder(bytes.NewReader(tt.wire), BigEndian).DetectOrder(tt.magic); !errors.Is(err, tt.want) {
+				t.Errorf("DetectOrder() error = %v, wanted %v", err, tt.want)
+			}
+		})
+	}
+}
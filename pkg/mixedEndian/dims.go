@@ -0,0 +1,76 @@
+package mixedEndian
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// dimsMax caps the bytes a dims tagged slice may be allocated, counting the slice headers of
+// every level along with the elements of the innermost
+const dimsMax = 1 << 28
+
+// dimsOf resolves the lengths a dims tag on nested slice field sf of struct v gives each level,
+// checking there's one for each level of slice
+func dimsOf(v reflect.Value, sf reflect.StructField) ([]int, error) {
+	refs := strings.Split(sf.Tag.Get("dims"), ",")
+
+	depth := 0
+	for t := sf.Type; t.Kind() == reflect.Slice; t = t.Elem() {
+		depth++
+	}
+	if depth != len(refs) {
+		return nil, fmt.Errorf("%w dims gives %d lengths for %d levels of slice", ErrTag, len(refs), depth)
+	}
+
+	dims := make([]int, len(refs))
+	total, alloc := 1, 0
+	t := sf.Type
+	for i, ref := range refs {
+		n, err := lengthOf(v, strings.TrimSpace(ref))
+		if err != nil {
+			return nil, err
+		}
+		dims[i] = n
+
+		// Every level is allocated before those within it, so each adds its running product of
+		// elements, slice headers but for the innermost, checked before it can overflow. An
+		// empty level further in doesn't excuse the slices holding it.
+		t = t.Elem()
+		size := int(t.Size())
+		if n != 0 && total > dimsMax/n {
+			return nil, fmt.Errorf("%w %s dims exceed %d elements", ErrLength, sf.Tag.Get("dims"), dimsMax)
+		}
+		total *= n
+		if size != 0 && total > (dimsMax-alloc)/size {
+			return nil, fmt.Errorf("%w %s dims need over %d bytes", ErrLength, sf.Tag.Get("dims"), dimsMax)
+		}
+		alloc += total * size
+	}
+	return dims, nil
+}
+
+// allocDims makes f, and every slice nested within it, the lengths given by dims
+func (r *reader) allocDims(f reflect.Value, dims []int) {
+	f.Set(r.makeSlice(f.Type(), dims[0]))
+	if len(dims) > 1 {
+		for i := 0; i < f.Len(); i++ {
+			r.allocDims(f.Index(i), dims[1:])
+		}
+	}
+}
+
+// checkDims errors unless f, and every slice nested within it, have the lengths given by dims
+func checkDims(f reflect.Value, dims []int, path string) error {
+	if f.Len() != dims[0] {
+		return fmt.Errorf("%w %s has %d elements, expected %d", ErrLength, path, f.Len(), dims[0])
+	}
+	if len(dims) > 1 {
+		for i := 0; i < f.Len(); i++ {
+			if err := checkDims(f.Index(i), dims[1:], fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
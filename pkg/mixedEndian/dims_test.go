@@ -0,0 +1,100 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type ImageStruct struct {
+	Height uint8
+	Width  uint16
+	Pixels [][]uint16 `dims:"Height,Width" endian:"little"`
+}
+
+func TestDims(t *testing.T) {
+	tests := []struct {
+		name string
+		data ImageStruct
+		wire []byte
+	}{
+		{
+			name: "2x3",
+			data: ImageStruct{Height: 2, Width: 3, Pixels: [][]uint16{{1, 2, 3}, {4, 5, 6}}},
+			wire: []byte{0x02, 0x00, 0x03, 0x01, 0x00, 0x02, 0x00, 0x03, 0x00, 0x04, 0x00, 0x05, 0x00, 0x06, 0x00},
+		},
+		{
+			name: "0xN",
+			data: ImageStruct{Height: 0, Width: 3, Pixels: [][]uint16{}},
+			wire: []byte{0x00, 0x00, 0x03},
+		},
+		{
+			name: "Nx0",
+			data: ImageStruct{Height: 2, Width: 0, Pixels: [][]uint16{{}, {}}},
+			wire: []byte{0x02, 0x00, 0x00},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			if err := Write(buf, BigEndian, tt.data); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if !bytes.Equal(buf.Bytes(), tt.wire) {
+				t.Errorf("Write() = % X, wanted % X", buf.Bytes(), tt.wire)
+			}
+
+			var got any = &ImageStruct{}
+			if err := Read(bytes.NewReader(tt.wire), BigEndian, &got); err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, &tt.data) {
+				t.Errorf("Read() = %v, wanted %v", got, tt.data)
+			}
+		})
+	}
+}
+
+func TestDimsErrors(t *testing.T) {
+	// Every row must have the declared width
+	ragged := ImageStruct{Height: 2, Width: 2, Pixels: [][]uint16{{1, 2}, {3}}}
+	if err := Write(&bytes.Buffer{}, BigEndian, ragged); !errors.Is(err, ErrLength) {
+		t.Errorf("Write() error = %v, wanted %v", err, ErrLength)
+	}
+
+	// Neither dimension is too large alone, but their product is
+	var huge any = &struct {
+		H, W  uint32
+		Cells [][]uint8 `dims:"H,W"`
+	}{}
+	if err := Read(bytes.NewReader([]byte{0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00}), BigEndian, &huge); !errors.Is(err, ErrLength) {
+		t.Errorf("Read() error = %v, wanted %v", err, ErrLength)
+	}
+
+	// An empty inner dimension doesn't excuse the rows holding it
+	huge = &struct {
+		H, W  uint32
+		Cells [][]uint8 `dims:"H,W"`
+	}{}
+	if err := Read(bytes.NewReader([]byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}), BigEndian, &huge); !errors.Is(err, ErrLength) {
+		t.Errorf("Read() zero width error = %v, wanted %v", err, ErrLength)
+	}
+
+	// Nor are the slice headers of outer levels free, small as the elements within them may be
+	huge = &struct {
+		H, W  uint32
+		Cells [][]uint8 `dims:"H,W"`
+	}{}
+	if err := Read(bytes.NewReader([]byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01}), BigEndian, &huge); !errors.Is(err, ErrLength) {
+		t.Errorf("Read() headers error = %v, wanted %v", err, ErrLength)
+	}
+
+	var shallow any = &struct {
+		H     uint8
+		Cells []uint8 `dims:"H,H"`
+	}{}
+	if err := Read(bytes.NewReader([]byte{0x01, 0x00}), BigEndian, &shallow); !errors.Is(err, ErrTag) {
+		t.Errorf("Read() error = %v, wanted %v", err, ErrTag)
+	}
+}
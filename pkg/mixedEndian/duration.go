@@ -0,0 +1,108 @@
+package mixedEndian
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// durationUnits are the units a dur tag may name
+var durationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+}
+
+// durationWidths are the integer types a duration's width tag may name
+var durationWidths = map[string]reflect.Type{
+	"int8":   reflect.TypeOf(int8(0)),
+	"int16":  reflect.TypeOf(int16(0)),
+	"int32":  reflect.TypeOf(int32(0)),
+	"int64":  reflect.TypeOf(int64(0)),
+	"uint8":  reflect.TypeOf(uint8(0)),
+	"uint16": reflect.TypeOf(uint16(0)),
+	"uint32": reflect.TypeOf(uint32(0)),
+	"uint64": reflect.TypeOf(uint64(0)),
+}
+
+// isDuration reports whether sf is a time.Duration stored in the unit of its dur tag
+func isDuration(sf reflect.StructField) bool {
+	return sf.Type == durationType && sf.Tag.Get("dur") != ""
+}
+
+// durationFormat resolves the unit and wire type of a duration field.
+// Durations are stored as int64 counts of their unit unless given a width tag.
+func durationFormat(sf reflect.StructField) (unit time.Duration, t reflect.Type, err error) {
+	unit, ok := durationUnits[sf.Tag.Get("dur")]
+	if !ok {
+		return 0, nil, fmt.Errorf("%w Unknown duration unit %q", ErrTag, sf.Tag.Get("dur"))
+	}
+
+	w := sf.Tag.Get("width")
+	if w == "" {
+		w = "int64"
+	}
+	if t, ok = durationWidths[w]; !ok {
+		return 0, nil, fmt.Errorf("%w %q is not an integer type", ErrTag, w)
+	}
+	return unit, t, nil
+}
+
+// readDuration reads duration f as a count of its unit
+func (r *reader) readDuration(sf reflect.StructField, f reflect.Value, o binary.ByteOrder) error {
+	unit, t, err := durationFormat(sf)
+	if err != nil {
+		return err
+	}
+
+	v := reflect.New(t).Elem()
+	if err = r.readOrdered(v, o); err != nil {
+		return err
+	}
+
+	var n int64
+	switch t.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = v.Int()
+	default:
+		if v.Uint() > 1<<63-1 {
+			return fmt.Errorf("%w %d%s does not fit in time.Duration", ErrRange, v.Uint(), sf.Tag.Get("dur"))
+		}
+		n = int64(v.Uint())
+	}
+
+	d := time.Duration(n) * unit
+	if d/unit != time.Duration(n) {
+		return fmt.Errorf("%w %d%s does not fit in time.Duration", ErrRange, n, sf.Tag.Get("dur"))
+	}
+	f.SetInt(int64(d))
+	return nil
+}
+
+// writeDuration writes duration f as a count of its unit, truncated toward zero
+func (w *writer) writeDuration(sf reflect.StructField, f reflect.Value, o binary.ByteOrder) error {
+	unit, t, err := durationFormat(sf)
+	if err != nil {
+		return err
+	}
+
+	n := f.Int() / int64(unit)
+	v := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.OverflowInt(n) {
+			return fmt.Errorf("%w %d%s does not fit in %s", ErrRange, n, sf.Tag.Get("dur"), t.String())
+		}
+		v.SetInt(n)
+	default:
+		if n < 0 || v.OverflowUint(uint64(n)) {
+			return fmt.Errorf("%w %d%s does not fit in %s", ErrRange, n, sf.Tag.Get("dur"), t.String())
+		}
+		v.SetUint(uint64(n))
+	}
+	return w.writeOrdered(v, o)
+}
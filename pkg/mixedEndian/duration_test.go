@@ -0,0 +1,87 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type DurationStruct struct {
+	Timeout time.Duration `dur:"ms" width:"uint32"`
+	Elapsed time.Duration `dur:"ns" width:"uint64" endian:"little"`
+	Raw     time.Duration
+}
+
+func TestDurationRoundTrip(t *testing.T) {
+	data := DurationStruct{
+		Timeout: 90 * time.Second,
+		Elapsed: 1500 * time.Microsecond,
+		Raw:     time.Nanosecond,
+	}
+	wire := []byte{
+		0x00, 0x01, 0x5F, 0x90,
+		0x60, 0xE3, 0x16, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01,
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, data); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+
+	var got any = &DurationStruct{}
+	if err := Read(bytes.NewReader(wire), BigEndian, &got); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, &data) {
+		t.Errorf("Read() = %v, wanted %v", got, data)
+	}
+}
+
+func TestDurationErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    any
+		wantErr error
+	}{
+		{
+			name:    "negative into unsigned",
+			data:    DurationStruct{Timeout: -time.Second},
+			wantErr: ErrRange,
+		},
+		{
+			name: "too wide",
+			data: struct {
+				D time.Duration `dur:"s" width:"uint8"`
+			}{D: time.Hour},
+			wantErr: ErrRange,
+		},
+		{
+			name: "unknown unit",
+			data: struct {
+				D time.Duration `dur:"fortnight"`
+			}{},
+			wantErr: ErrTag,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Write(&bytes.Buffer{}, BigEndian, tt.data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Write() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Counts too large for a Duration fail to read
+	var got any = &struct {
+		D time.Duration `dur:"s" width:"uint64"`
+	}{}
+	if err := Read(bytes.NewReader([]byte{0x00, 0x00, 0x00, 0x10, 0x00, 0x00, 0x00, 0x00}), BigEndian, &got); !errors.Is(err, ErrRange) {
+		t.Errorf("Read() error = %v, wantErr %v", err, ErrRange)
+	}
+}
@@ -0,0 +1,142 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Marshal returns the encoding of data, as Write would write it
+func Marshal(defaultEndian binary.ByteOrder, data any) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := Write(buf, defaultEndian, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Encoder writes values to an io.Writer as Write does.
+// Each value is encoded into an internal buffer, reused between calls, and reaches the
+// io.Writer in a single Write.
+type Encoder struct {
+	w   io.Writer
+	buf bytes.Buffer
+	enc writer
+
+	// limit guards buf when maxOutput is set, written counting what's reached w
+	limit     limitWriter
+	maxOutput int64
+	written   int64
+
+	// skip is the bytes of the next value to leave out, as set by ResumeFrom
+	skip int64
+}
+
+// NewEncoder returns an Encoder writing to w with default byte order defaultEndian
+func NewEncoder(w io.Writer, defaultEndian binary.ByteOrder, opts ...Option) *Encoder {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	e := &Encoder{w: w, maxOutput: o.maxOutput}
+	e.buf.Grow(o.bufferSize)
+	e.enc = writer{w: &e.buf, o: defaultEndian, ctx: o.context(), canonical: o.canonical}
+	e.enc.versions.vn = o.negotiator
+	e.enc.overrides = newOrderOverrides(o.overrides)
+	if e.maxOutput > 0 {
+		e.limit.w = &e.buf
+		e.enc.w = &e.limit
+		e.enc.limit = &e.limit
+	}
+	return e
+}
+
+// Encode writes data to the Encoder's io.Writer.
+// Nothing is written if data fails to encode.
+func (e *Encoder) Encode(data any) error {
+	return e.encode(data, e.enc.o)
+}
+
+// WriteIf writes data as Encode does, with default byte order defaultEndian, but only if
+// condition holds. It's for optional protocol fields:
+//
+//	err := enc.WriteIf(flags&HasExtension != 0, BigEndian, &extension)
+func (e *Encoder) WriteIf(condition bool, defaultEndian binary.ByteOrder, data any) error {
+	if !condition {
+		return nil
+	}
+	return e.encode(data, defaultEndian)
+}
+
+// encode writes data with default byte order o
+func (e *Encoder) encode(data any, o binary.ByteOrder) (err error) {
+	skip := e.skip
+	e.skip = 0
+
+	v := reflect.ValueOf(data)
+	if skip > 0 && !e.enc.canonical && unordered(v) {
+		return fmt.Errorf("%w Resuming a value holding maps needs WithCanonical", ErrCanonical)
+	}
+	if e.enc.overrides != nil && v.IsValid() {
+		if err = e.enc.overrides.check(v.Type()); err != nil {
+			return
+		}
+	}
+	e.buf.Reset()
+	e.limit.n = e.maxOutput - e.written + skip
+	if err = e.enc.writeOrdered(v, o); err != nil {
+		return
+	}
+	bs := e.buf.Bytes()
+	if skip > int64(len(bs)) {
+		return fmt.Errorf("%w Resuming from byte %d of a %d byte value", ErrLength, skip, len(bs))
+	}
+
+	n, err := writeFull(e.w, bs[skip:])
+	e.written += int64(n)
+	if err != nil {
+		return &WriteError{Written: skip + int64(n), Path: e.enc.pathAt(v, o, skip+int64(n)), Err: err}
+	}
+	return
+}
+
+// SetVersion makes the Encoder write fields as protocol version v has them, leaving out those
+// Decoder.SetVersion would skip
+func (e *Encoder) SetVersion(v int) {
+	e.enc.versions.version = v
+}
+
+// Reset points the Encoder at w, keeping its buffer, byte order, and options.
+// Any WithMaxOutput cap starts afresh, and any ResumeFrom is forgotten.
+func (e *Encoder) Reset(w io.Writer) {
+	e.w = w
+	e.buf.Reset()
+	e.written = 0
+	e.skip = 0
+}
+
+// limitWriter passes writes on to w until n bytes would be passed
+type limitWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (l *limitWriter) Write(bs []byte) (int, error) {
+	if int64(len(bs)) > l.n {
+		return 0, fmt.Errorf("%w Writing %d bytes with %d remaining", ErrOutputLimit, len(bs), l.n)
+	}
+	l.n -= int64(len(bs))
+	return l.w.Write(bs)
+}
+
+// room errors with ErrOutputLimit should n more bytes pass w's WithMaxOutput cap, so a field
+// too large for it is refused before it's buffered
+func (w *writer) room(n int) error {
+	if w.limit != nil && int64(n) > w.limit.n {
+		return fmt.Errorf("%w Writing %d bytes with %d remaining", ErrOutputLimit, n, w.limit.n)
+	}
+	return nil
+}
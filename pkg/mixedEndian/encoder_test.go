@@ -0,0 +1,114 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"runtime"
+	"testing"
+)
+
+// failWriter fails every write
+type failWriter struct{}
+
+var errWrite = errors.New("write failed")
+
+func (failWriter) Write([]byte) (int, error) { return 0, errWrite }
+
+func TestEncoder(t *testing.T) {
+	buf := &bytes.Buffer{}
+	e := NewEncoder(buf, BigEndian, WithBufferSize(64))
+
+	if err := e.Encode(NestedStruct{A: 0x0102, B: TaggedStruct{A: 0x0304, B: 0x0506}, C: 0x0708}); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if err := e.Encode(uint16(0x090A)); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	want := []byte{0x01, 0x02, 0x03, 0x04, 0x06, 0x05, 0x08, 0x07, 0x09, 0x0A}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("Encode() = % X, wanted % X", buf.Bytes(), want)
+	}
+
+	// Values failing to encode write nothing
+	if err := e.Encode(OddWidthSliceStruct{N: 2}); !errors.Is(err, ErrLength) {
+		t.Errorf("Encode() error = %v, wanted %v", err, ErrLength)
+	}
+	if buf.Len() != len(want) {
+		t.Errorf("Encode() wrote %d bytes after a failure", buf.Len()-len(want))
+	}
+
+	e.Reset(failWriter{})
+	if err := e.Encode(uint8(1)); !errors.Is(err, errWrite) {
+		t.Errorf("Encode() error = %v, wanted %v", err, errWrite)
+	}
+}
+
+func TestEncoderWriteIf(t *testing.T) {
+	const hasExtension = 0x01
+	buf := &bytes.Buffer{}
+	e := NewEncoder(buf, BigEndian)
+
+	ext := uint16(0x0102)
+	for _, flags := range []uint8{hasExtension, 0} {
+		if err := e.Encode(flags); err != nil {
+			t.Fatalf("Encode() error = %v", err)
+		}
+		if err := e.WriteIf(flags&hasExtension != 0, LittleEndian, &ext); err != nil {
+			t.Fatalf("WriteIf() error = %v", err)
+		}
+	}
+	want := []byte{0x01, 0x02, 0x01, 0x00}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("WriteIf() = % X, wanted % X", buf.Bytes(), want)
+	}
+
+	// Skipped values aren't encoded, so can't fail
+	if err := e.WriteIf(false, BigEndian, OddWidthSliceStruct{N: 2}); err != nil {
+		t.Errorf("WriteIf() error = %v", err)
+	}
+	if err := e.WriteIf(true, BigEndian, OddWidthSliceStruct{N: 2}); !errors.Is(err, ErrLength) {
+		t.Errorf("WriteIf() error = %v, wanted %v", err, ErrLength)
+	}
+}
+
+func TestEncoderMaxOutput(t *testing.T) {
+	buf := &bytes.Buffer{}
+	e := NewEncoder(buf, BigEndian, WithMaxOutput(1024))
+
+	// A slice far larger than the cap fails without writing anything
+	if err := e.Encode(make([]uint32, 1<<20)); !errors.Is(err, ErrOutputLimit) {
+		t.Errorf("Encode() error = %v, wanted %v", err, ErrOutputLimit)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Encode() wrote %d bytes past the cap", buf.Len())
+	}
+
+	// The cap covers everything written until Reset
+	if err := e.Encode(make([]uint8, 1000)); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if err := e.Encode(make([]uint16, 13)); !errors.Is(err, ErrOutputLimit) {
+		t.Errorf("Encode() error = %v, wanted %v", err, ErrOutputLimit)
+	}
+	if err := e.Encode(make([]uint16, 12)); err != nil {
+		t.Errorf("Encode() error = %v", err)
+	}
+
+	e.Reset(buf)
+	if err := e.Encode(make([]uint8, 1024)); err != nil {
+		t.Errorf("Encode() error = %v after Reset", err)
+	}
+
+	// Bulk fields are refused before they're encoded, so the cap bounds memory as well as output
+	e.Reset(buf)
+	samples := make([]uint32, 1<<20)
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	if err := e.Encode(samples); !errors.Is(err, ErrOutputLimit) {
+		t.Errorf("Encode() error = %v, wanted %v", err, ErrOutputLimit)
+	}
+	runtime.ReadMemStats(&after)
+	if n := after.TotalAlloc - before.TotalAlloc; n > 1<<20 {
+		t.Errorf("Encode() allocated %d bytes for a field over the cap", n)
+	}
+}
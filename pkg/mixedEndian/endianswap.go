@@ -0,0 +1,25 @@
+package mixedEndian
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// CopyWithEndianSwap reads structs described by schema from src in byte order order, writing
+// each to dst in the other, until src ends between structs. It's for gateways between peers
+// of different byte orders. Fields with an endian tag keep the order the format fixes for them,
+// so only those in the default order are swapped. It returns the number of bytes written.
+//
+// Ending partway through a struct is io.ErrUnexpectedEOF.
+func CopyWithEndianSwap(dst io.Writer, src io.Reader, order binary.ByteOrder, schema *Schema) (int64, error) {
+	if schema.typ == nil {
+		return 0, fmt.Errorf("%w Schema %q has no type; build it with LoadSchema or SchemaFromJSON", ErrSchema, schema.Name)
+	}
+	swapped := binary.ByteOrder(BigEndian)
+	if isBigEndian(order) {
+		swapped = LittleEndian
+	}
+
+	return transcodeType(dst, src, schema.Type(), schema.Name, order, swapped)
+}
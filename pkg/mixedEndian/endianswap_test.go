@@ -0,0 +1,58 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestCopyWithEndianSwap(t *testing.T) {
+	s, err := SchemaFromJSON([]byte(`{
+		"name": "Reading",
+		"fields": [
+			{"name": "Magic", "type": "uint16", "tag": "endian:\"big\""},
+			{"name": "Count", "type": "uint8"},
+			{"name": "Values", "type": "[]int16", "tag": "len:\"Count\""},
+			{"name": "Stamp", "type": "uint32"}
+		]
+	}`), nil)
+	if err != nil {
+		t.Fatalf("SchemaFromJSON() error = %v", err)
+	}
+
+	src := []byte{
+		0x4D, 0x45, 0x02, 0x01, 0x02, 0xFF, 0xFE, 0x0A, 0x0B, 0x0C, 0x0D,
+		0x4D, 0x45, 0x00, 0x00, 0x00, 0x00, 0x01,
+	}
+	want := []byte{
+		0x4D, 0x45, 0x02, 0x02, 0x01, 0xFE, 0xFF, 0x0D, 0x0C, 0x0B, 0x0A,
+		0x4D, 0x45, 0x00, 0x01, 0x00, 0x00, 0x00,
+	}
+
+	dst := &bytes.Buffer{}
+	n, err := CopyWithEndianSwap(dst, bytes.NewReader(src), BigEndian, s)
+	if err != nil {
+		t.Fatalf("CopyWithEndianSwap() error = %v", err)
+	}
+	if n != int64(len(want)) || !bytes.Equal(dst.Bytes(), want) {
+		t.Errorf("CopyWithEndianSwap() = %d, % X, wanted %d, % X", n, dst.Bytes(), len(want), want)
+	}
+
+	// Swapping back restores the input
+	back := &bytes.Buffer{}
+	if _, err = CopyWithEndianSwap(back, dst, LittleEndian, s); err != nil || !bytes.Equal(back.Bytes(), src) {
+		t.Errorf("CopyWithEndianSwap() = % X, %v, wanted % X", back.Bytes(), err, src)
+	}
+
+	// Ending partway through a struct keeps what came before it
+	dst.Reset()
+	n, err = CopyWithEndianSwap(dst, bytes.NewReader(src[:14]), BigEndian, s)
+	if !errors.Is(err, io.ErrUnexpectedEOF) || n != 11 {
+		t.Errorf("CopyWithEndianSwap() = %d, %v, wanted 11, %v", n, err, io.ErrUnexpectedEOF)
+	}
+
+	if _, err = CopyWithEndianSwap(io.Discard, bytes.NewReader(src), BigEndian, &Schema{Name: "Empty"}); !errors.Is(err, ErrSchema) {
+		t.Errorf("CopyWithEndianSwap(no type) error = %v, wanted %v", err, ErrSchema)
+	}
+}
@@ -0,0 +1,157 @@
+package mixedEndian
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// epochLayouts are the forms an epoch tag may take: a year, a date, or a time
+var epochLayouts = []string{"2006", "2006-01-02", time.RFC3339Nano}
+
+// isEpoch reports whether sf is a time.Time stored as a count of units since the epoch its tag gives
+func isEpoch(sf reflect.StructField) bool {
+	return sf.Type == timeType && sf.Tag.Get("epoch") != ""
+}
+
+// epochFormat resolves the epoch, unit, and wire type of a time field. Times are counted in
+// seconds unless given an epochunit tag, and stored as int64s unless given a width tag.
+func epochFormat(sf reflect.StructField) (epoch time.Time, unit time.Duration, t reflect.Type, err error) {
+	e := sf.Tag.Get("epoch")
+	for _, layout := range epochLayouts {
+		if epoch, err = time.Parse(layout, e); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return epoch, 0, nil, fmt.Errorf("%w epoch %q is not a year, date, or RFC 3339 time", ErrTag, e)
+	}
+
+	// Units are a unit a dur tag may name, with an optional multiple, as in "100ns"
+	u := sf.Tag.Get("epochunit")
+	if u == "" {
+		u = "s"
+	}
+	digits := strings.IndexFunc(u, func(r rune) bool { return r < '0' || r > '9' })
+	if digits < 0 {
+		return epoch, 0, nil, fmt.Errorf("%w Unknown epoch unit %q", ErrTag, u)
+	}
+	mult := 1
+	if digits > 0 {
+		mult, err = strconv.Atoi(u[:digits])
+	}
+	base, ok := durationUnits[u[digits:]]
+	if !ok || err != nil || mult < 1 {
+		return epoch, 0, nil, fmt.Errorf("%w Unknown epoch unit %q", ErrTag, u)
+	}
+	if unit = time.Duration(mult) * base; unit/base != time.Duration(mult) {
+		return epoch, 0, nil, fmt.Errorf("%w Epoch unit %q is too long", ErrTag, u)
+	}
+
+	// Units either divide a second or are whole seconds, so times are split into seconds exactly
+	if (unit > time.Second || time.Second%unit != 0) && unit%time.Second != 0 {
+		return epoch, 0, nil, fmt.Errorf("%w Epoch unit %q neither divides nor is a multiple of a second", ErrTag, u)
+	}
+
+	w := sf.Tag.Get("width")
+	if w == "" {
+		w = "int64"
+	}
+	if t, ok = durationWidths[w]; !ok {
+		return epoch, 0, nil, fmt.Errorf("%w %q is not an integer type", ErrTag, w)
+	}
+	return epoch, unit, t, nil
+}
+
+// readEpoch reads time f as a count of its unit since its epoch, in UTC
+func (r *reader) readEpoch(sf reflect.StructField, f reflect.Value, o binary.ByteOrder) error {
+	epoch, unit, t, err := epochFormat(sf)
+	if err != nil {
+		return err
+	}
+
+	v := reflect.New(t).Elem()
+	if err = r.readOrdered(v, o); err != nil {
+		return err
+	}
+
+	var n int64
+	switch t.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = v.Int()
+	default:
+		if v.Uint() > math.MaxInt64 {
+			return fmt.Errorf("%w %d units of %s since %s is out of range", ErrRange, v.Uint(), unit, epoch.Format(time.RFC3339))
+		}
+		n = int64(v.Uint())
+	}
+
+	var sec, nsec int64
+	if unit <= time.Second {
+		per := int64(time.Second / unit)
+		sec, nsec = n/per, n%per*int64(unit)
+	} else {
+		k := int64(unit / time.Second)
+		if n > math.MaxInt64/k || n < math.MinInt64/k {
+			return fmt.Errorf("%w %d units of %s since %s is out of range", ErrRange, n, unit, epoch.Format(time.RFC3339))
+		}
+		sec = n * k
+	}
+	base := epoch.Unix()
+	if (sec > 0 && base > math.MaxInt64-sec) || (sec < 0 && base < math.MinInt64-sec) {
+		return fmt.Errorf("%w %d units of %s since %s is out of range", ErrRange, n, unit, epoch.Format(time.RFC3339))
+	}
+	f.Set(reflect.ValueOf(time.Unix(base+sec, nsec+int64(epoch.Nanosecond())).UTC()))
+	return nil
+}
+
+// writeEpoch writes time f as a count of its unit since its epoch, truncated to a whole unit
+// toward the past
+func (w *writer) writeEpoch(sf reflect.StructField, f reflect.Value, o binary.ByteOrder) error {
+	epoch, unit, t, err := epochFormat(sf)
+	if err != nil {
+		return err
+	}
+	tm := f.Interface().(time.Time)
+
+	sec := tm.Unix() - epoch.Unix()
+	nsec := int64(tm.Nanosecond() - epoch.Nanosecond())
+	if nsec < 0 {
+		sec, nsec = sec-1, nsec+int64(time.Second)
+	}
+
+	var n int64
+	if unit <= time.Second {
+		per, part := int64(time.Second/unit), nsec/int64(unit)
+		if sec > math.MaxInt64/per || sec < math.MinInt64/per || sec*per > math.MaxInt64-part {
+			return fmt.Errorf("%w %s is too far from %s to count in %s", ErrRange, tm.Format(time.RFC3339Nano), epoch.Format(time.RFC3339), unit)
+		}
+		n = sec*per + part
+	} else {
+		k := int64(unit / time.Second)
+		if n = sec / k; sec%k < 0 {
+			n--
+		}
+	}
+
+	v := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.OverflowInt(n) {
+			return fmt.Errorf("%w %d units of %s since %s does not fit in %s", ErrRange, n, unit, epoch.Format(time.RFC3339), t.String())
+		}
+		v.SetInt(n)
+	default:
+		if n < 0 || v.OverflowUint(uint64(n)) {
+			return fmt.Errorf("%w %d units of %s since %s does not fit in %s", ErrRange, n, unit, epoch.Format(time.RFC3339), t.String())
+		}
+		v.SetUint(uint64(n))
+	}
+	return w.writeOrdered(v, o)
+}
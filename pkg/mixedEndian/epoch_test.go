@@ -0,0 +1,122 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type EpochStruct struct {
+	FileTime time.Time `epoch:"1601" epochunit:"100ns" width:"uint64" endian:"little"`
+	HFS      time.Time `epoch:"1904" width:"uint32"`
+	Unix     time.Time `epoch:"1970-01-01" epochunit:"ms"`
+}
+
+func TestEpochRoundTrip(t *testing.T) {
+	// 2000-01-01T00:00:00Z is FILETIME 0x01BF53EB256D4000, and HFS 0xB492F400
+	y2k := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	data := EpochStruct{FileTime: y2k, HFS: y2k, Unix: y2k.Add(1500 * time.Millisecond)}
+	wire := []byte{
+		0x00, 0x40, 0x6D, 0x25, 0xEB, 0x53, 0xBF, 0x01,
+		0xB4, 0x92, 0xF4, 0x00,
+		0x00, 0x00, 0x00, 0xDC, 0x6A, 0xCF, 0xB1, 0xDC,
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, data); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+
+	var got any = &EpochStruct{}
+	if err := Read(bytes.NewReader(wire), BigEndian, &got); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, &data) {
+		t.Errorf("Read() = %v, wanted %v", got, data)
+	}
+
+	if n, err := SizeOf(EpochStruct{}); err != nil || n != len(wire) {
+		t.Errorf("SizeOf() = %d, %v; wanted %d", n, err, len(wire))
+	}
+}
+
+func TestEpochTruncates(t *testing.T) {
+	// Times between units are written as the unit before them, before the epoch as after it
+	type stamp struct {
+		T time.Time `epoch:"2000" epochunit:"2s" width:"int8"`
+	}
+	y2k := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		at   time.Duration
+		want byte
+	}{
+		{3*time.Second + time.Millisecond, 0x01},
+		{-time.Millisecond, 0xFF},
+		{-2 * time.Second, 0xFF},
+		{-3 * time.Second, 0xFE},
+	} {
+		buf := &bytes.Buffer{}
+		if err := Write(buf, BigEndian, stamp{T: y2k.Add(tt.at)}); err != nil {
+			t.Fatalf("Write(%v) error = %v", tt.at, err)
+		}
+		if !bytes.Equal(buf.Bytes(), []byte{tt.want}) {
+			t.Errorf("Write(%v) = % X, wanted %02X", tt.at, buf.Bytes(), tt.want)
+		}
+	}
+}
+
+func TestEpochErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    any
+		wantErr error
+	}{
+		{
+			name: "before an unsigned epoch",
+			data: struct {
+				T time.Time `epoch:"1904" width:"uint32"`
+			}{T: time.Date(1903, 12, 31, 0, 0, 0, 0, time.UTC)},
+			wantErr: ErrRange,
+		},
+		{
+			name: "too wide",
+			data: struct {
+				T time.Time `epoch:"1904" width:"uint32"`
+			}{T: time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)},
+			wantErr: ErrRange,
+		},
+		{
+			name: "bad epoch",
+			data: struct {
+				T time.Time `epoch:"the dawn of time"`
+			}{},
+			wantErr: ErrTag,
+		},
+		{
+			name: "unknown unit",
+			data: struct {
+				T time.Time `epoch:"1970" epochunit:"100fortnights"`
+			}{},
+			wantErr: ErrTag,
+		},
+		{
+			name: "uneven unit",
+			data: struct {
+				T time.Time `epoch:"1970" epochunit:"1500ms"`
+			}{},
+			wantErr: ErrTag,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Write(&bytes.Buffer{}, BigEndian, tt.data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Write() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+)
+
+// FieldEncoder encodes a struct one field at a time, for callers doing their own framing
+// between fields. The fields' encodings, concatenated, are what Write would write.
+// Structs with checksums are encoded whole up front, so their sums can be filled in, and handed
+// out a field at a time. ISO 8583 messages, which lead with a bitmap of their fields, can't be.
+type FieldEncoder struct {
+	v  reflect.Value
+	so structOptions
+	i  int
+
+	buf bytes.Buffer
+	enc writer
+	err error
+
+	// align is the struct's align option, with off the bytes encoded so far and last the index
+	// of the field which takes the trailing padding
+	align int
+	off   int64
+	last  int
+
+	// whole is the checksummed struct's encoding, with spans where each field ends up in it
+	whole []byte
+	spans [][2]int64
+}
+
+// NewFieldEncoder returns a FieldEncoder over data, a struct or pointer to one, with default byte
+// order defaultEndian
+func NewFieldEncoder(defaultEndian binary.ByteOrder, data any) (*FieldEncoder, error) {
+	v := reflect.ValueOf(data)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, fmt.Errorf("%w Got nil %s", ErrUnexpectedType, v.Type().String())
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w Expected struct; Got %v", ErrUnexpectedType, v.Kind())
+	}
+	so, err := optionsOf(v.Type())
+	if err != nil {
+		return nil, err
+	} else if so.iso8583 {
+		return nil, fmt.Errorf("%w ISO 8583 messages can't be encoded a field at a time", ErrUnexpectedType)
+	}
+
+	// Sizes and sums are filled in as writeOrdered fills them in
+	fe := &FieldEncoder{v: v, so: so, align: so.align}
+	fe.enc = writer{w: &fe.buf, o: defaultEndian, ctx: context.Background()}
+	if len(so.scopes) > 0 {
+		if fe.v, err = fe.enc.scopeSized(fe.v, so, defaultEndian); err != nil {
+			return nil, err
+		}
+	}
+	if i, ok, err := selfSizeField(v.Type()); err != nil {
+		return nil, err
+	} else if ok {
+		if fe.v, err = fe.enc.selfSized(fe.v, i, so, defaultEndian); err != nil {
+			return nil, err
+		}
+	}
+	if cks, err := checksumsOf(v.Type()); err != nil {
+		return nil, err
+	} else if len(cks) > 0 {
+		if fe.whole, fe.spans, err = fe.enc.encodeChecksummed(fe.v, so, cks, defaultEndian); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		if sf := v.Type().Field(i); sf.Name != "_" || sf.Type.Size() != 0 {
+			fe.last = i
+		}
+	}
+	return fe, nil
+}
+
+// Next encodes the next field, returning its bytes and true, or false once there are no more.
+// After an error, Next keeps returning it. Fields sharing an overlay come out as one, the first
+// of them, with the rest empty.
+func (fe *FieldEncoder) Next() ([]byte, bool, error) {
+	if fe.err != nil {
+		return nil, false, fe.err
+	}
+
+	t := fe.v.Type()
+	for ; fe.i < t.NumField(); fe.i++ {
+		// Markers only carry struct options
+		if sf := t.Field(fe.i); sf.Name == "_" && sf.Type.Size() == 0 {
+			continue
+		}
+
+		if fe.whole != nil {
+			return fe.nextEncoded(), true, nil
+		}
+
+		fe.buf.Reset()
+		if fe.align > 0 {
+			fe.buf.Write(make([]byte, padding(fe.off, fieldAlign(t.Field(fe.i), fe.align))))
+		}
+		if fe.err = fe.enc.writeFieldAt(fe.v, fe.so, fe.i, fe.enc.o); fe.err != nil {
+			return nil, false, fe.err
+		}
+		if fe.align > 0 && fe.i == fe.last {
+			fe.buf.Write(make([]byte, padding(fe.off+int64(fe.buf.Len()), fe.align)))
+		}
+		fe.off += int64(fe.buf.Len())
+		fe.i++
+		return append([]byte(nil), fe.buf.Bytes()...), true, nil
+	}
+	return nil, false, nil
+}
+
+// nextEncoded hands out the next field of the struct encoded whole, with the padding before it,
+// and after it should it be the last
+func (fe *FieldEncoder) nextEncoded() []byte {
+	end := fe.spans[fe.i][1]
+	if fe.i == fe.last {
+		end = int64(len(fe.whole))
+	} else if end < fe.off {
+		// Overlaid fields after the first are already out
+		end = fe.off
+	}
+	chunk := append([]byte(nil), fe.whole[fe.off:end]...)
+	fe.off = end
+	fe.i++
+	return chunk
+}
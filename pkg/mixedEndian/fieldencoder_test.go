@@ -0,0 +1,113 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestFieldEncoder(t *testing.T) {
+	data := &RLEStruct{
+		N:       3,
+		Indices: []uint8{1, 1, 2},
+		Size:    4,
+		Pixels:  []RLEPixel{{1, 2, 3}},
+	}
+	want, err := Marshal(BigEndian, data)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	fe, err := NewFieldEncoder(BigEndian, data)
+	if err != nil {
+		t.Fatalf("NewFieldEncoder() error = %v", err)
+	}
+	var chunks [][]byte
+	for {
+		chunk, ok, err := fe.Next()
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		if !ok {
+			break
+		}
+		chunks = append(chunks, chunk)
+	}
+
+	if len(chunks) != 4 {
+		t.Errorf("Next() gave %d fields, wanted 4", len(chunks))
+	}
+	if got := bytes.Join(chunks, nil); !bytes.Equal(got, want) {
+		t.Errorf("Next() chunks = % X, wanted % X", got, want)
+	}
+	if !bytes.Equal(chunks[1], []byte{0x02, 0x01, 0x01, 0x02}) {
+		t.Errorf("Next() Indices = % X", chunks[1])
+	}
+}
+
+func TestFieldEncoderErrors(t *testing.T) {
+	if _, err := NewFieldEncoder(BigEndian, uint8(1)); !errors.Is(err, ErrUnexpectedType) {
+		t.Errorf("NewFieldEncoder() error = %v, wanted %v", err, ErrUnexpectedType)
+	}
+
+	fe, err := NewFieldEncoder(BigEndian, OddWidthSliceStruct{N: 2})
+	if err != nil {
+		t.Fatalf("NewFieldEncoder() error = %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, ok, err := fe.Next(); err != nil {
+			if !errors.Is(err, ErrLength) || ok {
+				t.Errorf("Next() = %v, %v, wanted %v", ok, err, ErrLength)
+			}
+			// Errors stick
+			if _, _, again := fe.Next(); again != err {
+				t.Errorf("Next() after error = %v, wanted %v", again, err)
+			}
+			return
+		}
+	}
+	t.Errorf("Next() never failed")
+}
+
+func TestFieldEncoderFixUps(t *testing.T) {
+	tests := []struct {
+		name string
+		data any
+	}{
+		{name: "overlay", data: OverlayRecord{Kind: 1, Pair: [2]uint32{2, 3}, Tail: 4}},
+		{name: "network_checksum", data: IPv4Header{VersionIHL: 0x45, TotalLength: 0x73, TTL: 0x40, Src: [4]byte{192, 168, 0, 1}}},
+		{name: "lengthscope", data: ScopedRecord{Kind: 1, Length: 99, A: 2, B: [2]uint8{3, 4}, C: 5, D: 6}},
+		{name: "crc", data: RangedCRCFrame{Header: 1, Payload: [4]byte{2, 3, 4, 5}, Seq: 6, Trailer: 7}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := Marshal(BigEndian, tt.data)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			fe, err := NewFieldEncoder(BigEndian, tt.data)
+			if err != nil {
+				t.Fatalf("NewFieldEncoder() error = %v", err)
+			}
+			var got []byte
+			for {
+				chunk, ok, err := fe.Next()
+				if err != nil {
+					t.Fatalf("Next() error = %v", err)
+				}
+				if !ok {
+					break
+				}
+				got = append(got, chunk...)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("Next() chunks = % X, wanted % X", got, want)
+			}
+		})
+	}
+
+	// ISO 8583 bitmaps lead the fields they describe, so aren't encoded a field at a time
+	if _, err := NewFieldEncoder(BigEndian, AuthorizationRequest{}); !errors.Is(err, ErrUnexpectedType) {
+		t.Errorf("NewFieldEncoder(iso8583) error = %v, wanted %v", err, ErrUnexpectedType)
+	}
+}
@@ -0,0 +1,59 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// fieldPad parses sf's padding tag key, padding_before or padding_after, into the number of
+// bytes it pads with and their value. Tags give a length, optionally followed by a fill byte,
+// such as "2" or "2,0xFF". Padding is zeros unless given a fill.
+func fieldPad(sf reflect.StructField, key string) (n int, fill byte, err error) {
+	s := sf.Tag.Get(key)
+	if s == "" {
+		return 0, 0, nil
+	}
+
+	c, b, hasFill := strings.Cut(s, ",")
+	if n, err = strconv.Atoi(c); err != nil || n < 0 {
+		return 0, 0, fmt.Errorf("%w %s %q is not a length", ErrTag, key, s)
+	}
+	if hasFill {
+		u, perr := strconv.ParseUint(b, 0, 8)
+		if perr != nil {
+			return 0, 0, fmt.Errorf("%w %s %q has fill %q, which is not a byte", ErrTag, key, s, b)
+		}
+		fill = byte(u)
+	}
+	return n, fill, nil
+}
+
+// fieldPads is the padding sf's tags put before and after it
+func fieldPads(sf reflect.StructField) (before, after int, err error) {
+	if before, _, err = fieldPad(sf, "padding_before"); err != nil {
+		return
+	}
+	after, _, err = fieldPad(sf, "padding_after")
+	return
+}
+
+// skipPad discards the padding sf's tag key calls for. Its bytes aren't checked against the fill.
+func (r *reader) skipPad(sf reflect.StructField, key string) error {
+	n, _, err := fieldPad(sf, key)
+	if err == nil && n > 0 {
+		_, err = r.next(n)
+	}
+	return err
+}
+
+// writePad writes the padding sf's tag key calls for
+func (w *writer) writePad(sf reflect.StructField, key string) error {
+	n, fill, err := fieldPad(sf, key)
+	if err == nil && n > 0 {
+		_, err = w.w.Write(bytes.Repeat([]byte{fill}, n))
+	}
+	return err
+}
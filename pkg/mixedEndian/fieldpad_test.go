@@ -0,0 +1,144 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type PaddedStruct struct {
+	Kind   uint8  `padding_after:"1"`
+	Length uint16 `padding_before:"2,0xFF" padding_after:"2,0xAA"`
+	HasExt bool
+	Ext    uint32 `presentif:"HasExt" padding_before:"1"`
+	Tail   uint8
+}
+
+func TestFieldPadding(t *testing.T) {
+	data := PaddedStruct{Kind: 7, Length: 0x0102, Tail: 9}
+	wire := []byte{
+		0x07, 0x00,
+		0xFF, 0xFF, 0x01, 0x02, 0xAA, 0xAA,
+		0x00,
+		0x09,
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, data); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+
+	// Padding's discarded whatever it holds
+	garbled := append([]byte{}, wire...)
+	garbled[1], garbled[2], garbled[7] = 0x55, 0x00, 0x33
+	var got any = &PaddedStruct{}
+	if err := Read(bytes.NewReader(garbled), BigEndian, &got); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, &data) {
+		t.Errorf("Read() = %+v, wanted %+v", got, data)
+	}
+
+	// Optional fields bring their padding with them
+	data.HasExt, data.Ext = true, 0x0A0B0C0D
+	wire = []byte{
+		0x07, 0x00,
+		0xFF, 0xFF, 0x01, 0x02, 0xAA, 0xAA,
+		0x01, 0x00, 0x0A, 0x0B, 0x0C, 0x0D,
+		0x09,
+	}
+	buf.Reset()
+	if err := Write(buf, BigEndian, data); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+	got = &PaddedStruct{}
+	if err := Read(bytes.NewReader(wire), BigEndian, &got); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, &data) {
+		t.Errorf("Read() = %+v, wanted %+v", got, data)
+	}
+}
+
+func TestFieldPaddingLayout(t *testing.T) {
+	type padded struct {
+		A uint8  `padding_after:"3"`
+		B uint32 `padding_before:"2" padding_after:"1,0xFF"`
+		C uint16
+	}
+
+	sl, err := Describe(padded{})
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	if sl.Size != 13 {
+		t.Errorf("Describe().Size = %d, wanted 13", sl.Size)
+	}
+	for i, want := range []struct{ offset, size int }{{0, 1}, {6, 4}, {11, 2}} {
+		if f := sl.Fields[i]; f.Offset != want.offset || f.Size != want.size {
+			t.Errorf("%s at %d, %d bytes; wanted %d, %d bytes", f.Name, f.Offset, f.Size, want.offset, want.size)
+		}
+	}
+
+	if n, err := CountBytes(BigEndian, padded{}); err != nil || n != sl.Size {
+		t.Errorf("CountBytes() = %d, %v; wanted %d", n, err, sl.Size)
+	}
+}
+
+func TestFieldPaddingBadTag(t *testing.T) {
+	for _, tag := range []reflect.StructTag{
+		`padding_before:"-1"`,
+		`padding_after:"two"`,
+		`padding_after:"2,0x100"`,
+		`padding_before:"2,fill"`,
+	} {
+		v := reflect.New(reflect.StructOf([]reflect.StructField{
+			{Name: "A", Type: reflect.TypeOf(uint16(0)), Tag: tag},
+		}))
+		if err := Write(&bytes.Buffer{}, BigEndian, v.Interface()); !errors.Is(err, ErrTag) {
+			t.Errorf("Write() with %s error = %v, wanted %v", tag, err, ErrTag)
+		}
+		if _, err := LayoutOf(v.Type()); !errors.Is(err, ErrTag) {
+			t.Errorf("LayoutOf() with %s error = %v, wanted %v", tag, err, ErrTag)
+		}
+	}
+}
+
+func TestFieldPaddingTrace(t *testing.T) {
+	// Padding is listed as bytes between fields, not as part of the field it surrounds
+	wire := []byte{
+		0x07, 0x00,
+		0xFF, 0xFF, 0x01, 0x02, 0xAA, 0xAA,
+		0x00,
+		0x09,
+	}
+	out, err := TraceJSON(bytes.NewReader(wire), BigEndian, PaddedStruct{})
+	if err != nil {
+		t.Fatalf("TraceJSON() error = %v", err)
+	}
+	var entries []traceEntry
+	if err = json.Unmarshal(out, &entries); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	type span struct {
+		path           string
+		offset, length int64
+	}
+	want := []span{{"Kind", 0, 1}, {"_", 1, 3}, {"Length", 4, 2}, {"_", 6, 2}, {"HasExt", 8, 1}, {"Tail", 9, 1}}
+	got := make([]span, len(entries))
+	for i, e := range entries {
+		got[i] = span{e.Path, e.Offset, e.Length}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TraceJSON() spans = %v, wanted %v", got, want)
+	}
+}
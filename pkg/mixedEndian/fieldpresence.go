@@ -0,0 +1,39 @@
+package mixedEndian
+
+// WithFieldPresence makes a Decoder ask present whether each field is on the wire before reading
+// it, so which are can be decided as it decodes, such as from a negotiated set of capabilities.
+// Fields are named by their dotted paths from the value decoded, as a StructLayout's Paths name
+// them, such as "Header.Length", with the elements of slices and arrays sharing their field's
+// path. Fields present gives false for are skipped, left as their zero values with nothing read
+// for them, whatever their tags say. Those it gives true for are read as their tags say, so may
+// still be skipped by presentif or version tags.
+func WithFieldPresence(present func(path string) bool) Option {
+	return func(o *options) {
+		o.presence = present
+	}
+}
+
+// fieldPresence asks the predicate of WithFieldPresence about fields as they're read
+type fieldPresence struct {
+	present func(path string) bool
+
+	// prefix is the path of the struct whose fields are being read, with a trailing dot
+	prefix string
+}
+
+// newFieldPresence returns the fieldPresence of present, or nil when there's none
+func newFieldPresence(present func(path string) bool) *fieldPresence {
+	if present == nil {
+		return nil
+	}
+	return &fieldPresence{present: present}
+}
+
+// enter returns whether field name, in the struct being read, is on the wire, and moves into it
+// until the returned func is called
+func (fp *fieldPresence) enter(name string) (bool, func()) {
+	prefix := fp.prefix
+	present := fp.present(prefix + name)
+	fp.prefix += name + "."
+	return present, func() { fp.prefix = prefix }
+}
@@ -0,0 +1,76 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+type PluginRecord struct {
+	Version  uint8
+	Ext      uint32
+	Tail     uint16
+	Location struct {
+		X, Y int16
+	}
+	Samples []uint8 `count:"2"`
+}
+
+func TestWithFieldPresence(t *testing.T) {
+	// Without Ext, or Location.X, what follows each moves up to take its place
+	wire := []byte{0x02, 0xBE, 0xEF, 0xFF, 0xFE, 0x07, 0x08}
+	var asked []string
+	skip := map[string]bool{"Ext": true, "Location.X": true}
+	dec := NewDecoder(bytes.NewReader(wire), BigEndian, WithFieldPresence(func(path string) bool {
+		asked = append(asked, path)
+		return !skip[path]
+	}))
+
+	got := PluginRecord{Ext: 99}
+	if err := dec.Decode(&got); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	want := PluginRecord{Version: 2, Tail: 0xBEEF, Samples: []uint8{7, 8}}
+	want.Location.Y = -2
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Decode() = %+v, wanted %+v", got, want)
+	}
+	if dec.in.n != int64(len(wire)) {
+		t.Errorf("Decode() read %d bytes, wanted %d", dec.in.n, len(wire))
+	}
+
+	wantAsked := []string{"Version", "Ext", "Tail", "Location", "Location.X", "Location.Y", "Samples"}
+	if !reflect.DeepEqual(asked, wantAsked) {
+		t.Errorf("predicate asked about %q, wanted %q", asked, wantAsked)
+	}
+}
+
+func TestWithFieldPresenceOptional(t *testing.T) {
+	// Ruled out Optionals take no bytes, not even their flag, and aren't present
+	tests := []struct {
+		name string
+		wire []byte
+		data any
+		want any
+	}{
+		{"flag", []byte{0x01, 0x01, 'a', 'b', 'c', 0x00}, &OptionalFlagStruct{}, &OptionalFlagStruct{A: 1, Name: Some("abc")}},
+		{"eof", []byte{0x00, 0x01, 0x02, 0x03}, &OptionalEOFStruct{}, &OptionalEOFStruct{A: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := bytes.NewReader(tt.wire)
+			dec := NewDecoder(in, BigEndian, WithFieldPresence(func(path string) bool {
+				return path != "Ext" && path != "Trail"
+			}))
+			if err := dec.Decode(tt.data); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(tt.data, tt.want) {
+				t.Errorf("Decode() = %+v, wanted %+v", tt.data, tt.want)
+			}
+			if rest := in.Len(); tt.name == "eof" && rest != 2 {
+				t.Errorf("Decode() left %d bytes, wanted the 2 of Trail", rest)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package mixedEndian
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FormatRegistry recognises file formats by their magic numbers, to decode them without being
+// told which they are. It is safe for concurrent use.
+type FormatRegistry struct {
+	mu      sync.RWMutex
+	formats map[string]format
+}
+
+// format is a registered file format
+type format struct {
+	magic  []byte
+	offset int64
+	order  binary.ByteOrder
+	typ    reflect.Type
+}
+
+// DefaultFormats holds the formats registered with RegisterFormat
+var DefaultFormats = NewFormatRegistry()
+
+// NewFormatRegistry returns an empty FormatRegistry
+func NewFormatRegistry() *FormatRegistry {
+	return &FormatRegistry{formats: map[string]format{}}
+}
+
+// RegisterFormat registers a format with DefaultFormats. See FormatRegistry.Register.
+func RegisterFormat(name string, magic []byte, magicOffset int64, order binary.ByteOrder, prototype any) {
+	DefaultFormats.Register(name, magic, magicOffset, order, prototype)
+}
+
+// DetectAndDecode detects and decodes a format registered with DefaultFormats.
+// See FormatRegistry.DetectAndDecode.
+func DetectAndDecode(r io.ReadSeeker) (name string, value any, err error) {
+	return DefaultFormats.DetectAndDecode(r)
+}
+
+// Register adds the format name, whose files hold magic magicOffset bytes in, and are decoded
+// into the struct type of prototype, or that it points to, with default byte order order.
+// A format already registered as name is replaced. Register panics if magic is empty,
+// magicOffset negative, or prototype not a struct or pointer to one.
+func (fr *FormatRegistry) Register(name string, magic []byte, magicOffset int64, order binary.ByteOrder, prototype any) {
+	t := reflect.TypeOf(prototype)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == nil || t.Kind() != reflect.Struct:
+		panic(fmt.Errorf("%s: %w Expected struct prototype; Got %T", name, ErrUnexpectedType, prototype))
+	case len(magic) == 0 || magicOffset < 0:
+		panic(fmt.Errorf("%s: %w Formats need a magic at a non-negative offset", name, ErrLength))
+	}
+
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+	fr.formats[name] = format{
+		magic:  append([]byte(nil), magic...),
+		offset: magicOffset,
+		order:  order,
+		typ:    t,
+	}
+}
+
+// DetectAndDecode peeks at r for the magics of the registered formats, then rewinds and decodes
+// the one matched into a new value of its type, returning its name and a pointer to the value.
+// Where magics of several formats match, the longest wins, and several of that length are
+// ErrAmbiguousFormat. Matching none is ErrUnknownFormat.
+func (fr *FormatRegistry) DetectAndDecode(r io.ReadSeeker) (name string, value any, err error) {
+	fr.mu.RLock()
+	peek := int64(0)
+	for _, f := range fr.formats {
+		if end := f.offset + int64(len(f.magic)); end > peek {
+			peek = end
+		}
+	}
+	fr.mu.RUnlock()
+
+	start, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", nil, err
+	}
+	head := make([]byte, peek)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", nil, err
+	}
+	head = head[:n]
+	if _, err = r.Seek(start, io.SeekStart); err != nil {
+		return "", nil, err
+	}
+
+	name, f, err := fr.match(head)
+	if err != nil {
+		return "", nil, err
+	}
+	value = reflect.New(f.typ).Interface()
+	if err = Read(r, f.order, &value); err != nil {
+		return name, nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return name, value, nil
+}
+
+// match finds the format whose magic is the longest found in head
+func (fr *FormatRegistry) match(head []byte) (string, format, error) {
+	fr.mu.RLock()
+	defer fr.mu.RUnlock()
+
+	var best []string
+	longest := 0
+	for name, f := range fr.formats {
+		end := f.offset + int64(len(f.magic))
+		if end > int64(len(head)) || string(head[f.offset:end]) != string(f.magic) {
+			continue
+		}
+		switch {
+		case len(f.magic) > longest:
+			best, longest = []string{name}, len(f.magic)
+		case len(f.magic) == longest:
+			best = append(best, name)
+		}
+	}
+
+	switch len(best) {
+	case 0:
+		return "", format{}, fmt.Errorf("%w No registered magic matches", ErrUnknownFormat)
+	case 1:
+		return best[0], fr.formats[best[0]], nil
+	default:
+		sort.Strings(best)
+		return "", format{}, fmt.Errorf("%w Matches %s", ErrAmbiguousFormat, strings.Join(best, ", "))
+	}
+}
@@ -0,0 +1,159 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type WAVHeader struct {
+	ChunkID       [4]byte `const:"0x52,0x49,0x46,0x46"`
+	ChunkSize     uint32
+	Format        [4]byte `const:"0x57,0x41,0x56,0x45"`
+	FmtID         [4]byte
+	FmtSize       uint32
+	AudioFormat   uint16
+	Channels      uint16
+	SampleRate    uint32
+	ByteRate      uint32
+	BlockAlign    uint16
+	BitsPerSample uint16
+}
+
+type MEHeader struct {
+	Magic   [2]byte `const:"0x4D,0x45"`
+	Version uint8
+	Length  uint16
+}
+
+type RIFFChunk struct {
+	ID   [4]byte
+	Size uint32
+}
+
+func TestDetectAndDecode(t *testing.T) {
+	RegisterFormat("riff", []byte("RIFF"), 0, LittleEndian, RIFFChunk{})
+	RegisterFormat("wav", []byte("WAVEfmt "), 8, LittleEndian, &WAVHeader{})
+	RegisterFormat("me", []byte("ME"), 0, BigEndian, MEHeader{})
+
+	wav := WAVHeader{
+		ChunkID:       [4]byte{'R', 'I', 'F', 'F'},
+		ChunkSize:     36,
+		Format:        [4]byte{'W', 'A', 'V', 'E'},
+		FmtID:         [4]byte{'f', 'm', 't', ' '},
+		FmtSize:       16,
+		AudioFormat:   1,
+		Channels:      2,
+		SampleRate:    44100,
+		ByteRate:      176400,
+		BlockAlign:    4,
+		BitsPerSample: 16,
+	}
+	wavBytes, err := Marshal(LittleEndian, wav)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		input     []byte
+		wantName  string
+		wantValue any
+		wantErr   error
+	}{
+		{name: "wav", input: wavBytes, wantName: "wav", wantValue: &wav},
+		{
+			name:      "riff",
+			input:     []byte{'R', 'I', 'F', 'F', 0x04, 0x00, 0x00, 0x00, 'A', 'V', 'I', ' '},
+			wantName:  "riff",
+			wantValue: &RIFFChunk{ID: [4]byte{'R', 'I', 'F', 'F'}, Size: 4},
+		},
+		{
+			name:      "custom",
+			input:     []byte{'M', 'E', 0x02, 0x01, 0x00},
+			wantName:  "me",
+			wantValue: &MEHeader{Magic: [2]byte{'M', 'E'}, Version: 2, Length: 0x0100},
+		},
+		{name: "unknown", input: []byte("GIF89a"), wantErr: ErrUnknownFormat},
+		{name: "short", input: []byte("M"), wantErr: ErrUnknownFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, value, err := DetectAndDecode(bytes.NewReader(tt.input))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DetectAndDecode() error = %v, wanted %v", err, tt.wantErr)
+			}
+			if name != tt.wantName || !reflect.DeepEqual(value, tt.wantValue) {
+				t.Errorf("DetectAndDecode() = %q, %+v, wanted %q, %+v", name, value, tt.wantName, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestDetectAndDecodeRewinds(t *testing.T) {
+	fr := NewFormatRegistry()
+	fr.Register("me", []byte("ME"), 0, BigEndian, MEHeader{})
+
+	// Decoding starts where r was, and leaves it after the value
+	r := bytes.NewReader([]byte{0xFF, 'M', 'E', 0x01, 0x00, 0x05, 0xAA})
+	if _, err := r.Seek(1, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, value, err := fr.DetectAndDecode(r); err != nil || value.(*MEHeader).Length != 5 {
+		t.Errorf("DetectAndDecode() = %+v, %v", value, err)
+	}
+	if r.Len() != 1 {
+		t.Errorf("DetectAndDecode() left %d bytes, wanted 1", r.Len())
+	}
+}
+
+func TestDetectAndDecodeAmbiguous(t *testing.T) {
+	fr := NewFormatRegistry()
+	fr.Register("me", []byte("ME"), 0, BigEndian, MEHeader{})
+	fr.Register("me-le", []byte("ME"), 0, LittleEndian, MEHeader{})
+
+	_, _, err := fr.DetectAndDecode(bytes.NewReader([]byte{'M', 'E', 0x01, 0x00, 0x05}))
+	if !errors.Is(err, ErrAmbiguousFormat) {
+		t.Errorf("DetectAndDecode() error = %v, wanted %v", err, ErrAmbiguousFormat)
+	}
+
+	// A longer magic settles it
+	fr.Register("me1", []byte("ME\x01"), 0, BigEndian, MEHeader{})
+	if name, _, err := fr.DetectAndDecode(bytes.NewReader([]byte{'M', 'E', 0x01, 0x00, 0x05})); err != nil || name != "me1" {
+		t.Errorf("DetectAndDecode() = %q, %v, wanted \"me1\"", name, err)
+	}
+}
+
+func TestFormatRegistryConcurrent(t *testing.T) {
+	fr := NewFormatRegistry()
+	fr.Register("me", []byte("ME"), 0, BigEndian, MEHeader{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			fr.Register(fmt.Sprintf("other%d", i), []byte{'X', byte(i)}, 4, BigEndian, RIFFChunk{})
+		}(i)
+		go func() {
+			defer wg.Done()
+			if name, _, err := fr.DetectAndDecode(bytes.NewReader([]byte{'M', 'E', 0x01, 0x00, 0x05})); err != nil || name != "me" {
+				t.Errorf("DetectAndDecode() = %q, %v, wanted \"me\"", name, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestRegisterFormatPanics(t *testing.T) {
+	defer func() {
+		if err, _ := recover().(error); !errors.Is(err, ErrUnexpectedType) {
+			t.Errorf("Register() panicked with %v, wanted %v", err, ErrUnexpectedType)
+		}
+	}()
+	NewFormatRegistry().Register("int", []byte("I"), 0, BigEndian, 7)
+}
@@ -0,0 +1,294 @@
+package mixedEndian
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// stuffing is how a serial link protocol delimits frames and escapes the delimiter within them
+type stuffing struct {
+	name  string
+	delim byte
+
+	// lead has frames open with the delimiter as well as close with it, flushing line noise
+	lead bool
+
+	// unstuff appends the payload of raw, a frame without its delimiters, to dst.
+	// stuff appends the frame of payload, without delimiters, to dst.
+	unstuff func(dst, raw []byte) ([]byte, error)
+	stuff   func(dst, payload []byte) []byte
+}
+
+const (
+	hdlcFlag   = 0x7E
+	hdlcEscape = 0x7D
+
+	slipEnd    = 0xC0
+	slipEsc    = 0xDB
+	slipEscEnd = 0xDC
+	slipEscEsc = 0xDD
+)
+
+// hdlcStuffing is the asynchronous HDLC framing of RFC 1662, used by PPP. Escaped bytes follow
+// 0x7D, with bit 5 flipped. The flag and escape are always escaped, as are the control
+// characters below 0x20, which the default async control character map covers.
+var hdlcStuffing = stuffing{
+	name:  "HDLC",
+	delim: hdlcFlag,
+	lead:  true,
+	unstuff: func(dst, raw []byte) ([]byte, error) {
+		for i := 0; i < len(raw); i++ {
+			b := raw[i]
+			if b == hdlcEscape {
+				// An escape before the closing flag aborts the frame
+				if i++; i == len(raw) {
+					return dst, fmt.Errorf("%w HDLC frame aborted by an escape before its flag", ErrFrame)
+				}
+				b = raw[i] ^ 0x20
+			}
+			dst = append(dst, b)
+		}
+		return dst, nil
+	},
+	stuff: func(dst, payload []byte) []byte {
+		for _, b := range payload {
+			if b == hdlcFlag || b == hdlcEscape || b < 0x20 {
+				dst = append(dst, hdlcEscape, b^0x20)
+			} else {
+				dst = append(dst, b)
+			}
+		}
+		return dst
+	},
+}
+
+// slipStuffing is the SLIP framing of RFC 1055. END, 0xC0, is sent as 0xDB 0xDC, and ESC, 0xDB,
+// as 0xDB 0xDD.
+var slipStuffing = stuffing{
+	name:  "SLIP",
+	delim: slipEnd,
+	lead:  true,
+	unstuff: func(dst, raw []byte) ([]byte, error) {
+		for i := 0; i < len(raw); i++ {
+			b := raw[i]
+			if b == slipEsc {
+				if i++; i == len(raw) {
+					return dst, fmt.Errorf("%w SLIP frame ends in an escape", ErrFrame)
+				}
+				switch raw[i] {
+				case slipEscEnd:
+					b = slipEnd
+				case slipEscEsc:
+					b = slipEsc
+				default:
+					return dst, fmt.Errorf("%w SLIP escape before %#02x", ErrFrame, raw[i])
+				}
+			}
+			dst = append(dst, b)
+		}
+		return dst, nil
+	},
+	stuff: func(dst, payload []byte) []byte {
+		for _, b := range payload {
+			switch b {
+			case slipEnd:
+				dst = append(dst, slipEsc, slipEscEnd)
+			case slipEsc:
+				dst = append(dst, slipEsc, slipEscEsc)
+			default:
+				dst = append(dst, b)
+			}
+		}
+		return dst
+	},
+}
+
+// cobsStuffing is Consistent Overhead Byte Stuffing, as Cheshire and Baker describe it, with
+// frames ending in a zero byte. Each block opens with a code n, followed by n-1 nonzero bytes and,
+// unless n is 0xFF or the block ends the frame, a zero.
+var cobsStuffing = stuffing{
+	name:  "COBS",
+	delim: 0x00,
+	unstuff: func(dst, raw []byte) ([]byte, error) {
+		for i := 0; i < len(raw); {
+			code := int(raw[i])
+			if i+code > len(raw) {
+				return dst, fmt.Errorf("%w COBS block of %d bytes overruns its frame by %d", ErrFrame, code, i+code-len(raw))
+			}
+			dst = append(dst, raw[i+1:i+code]...)
+			if i += code; code < 0xFF && i < len(raw) {
+				dst = append(dst, 0)
+			}
+		}
+		return dst, nil
+	},
+	stuff: func(dst, payload []byte) []byte {
+		// code is where the current block's code goes, filled in once its length's known
+		code := len(dst)
+		dst = append(dst, 0)
+		for i, b := range payload {
+			if b == 0 {
+				dst[code] = byte(len(dst) - code)
+				code = len(dst)
+				dst = append(dst, 0)
+				continue
+			}
+
+			// A full block has no zero after it, so only opens another should more follow
+			if dst = append(dst, b); len(dst)-code == 0xFF && i+1 < len(payload) {
+				dst[code] = 0xFF
+				code = len(dst)
+				dst = append(dst, 0)
+			}
+		}
+		dst[code] = byte(len(dst) - code)
+		return dst
+	},
+}
+
+// FrameReader reads the frames of a byte-stuffed serial link one at a time, unstuffing them, so a
+// tagged struct can be decoded directly from one. Read reads the current frame, returning io.EOF
+// at its end, and Next moves on to the following one.
+//
+// Empty frames are skipped, whatever the framing. HDLC and SLIP can't tell one from a run of
+// delimiters between frames, as line noise is flushed with, so COBS's are treated the same way,
+// and a payload of nothing never reaches a Decoder.
+//
+// A frame that can't be unstuffed fails Next with ErrFrame, having been consumed, so calling Next
+// again resynchronises on the frame after it. Within a frame, a Decoder's DecodeAll reads every
+// record, and WithResync recovers from bad ones.
+type FrameReader struct {
+	r     *bufio.Reader
+	stuff stuffing
+
+	// raw is the frame as read, and frame as unstuffed, buf what's left of it to Read
+	raw, frame, buf []byte
+	started         bool
+}
+
+// NewHDLCReader returns a FrameReader of the asynchronous HDLC frames of RFC 1662, as PPP sends
+// them. Frames are delimited by the flag 0x7E, and the checksum is left in the frame for a crc tag.
+func NewHDLCReader(r io.Reader) *FrameReader {
+	return newFrameReader(r, hdlcStuffing)
+}
+
+// NewSLIPReader returns a FrameReader of the SLIP frames of RFC 1055, delimited by 0xC0
+func NewSLIPReader(r io.Reader) *FrameReader {
+	return newFrameReader(r, slipStuffing)
+}
+
+// NewCOBSReader returns a FrameReader of COBS encoded frames, each ended by a zero byte
+func NewCOBSReader(r io.Reader) *FrameReader {
+	return newFrameReader(r, cobsStuffing)
+}
+
+func newFrameReader(r io.Reader, s stuffing) *FrameReader {
+	return &FrameReader{r: bufio.NewReader(guardProgress(r)), stuff: s}
+}
+
+// Next discards what's left of the current frame and moves to the next, returning io.EOF once
+// the link's exhausted, or io.ErrUnexpectedEOF should it end partway through a frame.
+func (f *FrameReader) Next() error {
+	f.started = true
+	f.frame, f.buf = f.frame[:0], nil
+	for {
+		f.raw = f.raw[:0]
+		var err error
+		for {
+			var part []byte
+			part, err = f.r.ReadSlice(f.stuff.delim)
+			f.raw = append(f.raw, part...)
+			if err != bufio.ErrBufferFull {
+				break
+			}
+		}
+
+		switch {
+		case err == io.EOF && len(f.raw) == 0:
+			return io.EOF
+		case err == io.EOF:
+			return io.ErrUnexpectedEOF
+		case err != nil:
+			return err
+		case len(f.raw) == 1:
+			// Delimiters between frames, or leading them
+			continue
+		}
+
+		if f.frame, err = f.stuff.unstuff(f.frame[:0], f.raw[:len(f.raw)-1]); err != nil {
+			f.frame = f.frame[:0]
+			return err
+		} else if len(f.frame) == 0 {
+			// Empty frames, such as COBS's 0x01 0x00, are skipped like delimiter runs
+			continue
+		}
+		f.buf = f.frame
+		return nil
+	}
+}
+
+// Frame returns the whole of the current frame, valid until the next call to Next
+func (f *FrameReader) Frame() []byte {
+	return f.frame
+}
+
+// Read reads the current frame, returning io.EOF at its end. Before the first call to Next, it
+// moves to the first frame itself.
+func (f *FrameReader) Read(bs []byte) (int, error) {
+	if !f.started {
+		if err := f.Next(); err != nil {
+			return 0, err
+		}
+	}
+	if len(f.buf) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(bs, f.buf)
+	f.buf = f.buf[n:]
+	return n, nil
+}
+
+// FrameWriter writes frames to a byte-stuffed serial link. What's written is buffered until
+// EndFrame stuffs and delimits it, so an Encoder can write a struct to one frame.
+type FrameWriter struct {
+	w     io.Writer
+	stuff stuffing
+
+	// payload is what's been written since the last frame, out the frame made of it
+	payload, out []byte
+}
+
+// NewHDLCWriter returns a FrameWriter of asynchronous HDLC frames, as NewHDLCReader reads them
+func NewHDLCWriter(w io.Writer) *FrameWriter {
+	return &FrameWriter{w: w, stuff: hdlcStuffing}
+}
+
+// NewSLIPWriter returns a FrameWriter of SLIP frames, as NewSLIPReader reads them
+func NewSLIPWriter(w io.Writer) *FrameWriter {
+	return &FrameWriter{w: w, stuff: slipStuffing}
+}
+
+// NewCOBSWriter returns a FrameWriter of COBS encoded frames, as NewCOBSReader reads them
+func NewCOBSWriter(w io.Writer) *FrameWriter {
+	return &FrameWriter{w: w, stuff: cobsStuffing}
+}
+
+func (f *FrameWriter) Write(bs []byte) (int, error) {
+	f.payload = append(f.payload, bs...)
+	return len(bs), nil
+}
+
+// EndFrame writes what's been written since the last frame as a frame of its own. Should nothing
+// have been, it writes an empty frame, which FrameReaders skip, so it can flush line noise or
+// keep a link alive without being read as a frame.
+func (f *FrameWriter) EndFrame() error {
+	f.out = f.out[:0]
+	if f.stuff.lead {
+		f.out = append(f.out, f.stuff.delim)
+	}
+	f.out = append(f.stuff.stuff(f.out, f.payload), f.stuff.delim)
+	f.payload = f.payload[:0]
+	_, err := writeFull(f.w, f.out)
+	return err
+}
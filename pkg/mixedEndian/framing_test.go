@@ -0,0 +1,224 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+type StuffedStruct struct {
+	Kind uint8
+	Seq  uint16
+	Body [3]byte
+}
+
+// byteRun is the bytes from first to last, inclusive
+func byteRun(first, last int) []byte {
+	bs := make([]byte, 0, last-first+1)
+	for b := first; b <= last; b++ {
+		bs = append(bs, byte(b))
+	}
+	return bs
+}
+
+func cat(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+func TestFrameVectors(t *testing.T) {
+	tests := []struct {
+		name    string
+		reader  func(io.Reader) *FrameReader
+		writer  func(io.Writer) *FrameWriter
+		payload []byte
+		wire    []byte
+	}{
+		// The examples of Cheshire and Baker's COBS paper, maximal 254 byte runs included
+		{"cobs empty", NewCOBSReader, NewCOBSWriter, []byte{}, []byte{0x01, 0x00}},
+		{"cobs zero", NewCOBSReader, NewCOBSWriter, []byte{0x00}, []byte{0x01, 0x01, 0x00}},
+		{"cobs zeros", NewCOBSReader, NewCOBSWriter, []byte{0x00, 0x00}, []byte{0x01, 0x01, 0x01, 0x00}},
+		{"cobs inner", NewCOBSReader, NewCOBSWriter, []byte{0x00, 0x11, 0x00}, []byte{0x01, 0x02, 0x11, 0x01, 0x00}},
+		{"cobs mixed", NewCOBSReader, NewCOBSWriter, []byte{0x11, 0x22, 0x00, 0x33}, []byte{0x03, 0x11, 0x22, 0x02, 0x33, 0x00}},
+		{"cobs nonzero", NewCOBSReader, NewCOBSWriter, []byte{0x11, 0x22, 0x33, 0x44}, []byte{0x05, 0x11, 0x22, 0x33, 0x44, 0x00}},
+		{"cobs trailing zeros", NewCOBSReader, NewCOBSWriter, []byte{0x11, 0x00, 0x00, 0x00}, []byte{0x02, 0x11, 0x01, 0x01, 0x01, 0x00}},
+		{"cobs 254", NewCOBSReader, NewCOBSWriter, byteRun(0x01, 0xFE), cat([]byte{0xFF}, byteRun(0x01, 0xFE), []byte{0x00})},
+		{"cobs zero then 254", NewCOBSReader, NewCOBSWriter, byteRun(0x00, 0xFE), cat([]byte{0x01, 0xFF}, byteRun(0x01, 0xFE), []byte{0x00})},
+		{"cobs 255", NewCOBSReader, NewCOBSWriter, byteRun(0x01, 0xFF), cat([]byte{0xFF}, byteRun(0x01, 0xFE), []byte{0x02, 0xFF, 0x00})},
+		{"cobs 254 then zero", NewCOBSReader, NewCOBSWriter, cat(byteRun(0x02, 0xFF), []byte{0x00}), cat([]byte{0xFF}, byteRun(0x02, 0xFF), []byte{0x01, 0x01, 0x00})},
+		{"cobs 253 zero one", NewCOBSReader, NewCOBSWriter, cat(byteRun(0x03, 0xFF), []byte{0x00, 0x01}), cat([]byte{0xFE}, byteRun(0x03, 0xFF), []byte{0x02, 0x01, 0x00})},
+
+		// RFC 1055's END and ESC, escaped
+		{"slip plain", NewSLIPReader, NewSLIPWriter, []byte{0x01, 0x02}, []byte{0xC0, 0x01, 0x02, 0xC0}},
+		{"slip escapes", NewSLIPReader, NewSLIPWriter, []byte{0xC0, 0x01, 0xDB, 0xDC}, []byte{0xC0, 0xDB, 0xDC, 0x01, 0xDB, 0xDD, 0xDC, 0xC0}},
+
+		// RFC 1662's flag, escape, and control characters in the default async map
+		{"hdlc plain", NewHDLCReader, NewHDLCWriter, []byte{0xFF, 0x03, 0xC0, 0x21}, []byte{0x7E, 0xFF, 0x7D, 0x23, 0xC0, 0x21, 0x7E}},
+		{"hdlc escapes", NewHDLCReader, NewHDLCWriter, []byte{0x7E, 0x7D, 0x20, 0x5E}, []byte{0x7E, 0x7D, 0x5E, 0x7D, 0x5D, 0x20, 0x5E, 0x7E}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			fw := tt.writer(buf)
+			fw.Write(tt.payload)
+			if err := fw.EndFrame(); err != nil {
+				t.Fatalf("EndFrame() error = %v", err)
+			}
+			if !bytes.Equal(buf.Bytes(), tt.wire) {
+				t.Errorf("EndFrame() wrote % X, wanted % X", buf.Bytes(), tt.wire)
+			}
+
+			fr := tt.reader(bytes.NewReader(tt.wire))
+			got, err := io.ReadAll(fr)
+			if err != nil {
+				t.Fatalf("ReadAll() error = %v", err)
+			}
+			if !bytes.Equal(got, tt.payload) {
+				t.Errorf("ReadAll() = % X, wanted % X", got, tt.payload)
+			}
+			if err = fr.Next(); err != io.EOF {
+				t.Errorf("Next() at end error = %v, wanted %v", err, io.EOF)
+			}
+		})
+	}
+}
+
+func TestFrameErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		reader func(io.Reader) *FrameReader
+		wire   []byte
+		want   error
+
+		// next is a frame of 0x42 following the bad one
+		next []byte
+	}{
+		{"hdlc abort", NewHDLCReader, []byte{0x7E, 0x01, 0x7D, 0x7E}, ErrFrame, []byte{0x42, 0x7E}},
+		{"slip trailing escape", NewSLIPReader, []byte{0xC0, 0x01, 0xDB, 0xC0}, ErrFrame, []byte{0x42, 0xC0}},
+		{"slip bad escape", NewSLIPReader, []byte{0xC0, 0xDB, 0x01, 0xC0}, ErrFrame, []byte{0x42, 0xC0}},
+		{"cobs overrun", NewCOBSReader, []byte{0x05, 0x11, 0x22, 0x00}, ErrFrame, []byte{0x02, 0x42, 0x00}},
+		{"unterminated", NewSLIPReader, []byte{0xC0, 0x01, 0x02}, io.ErrUnexpectedEOF, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fr := tt.reader(bytes.NewReader(cat(tt.wire, tt.next)))
+			if err := fr.Next(); !errors.Is(err, tt.want) {
+				t.Fatalf("Next() error = %v, wanted %v", err, tt.want)
+			}
+			if tt.next == nil {
+				return
+			}
+
+			// The frame after a bad one still reads
+			if err := fr.Next(); err != nil || !bytes.Equal(fr.Frame(), []byte{0x42}) {
+				t.Errorf("Next() = % X, %v; wanted 42", fr.Frame(), err)
+			}
+		})
+	}
+}
+
+func TestFrameDecode(t *testing.T) {
+	for name, pair := range map[string]struct {
+		reader func(io.Reader) *FrameReader
+		writer func(io.Writer) *FrameWriter
+	}{
+		"hdlc": {NewHDLCReader, NewHDLCWriter},
+		"slip": {NewSLIPReader, NewSLIPWriter},
+		"cobs": {NewCOBSReader, NewCOBSWriter},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Each struct in its own frame, with line noise of empty frames between
+			want := []StuffedStruct{{Kind: 0x7E, Seq: 0xC0DB, Body: [3]byte{0, 0x7D, 0}}, {Kind: 1, Seq: 2, Body: [3]byte{3, 4, 5}}}
+			buf := &bytes.Buffer{}
+			fw := pair.writer(buf)
+			enc := NewEncoder(fw, BigEndian)
+			for _, v := range want {
+				if err := enc.Encode(v); err != nil {
+					t.Fatalf("Encode() error = %v", err)
+				}
+				if err := fw.EndFrame(); err != nil {
+					t.Fatalf("EndFrame() error = %v", err)
+				}
+				buf.WriteByte(fw.stuff.delim)
+			}
+
+			fr := pair.reader(buf)
+			dec := NewDecoder(fr, BigEndian)
+			for i := range want {
+				if err := fr.Next(); err != nil {
+					t.Fatalf("Next() error = %v", err)
+				}
+				var got StuffedStruct
+				if err := dec.Decode(&got); err != nil {
+					t.Fatalf("Decode() error = %v", err)
+				}
+				if got != want[i] {
+					t.Errorf("Decode() = %+v, wanted %+v", got, want[i])
+				}
+			}
+			if err := fr.Next(); err != io.EOF {
+				t.Errorf("Next() at end error = %v, wanted %v", err, io.EOF)
+			}
+		})
+	}
+}
+
+func TestEmptyFrames(t *testing.T) {
+	tests := []struct {
+		name   string
+		reader func(io.Reader) *FrameReader
+		writer func(io.Writer) *FrameWriter
+		empty  []byte
+	}{
+		{"hdlc", NewHDLCReader, NewHDLCWriter, []byte{0x7E, 0x7E}},
+		{"slip", NewSLIPReader, NewSLIPWriter, []byte{0xC0, 0xC0}},
+		{"cobs", NewCOBSReader, NewCOBSWriter, []byte{0x01, 0x00}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Empty frames are written, but never read
+			buf := &bytes.Buffer{}
+			fw := tt.writer(buf)
+			if err := fw.EndFrame(); err != nil {
+				t.Fatalf("EndFrame() error = %v", err)
+			}
+			if !bytes.Equal(buf.Bytes(), tt.empty) {
+				t.Errorf("EndFrame() wrote % X, wanted % X", buf.Bytes(), tt.empty)
+			}
+			fw.Write([]byte{0x42})
+			if err := fw.EndFrame(); err != nil {
+				t.Fatalf("EndFrame() error = %v", err)
+			}
+			if err := fw.EndFrame(); err != nil {
+				t.Fatalf("EndFrame() error = %v", err)
+			}
+
+			fr := tt.reader(buf)
+			if err := fr.Next(); err != nil || !bytes.Equal(fr.Frame(), []byte{0x42}) {
+				t.Errorf("Next() = % X, %v; wanted 42", fr.Frame(), err)
+			}
+			if err := fr.Next(); err != io.EOF {
+				t.Errorf("Next() after empty frames error = %v, wanted %v", err, io.EOF)
+			}
+		})
+	}
+}
+
+func TestFrameDecodeAll(t *testing.T) {
+	// A frame of several records, one bad, recovered from within the frame
+	buf := &bytes.Buffer{}
+	fw := NewCOBSWriter(buf)
+	fw.Write(records([]uint16{10, 11, 12}, map[int]bool{1: true}))
+	if err := fw.EndFrame(); err != nil {
+		t.Fatalf("EndFrame() error = %v", err)
+	}
+
+	var got []RecordStruct
+	bad, err := NewDecoder(NewCOBSReader(buf), BigEndian, WithResync([]byte{0xAA, 0x55})).DecodeAll(&got)
+	if err != nil {
+		t.Fatalf("DecodeAll() error = %v", err)
+	}
+	if len(got) != 2 || got[1].Value != 12 || len(bad) != 1 || bad[0].Index != 1 {
+		t.Errorf("DecodeAll() = %+v, %v; wanted records 10 and 12, and 11 bad", got, bad)
+	}
+}
@@ -0,0 +1,197 @@
+package mixedEndian
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// alawTable and ulawTable expand each G.711 code to 16 bit linear PCM
+var alawTable, ulawTable = func() (a, u [256]int16) {
+	for i := range a {
+		// A-law flips every even bit, then holds sign, a 3 bit segment, and a 4 bit step
+		c := byte(i) ^ 0x55
+		t := int16(c&0x0F)<<4 + 8
+		if seg := c & 0x70 >> 4; seg > 0 {
+			t = (t + 0x100) << (seg - 1)
+		}
+		if c&0x80 == 0 {
+			t = -t
+		}
+		a[i] = t
+
+		// µ-law is inverted, and biased by 0x84 so every segment starts at a power of 2
+		c = ^byte(i)
+		t = (int16(c&0x0F)<<3 + 0x84) << (c & 0x70 >> 4)
+		if c&0x80 != 0 {
+			u[i] = 0x84 - t
+		} else {
+			u[i] = t - 0x84
+		}
+	}
+	return
+}()
+
+// linearToALaw compresses 16 bit linear sample s to A-law, as the G.711 reference encoder does
+func linearToALaw(s int16) byte {
+	// A-law carries 13 bits
+	v, mask := int(s)>>3, byte(0xD5)
+	if v < 0 {
+		v, mask = -v-1, 0x55
+	}
+
+	seg := byte(0)
+	for end := 0x1F; v > end; end = end<<1 | 1 {
+		if seg++; seg == 8 {
+			return 0x7F ^ mask
+		}
+	}
+	shift := seg
+	if shift < 2 {
+		shift = 1
+	}
+	return (seg<<4 | byte(v>>shift)&0x0F) ^ mask
+}
+
+// linearToULaw compresses 16 bit linear sample s to µ-law, as the G.711 reference encoder does
+func linearToULaw(s int16) byte {
+	// µ-law carries 14 bits
+	v, mask := int(s)>>2, byte(0xFF)
+	if v < 0 {
+		v, mask = -v, 0x7F
+	}
+	if v > 8159 {
+		v = 8159
+	}
+	v += 0x84 >> 2
+
+	seg := byte(0)
+	for end := 0x3F; v > end; end = end<<1 | 1 {
+		if seg++; seg == 8 {
+			return 0x7F ^ mask
+		}
+	}
+	return (seg<<4 | byte(v>>(seg+1))&0x0F) ^ mask
+}
+
+// pcmCodec is a G.711 law: its expansion table and compressor
+type pcmCodec struct {
+	table    *[256]int16
+	compress func(int16) byte
+}
+
+var pcmCodecs = map[string]pcmCodec{
+	"alaw": {&alawTable, linearToALaw},
+	"ulaw": {&ulawTable, linearToULaw},
+}
+
+// isPCM reports whether sf holds G.711 companded samples
+func isPCM(sf reflect.StructField) bool {
+	return sf.Tag.Get("pcm") != ""
+}
+
+// pcmFormat resolves the law named by sf's pcm tag, checking sf is an int16 or float32, or an
+// array or slice of them
+func pcmFormat(sf reflect.StructField) (pcmCodec, error) {
+	c, ok := pcmCodecs[sf.Tag.Get("pcm")]
+	if !ok {
+		return c, fmt.Errorf("%w Unknown pcm %q", ErrTag, sf.Tag.Get("pcm"))
+	}
+
+	t := sf.Type
+	if k := t.Kind(); k == reflect.Array || k == reflect.Slice {
+		t = t.Elem()
+	}
+	if k := t.Kind(); k != reflect.Int16 && k != reflect.Float32 {
+		return c, fmt.Errorf("%w pcm needs int16 or float32 samples; Got %s", ErrUnexpectedType, sf.Type.String())
+	}
+	return c, nil
+}
+
+// readPCM reads sample f, or each sample of array or slice f, already sized, as a byte each.
+// float32 samples are scaled to [-1, 1).
+func (r *reader) readPCM(sf reflect.StructField, f reflect.Value) error {
+	c, err := pcmFormat(sf)
+	if err != nil {
+		return err
+	}
+
+	n, at := normElems(f)
+	bs, err := r.next(n)
+	if err != nil {
+		return err
+	}
+
+	// Plain slices skip reflection per sample
+	if f.Kind() == reflect.Slice && f.CanInterface() {
+		switch s := f.Interface().(type) {
+		case []int16:
+			for i, b := range bs {
+				s[i] = c.table[b]
+			}
+			return nil
+		case []float32:
+			for i, b := range bs {
+				s[i] = float32(c.table[b]) / 32768
+			}
+			return nil
+		}
+	}
+
+	for i, b := range bs {
+		if v := at(i); v.Kind() == reflect.Int16 {
+			v.SetInt(int64(c.table[b]))
+		} else {
+			v.SetFloat(float64(c.table[b]) / 32768)
+		}
+	}
+	return nil
+}
+
+// writePCM writes sample f, or each sample of array or slice f, compressed to a byte each.
+// float32 samples are clamped to [-1, 1), NaN written as silence.
+func (w *writer) writePCM(sf reflect.StructField, f reflect.Value) error {
+	c, err := pcmFormat(sf)
+	if err != nil {
+		return err
+	}
+
+	n, at := normElems(f)
+	if err = w.room(n); err != nil {
+		return err
+	}
+	bs := make([]byte, n)
+	var s any
+	if f.CanInterface() {
+		s = f.Interface()
+	}
+	switch s := s.(type) {
+	case []int16:
+		for i, v := range s {
+			bs[i] = c.compress(v)
+		}
+	case []float32:
+		for i, v := range s {
+			bs[i] = c.compress(floatSample(float64(v)))
+		}
+	default:
+		for i := range bs {
+			if v := at(i); v.Kind() == reflect.Int16 {
+				bs[i] = c.compress(int16(v.Int()))
+			} else {
+				bs[i] = c.compress(floatSample(v.Float()))
+			}
+		}
+	}
+
+	_, err = w.w.Write(bs)
+	return err
+}
+
+// floatSample is 16 bit linear sample x, scaled from [-1, 1)
+func floatSample(x float64) int16 {
+	if math.IsNaN(x) {
+		return 0
+	}
+	return int16(math.Max(math.Min(math.Round(x*32768), math.MaxInt16), math.MinInt16))
+}
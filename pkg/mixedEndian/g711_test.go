@@ -0,0 +1,150 @@
+package mixedEndian
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type Capture struct {
+	N       uint16
+	Samples []int16 `pcm:"ulaw" len:"N"`
+}
+
+type FloatCapture struct {
+	Samples [4]float32 `pcm:"alaw"`
+}
+
+// g711Reference is testdata/g711.txt: the linear value of every code, and where each code's run of
+// samples starts when encoding, for each law
+type g711Reference struct {
+	decode map[string]*[256]int16
+	encode map[string][][2]int
+}
+
+func loadG711Reference(t *testing.T) g711Reference {
+	f, err := os.Open(filepath.Join("testdata", "g711.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	ref := g711Reference{
+		decode: map[string]*[256]int16{"alaw": {}, "ulaw": {}},
+		encode: map[string][][2]int{},
+	}
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if bytes.HasPrefix(sc.Bytes(), []byte("#")) {
+			continue
+		}
+		var op, law string
+		var a, b int
+		if _, err := fmt.Sscan(sc.Text(), &op, &law, &a, &b); err != nil {
+			t.Fatalf("Bad reference line %q: %v", sc.Text(), err)
+		}
+		if op == "decode" {
+			ref.decode[law][a] = int16(b)
+		} else {
+			ref.encode[law] = append(ref.encode[law], [2]int{a, b})
+		}
+	}
+	return ref
+}
+
+func TestG711Reference(t *testing.T) {
+	ref := loadG711Reference(t)
+	for law, c := range pcmCodecs {
+		t.Run(law, func(t *testing.T) {
+			for code, want := range ref.decode[law] {
+				if got := c.table[code]; got != want {
+					t.Errorf("Expanding %#02x = %d, wanted %d", code, got, want)
+				}
+			}
+
+			runs := ref.encode[law]
+			for i, run := range runs {
+				end := 32768
+				if i+1 < len(runs) {
+					end = runs[i+1][0]
+				}
+				for s := run[0]; s < end; s++ {
+					if got := c.compress(int16(s)); int(got) != run[1] {
+						t.Fatalf("Compressing %d = %#02x, wanted %#02x", s, got, run[1])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPCMReadWrite(t *testing.T) {
+	wire := []byte{0x00, 0x04, 0x00, 0x7F, 0x80, 0xFF}
+	var data any = &Capture{}
+	if err := Read(bytes.NewReader(wire), BigEndian, &data); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := &Capture{N: 4, Samples: []int16{-32124, 0, 32124, 0}}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("Read() = %v, wanted %v", data, want)
+	}
+
+	// Both µ-law zeroes are written as the positive one
+	got, err := Marshal(BigEndian, want)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if w := []byte{0x00, 0x04, 0x00, 0xFF, 0x80, 0xFF}; !bytes.Equal(got, w) {
+		t.Errorf("Marshal() = % X, wanted % X", got, w)
+	}
+
+	fc := FloatCapture{Samples: [4]float32{-1, 0, 0.5, 2}}
+	if got, err = Marshal(BigEndian, fc); err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if w := []byte{0x2A, 0xD5, 0xA5, 0xAA}; !bytes.Equal(got, w) {
+		t.Errorf("Marshal() = % X, wanted % X", got, w)
+	}
+	data = &FloatCapture{}
+	if err = Read(bytes.NewReader(got), BigEndian, &data); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if w := [4]float32{-32256.0 / 32768, 8.0 / 32768, 16896.0 / 32768, 32256.0 / 32768}; data.(*FloatCapture).Samples != w {
+		t.Errorf("Read() = %v, wanted %v", data.(*FloatCapture).Samples, w)
+	}
+}
+
+func TestPCMErrors(t *testing.T) {
+	type unknown struct {
+		S int16 `pcm:"g722"`
+	}
+	if _, err := Marshal(BigEndian, unknown{}); !errors.Is(err, ErrTag) {
+		t.Errorf("Marshal() error = %v, wanted %v", err, ErrTag)
+	}
+	type wrongType struct {
+		S []int32 `pcm:"alaw" count:"2"`
+	}
+	if _, err := Marshal(BigEndian, wrongType{S: make([]int32, 2)}); !errors.Is(err, ErrUnexpectedType) {
+		t.Errorf("Marshal() error = %v, wanted %v", err, ErrUnexpectedType)
+	}
+}
+
+func BenchmarkReadPCM(b *testing.B) {
+	wire := make([]byte, 2+8000)
+	wire[0], wire[1] = 0x1F, 0x40
+	for i := range wire[2:] {
+		wire[2+i] = byte(i)
+	}
+	b.SetBytes(int64(len(wire)))
+	for i := 0; i < b.N; i++ {
+		var c Capture
+		if err := Unmarshal(BigEndian, wire, &c); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
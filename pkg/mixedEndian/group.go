@@ -0,0 +1,248 @@
+package mixedEndian
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// fieldGroup is a run of fields tagged with the same group name, which DecodeGroup reads apart
+// from the rest of their struct
+type fieldGroup struct {
+	name     string
+	from, to int
+}
+
+// parseGroups collects the field groups of struct t. Once any field of t is grouped, every
+// field on the wire must be, each group a run of adjacent fields.
+func parseGroups(t reflect.Type, so structOptions) (groups []fieldGroup, err error) {
+	last := -1
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := sf.Tag.Get("group")
+		if !sf.IsExported() || sf.Name == "_" {
+			if name != "" {
+				return nil, fmt.Errorf("%s: %w group needs an exported field", sf.Name, ErrTag)
+			}
+			continue
+		}
+		if name == "" {
+			if len(groups) > 0 {
+				return nil, fmt.Errorf("%s: %w Field is in no group, though %s groups its fields", sf.Name, ErrTag, t.String())
+			}
+			last = i
+			continue
+		}
+		if last >= 0 && len(groups) == 0 {
+			return nil, fmt.Errorf("%s: %w Field is in no group, though %s groups its fields", t.Field(last).Name, ErrTag, t.String())
+		}
+
+		switch n := len(groups); {
+		case n > 0 && groups[n-1].name == name:
+			groups[n-1].to = i
+		case groupNamed(groups, name) >= 0:
+			return nil, fmt.Errorf("%s: %w group %q is not a run of adjacent fields", sf.Name, ErrTag, name)
+		default:
+			groups = append(groups, fieldGroup{name: name, from: i, to: i})
+		}
+		last = i
+	}
+	if len(groups) == 0 {
+		return nil, nil
+	}
+
+	// Groups are read alone, so can't split what's read as a whole
+	if so.align > 0 || len(so.scopes) > 0 || so.iso8583 {
+		return nil, fmt.Errorf("%w %s can't have groups and be aligned, ISO 8583, or have length scopes", ErrTag, t.String())
+	}
+	for _, og := range so.overlays {
+		if groupOf(groups, og.fields[0]) != groupOf(groups, og.fields[len(og.fields)-1]) {
+			return nil, fmt.Errorf("%w Overlay %q spans groups", ErrTag, og.name)
+		}
+	}
+	return groups, nil
+}
+
+// groupNamed is the index of the group called name, or -1
+func groupNamed(groups []fieldGroup, name string) int {
+	for i, g := range groups {
+		if g.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// groupOf is the index of the group holding field i, or -1
+func groupOf(groups []fieldGroup, i int) int {
+	for j, g := range groups {
+		if g.from <= i && i <= g.to {
+			return j
+		}
+	}
+	return -1
+}
+
+// groupStage is where a Decoder is within a struct it's reading a group at a time
+type groupStage struct {
+	t    reflect.Type
+	next int
+}
+
+// DecodeGroup reads only the fields of the struct dst points to tagged `group:"name"`, leaving
+// the rest alone and the Decoder's io.Reader positioned for the group that follows:
+//
+//	type message struct {
+//		Kind   uint8  `group:"header"`
+//		Length uint16 `group:"header"`
+//		Body   []byte `group:"body" len:"Length"`
+//	}
+//
+// Groups are read in order, each by DecodeGroup or passed over by SkipGroup, and once the last
+// group's done the next call starts on the next struct. Asking for any group but the next
+// gives ErrLayout. Fields of later groups may refer to those of earlier ones, such as for their
+// lengths, as they would were the struct read whole.
+//
+// Once any field of a struct is grouped every field on the wire must be, each group a run of
+// adjacent fields, or reading it fails with ErrTag. Structs grouping their fields can't be
+// aligned, ISO 8583, or have length scopes, and those with checksums or raw fields can't be read
+// a group at a time.
+func (d *Decoder) DecodeGroup(dst any, name string) error {
+	v, so, g, err := d.group(dst, name)
+	if err != nil {
+		return err
+	}
+
+	start := d.in.n
+	for i := g.from; i <= g.to; i++ {
+		if f := v.Field(i); f.CanSet() && v.Type().Field(i).Name != "_" {
+			if err = d.dec.readFieldAt(v, so, i, d.dec.o); err != nil {
+				break
+			}
+		}
+	}
+	return d.endGroup(so, g, start, err)
+}
+
+// SkipGroup passes over the group called name of the struct dst points to, as DecodeGroup would
+// read it, but leaving dst alone. Groups of a fixed size are skipped without being decoded.
+func (d *Decoder) SkipGroup(dst any, name string) error {
+	v, so, g, err := d.group(dst, name)
+	if err != nil {
+		return err
+	}
+
+	start := d.in.n
+	if n := groupSize(v.Type(), so, g); n >= 0 {
+		if err = d.dec.ctx.Err(); err == nil {
+			_, err = io.CopyN(io.Discard, d.dec.r, int64(n))
+		}
+		return d.endGroup(so, g, start, err)
+	}
+
+	// Variable sized groups are read into a copy, which may take their sizes from dst's fields
+	scratch := reflect.New(v.Type()).Elem()
+	scratch.Set(v)
+	for i := g.from; i <= g.to; i++ {
+		if f := scratch.Field(i); f.CanSet() && v.Type().Field(i).Name != "_" {
+			if err = d.dec.readFieldAt(scratch, so, i, d.dec.o); err != nil {
+				break
+			}
+		}
+	}
+	return d.endGroup(so, g, start, err)
+}
+
+// group resolves the struct dst points to, and its group called name, checking it's the next
+func (d *Decoder) group(dst any, name string) (v reflect.Value, so structOptions, g fieldGroup, err error) {
+	v = reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return v, so, g, fmt.Errorf("%w Expected pointer to struct; Got %T", ErrUnexpectedType, dst)
+	}
+	if d.strict {
+		if err = checkStrict(v.Type()); err != nil {
+			return
+		}
+	}
+	if d.dec.overrides != nil {
+		if err = d.dec.overrides.check(v.Type()); err != nil {
+			return
+		}
+	}
+	v = v.Elem()
+	t := v.Type()
+
+	if so, err = optionsOf(t); err != nil {
+		return
+	}
+	if cks, err := checksumsOf(t); err != nil {
+		return v, so, g, err
+	} else if raws, err := rawFieldsOf(t); err != nil {
+		return v, so, g, err
+	} else if len(cks) > 0 || len(raws) > 0 {
+		return v, so, g, fmt.Errorf("%w %s has checksums or raw fields, so is only read whole", ErrLayout, t.String())
+	}
+
+	i := groupNamed(so.groups, name)
+	if i < 0 {
+		return v, so, g, fmt.Errorf("%w %s has no group %q", ErrTag, t.String(), name)
+	}
+	g = so.groups[i]
+
+	// Groups follow each other, starting over once the last is read
+	if d.stage.t != t {
+		d.stage = groupStage{t: t}
+	}
+	next := 0
+	for so.groups[next].from < d.stage.next {
+		next++
+	}
+	if next != i {
+		return v, so, g, fmt.Errorf("%w Group %q of %s is next, not %q", ErrLayout, so.groups[next].name, t.String(), name)
+	}
+	return
+}
+
+// endGroup notes group g of a struct with options so has been read from start, wrapping err as
+// Decode would. Only a struct's first group may end cleanly at the end of the input.
+func (d *Decoder) endGroup(so structOptions, g fieldGroup, start int64, err error) error {
+	if err == io.EOF && (d.in.n != start || g.from != so.groups[0].from) {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return readError(d.seeker, start, d.in.n, err)
+	}
+
+	d.stage.next = g.to + 1
+	if g.to == so.groups[len(so.groups)-1].to {
+		d.stage = groupStage{}
+	}
+	return nil
+}
+
+// groupSize is the number of bytes group g of struct t always takes, or -1 if that varies
+func groupSize(t reflect.Type, so structOptions, g fieldGroup) int {
+	size := 0
+	for i := g.from; i <= g.to; i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() || sf.Name == "_" {
+			continue
+		}
+		fl := FieldLayout{Name: sf.Name, Type: sf.Type, Tag: sf.Tag}
+		if err := describeField(&fl, sf); err != nil || fl.Size < 0 {
+			return -1
+		}
+		before, after, err := fieldPads(sf)
+		if err != nil {
+			return -1
+		}
+
+		// Overlay members share the region of their group, which takes the largest's size
+		if og, ok := so.overlayOf(i); ok && og.fields[0] == i {
+			size += og.size
+		} else if !ok {
+			size += before + fl.Size + after
+		}
+	}
+	return size
+}
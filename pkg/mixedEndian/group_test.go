@@ -0,0 +1,135 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+type StagedRecord struct {
+	Kind   uint8  `group:"header"`
+	Length uint16 `group:"header"`
+	Flags  uint8  `group:"meta"`
+	Stamp  uint32 `group:"meta" endian:"little"`
+	Body   []byte `group:"body" len:"Length"`
+}
+
+func stagedRecords(t *testing.T, recs ...StagedRecord) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	enc := NewEncoder(buf, BigEndian)
+	for _, rec := range recs {
+		if err := enc.Encode(rec); err != nil {
+			t.Fatalf("Encode() error = %v", err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestDecodeGroup(t *testing.T) {
+	recs := []StagedRecord{
+		{Kind: 1, Length: 3, Flags: 0x10, Stamp: 100, Body: []byte{1, 2, 3}},
+		{Kind: 2, Length: 5, Flags: 0x20, Stamp: 200, Body: []byte{4, 5, 6, 7, 8}},
+		{Kind: 3, Length: 1, Flags: 0x30, Stamp: 300, Body: []byte{9}},
+	}
+	dec := NewDecoder(bytes.NewReader(stagedRecords(t, recs...)), BigEndian)
+
+	// Every group of the first
+	var got StagedRecord
+	for _, g := range []string{"header", "meta", "body"} {
+		if err := dec.DecodeGroup(&got, g); err != nil {
+			t.Fatalf("DecodeGroup(%q) error = %v", g, err)
+		}
+	}
+	if !reflect.DeepEqual(got, recs[0]) {
+		t.Errorf("DecodeGroup() = %+v, wanted %+v", got, recs[0])
+	}
+
+	// Only the header of the second, its fixed size meta and sized body skipped
+	got = StagedRecord{}
+	if err := dec.DecodeGroup(&got, "header"); err != nil {
+		t.Fatalf("DecodeGroup(header) error = %v", err)
+	}
+	if err := dec.SkipGroup(&got, "meta"); err != nil {
+		t.Fatalf("SkipGroup(meta) error = %v", err)
+	}
+	if err := dec.SkipGroup(&got, "body"); err != nil {
+		t.Fatalf("SkipGroup(body) error = %v", err)
+	}
+	if want := (StagedRecord{Kind: 2, Length: 5}); !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeGroup() = %+v, wanted %+v", got, want)
+	}
+
+	// The meta skipped over, but not the body of the third
+	got = StagedRecord{}
+	if err := dec.DecodeGroup(&got, "header"); err != nil {
+		t.Fatalf("DecodeGroup(header) error = %v", err)
+	}
+	if err := dec.SkipGroup(&got, "meta"); err != nil {
+		t.Fatalf("SkipGroup(meta) error = %v", err)
+	}
+	if err := dec.DecodeGroup(&got, "body"); err != nil {
+		t.Fatalf("DecodeGroup(body) error = %v", err)
+	}
+	if want := (StagedRecord{Kind: 3, Length: 1, Body: []byte{9}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeGroup() = %+v, wanted %+v", got, want)
+	}
+
+	// The input ends cleanly between records
+	if err := dec.DecodeGroup(&got, "header"); err != io.EOF {
+		t.Errorf("DecodeGroup() at end error = %v, wanted %v", err, io.EOF)
+	}
+}
+
+func TestDecodeGroupErrors(t *testing.T) {
+	wire := stagedRecords(t, StagedRecord{Kind: 1, Length: 2, Body: []byte{1, 2}})
+
+	// Groups are taken in order
+	var got StagedRecord
+	dec := NewDecoder(bytes.NewReader(wire), BigEndian)
+	if err := dec.DecodeGroup(&got, "body"); !errors.Is(err, ErrLayout) {
+		t.Errorf("DecodeGroup(body) first error = %v, wanted %v", err, ErrLayout)
+	}
+	if err := dec.DecodeGroup(&got, "header"); err != nil {
+		t.Fatalf("DecodeGroup(header) error = %v", err)
+	}
+	if err := dec.DecodeGroup(&got, "header"); !errors.Is(err, ErrLayout) {
+		t.Errorf("DecodeGroup(header) again error = %v, wanted %v", err, ErrLayout)
+	}
+	if err := dec.SkipGroup(&got, "footer"); !errors.Is(err, ErrTag) {
+		t.Errorf("SkipGroup(footer) error = %v, wanted %v", err, ErrTag)
+	}
+
+	// A record cut short within a group, or between them
+	for _, n := range []int{2, 3, 7} {
+		dec = NewDecoder(bytes.NewReader(wire[:n]), BigEndian)
+		var err error
+		for _, g := range []string{"header", "meta", "body"} {
+			if err = dec.DecodeGroup(&got, g); err != nil {
+				break
+			}
+		}
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("DecodeGroup() of %d bytes error = %v, wanted %v", n, err, io.ErrUnexpectedEOF)
+		}
+	}
+
+	// Groups must be runs of adjacent fields, and cover every field once any does
+	type split struct {
+		A uint8 `group:"a"`
+		B uint8 `group:"b"`
+		C uint8 `group:"a"`
+	}
+	type partial struct {
+		A uint8 `group:"a"`
+		B uint8
+	}
+	for name, v := range map[string]any{"split": &split{}, "partial": &partial{}} {
+		var data any = v
+		if err := Read(bytes.NewReader(make([]byte, 3)), BigEndian, &data); !errors.Is(err, ErrTag) {
+			t.Errorf("%s: Read() error = %v, wanted %v", name, err, ErrTag)
+		}
+	}
+}
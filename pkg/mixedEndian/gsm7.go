@@ -0,0 +1,183 @@
+package mixedEndian
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// gsm7Basic is the GSM 03.38 default alphabet, indexed by septet.
+// 0x1B escapes to gsm7Extension and has no character of its own.
+var gsm7Basic = []rune("@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞ\x1BÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+	"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà")
+
+// gsm7Extension holds the characters reached through the escape septet
+var gsm7Extension = map[byte]rune{
+	0x0A: '\f',
+	0x14: '^',
+	0x28: '{',
+	0x29: '}',
+	0x2F: '\\',
+	0x3C: '[',
+	0x3D: '~',
+	0x3E: ']',
+	0x40: '|',
+	0x65: '€',
+}
+
+const gsm7Escape = 0x1B
+
+// gsm7Encoding maps characters back to their septets, escaped ones being two long
+var gsm7Encoding = func() map[rune][]byte {
+	m := map[rune][]byte{}
+	for i, c := range gsm7Basic {
+		if i != gsm7Escape {
+			m[c] = []byte{byte(i)}
+		}
+	}
+	for s, c := range gsm7Extension {
+		m[c] = []byte{gsm7Escape, s}
+	}
+	return m
+}()
+
+// gsm7Options are parsed from a `string:"gsm7,len=Field,replace=?"` tag
+type gsm7Options struct {
+	len     string
+	replace []byte
+}
+
+// parseGSM7 reads the options of a gsm7 string tag
+func parseGSM7(tag string) (opts gsm7Options, err error) {
+	parts := strings.Split(tag, ",")
+	if parts[0] != "gsm7" {
+		return opts, fmt.Errorf("%w Unknown string encoding %q", ErrTag, parts[0])
+	}
+	for _, p := range parts[1:] {
+		key, val, _ := strings.Cut(p, "=")
+		switch key {
+		case "len":
+			opts.len = val
+		case "replace":
+			r, size := utf8.DecodeRuneInString(val)
+			if size != len(val) || gsm7Encoding[r] == nil {
+				return opts, fmt.Errorf("%w replacement %q is not a single GSM 7 bit character", ErrTag, val)
+			}
+			opts.replace = gsm7Encoding[r]
+		default:
+			return opts, fmt.Errorf("%w Unknown gsm7 option %q", ErrTag, key)
+		}
+	}
+	if opts.len == "" {
+		return opts, fmt.Errorf("%w gsm7 strings need a len option naming their septet count", ErrTag)
+	}
+	return
+}
+
+// packSeptets packs 7 bit values into octets, least significant bits first.
+// When the last octet would have 7 spare bits they're filled with CR, as a receiver working from
+// the octet count would otherwise see an extra '@'.
+func packSeptets(septets []byte) []byte {
+	n := len(septets)
+	if n%8 == 7 {
+		septets = append(septets, '\r')
+	}
+
+	bs := make([]byte, (n*7+7)/8)
+	for i, s := range septets {
+		bit := i * 7
+		if bit/8 >= len(bs) {
+			break
+		}
+		bs[bit/8] |= s << (bit % 8)
+		if bit%8 > 1 && bit/8+1 < len(bs) {
+			bs[bit/8+1] |= s >> (8 - bit%8)
+		}
+	}
+	return bs
+}
+
+// unpackSeptets extracts n 7 bit values from packed octets
+func unpackSeptets(bs []byte, n int) []byte {
+	septets := make([]byte, n)
+	for i := range septets {
+		bit := i * 7
+		s := bs[bit/8] >> (bit % 8)
+		if bit%8 > 1 {
+			s |= bs[bit/8+1] << (8 - bit%8)
+		}
+		septets[i] = s & 0x7F
+	}
+	return septets
+}
+
+// readGSM7 reads a packed GSM 7 bit string whose septet count is held by another field of struct v.
+// Escapes must be followed by a character of gsm7Extension, or it fails with ErrRange.
+func (r *reader) readGSM7(v reflect.Value, sf reflect.StructField, f reflect.Value) error {
+	opts, err := parseGSM7(sf.Tag.Get("string"))
+	if err != nil {
+		return err
+	}
+	n, err := lengthOf(v, opts.len)
+	if err != nil {
+		return err
+	}
+
+	bs := make([]byte, (n*7+7)/8)
+	if _, err = io.ReadFull(r.r, bs); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	septets := unpackSeptets(bs, n)
+	for i := 0; i < len(septets); i++ {
+		if septets[i] != gsm7Escape {
+			b.WriteRune(gsm7Basic[septets[i]])
+			continue
+		}
+
+		// Escapes with nothing, or nothing known, after them couldn't be written back
+		if i++; i == len(septets) {
+			return fmt.Errorf("%w Escape ends the string", ErrRange)
+		}
+		c, ok := gsm7Extension[septets[i]]
+		if !ok {
+			return fmt.Errorf("%w Unknown escape 0x%02X", ErrRange, septets[i])
+		}
+		b.WriteRune(c)
+	}
+	f.SetString(b.String())
+	return nil
+}
+
+// writeGSM7 writes a packed GSM 7 bit string, which must agree with the septet count field of struct v
+func (w *writer) writeGSM7(v reflect.Value, sf reflect.StructField, f reflect.Value) error {
+	opts, err := parseGSM7(sf.Tag.Get("string"))
+	if err != nil {
+		return err
+	}
+	n, err := lengthOf(v, opts.len)
+	if err != nil {
+		return err
+	}
+
+	var septets []byte
+	for _, c := range f.String() {
+		s, ok := gsm7Encoding[c]
+		if !ok {
+			if opts.replace == nil {
+				return fmt.Errorf("%w %q is not in the GSM 7 bit alphabet", ErrRange, c)
+			}
+			s = opts.replace
+		}
+		septets = append(septets, s...)
+	}
+	if len(septets) != n {
+		return fmt.Errorf("%w %d septets, but %s is %d", ErrLength, len(septets), opts.len, n)
+	}
+
+	_, err = w.w.Write(packSeptets(septets))
+	return err
+}
@@ -0,0 +1,133 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type SMSText struct {
+	UDL  uint8
+	Text string `string:"gsm7,len=UDL"`
+}
+
+type SMSReplacedText struct {
+	UDL  uint8
+	Text string `string:"gsm7,len=UDL,replace=?"`
+}
+
+func TestGSM7Alphabet(t *testing.T) {
+	if len(gsm7Basic) != 128 {
+		t.Fatalf("basic alphabet has %d characters", len(gsm7Basic))
+	}
+}
+
+func TestGSM7(t *testing.T) {
+	tests := []struct {
+		name string
+		data SMSText
+		wire []byte
+	}{
+		{
+			name: "hellohello",
+			data: SMSText{UDL: 10, Text: "hellohello"},
+			wire: []byte{10, 0xE8, 0x32, 0x9B, 0xFD, 0x46, 0x97, 0xD9, 0xEC, 0x37},
+		},
+		{
+			name: "How are you?",
+			data: SMSText{UDL: 12, Text: "How are you?"},
+			wire: []byte{12, 0xC8, 0xF7, 0x1D, 0x14, 0x96, 0x97, 0x41, 0xF9, 0x77, 0xFD, 0x07},
+		},
+		{
+			// 8 septets fill 7 octets exactly
+			name: "octet boundary",
+			data: SMSText{UDL: 8, Text: "12345678"},
+			wire: []byte{8, 0x31, 0xD9, 0x8C, 0x56, 0xB3, 0xDD, 0x70},
+		},
+		{
+			// 7 septets leave 7 spare bits, which are CR rather than '@'
+			name: "seven spare bits",
+			data: SMSText{UDL: 7, Text: "1234567"},
+			wire: []byte{7, 0x31, 0xD9, 0x8C, 0x56, 0xB3, 0xDD, 0x1A},
+		},
+		{
+			name: "escaped",
+			data: SMSText{UDL: 4, Text: "€{"},
+			wire: []byte{4, 0x9B, 0xF2, 0x06, 0x05},
+		},
+		{
+			name: "empty",
+			data: SMSText{},
+			wire: []byte{0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			if err := Write(buf, BigEndian, tt.data); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if !bytes.Equal(buf.Bytes(), tt.wire) {
+				t.Errorf("Write() = % X, wanted % X", buf.Bytes(), tt.wire)
+			}
+
+			var got any = &SMSText{}
+			if err := Read(bytes.NewReader(tt.wire), BigEndian, &got); err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, &tt.data) {
+				t.Errorf("Read() = %+v, wanted %+v", got, tt.data)
+			}
+		})
+	}
+}
+
+func TestGSM7Errors(t *testing.T) {
+	if err := Write(&bytes.Buffer{}, BigEndian, SMSText{UDL: 1, Text: "ж"}); !errors.Is(err, ErrRange) {
+		t.Errorf("Write() error = %v, wanted %v", err, ErrRange)
+	}
+	if err := Write(&bytes.Buffer{}, BigEndian, SMSText{UDL: 3, Text: "ab"}); !errors.Is(err, ErrLength) {
+		t.Errorf("Write() error = %v, wanted %v", err, ErrLength)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, SMSReplacedText{UDL: 2, Text: "aж"}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	var got any = &SMSText{}
+	if err := Read(bytes.NewReader(buf.Bytes()), BigEndian, &got); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if text := got.(*SMSText).Text; text != "a?" {
+		t.Errorf("Read() = %q, wanted %q", text, "a?")
+	}
+}
+
+func TestGSM7Escapes(t *testing.T) {
+	tests := []struct {
+		name    string
+		septets []byte
+	}{
+		{name: "trailing escape", septets: []byte{'a', gsm7Escape}},
+		{name: "unknown escape", septets: []byte{gsm7Escape, 0x41, 'b'}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wire := append([]byte{byte(len(tt.septets))}, packSeptets(tt.septets)...)
+			var got any = &SMSText{}
+			err := Read(bytes.NewReader(wire), BigEndian, &got)
+			if !errors.Is(err, ErrRange) {
+				t.Errorf("Read() error = %v, wanted %v", err, ErrRange)
+			}
+
+			// Anything read must write back as it was
+			if err == nil {
+				buf := &bytes.Buffer{}
+				if err = Write(buf, BigEndian, got); err != nil || !bytes.Equal(buf.Bytes(), wire) {
+					t.Errorf("Write() = % X, %v, wanted % X", buf.Bytes(), err, wire)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package mixedEndian
+
+import "fmt"
+
+// interleaveChunk is the most samples the streaming interleave functions hold at once
+const interleaveChunk = 4096
+
+// Deinterleave splits samples, interleaved a frame of channels samples at a time, into a plane
+// per channel. Its length must be a whole number of frames, or it errors with ErrLength.
+func Deinterleave[T any](samples []T, channels int) ([][]T, error) {
+	frames, err := frameCount(len(samples), channels)
+	if err != nil {
+		return nil, err
+	}
+	planes := make([][]T, channels)
+	for c := range planes {
+		planes[c] = make([]T, frames)
+		for i := range planes[c] {
+			planes[c][i] = samples[i*channels+c]
+		}
+	}
+	return planes, nil
+}
+
+// Interleave joins planes, one per channel, into a single slice of frames holding a sample of
+// each channel in turn. Planes of differing lengths error with ErrLength.
+func Interleave[T any](planes [][]T) ([]T, error) {
+	frames, err := planeLen(planes)
+	if err != nil {
+		return nil, err
+	}
+	samples := make([]T, frames*len(planes))
+	for c, plane := range planes {
+		for i, s := range plane {
+			samples[i*len(planes)+c] = s
+		}
+	}
+	return samples, nil
+}
+
+// DecodePlanar reads channels planes of samples from d, one after another, interleaving them
+// into dst as they're read, so audio stored planar ends up interleaved without an intermediate
+// copy of the whole buffer. Each plane is len(dst)/channels samples long, and dst must hold a
+// whole number of frames.
+func DecodePlanar[T any](d *Decoder, dst []T, channels int) error {
+	frames, err := frameCount(len(dst), channels)
+	if err != nil {
+		return err
+	}
+	chunk := make([]T, minInt(frames, interleaveChunk))
+	for c := 0; c < channels; c++ {
+		for at := 0; at < frames; at += len(chunk) {
+			part := chunk[:minInt(len(chunk), frames-at)]
+			if err = d.Decode(&part); err != nil {
+				return fmt.Errorf("channel %d: %w", c, err)
+			}
+			for i, s := range part {
+				dst[(at+i)*channels+c] = s
+			}
+		}
+	}
+	return nil
+}
+
+// DecodeInterleaved reads samples interleaved a frame at a time from d, splitting them among
+// planes, one per channel, as they're read. As many frames are read as the planes are long, and
+// planes of differing lengths error with ErrLength.
+func DecodeInterleaved[T any](d *Decoder, planes [][]T) error {
+	frames, err := planeLen(planes)
+	if err != nil {
+		return err
+	}
+	channels := len(planes)
+	chunk := make([]T, minInt(frames, interleaveChunk/channels+1)*channels)
+	for at := 0; at < frames; at += len(chunk) / channels {
+		part := chunk[:minInt(len(chunk), (frames-at)*channels)]
+		if err = d.Decode(&part); err != nil {
+			return fmt.Errorf("frame %d: %w", at, err)
+		}
+		for c, plane := range planes {
+			plane = plane[at : at+len(part)/channels]
+			for i := range plane {
+				plane[i] = part[i*channels+c]
+			}
+		}
+	}
+	return nil
+}
+
+// EncodePlanar writes samples, interleaved a frame of channels samples at a time, to e as a
+// plane per channel, one after another. samples must hold a whole number of frames.
+func EncodePlanar[T any](e *Encoder, samples []T, channels int) error {
+	frames, err := frameCount(len(samples), channels)
+	if err != nil {
+		return err
+	}
+	chunk := make([]T, minInt(frames, interleaveChunk))
+	for c := 0; c < channels; c++ {
+		for at := 0; at < frames; at += len(chunk) {
+			part := chunk[:minInt(len(chunk), frames-at)]
+			for i := range part {
+				part[i] = samples[(at+i)*channels+c]
+			}
+			if err = e.Encode(part); err != nil {
+				return fmt.Errorf("channel %d: %w", c, err)
+			}
+		}
+	}
+	return nil
+}
+
+// EncodeInterleaved writes planes, one per channel, to e interleaved a frame at a time. Planes
+// of differing lengths error with ErrLength.
+func EncodeInterleaved[T any](e *Encoder, planes [][]T) error {
+	frames, err := planeLen(planes)
+	if err != nil {
+		return err
+	}
+	channels := len(planes)
+	chunk := make([]T, minInt(frames, interleaveChunk/channels+1)*channels)
+	for at := 0; at < frames; at += len(chunk) / channels {
+		part := chunk[:minInt(len(chunk), (frames-at)*channels)]
+		for c, plane := range planes {
+			plane = plane[at : at+len(part)/channels]
+			for i, s := range plane {
+				part[i*channels+c] = s
+			}
+		}
+		if err = e.Encode(part); err != nil {
+			return fmt.Errorf("frame %d: %w", at, err)
+		}
+	}
+	return nil
+}
+
+// frameCount is the number of frames of channels samples in n samples
+func frameCount(n, channels int) (int, error) {
+	if channels < 1 {
+		return 0, fmt.Errorf("%w %d channels", ErrRange, channels)
+	}
+	if n%channels != 0 {
+		return 0, fmt.Errorf("%w %d samples are not a whole number of %d channel frames", ErrLength, n, channels)
+	}
+	return n / channels, nil
+}
+
+// planeLen is the length of planes, all of which must have it
+func planeLen[T any](planes [][]T) (int, error) {
+	if len(planes) == 0 {
+		return 0, fmt.Errorf("%w No channels", ErrRange)
+	}
+	for c, plane := range planes {
+		if len(plane) != len(planes[0]) {
+			return 0, fmt.Errorf("%w Channel %d has %d samples, where channel 0 has %d", ErrLength, c, len(plane), len(planes[0]))
+		}
+	}
+	return len(planes[0]), nil
+}
+
+// minInt is the smaller of a and b
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,334 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestInterleave(t *testing.T) {
+	samples := []int16{1, -1, 2, -2, 3, -3}
+	planes := [][]int16{{1, 2, 3}, {-1, -2, -3}}
+
+	got, err := Deinterleave(samples, 2)
+	if err != nil {
+		t.Fatalf("Deinterleave() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, planes) {
+		t.Errorf("Deinterleave() = %v, wanted %v", got, planes)
+	}
+	back, err := Interleave(got)
+	if err != nil {
+		t.Fatalf("Interleave() error = %v", err)
+	}
+	if !reflect.DeepEqual(back, samples) {
+		t.Errorf("Interleave() = %v, wanted %v", back, samples)
+	}
+
+	if _, err = Deinterleave(samples, 4); !errors.Is(err, ErrLength) {
+		t.Errorf("Deinterleave(6 samples, 4 channels) error = %v, wanted ErrLength", err)
+	}
+	if _, err = Deinterleave(samples, 0); !errors.Is(err, ErrRange) {
+		t.Errorf("Deinterleave(0 channels) error = %v, wanted ErrRange", err)
+	}
+	if _, err = Interleave([][]float32{{1, 2}, {3}}); !errors.Is(err, ErrLength) {
+		t.Errorf("Interleave(ragged) error = %v, wanted ErrLength", err)
+	}
+	if _, err = Interleave([][]float32{}); !errors.Is(err, ErrRange) {
+		t.Errorf("Interleave(no planes) error = %v, wanted ErrRange", err)
+	}
+}
+
+// interleaveSamples is n frames of channels Int24 samples, each distinct
+func interleaveSamples(n, channels int) []Int24 {
+	samples := make([]Int24, n*channels)
+	for i := range samples {
+		samples[i] = Int24(i*7 - n)
+	}
+	return samples
+}
+
+func TestDecodePlanar(t *testing.T) {
+	// Enough frames to take several chunks, and a partial one
+	const channels = 3
+	samples := interleaveSamples(interleaveChunk+5, channels)
+	planes, _ := Deinterleave(samples, channels)
+
+	buf := &bytes.Buffer{}
+	if err := EncodePlanar(NewEncoder(buf, LittleEndian), samples, channels); err != nil {
+		t.Fatalf("EncodePlanar() error = %v", err)
+	}
+	var want []byte
+	for _, plane := range planes {
+		bs, _ := Marshal(LittleEndian, plane)
+		want = append(want, bs...)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("EncodePlanar() wrote %d bytes, not the planes one after another", buf.Len())
+	}
+
+	got := make([]Int24, len(samples))
+	if err := DecodePlanar(NewDecoder(bytes.NewReader(want), LittleEndian), got, channels); err != nil {
+		t.Fatalf("DecodePlanar() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, samples) {
+		t.Errorf("DecodePlanar() didn't interleave the planes")
+	}
+
+	if err := DecodePlanar(NewDecoder(bytes.NewReader(want[:len(want)-1]), LittleEndian), got, channels); err == nil {
+		t.Errorf("DecodePlanar(short) error = nil")
+	}
+	if err := DecodePlanar(NewDecoder(bytes.NewReader(want), LittleEndian), got[:4], channels); !errors.Is(err, ErrLength) {
+		t.Errorf("DecodePlanar(4 samples, 3 channels) error = %v, wanted ErrLength", err)
+	}
+	if err := EncodePlanar(NewEncoder(buf, LittleEndian), samples[:4], channels); !errors.Is(err, ErrLength) {
+		t.Errorf("EncodePlanar(4 samples, 3 channels) error = %v, wanted ErrLength", err)
+	}
+}
+
+func TestDecodeInterleaved(t *testing.T) {
+	const channels = 3
+	samples := interleaveSamples(interleaveChunk+5, channels)
+	planes, _ := Deinterleave(samples, channels)
+	want, _ := Marshal(BigEndian, samples)
+
+	buf := &bytes.Buffer{}
+	if err := EncodeInterleaved(NewEncoder(buf, BigEndian), planes); err != nil {
+		t.Fatalf("EncodeInterleaved() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("EncodeInterleaved() didn't write the samples interleaved")
+	}
+
+	got := make([][]Int24, channels)
+	for c := range got {
+		got[c] = make([]Int24, len(planes[c]))
+	}
+	if err := DecodeInterleaved(NewDecoder(bytes.NewReader(want), BigEndian), got); err != nil {
+		t.Fatalf("DecodeInterleaved() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, planes) {
+		t.Errorf("DecodeInterleaved() didn't split the samples into planes")
+	}
+
+	got[1] = got[1][:1]
+	if err := DecodeInterleaved(NewDecoder(bytes.NewReader(want), BigEndian), got); !errors.Is(err, ErrLength) {
+		t.Errorf("DecodeInterleaved(ragged) error = %v, wanted ErrLength", err)
+	}
+	if err := EncodeInterleaved(NewEncoder(buf, BigEndian), got); !errors.Is(err, ErrLength) {
+		t.Errorf("EncodeInterleaved(ragged) error = %v, wanted ErrLength", err)
+	}
+}
+
+func TestEncodeInterleave(t *testing.T) {
+	// Round trips of a partial chunk, exactly one, and one and a bit
+	for _, n := range []int{5, interleaveChunk, interleaveChunk + 1} {
+		const channels = 2
+		samples := interleaveSamples(n, channels)
+		planes, _ := Deinterleave(samples, channels)
+
+		buf := &bytes.Buffer{}
+		if err := EncodePlanar(NewEncoder(buf, BigEndian), samples, channels); err != nil {
+			t.Fatalf("EncodePlanar() error = %v", err)
+		}
+		got := make([]Int24, len(samples))
+		if err := DecodePlanar(NewDecoder(buf, BigEndian), got, channels); err != nil {
+			t.Fatalf("DecodePlanar() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, samples) {
+			t.Errorf("DecodePlanar(EncodePlanar()) of %d frames differs", n)
+		}
+
+		buf.Reset()
+		if err := EncodeInterleaved(NewEncoder(buf, BigEndian), planes); err != nil {
+			t.Fatalf("EncodeInterleaved() error = %v", err)
+		}
+		back := [][]Int24{make([]Int24, n), make([]Int24, n)}
+		if err := DecodeInterleaved(NewDecoder(buf, BigEndian), back); err != nil {
+			t.Fatalf("DecodeInterleaved() error = %v", err)
+		}
+		if !reflect.DeepEqual(back, planes) {
+			t.Errorf("DecodeInterleaved(EncodeInterleaved()) of %d frames differs", n)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		encode func(*Encoder) error
+		want   error
+	}{
+		{"planar not whole frames", func(e *Encoder) error { return EncodePlanar(e, []int16{1, 2, 3, 4, 5}, 2) }, ErrLength},
+		{"planar no channels", func(e *Encoder) error { return EncodePlanar(e, []int16{1, 2}, 0) }, ErrRange},
+		{"interleaved ragged", func(e *Encoder) error { return EncodeInterleaved(e, [][]int16{{1, 2}, {3}}) }, ErrLength},
+		{"interleaved no planes", func(e *Encoder) error { return EncodeInterleaved(e, [][]int16{}) }, ErrRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			if err := tt.encode(NewEncoder(buf, BigEndian)); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, wanted %v", err, tt.want)
+			}
+			if buf.Len() != 0 {
+				t.Errorf("wrote %d bytes, wanted none", buf.Len())
+			}
+		})
+	}
+}
+
+var interleaveFrames = []int{1 << 12, 1 << 18}
+
+// BenchmarkDecodeInterleaved splits stereo int16 audio into planes as it's decoded
+func BenchmarkDecodeInterleaved(b *testing.B) {
+	for _, n := range interleaveFrames {
+		wire := make([]byte, n*2*2)
+		b.Run(fmt.Sprintf("%dframes", n), func(b *testing.B) {
+			b.SetBytes(int64(len(wire)))
+			b.ReportAllocs()
+			planes := [][]int16{make([]int16, n), make([]int16, n)}
+			for i := 0; i < b.N; i++ {
+				if err := DecodeInterleaved(NewDecoder(bytes.NewReader(wire), LittleEndian), planes); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkDecodeInterleavedBaseline decodes the same whole, then splits it with Deinterleave,
+// copying the full buffer a second time
+func BenchmarkDecodeInterleavedBaseline(b *testing.B) {
+	for _, n := range interleaveFrames {
+		wire := make([]byte, n*2*2)
+		b.Run(fmt.Sprintf("%dframes", n), func(b *testing.B) {
+			b.SetBytes(int64(len(wire)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				samples := make([]int16, n*2)
+				if err := NewDecoder(bytes.NewReader(wire), LittleEndian).Decode(&samples); err != nil {
+					b.Fatal(err)
+				}
+				if _, err := Deinterleave(samples, 2); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkDecodePlanar interleaves stereo int16 audio stored planar as it's decoded
+func BenchmarkDecodePlanar(b *testing.B) {
+	for _, n := range interleaveFrames {
+		wire := make([]byte, n*2*2)
+		b.Run(fmt.Sprintf("%dframes", n), func(b *testing.B) {
+			b.SetBytes(int64(len(wire)))
+			b.ReportAllocs()
+			samples := make([]int16, n*2)
+			for i := 0; i < b.N; i++ {
+				if err := DecodePlanar(NewDecoder(bytes.NewReader(wire), LittleEndian), samples, 2); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkDecodePlanarBaseline decodes the same whole, as planes, then joins them with Interleave
+func BenchmarkDecodePlanarBaseline(b *testing.B) {
+	for _, n := range interleaveFrames {
+		wire := make([]byte, n*2*2)
+		b.Run(fmt.Sprintf("%dframes", n), func(b *testing.B) {
+			b.SetBytes(int64(len(wire)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				planes := [][]int16{make([]int16, n), make([]int16, n)}
+				dec := NewDecoder(bytes.NewReader(wire), LittleEndian)
+				for _, plane := range planes {
+					if err := dec.Decode(&plane); err != nil {
+						b.Fatal(err)
+					}
+				}
+				if _, err := Interleave(planes); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkEncodePlanar writes interleaved stereo int16 audio as planes
+func BenchmarkEncodePlanar(b *testing.B) {
+	for _, n := range interleaveFrames {
+		samples := make([]int16, n*2)
+		b.Run(fmt.Sprintf("%dframes", n), func(b *testing.B) {
+			b.SetBytes(int64(n * 2 * 2))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := EncodePlanar(NewEncoder(io.Discard, LittleEndian), samples, 2); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkEncodePlanarBaseline splits the same with Deinterleave, then encodes each plane whole
+func BenchmarkEncodePlanarBaseline(b *testing.B) {
+	for _, n := range interleaveFrames {
+		samples := make([]int16, n*2)
+		b.Run(fmt.Sprintf("%dframes", n), func(b *testing.B) {
+			b.SetBytes(int64(n * 2 * 2))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				planes, err := Deinterleave(samples, 2)
+				if err != nil {
+					b.Fatal(err)
+				}
+				enc := NewEncoder(io.Discard, LittleEndian)
+				for _, plane := range planes {
+					if err = enc.Encode(plane); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkEncodeInterleaved writes planar stereo int16 audio interleaved
+func BenchmarkEncodeInterleaved(b *testing.B) {
+	for _, n := range interleaveFrames {
+		planes := [][]int16{make([]int16, n), make([]int16, n)}
+		b.Run(fmt.Sprintf("%dframes", n), func(b *testing.B) {
+			b.SetBytes(int64(n * 2 * 2))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := EncodeInterleaved(NewEncoder(io.Discard, LittleEndian), planes); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkEncodeInterleavedBaseline joins the same with Interleave, then encodes the result whole
+func BenchmarkEncodeInterleavedBaseline(b *testing.B) {
+	for _, n := range interleaveFrames {
+		planes := [][]int16{make([]int16, n), make([]int16, n)}
+		b.Run(fmt.Sprintf("%dframes", n), func(b *testing.B) {
+			b.SetBytes(int64(n * 2 * 2))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				samples, err := Interleave(planes)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if err = NewEncoder(io.Discard, LittleEndian).Encode(samples); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
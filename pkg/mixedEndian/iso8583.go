@@ -0,0 +1,155 @@
+package mixedEndian
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// iso8583Bitmap is an ISO 8583 bitmap: bit 1, the most significant of the first byte, flags a
+// secondary bitmap, and bits 2 through 128 the data elements of those numbers
+type iso8583Bitmap [16]byte
+
+func (b *iso8583Bitmap) has(n int) bool {
+	return b[(n-1)/8]&(0x80>>((n-1)%8)) != 0
+}
+
+func (b *iso8583Bitmap) set(n int) {
+	b[(n-1)/8] |= 0x80 >> ((n - 1) % 8)
+}
+
+// dataElements gives, for each field of ISO 8583 struct t, its data element number, or 0 for the
+// fields before the bitmap. Data elements follow the bitmap in ascending order.
+func dataElements(t reflect.Type) (des []int, err error) {
+	des = make([]int, t.NumField())
+	last := 0
+	for i := range des {
+		sf := t.Field(i)
+		s := sf.Tag.Get("de")
+		if s == "" {
+			if last > 0 && sf.Name != "_" {
+				return nil, fmt.Errorf("%s: %w Fields after the bitmap need a de tag", sf.Name, ErrTag)
+			}
+			continue
+		}
+
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 2 || n > 128 {
+			return nil, fmt.Errorf("%s: %w de %q is not a data element from 2 to 128", sf.Name, ErrTag, s)
+		} else if n <= last {
+			return nil, fmt.Errorf("%s: %w de %d doesn't follow de %d", sf.Name, ErrTag, n, last)
+		}
+		des[i], last = n, n
+	}
+	return
+}
+
+// readISO8583 reads the fields of ISO 8583 struct v: those before the bitmap, the bitmap, then
+// the data elements it flags. Those it doesn't are zeroed.
+func (r *reader) readISO8583(v reflect.Value, o binary.ByteOrder) error {
+	t := v.Type()
+	des, err := dataElements(t)
+	if err != nil {
+		return err
+	}
+
+	var bitmap iso8583Bitmap
+	read := false
+	for i, n := range des {
+		sf, f := t.Field(i), v.Field(i)
+		if n > 0 && !read {
+			if err = r.readBitmap(&bitmap, des); err != nil {
+				return err
+			}
+			read = true
+		}
+		if !f.CanSet() || sf.Name == "_" {
+			continue
+		}
+		if n > 0 && !bitmap.has(n) {
+			f.Set(reflect.Zero(f.Type()))
+			continue
+		}
+		if err = r.readField(v, sf, f, o); err != nil {
+			return err
+		}
+	}
+	if !read {
+		return r.readBitmap(&bitmap, des)
+	}
+	return nil
+}
+
+// readBitmap reads the primary bitmap, and the secondary should the primary flag it, erroring on
+// any data element not among des, whose size can't be known
+func (r *reader) readBitmap(bitmap *iso8583Bitmap, des []int) error {
+	bs, err := r.next(8)
+	if err != nil {
+		return err
+	}
+	copy(bitmap[:8], bs)
+	if bitmap.has(1) {
+		if bs, err = r.next(8); err != nil {
+			return err
+		}
+		copy(bitmap[8:], bs)
+	}
+
+	known := map[int]bool{1: true}
+	for _, n := range des {
+		known[n] = true
+	}
+	for n := 1; n <= 128; n++ {
+		if bitmap.has(n) && !known[n] {
+			return fmt.Errorf("%w Bitmap flags data element %d, which has no field", ErrLayout, n)
+		}
+	}
+	return nil
+}
+
+// writeISO8583 writes the fields of ISO 8583 struct v: those before the bitmap, a bitmap flagging
+// each data element that isn't its zero value, then those data elements. The secondary bitmap is
+// only written when an element above 64 is present.
+func (w *writer) writeISO8583(v reflect.Value, o binary.ByteOrder) error {
+	t := v.Type()
+	des, err := dataElements(t)
+	if err != nil {
+		return err
+	}
+
+	var bitmap iso8583Bitmap
+	for i, n := range des {
+		if n > 0 && !v.Field(i).IsZero() {
+			bitmap.set(n)
+			if n > 64 {
+				bitmap.set(1)
+			}
+		}
+	}
+	size := 8
+	if bitmap.has(1) {
+		size = 16
+	}
+
+	written := false
+	for i, n := range des {
+		sf, f := t.Field(i), v.Field(i)
+		if n > 0 && !written {
+			if _, err = w.w.Write(bitmap[:size]); err != nil {
+				return err
+			}
+			written = true
+		}
+		if (sf.Name == "_" && sf.Type.Size() == 0) || (n > 0 && !bitmap.has(n)) {
+			continue
+		}
+		if err = w.writeField(v, sf, f, o); err != nil {
+			return err
+		}
+	}
+	if !written {
+		_, err = w.w.Write(bitmap[:size])
+	}
+	return err
+}
@@ -0,0 +1,104 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type AuthorizationRequest struct {
+	_   struct{} `iso8583:"true"`
+	MTI [4]byte
+
+	PAN            [8]byte `de:"2"`
+	ProcessingCode [3]byte `de:"3"`
+	Amount         uint64  `de:"4"`
+	STAN           [3]byte `de:"11"`
+	Network        uint16  `de:"70"`
+}
+
+func TestISO8583(t *testing.T) {
+	tests := []struct {
+		name string
+		data AuthorizationRequest
+		wire []byte
+	}{
+		{
+			name: "primary",
+			data: AuthorizationRequest{MTI: [4]byte{'0', '1', '0', '0'}, ProcessingCode: [3]byte{0, 0, 1}, STAN: [3]byte{0x12, 0x34, 0x56}},
+			wire: []byte{
+				'0', '1', '0', '0',
+				0x20, 0x20, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x01,
+				0x12, 0x34, 0x56,
+			},
+		},
+		{
+			name: "secondary",
+			data: AuthorizationRequest{MTI: [4]byte{'0', '8', '0', '0'}, Amount: 1000, Network: 301},
+			wire: []byte{
+				'0', '8', '0', '0',
+				0x90, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0xE8,
+				0x01, 0x2D,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			if err := Write(buf, BigEndian, tt.data); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if !bytes.Equal(buf.Bytes(), tt.wire) {
+				t.Errorf("Write() = % X, wanted % X", buf.Bytes(), tt.wire)
+			}
+
+			// Stale values in absent elements are cleared
+			var data any = &AuthorizationRequest{PAN: [8]byte{9}, Amount: 5}
+			if err := Read(bytes.NewReader(tt.wire), BigEndian, &data); err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if !reflect.DeepEqual(data, &tt.data) {
+				t.Errorf("Read() data = %+v, wanted %+v", data, &tt.data)
+			}
+		})
+	}
+}
+
+func TestISO8583Errors(t *testing.T) {
+	// Data element 5 is flagged, but has no field to give its size
+	wire := []byte{'0', '1', '0', '0', 0x08, 0, 0, 0, 0, 0, 0, 0}
+	var data any = &AuthorizationRequest{}
+	if err := Read(bytes.NewReader(wire), BigEndian, &data); !errors.Is(err, ErrLayout) {
+		t.Errorf("Read() error = %v, wanted %v", err, ErrLayout)
+	}
+
+	tests := []struct {
+		name string
+		data any
+	}{
+		{"descending", struct {
+			_    struct{} `iso8583:"true"`
+			A, B uint8    `de:"3"`
+		}{}},
+		{"out of range", struct {
+			_ struct{} `iso8583:"true"`
+			A uint8    `de:"1"`
+		}{}},
+		{"untagged after bitmap", struct {
+			_ struct{} `iso8583:"true"`
+			A uint8    `de:"2"`
+			B uint8
+		}{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Write(&bytes.Buffer{}, BigEndian, tt.data); !errors.Is(err, ErrTag) {
+				t.Errorf("Write() error = %v, wanted %v", err, ErrTag)
+			}
+		})
+	}
+}
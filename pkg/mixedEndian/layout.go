@@ -0,0 +1,360 @@
+package mixedEndian
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+)
+
+// StructLayout describes how a struct is laid out on the wire
+type StructLayout struct {
+	Type reflect.Type
+
+	// Size in bytes, or -1 if any field is variable sized
+	Size int
+
+	Fields []FieldLayout
+}
+
+// FieldLayout describes a single field of a StructLayout
+type FieldLayout struct {
+	// Name of the Go field, and its dotted path from the described type
+	Name string
+	Path string
+
+	Type reflect.Type
+	Tag  reflect.StructTag
+
+	// Offset in bytes from the start of the enclosing struct,
+	// or -1 if it follows a variable sized field
+	Offset int
+
+	// Size in bytes, or -1 if variable
+	Size int
+
+	// Order is the byte order set by tags on this field or an enclosing one.
+	// It is nil when the field uses the default order given to Read or Write.
+	Order binary.ByteOrder
+
+	// Count is the number of elements of an array or slice, or -1 if only known once read.
+	// Slices with a literal count tag are as fixed as arrays.
+	// CountRef names the field holding a slice's element count, when set by a len tag.
+	Count    int
+	CountRef string
+
+	// Elem is the layout of a struct field, or of an array or slice's struct elements
+	Elem *StructLayout
+}
+
+// Describe resolves the wire layout of sample, which must be a struct or pointer to one.
+// Only fields which Read would fill are included.
+func Describe(sample any) (*StructLayout, error) {
+	return LayoutOf(reflect.TypeOf(sample))
+}
+
+// LayoutOf is Describe for a struct type, or pointer to one, rather than a sample value
+func LayoutOf(t reflect.Type) (*StructLayout, error) {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w Expected struct; Got %v", ErrUnexpectedType, t)
+	}
+	return describeStruct(t, nil, "")
+}
+
+// SizeOf is the number of bytes sample, a struct or pointer to one, takes on the wire.
+// Structs whose size depends on their contents give ErrLength.
+func SizeOf(sample any) (int, error) {
+	sl, err := Describe(sample)
+	if err != nil {
+		return 0, err
+	}
+	if sl.Size < 0 {
+		return 0, fmt.Errorf("%w %s is variable sized", ErrLength, sl.Type.String())
+	}
+	return sl.Size, nil
+}
+
+// describeStruct lays out t, whose fields default to byte order o
+func describeStruct(t reflect.Type, o binary.ByteOrder, prefix string) (sl *StructLayout, err error) {
+	so, err := optionsOf(t)
+	if err != nil {
+		return
+	}
+
+	sl = &StructLayout{Type: t}
+	overlayAt := 0
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() || sf.Name == "_" {
+			continue
+		}
+
+		// Aligned structs pad before fields
+		if so.align > 0 && sl.Size >= 0 {
+			sl.Size += int(padding(int64(sl.Size), fieldAlign(sf, so.align)))
+		}
+
+		fl := FieldLayout{
+			Name:   sf.Name,
+			Path:   prefix + sf.Name,
+			Type:   sf.Type,
+			Tag:    sf.Tag,
+			Offset: sl.Size,
+			Order:  o,
+		}
+
+		switch sf.Tag.Get("endian") {
+		case "big":
+			fl.Order = BigEndian
+		case "little":
+			fl.Order = LittleEndian
+		}
+		if o := typeOrder(sf.Type, nil); o != nil {
+			fl.Order = o
+		}
+
+		if err = describeField(&fl, sf); err != nil {
+			return nil, fmt.Errorf("%s: %w", fl.Path, err)
+		}
+
+		// Padding tags take bytes either side of the field
+		var before, after int
+		if before, after, err = fieldPads(sf); err != nil {
+			return nil, fmt.Errorf("%s: %w", fl.Path, err)
+		}
+		if fl.Offset >= 0 {
+			fl.Offset += before
+		}
+
+		// Overlay members all start where their group does, which takes the largest's size
+		size := fl.Size
+		if size >= 0 {
+			size += before + after
+		}
+		if g, ok := so.overlayOf(i); ok && g.fields[0] == i {
+			overlayAt, size = fl.Offset, g.size
+		} else if ok {
+			fl.Offset, size = overlayAt, 0
+		}
+
+		if sl.Size >= 0 && size >= 0 {
+			sl.Size += size
+		} else {
+			sl.Size = -1
+		}
+		sl.Fields = append(sl.Fields, fl)
+
+		// Length scopes may hold more than their fields, so what follows is only placed once read
+		for _, sc := range so.scopes {
+			if sc.to == i {
+				sl.Size = -1
+			}
+		}
+	}
+
+	if so.align > 0 && sl.Size >= 0 {
+		sl.Size += int(padding(int64(sl.Size), so.align))
+	}
+	return
+}
+
+// describeField fills in the size, count, and nested layout of fl
+func describeField(fl *FieldLayout, sf reflect.StructField) (err error) {
+	// Optionals are laid out as their values, but may be there or not, so are variable sized
+	if isOptional(fl.Type) {
+		defer func(t reflect.Type) { fl.Type, fl.Size = t, -1 }(fl.Type)
+		sf = optionalValue(sf)
+		fl.Type = sf.Type
+	}
+
+	t := fl.Type
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	// Varints are sized by their values, and optional fields by their flags, versions, or bitmap
+	if isVarint(sf) || sf.Tag.Get("presentif") != "" || isVersioned(sf) || sf.Tag.Get("de") != "" {
+		defer func() { fl.Size = -1 }()
+	}
+
+	// Length prefixes are only counted once read, like any other slice without a count
+	if _, _, err = lenPrefixOf(sf); err != nil {
+		return
+	}
+
+	// Block CRCs follow each block of the payload
+	if s := sf.Tag.Get("crcblocks"); s != "" {
+		defer func() {
+			if size, crc, cerr := parseCRCBlocks(s); cerr != nil {
+				err = cerr
+			} else if fl.Size > 0 {
+				fl.Size += crc.Width / 8 * ((fl.Size + size - 1) / size)
+			}
+		}()
+	}
+
+	switch {
+	case isRaw(sf) || sf.Type == serializableType:
+		// Raw captures and Serializables aren't on the wire
+		return
+
+	case isDecimal(sf):
+		var df decimalFormat
+		if df, err = decimalFormatOf(sf); err == nil {
+			fl.Size = df.bits / 8
+		}
+		return
+
+	case sf.Tag.Get("ssh") != "":
+		// SSH types are sized by their length prefixes
+		_, err = sshFormat(sf)
+		fl.Size, fl.Count = -1, -1
+		return
+
+	case sf.Tag.Get("compress") != "":
+		// Compressed blocks are sized by their length prefixes
+		_, err = compressorOf(sf)
+		fl.Size, fl.Count = -1, -1
+		return
+
+	case isDuration(sf):
+		var wt reflect.Type
+		if _, wt, err = durationFormat(sf); err == nil {
+			fl.Size = typeSize(wt)
+		}
+		return
+
+	case isEpoch(sf):
+		var wt reflect.Type
+		if _, _, wt, err = epochFormat(sf); err == nil {
+			fl.Size = typeSize(wt)
+		}
+		return
+
+	case t == timecodeType:
+		_, err = timecodeLayoutOf(sf)
+		fl.Size = 4
+		return
+
+	case t == hardwareAddrType:
+		fl.Count, err = hardwareAddrLen(sf)
+		fl.Size = fl.Count
+		return
+
+	case t.Kind() == reflect.String && sf.Tag.Get("string") != "":
+		fl.Size, fl.Count = -1, -1
+		return
+
+	case t.Kind() == reflect.String:
+		var ok bool
+		if fl.Size, ok, err = tagSize(sf); err == nil && !ok {
+			err = fmt.Errorf("%w string fields need a size tag", ErrTag)
+		}
+		fl.Count = fl.Size
+		return
+
+	case sf.Tag.Get("dims") != "":
+		// Nested slices are only sized once read
+		fl.Size, fl.Count = -1, -1
+		return
+
+	case isNorm(sf) && t.Kind() != reflect.Array && t.Kind() != reflect.Slice:
+		var wt reflect.Type
+		if wt, _, err = normFormat(sf); err == nil {
+			fl.Size = typeSize(wt)
+		}
+		return
+
+	case isPCM(sf) && t.Kind() != reflect.Array && t.Kind() != reflect.Slice:
+		_, err = pcmFormat(sf)
+		fl.Size = 1
+		return
+
+	case t.Kind() == reflect.Array || t.Kind() == reflect.Slice:
+		// Arrays of arrays are laid out element after element, so are sized by the innermost
+		elem, per := t.Elem(), 1
+		for elem.Kind() == reflect.Array {
+			per *= elem.Len()
+			elem = elem.Elem()
+		}
+		elemSize := typeSize(elem)
+		if isNorm(sf) {
+			// Normalized floats are stored as integers
+			var wt reflect.Type
+			if wt, _, err = normFormat(sf); err != nil {
+				return
+			}
+			elemSize = typeSize(wt)
+		} else if isPCM(sf) {
+			// Companded samples are a byte each
+			if _, err = pcmFormat(sf); err != nil {
+				return
+			}
+			elemSize = 1
+		} else if elem == timecodeType {
+			// Timecodes are uint32s of BCD digits
+			elemSize = 4
+		} else if elem.Kind() == reflect.Struct && elemSize == 0 {
+			if fl.Elem, err = describeStruct(elem, fl.Order, fl.Path+"."); err != nil {
+				return
+			}
+			elemSize = fl.Elem.Size
+		} else if elemSize == 0 {
+			return fmt.Errorf("%w Unsupported element type %s", ErrUnexpectedType, elem.String())
+		}
+		if elemSize > 0 {
+			elemSize *= per
+		}
+
+		fl.Count = -1
+		if t.Kind() == reflect.Array {
+			fl.Count = t.Len()
+		} else if n, _, ok, err := literalCount(sf); err != nil {
+			return err
+		} else if ok {
+			fl.Count = n
+		}
+		if fl.CountRef = sf.Tag.Get("len"); fl.CountRef == "" {
+			fl.CountRef = sf.Tag.Get("countfrom")
+		}
+
+		// Sparse and run length encoded slices vary in size whatever their count
+		fl.Size = -1
+		if fl.Count >= 0 && elemSize >= 0 && sf.Tag.Get("sparse") == "" && sf.Tag.Get("rle") == "" {
+			fl.Size = fl.Count * elemSize
+		}
+		return
+
+	case t.Kind() == reflect.Map:
+		// Entries vary in order, but not in size
+		fl.Count = -1
+		if n, _, ok, err := literalCount(sf); err != nil {
+			return err
+		} else if ok {
+			fl.Count = n
+		}
+		if fl.CountRef = sf.Tag.Get("len"); fl.CountRef == "" {
+			fl.CountRef = sf.Tag.Get("countfrom")
+		}
+		fl.Size = -1
+		if k, e := typeSize(t.Key()), typeSize(t.Elem()); fl.Count >= 0 && k > 0 && e > 0 {
+			fl.Size = fl.Count * (k + e)
+		}
+		return
+
+	case t.Kind() == reflect.Struct && typeSize(t) == 0:
+		if fl.Elem, err = describeStruct(t, fl.Order, fl.Path+"."); err != nil {
+			return
+		}
+		fl.Size = fl.Elem.Size
+		return
+
+	default:
+		if fl.Size = typeSize(t); fl.Size == 0 {
+			return fmt.Errorf("%w Unsupported type %s", ErrUnexpectedType, t.String())
+		}
+		return
+	}
+}
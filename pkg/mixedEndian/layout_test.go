@@ -0,0 +1,189 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	sl, err := Describe(&NestedStruct{})
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	if sl.Size != 8 {
+		t.Errorf("Describe() size = %d, wanted 8", sl.Size)
+	}
+
+	want := []struct {
+		path   string
+		offset int
+		size   int
+		order  string
+	}{
+		{"A", 0, 2, "big"},
+		{"B", 2, 4, ""},
+		{"C", 6, 2, "little"},
+	}
+	if len(sl.Fields) != len(want) {
+		t.Fatalf("Describe() has %d fields, wanted %d", len(sl.Fields), len(want))
+	}
+	for i, w := range want {
+		f := sl.Fields[i]
+		order := ""
+		if f.Order != nil {
+			order = map[bool]string{true: "big", false: "little"}[isBigEndian(f.Order)]
+		}
+		if f.Path != w.path || f.Offset != w.offset || f.Size != w.size || order != w.order {
+			t.Errorf("field %d = %s@%d+%d %s, wanted %s@%d+%d %s", i, f.Path, f.Offset, f.Size, order, w.path, w.offset, w.size, w.order)
+		}
+	}
+	if inner := sl.Fields[1].Elem; inner == nil || inner.Fields[1].Path != "B.B" || inner.Fields[1].Offset != 2 {
+		t.Errorf("Describe() nested layout = %+v", inner)
+	}
+
+	sl, err = Describe(OddWidthSliceStruct{})
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	if f := sl.Fields[1]; sl.Size != -1 || f.Size != -1 || f.Count != -1 || f.CountRef != "N" {
+		t.Errorf("Describe() variable field = %+v", f)
+	}
+
+	if _, err = Describe(3); !errors.Is(err, ErrUnexpectedType) {
+		t.Errorf("Describe() error = %v, wanted %v", err, ErrUnexpectedType)
+	}
+}
+
+type NestedArrayStruct struct {
+	Grid   [2][3]uint16
+	Colors [2][4]byte
+	Cells  [2][2]GenInner
+}
+
+func TestDescribeNestedArrays(t *testing.T) {
+	sl, err := Describe(NestedArrayStruct{})
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	want := []struct {
+		path   string
+		offset int
+		size   int
+		count  int
+	}{
+		{"Grid", 0, 12, 2},
+		{"Colors", 12, 8, 2},
+		{"Cells", 20, 4 * sizeOfGenInner(t), 2},
+	}
+	for i, w := range want {
+		f := sl.Fields[i]
+		if f.Path != w.path || f.Offset != w.offset || f.Size != w.size || f.Count != w.count {
+			t.Errorf("field %d = %s@%d+%d x%d, wanted %s@%d+%d x%d", i, f.Path, f.Offset, f.Size, f.Count, w.path, w.offset, w.size, w.count)
+		}
+	}
+	if sl.Fields[2].Elem == nil || sl.Fields[2].Elem.Type != reflect.TypeOf(GenInner{}) {
+		t.Errorf("Describe() Cells element = %+v, wanted GenInner", sl.Fields[2].Elem)
+	}
+
+	bs, err := Marshal(BigEndian, NestedArrayStruct{})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if n, err := SizeOf(NestedArrayStruct{}); err != nil || n != len(bs) {
+		t.Errorf("SizeOf() = %d, %v, wanted %d", n, err, len(bs))
+	}
+
+	// Byte arrays within slices are sized by their length
+	sl, err = Describe(struct {
+		Colors [][4]byte `count:"3"`
+	}{})
+	if err != nil || sl.Size != 12 {
+		t.Errorf("Describe() = %+v, %v, wanted 12 bytes", sl, err)
+	}
+}
+
+// sizeOfGenInner is the wire size of a GenInner
+func sizeOfGenInner(t *testing.T) int {
+	n, err := SizeOf(GenInner{})
+	if err != nil {
+		t.Fatalf("SizeOf() error = %v", err)
+	}
+	return n
+}
+
+type PaletteStruct struct {
+	Version uint8
+	Palette []uint16 `count:"4,pad"`
+	Flags   []uint8  `count:"2"`
+}
+
+func TestLiteralCount(t *testing.T) {
+	sl, err := Describe(PaletteStruct{})
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	if f := sl.Fields[1]; f.Count != 4 || f.Size != 8 || sl.Fields[2].Offset != 9 {
+		t.Errorf("Describe() Palette count = %d size = %d, Flags offset = %d", f.Count, f.Size, sl.Fields[2].Offset)
+	}
+	if n, err := SizeOf(&PaletteStruct{}); err != nil || n != 11 {
+		t.Errorf("SizeOf() = %d, %v, wanted 11", n, err)
+	}
+	if _, err := SizeOf(UnsizedSliceStruct{}); !errors.Is(err, ErrLength) {
+		t.Errorf("SizeOf() error = %v, wanted %v", err, ErrLength)
+	}
+
+	// Short slices are padded if asked, otherwise lengths must match
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, PaletteStruct{Version: 1, Palette: []uint16{0x0102}, Flags: []uint8{3, 4}}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	wire := []byte{0x01, 0x01, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x04}
+	if !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+	if err := Write(buf, BigEndian, PaletteStruct{Palette: make([]uint16, 5), Flags: []uint8{3, 4}}); !errors.Is(err, ErrLength) {
+		t.Errorf("Write() error = %v, wanted %v", err, ErrLength)
+	}
+	if err := Write(buf, BigEndian, PaletteStruct{Flags: []uint8{3}}); !errors.Is(err, ErrLength) {
+		t.Errorf("Write() error = %v, wanted %v", err, ErrLength)
+	}
+
+	var got any = &PaletteStruct{}
+	if err := Read(bytes.NewReader(wire), BigEndian, &got); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := &PaletteStruct{Version: 1, Palette: []uint16{0x0102, 0, 0, 0}, Flags: []uint8{3, 4}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Read() = %v, wanted %v", got, want)
+	}
+}
+
+type FixedCountStruct struct {
+	Values []uint16 `count:"fixed:4"`
+}
+
+func TestFixedCount(t *testing.T) {
+	wire := []byte{0x00, 0x01, 0x00, 0x02, 0x00, 0x03, 0x00, 0x04}
+	want := &FixedCountStruct{Values: []uint16{1, 2, 3, 4}}
+
+	var got any = &FixedCountStruct{}
+	if err := Read(bytes.NewReader(wire), BigEndian, &got); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Read() = %v, wanted %v", got, want)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, want); err != nil || !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, %v, wanted % X", buf.Bytes(), err, wire)
+	}
+	if err := Write(buf, BigEndian, FixedCountStruct{Values: []uint16{1, 2, 3}}); !errors.Is(err, ErrLength) {
+		t.Errorf("Write() error = %v, wanted %v", err, ErrLength)
+	}
+	if n, err := SizeOf(FixedCountStruct{}); err != nil || n != 8 {
+		t.Errorf("SizeOf() = %d, %v, wanted 8", n, err)
+	}
+}
@@ -0,0 +1,81 @@
+package mixedEndian
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// lengthScope is an integer field tagged lengthscope, holding the encoded size of fields from
+// through to, which follow it
+type lengthScope struct {
+	field, from, to int
+}
+
+// parseLengthScope parses the lengthscope tag s of field i of struct t, such as "A..C"
+func parseLengthScope(t reflect.Type, i int, s string) (sc lengthScope, err error) {
+	switch t.Field(i).Type.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		return sc, fmt.Errorf("%w lengthscope needs an integer; Got %s", ErrUnexpectedType, t.Field(i).Type.String())
+	}
+
+	from, to, ok := strings.Cut(s, "..")
+	if !ok {
+		to = from
+	}
+	a, aok := t.FieldByName(from)
+	c, cok := t.FieldByName(to)
+	if !aok || !cok || len(a.Index) != 1 || len(c.Index) != 1 || a.Index[0] <= i || a.Index[0] > c.Index[0] {
+		return sc, fmt.Errorf("%w lengthscope %q is not a range of later fields", ErrTag, s)
+	}
+	return lengthScope{field: i, from: a.Index[0], to: c.Index[0]}, nil
+}
+
+// scopeAt is the length scope starting at field i, if any
+func (so structOptions) scopeAt(i int) (lengthScope, bool) {
+	for _, sc := range so.scopes {
+		if sc.from == i {
+			return sc, true
+		}
+	}
+	return lengthScope{}, false
+}
+
+// scopeSized returns a copy of struct v with the length field of each of its scopes set to the
+// encoded size of the fields within it, found by a dry run writing them
+func (w *writer) scopeSized(v reflect.Value, so structOptions, o binary.ByteOrder) (reflect.Value, error) {
+	sized := reflect.New(v.Type()).Elem()
+	sized.Set(v)
+
+	dry := *w
+	for _, sc := range so.scopes {
+		cw := &countingWriter{w: io.Discard}
+		dry.w = cw
+		for i := sc.from; i <= sc.to; i++ {
+			if err := dry.writeFieldAt(sized, so, i, o); err != nil {
+				return v, err
+			}
+		}
+
+		f, name := sized.Field(sc.field), v.Type().Field(sc.field).Name
+		if !f.CanSet() {
+			return v, fmt.Errorf("%s: %w lengthscope needs an exported field", name, ErrTag)
+		}
+		if f.CanInt() {
+			if f.OverflowInt(cw.n) {
+				return v, fmt.Errorf("%s: %w %d bytes don't fit %s", name, ErrRange, cw.n, f.Type().String())
+			}
+			f.SetInt(cw.n)
+		} else {
+			if f.OverflowUint(uint64(cw.n)) {
+				return v, fmt.Errorf("%s: %w %d bytes don't fit %s", name, ErrRange, cw.n, f.Type().String())
+			}
+			f.SetUint(uint64(cw.n))
+		}
+	}
+	return sized, nil
+}
@@ -0,0 +1,95 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type ScopedRecord struct {
+	Kind   uint8
+	Length uint16 `lengthscope:"A..B"`
+	A      uint16
+	B      [2]uint8
+	C      uint8
+	D      uint16
+}
+
+func TestLengthScope(t *testing.T) {
+	want := &ScopedRecord{Kind: 1, Length: 4, A: 0x0203, B: [2]uint8{4, 5}, C: 6, D: 0x0708}
+	wire := []byte{0x01, 0x00, 0x04, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}
+
+	// The length is set however it's left
+	unsized := *want
+	unsized.Length = 99
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, unsized); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+
+	var data any = &ScopedRecord{}
+	if err := Read(bytes.NewReader(wire), BigEndian, &data); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("Read() data = %v, wanted %v", data, want)
+	}
+
+	// A longer scope, from a later version adding fields to it, is skipped past
+	longer := []byte{0x01, 0x00, 0x06, 0x02, 0x03, 0x04, 0x05, 0xEE, 0xEE, 0x06, 0x07, 0x08}
+	want.Length = 6
+	data = &ScopedRecord{}
+	if err := Read(bytes.NewReader(longer), BigEndian, &data); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("Read() data = %v, wanted %v", data, want)
+	}
+}
+
+func TestLengthScopeErrors(t *testing.T) {
+	// A scope too short for its fields
+	short := []byte{0x01, 0x00, 0x03, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}
+	var data any = &ScopedRecord{}
+	if err := Read(bytes.NewReader(short), BigEndian, &data); !errors.Is(err, ErrLength) {
+		t.Errorf("Read() error = %v, wanted %v", err, ErrLength)
+	}
+
+	tests := []struct {
+		name    string
+		data    any
+		wantErr error
+	}{
+		{"earlier field", struct {
+			A uint8
+			N uint8 `lengthscope:"A"`
+		}{}, ErrTag},
+		{"backwards", struct {
+			N    uint8 `lengthscope:"B..A"`
+			A, B uint8
+		}{}, ErrTag},
+		{"not an integer", struct {
+			N string `lengthscope:"A"`
+			A uint8
+		}{}, ErrUnexpectedType},
+		{"overflow", struct {
+			N uint8 `lengthscope:"A"`
+			A [300]byte
+		}{}, ErrRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Write(&bytes.Buffer{}, BigEndian, tt.data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Write() error = %v, wanted %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if sl, err := Describe(ScopedRecord{}); err != nil || sl.Size != -1 {
+		t.Errorf("Describe() = %v, %v, wanted a variable size", sl, err)
+	}
+}
@@ -0,0 +1,55 @@
+package mixedEndian
+
+import "reflect"
+
+// readable reports whether Read can fill field sf, either from its type or from a tag giving its
+// type a wire form. It mirrors readField's dispatch, which TestReadableMatchesRead holds it to.
+func readable(sf reflect.StructField) bool {
+	return readableField(sf, map[reflect.Type]bool{})
+}
+
+// readableField is readable, taking the types in seen, already being checked, to be readable so
+// recursive types end
+func readableField(sf reflect.StructField, seen map[reflect.Type]bool) bool {
+	switch {
+	case isRaw(sf) || sf.Type == serializableType:
+		// Neither is read from the wire
+		return true
+	case isDecimal(sf) || isNorm(sf) || isPCM(sf) || isDuration(sf) || isEpoch(sf) || sf.Tag.Get("ssh") != "" || sf.Tag.Get("compress") != "":
+		return true
+	}
+	return readableType(sf.Type, seen)
+}
+
+// readableType reports whether Read can fill a t
+func readableType(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return true
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Array, reflect.Slice:
+		return readableType(t.Elem(), seen)
+
+	case reflect.Map:
+		return readableType(t.Key(), seen) && readableType(t.Elem(), seen)
+
+	case reflect.Struct:
+		if typeSize(t) > 0 {
+			return true
+		}
+		for i := 0; i < t.NumField(); i++ {
+			if sf := t.Field(i); sf.IsExported() && !readableField(sf, seen) {
+				return false
+			}
+		}
+		return true
+
+	case reflect.String, reflect.Int, reflect.Uint:
+		return true
+
+	default:
+		return typeSize(t) > 0
+	}
+}
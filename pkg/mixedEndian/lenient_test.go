@@ -0,0 +1,119 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+type PartlyModelledStruct struct {
+	A     uint16
+	Ratio float64 `size:"8"`
+	Extra struct {
+		Weight complex64
+		Flags  uint8
+	} `size:"9"`
+	B uint16
+}
+
+func TestLenient(t *testing.T) {
+	wire := []byte{
+		0x01, 0x02,
+		0x3F, 0xF0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09,
+		0x03, 0x04,
+	}
+
+	got := &PartlyModelledStruct{Ratio: 2}
+	if err := NewDecoder(bytes.NewReader(wire), BigEndian, WithLenient(true)).Decode(got); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if want := (&PartlyModelledStruct{A: 0x0102, B: 0x0304}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Decode() = %+v, wanted %+v", got, want)
+	}
+
+	// Without leniency, or with too few bytes to skip, decoding fails
+	err := NewDecoder(bytes.NewReader(wire), BigEndian).Decode(&PartlyModelledStruct{})
+	if !errors.Is(err, ErrUnexpectedType) {
+		t.Errorf("Decode() error = %v, wanted %v", err, ErrUnexpectedType)
+	}
+	err = NewDecoder(bytes.NewReader(wire[:6]), BigEndian, WithLenient(true)).Decode(&PartlyModelledStruct{})
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Decode() error = %v, wanted %v", err, io.ErrUnexpectedEOF)
+	}
+
+	// Fields without a size can't be skipped
+	err = NewDecoder(bytes.NewReader(wire), BigEndian, WithLenient(true)).Decode(&struct {
+		A     uint16
+		Ratio float64
+	}{})
+	if !errors.Is(err, ErrUnexpectedType) {
+		t.Errorf("Decode() error = %v, wanted %v", err, ErrUnexpectedType)
+	}
+}
+
+// TestReadableMatchesRead checks readable says a field can be read exactly when reading it doesn't
+// fail for its type, for fields of every kind and of each tag giving a type a wire form
+func TestReadableMatchesRead(t *testing.T) {
+	tests := []struct {
+		typ reflect.Type
+		tag reflect.StructTag
+	}{
+		{reflect.TypeOf(false), ""},
+		{reflect.TypeOf(int8(0)), ""},
+		{reflect.TypeOf(int16(0)), ""},
+		{reflect.TypeOf(int32(0)), ""},
+		{reflect.TypeOf(int64(0)), ""},
+		{reflect.TypeOf(0), `size:"4"`},
+		{reflect.TypeOf(uint8(0)), ""},
+		{reflect.TypeOf(uint16(0)), ""},
+		{reflect.TypeOf(uint32(0)), ""},
+		{reflect.TypeOf(uint64(0)), ""},
+		{reflect.TypeOf(uint(0)), `size:"4"`},
+		{reflect.TypeOf(uintptr(0)), ""},
+		{reflect.TypeOf(float32(0)), ""},
+		{reflect.TypeOf(float64(0)), ""},
+		{reflect.TypeOf(complex64(0)), ""},
+		{reflect.TypeOf(complex128(0)), ""},
+		{reflect.TypeOf(""), `size:"4"`},
+		{reflect.TypeOf([2]uint16{}), ""},
+		{reflect.TypeOf([2]complex64{}), ""},
+		{reflect.TypeOf([]uint16{}), `count:"2"`},
+		{reflect.TypeOf([]complex64{}), `count:"2"`},
+		{reflect.TypeOf(map[uint8]uint16{}), `count:"2"`},
+		{reflect.TypeOf(map[uint8]complex64{}), `count:"2"`},
+		{reflect.TypeOf(struct{ A uint16 }{}), ""},
+		{reflect.TypeOf(struct{ A complex64 }{}), ""},
+		{reflect.TypeOf(new(uint16)), ""},
+		{reflect.TypeOf(new(complex64)), ""},
+		{reflect.TypeOf((*any)(nil)).Elem(), ""},
+		{reflect.TypeOf(make(chan int)), ""},
+		{reflect.TypeOf(func() {}), ""},
+		{uint24Type, ""},
+		{int48Type, ""},
+		{uint128Type, ""},
+		{nbo32Type, ""},
+		{hardwareAddrType, ""},
+		{timecodeType, ""},
+		{serializableType, ""},
+		{timeType, ""},
+		{timeType, `epoch:"unix"`},
+		{durationType, `dur:"ms"`},
+		{reflect.TypeOf(float64(0)), `floatfmt:"ibm32"`},
+		{reflect.TypeOf(float64(0)), `norm:"int16"`},
+		{reflect.TypeOf(float32(0)), `pcm:"alaw"`},
+		{bigIntPtrType, `ssh:"mpint"`},
+		{reflect.TypeOf([]byte{}), `compress:"gzip"`},
+		{reflect.TypeOf([]byte{}), `raw:"true"`},
+	}
+	for _, tt := range tests {
+		sf := reflect.StructField{Name: "F", Type: tt.typ, Tag: tt.tag}
+		v := reflect.New(reflect.StructOf([]reflect.StructField{sf}))
+		err := NewDecoder(bytes.NewReader(make([]byte, 64)), BigEndian).Decode(v.Interface())
+		if got, want := readable(sf), !errors.Is(err, ErrUnexpectedType); got != want {
+			t.Errorf("readable(%s `%s`) = %t, where reading it gave error %v", tt.typ, tt.tag, got, err)
+		}
+	}
+}
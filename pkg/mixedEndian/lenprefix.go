@@ -0,0 +1,87 @@
+package mixedEndian
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// lenPrefix is the unsigned integer leading a slice or map tagged lenprefix with its count
+type lenPrefix struct {
+	t reflect.Type
+
+	// o is the prefix's own byte order, or nil to use the field's
+	o binary.ByteOrder
+}
+
+// lenPrefixOf parses sf's lenprefix tag, such as "uint16" or "uint16,big", reporting whether
+// it's set
+func lenPrefixOf(sf reflect.StructField) (p lenPrefix, ok bool, err error) {
+	s := sf.Tag.Get("lenprefix")
+	if s == "" {
+		return p, false, nil
+	}
+
+	name, endian, _ := strings.Cut(s, ",")
+	if p.t, err = tagUintType(name); err != nil {
+		return p, true, err
+	}
+	switch endian {
+	case "":
+	case "big":
+		p.o = BigEndian
+	case "little":
+		p.o = LittleEndian
+	default:
+		return p, true, fmt.Errorf("%w Unknown lenprefix endian %q", ErrTag, endian)
+	}
+
+	switch t := sf.Type; {
+	case t.Kind() != reflect.Slice && t.Kind() != reflect.Map:
+		return p, true, fmt.Errorf("%w lenprefix needs a slice or map; Got %s", ErrUnexpectedType, t.String())
+	case sf.Tag.Get("len") != "" || sf.Tag.Get("countfrom") != "" || sf.Tag.Get("count") != "" || sf.Tag.Get("dims") != "":
+		return p, true, fmt.Errorf("%w lenprefix can't be combined with another count", ErrTag)
+	case sf.Tag.Get("rle") != "" || sf.Tag.Get("ssh") != "":
+		return p, true, fmt.Errorf("%w lenprefix can't be combined with rle or ssh, which count themselves", ErrTag)
+	}
+	return p, true, nil
+}
+
+// order is the byte order of the prefix of a field in order o
+func (p lenPrefix) order(o binary.ByteOrder) binary.ByteOrder {
+	if p.o != nil {
+		return p.o
+	}
+	return o
+}
+
+// sliceLen is sliceLen, but reading the count of length prefixed fields, in byte order o unless
+// their tag gives another
+func (r *reader) sliceLen(v reflect.Value, sf reflect.StructField, o binary.ByteOrder) (int, bool, error) {
+	p, ok, err := lenPrefixOf(sf)
+	if err != nil {
+		return 0, true, err
+	} else if !ok {
+		return sliceLen(v, sf)
+	}
+
+	u, err := r.readUint(p.t, p.order(o))
+	if err != nil {
+		return 0, true, err
+	}
+	if u > lenMax {
+		return 0, true, fmt.Errorf("%w lenprefix of %d is over %d", ErrLength, u, lenMax)
+	}
+	return int(u), true, nil
+}
+
+// writeLenPrefix writes the count of f, should sf tag it length prefixed, in byte order o
+// unless its tag gives another
+func (w *writer) writeLenPrefix(sf reflect.StructField, f reflect.Value, o binary.ByteOrder) error {
+	p, ok, err := lenPrefixOf(sf)
+	if err != nil || !ok {
+		return err
+	}
+	return w.writeUint(p.t, uint64(f.Len()), p.order(o))
+}
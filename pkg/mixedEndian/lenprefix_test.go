@@ -0,0 +1,145 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type PrefixedRecord struct {
+	Kind    uint8
+	Samples []uint16         `lenprefix:"uint16,big" endian:"little"`
+	Names   []uint8          `lenprefix:"uint8"`
+	Table   map[uint8]uint16 `lenprefix:"uint32,little"`
+}
+
+func TestLenPrefix(t *testing.T) {
+	want := &PrefixedRecord{
+		Kind:    7,
+		Samples: []uint16{0x0102, 0x0304, 0x0506},
+		Names:   []uint8{'a', 'b'},
+		Table:   map[uint8]uint16{9: 0x0A0B},
+	}
+	wire := []byte{
+		0x07,
+		0x00, 0x03, 0x02, 0x01, 0x04, 0x03, 0x06, 0x05, // big endian count, little endian elements
+		0x02, 'a', 'b',
+		0x01, 0x00, 0x00, 0x00, 0x09, 0x0A, 0x0B, // little endian count, big endian entries
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, want); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+
+	var data any = &PrefixedRecord{}
+	if err := Read(bytes.NewReader(wire), BigEndian, &data); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("Read() data = %v, wanted %v", data, want)
+	}
+
+	// Without an order of its own the count follows the field's
+	var little any = &struct {
+		A []uint16 `lenprefix:"uint16" endian:"little"`
+	}{}
+	if err := Read(bytes.NewReader([]byte{0x01, 0x00, 0x02, 0x01}), BigEndian, &little); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	} else if a := reflect.ValueOf(little).Elem().Field(0).Interface(); !reflect.DeepEqual(a, []uint16{0x0102}) {
+		t.Errorf("Read() A = %v, wanted [258]", a)
+	}
+}
+
+func TestLenPrefixErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    any
+		wantErr error
+	}{
+		{
+			name: "not an unsigned type",
+			data: &struct {
+				A []uint8 `lenprefix:"int16"`
+			}{},
+			wantErr: ErrTag,
+		},
+		{
+			name: "unknown order",
+			data: &struct {
+				A []uint8 `lenprefix:"uint16,middle"`
+			}{},
+			wantErr: ErrTag,
+		},
+		{
+			name: "counted twice",
+			data: &struct {
+				N uint8
+				A []uint8 `len:"N" lenprefix:"uint16"`
+			}{},
+			wantErr: ErrTag,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Write(&bytes.Buffer{}, BigEndian, tt.data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Write() error = %v, wanted %v", err, tt.wantErr)
+			}
+			if err := Read(bytes.NewReader(make([]byte, 8)), BigEndian, &tt.data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Read() error = %v, wanted %v", err, tt.wantErr)
+			}
+			if _, err := Describe(tt.data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Describe() error = %v, wanted %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Read and Write ignore it elsewhere, as they do other counts, but Describe doesn't
+	if _, err := Describe(struct {
+		A [2]uint8 `lenprefix:"uint16"`
+	}{}); !errors.Is(err, ErrUnexpectedType) {
+		t.Errorf("Describe() error = %v, wanted %v", err, ErrUnexpectedType)
+	}
+
+	// Counts must fit the prefix
+	long := struct {
+		A []uint8 `lenprefix:"uint8"`
+	}{A: make([]uint8, 256)}
+	if err := Write(&bytes.Buffer{}, BigEndian, long); !errors.Is(err, ErrRange) {
+		t.Errorf("Write() error = %v, wanted %v", err, ErrRange)
+	}
+
+	// and read counts must be within lenMax
+	for _, wire := range [][]byte{
+		{0x0F, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		{0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+	} {
+		var huge any = &struct {
+			A []uint64 `lenprefix:"uint64"`
+		}{}
+		if err := Read(bytes.NewReader(wire), BigEndian, &huge); !errors.Is(err, ErrLength) {
+			t.Errorf("Read() of % X error = %v, wanted %v", wire, err, ErrLength)
+		}
+	}
+}
+
+func TestLenPrefixTemplate010(t *testing.T) {
+	buf := &strings.Builder{}
+	if err := ExportTemplate010(buf, PrefixedRecord{}); err != nil {
+		t.Fatalf("ExportTemplate010() error = %v", err)
+	}
+	for _, want := range []string{
+		"\tBigEndian();\n\tuint16 SamplesLen; // length prefix of Samples\n\tLittleEndian();\n\tuint16 Samples[SamplesLen];",
+		"\tubyte NamesLen; // length prefix of Names\n",
+		"ubyte Names[NamesLen];",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("ExportTemplate010() = %s, wanted it to contain %q", buf.String(), want)
+		}
+	}
+}
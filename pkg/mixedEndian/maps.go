@@ -0,0 +1,81 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// writeMap writes the entries of map v, each key followed by its value. Canonical writers order
+// entries by their encoded keys; others leave them in Go's map iteration order, which varies.
+func (w *writer) writeMap(v reflect.Value, o binary.ByteOrder) error {
+	if !w.canonical {
+		// Entries' order varies between writes, so the path of a write stops at the map
+		sub := *w
+		sub.progress = nil
+		for it := v.MapRange(); it.Next(); {
+			if err := sub.writeOrdered(it.Key(), o); err != nil {
+				return err
+			}
+			if err := sub.writeOrdered(it.Value(), o); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	type entry struct {
+		key []byte
+		val reflect.Value
+	}
+	entries := make([]entry, 0, v.Len())
+	sub := *w
+	for it := v.MapRange(); it.Next(); {
+		buf := &bytes.Buffer{}
+		sub.w = buf
+		if err := sub.writeOrdered(it.Key(), o); err != nil {
+			return err
+		}
+		entries = append(entries, entry{key: buf.Bytes(), val: it.Value()})
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return bytes.Compare(entries[i].key, entries[j].key) < 0 })
+	for i, e := range entries {
+		if i > 0 && bytes.Equal(entries[i-1].key, e.key) {
+			return fmt.Errorf("%w Keys of %s encode the same, so have no canonical order", ErrCanonical, v.Type().String())
+		}
+		if _, err := w.w.Write(e.key); err != nil {
+			return err
+		}
+		if err := w.writeOrdered(e.val, o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readMap reads n entries into map f, each key followed by its value. Keys must be distinct, as
+// entries would otherwise be lost.
+func (r *reader) readMap(f reflect.Value, n int, o binary.ByteOrder) error {
+	t := f.Type()
+	m := r.makeMap(t, n)
+	key, val := reflect.New(t.Key()).Elem(), reflect.New(t.Elem()).Elem()
+	for i := 0; i < n; i++ {
+		key.Set(reflect.Zero(t.Key()))
+		val.Set(reflect.Zero(t.Elem()))
+		if err := r.readOrdered(key, o); err != nil {
+			return err
+		}
+		if err := r.readOrdered(val, o); err != nil {
+			return err
+		}
+		if m.MapIndex(key).IsValid() {
+			return fmt.Errorf("%w Key %v appears more than once", ErrValidation, key.Interface())
+		}
+		m.SetMapIndex(key, val)
+	}
+	f.Set(m)
+	return nil
+}
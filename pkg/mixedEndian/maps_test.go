@@ -0,0 +1,86 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type MapStruct struct {
+	N       uint8
+	Entries map[uint16]uint32 `len:"N"`
+}
+
+func TestCanonicalMap(t *testing.T) {
+	data := MapStruct{N: 64, Entries: map[uint16]uint32{}}
+	for i := 0; i < 64; i++ {
+		data.Entries[uint16(i*37%64)] = uint32(i)
+	}
+
+	encode := func() []byte {
+		buf := &bytes.Buffer{}
+		if err := NewEncoder(buf, BigEndian, WithCanonical(true)).Encode(data); err != nil {
+			t.Fatalf("Encode() error = %v", err)
+		}
+		return buf.Bytes()
+	}
+	first, second := encode(), encode()
+	if !bytes.Equal(first, second) {
+		t.Errorf("Encode() = % X, then % X", first, second)
+	}
+	if len(first) != 1+64*6 {
+		t.Fatalf("Encode() wrote %d bytes, wanted %d", len(first), 1+64*6)
+	}
+
+	// Entries are in order of their keys
+	for i := 0; i < 64; i++ {
+		if k := int(first[1+i*6])<<8 | int(first[2+i*6]); k != i {
+			t.Fatalf("Entry %d has key %d", i, k)
+		}
+	}
+
+	var got any = &MapStruct{}
+	if err := Read(bytes.NewReader(first), BigEndian, &got); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, &data) {
+		t.Errorf("Read() = %v, wanted %v", got, data)
+	}
+}
+
+func TestCanonicalMapErrors(t *testing.T) {
+	// Keys that encode the same have no order
+	type key struct {
+		A uint8
+		B uint8 `const:"0"`
+	}
+	type sameKeys struct {
+		M map[key]uint8
+	}
+	data := sameKeys{M: map[key]uint8{{1, 1}: 1, {1, 2}: 2}}
+	if err := NewEncoder(&bytes.Buffer{}, BigEndian, WithCanonical(true)).Encode(data); !errors.Is(err, ErrCanonical) {
+		t.Errorf("Encode() error = %v, wanted %v", err, ErrCanonical)
+	}
+	if err := NewEncoder(&bytes.Buffer{}, BigEndian).Encode(data); err != nil {
+		t.Errorf("Encode() error = %v", err)
+	}
+
+	// Map lengths must agree with their tags
+	if err := Write(&bytes.Buffer{}, BigEndian, MapStruct{N: 2, Entries: map[uint16]uint32{1: 1}}); !errors.Is(err, ErrLength) {
+		t.Errorf("Write() error = %v, wanted %v", err, ErrLength)
+	}
+
+	var got any = &struct{ M map[uint8]uint8 }{}
+	if err := Read(bytes.NewReader([]byte{1, 2}), BigEndian, &got); !errors.Is(err, ErrLength) {
+		t.Errorf("Read() error = %v, wanted %v", err, ErrLength)
+	}
+
+	// Repeated keys would lose an entry
+	got = &struct {
+		M map[uint8]uint8 `count:"2"`
+	}{}
+	if err := Read(bytes.NewReader([]byte{1, 2, 1, 3}), BigEndian, &got); !errors.Is(err, ErrValidation) {
+		t.Errorf("Read() duplicate key error = %v, wanted %v", err, ErrValidation)
+	}
+}
@@ -0,0 +1,230 @@
+package mixedEndian
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// ExportMarkdown writes protocol documentation of the structs samples lay out as, in Markdown.
+//
+// Where WireDoc gives one table flattening nested structs, ExportMarkdown gives each struct its
+// own section, and table of its fields: their offsets and sizes in bytes, names, types, byte
+// orders, the constraints their tags place on them, such as const values, valid ranges, and the
+// fields holding their lengths, and the descriptions in their doc tags. Fields of struct types,
+// and arrays and slices of them, link to those structs' sections, which follow. Offsets
+// following a variable sized field are given from it, as in "after Payload + 2".
+//
+// Byte orders are those the fields' own tags give, "default" meaning the order given to Read or
+// Write, or within a nested struct that of its field.
+func ExportMarkdown(w io.Writer, samples ...any) error {
+	d := &markdownDoc{
+		anchors: map[reflect.Type]string{},
+		names:   map[reflect.Type]string{},
+		used:    map[string]bool{},
+		written: map[reflect.Type]bool{},
+	}
+	for _, sample := range samples {
+		sl, err := Describe(sample)
+		if err != nil {
+			return err
+		}
+		d.name(sl.Type, "anonymous")
+		if err = d.section(sl.Type); err != nil {
+			return err
+		}
+	}
+
+	// Nothing's written unless it all could be
+	_, err := io.WriteString(w, strings.TrimSuffix(d.b.String(), "\n"))
+	return err
+}
+
+type markdownDoc struct {
+	b strings.Builder
+
+	// anchors and names are those of the structs with sections, used those anchors taken
+	anchors map[reflect.Type]string
+	names   map[reflect.Type]string
+	used    map[string]bool
+
+	// written are the structs whose sections are written
+	written map[reflect.Type]bool
+}
+
+// name gives struct t a name and an anchor, should it not have them. Anonymous structs are named
+// by path, that of the field they were first found in.
+func (d *markdownDoc) name(t reflect.Type, path string) {
+	if _, ok := d.names[t]; ok {
+		return
+	}
+	name := t.Name()
+	if name == "" {
+		name = path
+	}
+
+	anchor := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return '-'
+	}, name)
+	for base, n := anchor, 2; d.used[anchor]; n++ {
+		anchor = fmt.Sprintf("%s-%d", base, n)
+	}
+	d.names[t], d.anchors[t], d.used[anchor] = name, anchor, true
+}
+
+// section writes the section of struct t, once named, then those of the structs within it
+func (d *markdownDoc) section(t reflect.Type) error {
+	if d.written[t] {
+		return nil
+	}
+	d.written[t] = true
+
+	// Laid out alone, fields show only the orders their own tags give
+	sl, err := LayoutOf(t)
+	if err != nil {
+		return err
+	}
+	rows, err := wireDocRows(nil, sl, docPos{}, nil, 0, "", false)
+	if err != nil {
+		return err
+	}
+
+	var nested []FieldLayout
+	for _, f := range sl.Fields {
+		if f.Elem != nil {
+			d.name(f.Elem.Type, d.names[t]+"."+f.Name)
+			nested = append(nested, f)
+		}
+	}
+
+	fmt.Fprintf(&d.b, "<a id=\"%s\"></a>\n\n## %s\n\n", d.anchors[t], d.names[t])
+	if sl.Size >= 0 {
+		fmt.Fprintf(&d.b, "Size: %d bytes\n\n", sl.Size)
+	} else {
+		fmt.Fprintf(&d.b, "Size: variable\n\n")
+	}
+	writeWireDocTable(&d.b, rows, d.typeName, markdownConstraints)
+	d.b.WriteString("\n")
+
+	for _, f := range nested {
+		if err = d.section(f.Elem.Type); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// typeName is the Go type of f, linking to the section of any struct it holds
+func (d *markdownDoc) typeName(f FieldLayout) string {
+	if f.Elem == nil {
+		return "`" + f.Type.String() + "`"
+	}
+
+	// Arrays, slices, and pointers keep their brackets, around the struct's section name
+	prefix := ""
+	for t := f.Type; t.Kind() != reflect.Struct; t = t.Elem() {
+		switch t.Kind() {
+		case reflect.Pointer:
+			prefix += "*"
+		case reflect.Array:
+			prefix += fmt.Sprintf("[%d]", t.Len())
+		case reflect.Slice:
+			prefix += "[]"
+		}
+	}
+	return fmt.Sprintf("[`%s%s`](#%s)", prefix, d.names[f.Elem.Type], d.anchors[f.Elem.Type])
+}
+
+// markdownConstraints describes the limits f's tags place on it
+func markdownConstraints(f FieldLayout) (cs []string) {
+	tag := func(key string) string { return f.Tag.Get(key) }
+
+	if c := tag("const"); c != "" {
+		cs = append(cs, "always "+c)
+	}
+	if e := tag("enum"); e != "" {
+		s := "one of " + e
+		if d := tag("enumdefault"); d != "" {
+			s += ", others read as " + d
+		}
+		cs = append(cs, s)
+	}
+	switch lo, hi := tag("minvalue"), tag("maxvalue"); {
+	case lo != "" && hi != "":
+		cs = append(cs, lo+" to "+hi)
+	case lo != "":
+		cs = append(cs, "at least "+lo)
+	case hi != "":
+		cs = append(cs, "at most "+hi)
+	}
+	if tag("finite") == "true" {
+		cs = append(cs, "finite")
+	}
+	if e := tag("epoch"); e != "" {
+		u := tag("epochunit")
+		if u == "" {
+			u = "s"
+		}
+		cs = append(cs, u+" since "+e)
+	}
+	if f.Type == timecodeType {
+		cs = append(cs, "BCD timecode")
+	}
+	if tag("bitreverse") == "true" {
+		cs = append(cs, "bits of each byte reversed")
+	}
+	if b := tag("bitwidth"); b != "" {
+		cs = append(cs, b+" bits")
+	}
+
+	// Lengths, and the fields holding them
+	if f.CountRef != "" {
+		cs = append(cs, "count in "+f.CountRef)
+	}
+	if p := tag("lenprefix"); p != "" {
+		cs = append(cs, "led by a "+strings.Split(p, ",")[0]+" count")
+	}
+	if dims := tag("dims"); dims != "" {
+		cs = append(cs, "dimensions in "+dims)
+	}
+	if s := tag("lengthscope"); s != "" {
+		cs = append(cs, "bytes of "+s)
+	}
+	if tag("restlen") != "" {
+		cs = append(cs, "rest of the length scope")
+	}
+	if tag("sizeof_field") != "" {
+		cs = append(cs, "bytes of the struct")
+	}
+
+	// Fields that are only sometimes there
+	if p := tag("presentif"); p != "" {
+		cs = append(cs, "present if "+p)
+	}
+	if v := tag("added_in"); v != "" {
+		cs = append(cs, "from version "+v)
+	}
+	if v := tag("removed_in"); v != "" {
+		cs = append(cs, "before version "+v)
+	}
+
+	// Fields derived from others
+	if m := tag("mirror"); m != "" {
+		cs = append(cs, "copy of "+m)
+	}
+	if s := tag("network_checksum"); s != "" {
+		cs = append(cs, "Internet checksum of "+s)
+	}
+	if c := tag("crc"); c != "" {
+		if r := tag("crcrange"); r != "" {
+			c += " of " + r
+		}
+		cs = append(cs, c)
+	}
+	return
+}
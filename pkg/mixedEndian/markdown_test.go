@@ -0,0 +1,43 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type SpecPacket struct {
+	Magic   [2]byte `const:"0x4D,0x45" doc:"Always \"ME\""`
+	Version uint8   `enum:"1,2" enumdefault:"1"`
+	Level   int8    `minvalue:"-3" maxvalue:"3" doc:"Signed | clamped"`
+	Route   TaggedStruct
+	Hops    uint8
+	Path    []TaggedStruct `len:"Hops" endian:"little"`
+	Payload []uint16       `lenprefix:"uint16"`
+	Check   uint32         `endian:"big" padding_before:"2"`
+	Trailer struct {
+		Length uint16 `lengthscope:"Kind..Rest"`
+		Kind   uint8
+		Rest   []byte `restlen:"true" doc:"To the end of the record"`
+		Sum    uint16 `network_checksum:"Length:Rest"`
+	}
+	Origin *TaggedStruct `endian:"big"`
+}
+
+func TestExportMarkdown(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := ExportMarkdown(buf, SpecPacket{}); err != nil {
+		t.Fatalf("ExportMarkdown() error = %v", err)
+	}
+	checkGolden(t, "SpecPacket.md", buf.Bytes())
+}
+
+func TestExportMarkdownErrors(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := ExportMarkdown(buf, SpecPacket{}, 7); !errors.Is(err, ErrUnexpectedType) {
+		t.Errorf("ExportMarkdown() error = %v, wanted %v", err, ErrUnexpectedType)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("ExportMarkdown() wrote %d bytes before failing, wanted none", buf.Len())
+	}
+}
@@ -0,0 +1,68 @@
+package mixedEndian
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Merge combines two structs of the same type field by field, taking each field from overlay
+// unless it's the zero value there, in which case it comes from base. Nested structs are merged
+// the same way, so a partially filled nested struct in overlay only replaces what it sets.
+// Unexported fields always come from base.
+//
+// base and overlay may both be structs or both be pointers to them; the result matches.
+// Either being nil is an error.
+// The merged struct must be writable with defaultEndian, otherwise an error is returned.
+func Merge(base, overlay any, defaultEndian binary.ByteOrder) (any, error) {
+	b, o := reflect.ValueOf(base), reflect.ValueOf(overlay)
+	if !b.IsValid() || !o.IsValid() {
+		return nil, fmt.Errorf("%w Can't merge nil", ErrUnexpectedType)
+	}
+	if b.Type() != o.Type() {
+		return nil, fmt.Errorf("%w Can't merge %s with %s", ErrUnexpectedType, o.Type().String(), b.Type().String())
+	}
+
+	ptr := b.Kind() == reflect.Pointer
+	if ptr {
+		if b.IsNil() || o.IsNil() {
+			return nil, fmt.Errorf("%w Got nil %s", ErrUnexpectedType, b.Type().String())
+		}
+		b, o = b.Elem(), o.Elem()
+	}
+	if b.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w Expected struct; Got %s", ErrUnexpectedType, b.Type().String())
+	}
+
+	out := reflect.New(b.Type())
+	out.Elem().Set(b)
+	mergeStruct(out.Elem(), o)
+
+	w := writer{w: io.Discard, o: defaultEndian, ctx: context.Background()}
+	if err := w.writeOrdered(out, defaultEndian); err != nil {
+		return nil, err
+	}
+
+	if ptr {
+		return out.Interface(), nil
+	}
+	return out.Elem().Interface(), nil
+}
+
+// mergeStruct overwrites the fields of dst, already a copy of base, with those set in overlay
+func mergeStruct(dst, overlay reflect.Value) {
+	for i := 0; i < dst.NumField(); i++ {
+		f, of := dst.Field(i), overlay.Field(i)
+		if !f.CanSet() || of.IsZero() {
+			continue
+		}
+
+		if f.Kind() == reflect.Struct {
+			mergeStruct(f, of)
+		} else {
+			f.Set(of)
+		}
+	}
+}
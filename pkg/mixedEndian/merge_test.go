@@ -0,0 +1,92 @@
+package mixedEndian
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type MergeStruct struct {
+	A      uint16
+	B      TaggedStruct
+	C      *uint32
+	N      uint8
+	Data   []uint8 `len:"N"`
+	hidden uint8
+}
+
+func TestMerge(t *testing.T) {
+	c1, c2 := uint32(1), uint32(2)
+
+	tests := []struct {
+		name    string
+		base    any
+		overlay any
+		want    any
+		wantErr error
+	}{
+		{
+			name:    "overlay wins when set",
+			base:    MergeStruct{A: 1, B: TaggedStruct{A: 2, B: 3}, C: &c1, hidden: 9},
+			overlay: MergeStruct{A: 10, B: TaggedStruct{B: 30}, hidden: 90},
+			want:    MergeStruct{A: 10, B: TaggedStruct{A: 2, B: 30}, C: &c1, hidden: 9},
+		},
+		{
+			name:    "nil pointer from base",
+			base:    &MergeStruct{C: &c1},
+			overlay: &MergeStruct{A: 5},
+			want:    &MergeStruct{A: 5, C: &c1},
+		},
+		{
+			name:    "set pointer from overlay",
+			base:    &MergeStruct{C: &c1},
+			overlay: &MergeStruct{C: &c2},
+			want:    &MergeStruct{C: &c2},
+		},
+		{
+			name:    "slice and length",
+			base:    MergeStruct{C: &c1, N: 1, Data: []uint8{1}},
+			overlay: MergeStruct{N: 2, Data: []uint8{1, 2}},
+			want:    MergeStruct{C: &c1, N: 2, Data: []uint8{1, 2}},
+		},
+		{
+			name:    "result not writable",
+			base:    MergeStruct{C: &c1, N: 1, Data: []uint8{1}},
+			overlay: MergeStruct{N: 2},
+			wantErr: ErrLength,
+		},
+		{
+			name:    "different types",
+			base:    MergeStruct{},
+			overlay: TaggedStruct{},
+			wantErr: ErrUnexpectedType,
+		},
+		{
+			name:    "nil overlay",
+			base:    MergeStruct{},
+			wantErr: ErrUnexpectedType,
+		},
+		{
+			name:    "nil base",
+			overlay: &MergeStruct{},
+			wantErr: ErrUnexpectedType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Merge(tt.base, tt.overlay, BigEndian)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Merge() error = %v, wanted %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Merge() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Merge() = %+v, wanted %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -8,13 +8,253 @@
 //		b uint16 `endian:"little"`
 //		c uint32 `endian:"big"`
 //	}
+//
+// Slices are read and written element by element. When reading, a slice's length may be taken
+// from an earlier integer field of the same struct with a "len" tag:
+//
+//	type def struct {
+//		N    uint8
+//		Data []uint16 `len:"N"`
+//	}
+//
+// Writing such a struct errors if the slice's length doesn't match the field.
+// "countfrom" is a synonym for "len", and `count:"16"` gives a literal element count, making the
+// slice as fixed in size as an array, which `count:"fixed:16"` spells out. `count:"16,pad"` pads
+// shorter slices with zero values.
+//
+// Slices and maps tagged `lenprefix:"uint16"` are instead led by their element count, as the named
+// unsigned integer type, in the field's byte order. `lenprefix:"uint16,big"` gives the count its
+// own, so a big endian count can lead little endian elements:
+//
+//	type pqr struct {
+//		Samples []int16 `lenprefix:"uint16,big" endian:"little"`
+//	}
+//
+// Nested slices take a length per level from a "dims" tag, naming earlier integer fields outermost
+// first, and are read and written in row-major order:
+//
+//	type image struct {
+//		Height, Width uint16
+//		Pixels        [][]uint16 `dims:"Height,Width"`
+//	}
+//
+// 2-D arrays, and nested slices with a dims tag, tagged `order:"colmajor"` are stored a column at
+// a time, as Fortran lays them out, while staying indexed [row][column] in Go.
+//
+// Fields tagged `crcblocks:"16,crc16-dnp"` are split into blocks of 16 bytes, each followed by
+// its little endian CRC, the last block possibly short, as DNP3 link frames are. CRCs are checked
+// as they're read, failing with ErrChecksum. CRCBlockReader and CRCBlockWriter do the same for
+// streams. The CRC is named as a crc tag names it, below.
+//
+// Serial links that byte-stuff their frames are read a frame at a time by the FrameReaders
+// NewHDLCReader, NewSLIPReader, and NewCOBSReader return, so a struct can be decoded from one,
+// and written by their FrameWriters.
+//
+// Slices tagged `sparse:"uint16"` store only their nonzero elements, as a count of them followed by
+// index and value pairs. The count and indices are of the tagged type, and the full length of the
+// slice must be given by one of the tags above.
+//
+// Slices tagged `rle:"count=uint8"` store runs of equal elements as a run length, of the given type,
+// followed by the element. Runs are read until the slice's length, from the tags above, is filled.
+// Without one, a "bytes" option names an earlier field holding the encoded size, as in
+// `rle:"count=uint8,bytes=Size,max=4096"`, with "max" capping the elements it expands to.
+// Arrays may be run length encoded too, filling their length, and `rle:"true"`, for bitmap masks
+// and the like, is short for `rle:"count=uint16"`.
+//
+// time.Duration fields tagged `dur:"ms"` are stored as a count of milliseconds, or whichever of
+// "ns", "us", "ms", or "s" is given, truncated toward zero when written. The count is an int64
+// unless a "width" tag names another integer type, as in `dur:"ms" width:"uint32"`.
+//
+// time.Time fields tagged `epoch:"1601"` are stored as a count of units since the start of that
+// year, UTC, or since the date or RFC 3339 time given. The unit is seconds unless an "epochunit"
+// tag gives another, such as "100ns": any of the units of dur, optionally multiplied, dividing or
+// a multiple of a second. Times are read in UTC, and truncated to a whole unit toward the past
+// when written. As with durations the count is an int64 unless a "width" tag says otherwise:
+//
+//	type stamps struct {
+//		FileTime time.Time `epoch:"1601" epochunit:"100ns" width:"uint64" endian:"little"`
+//		HFS      time.Time `epoch:"1904" width:"uint32" endian:"big"`
+//	}
+//
+// Timecode fields are SMPTE timecodes, stored as a uint32 of BCD digits HHMMSSFF with drop
+// frame, colour frame, and field mark flags in the bits the digits leave spare. A tag such as
+// `timecode:"fm=23"` moves a flag to another spare bit, or with "-" leaves it out.
+//
+// Fields tagged `floatfmt:"decimal64-bid"` hold IEEE 754 decimal floating point numbers, in the
+// binary integer ("-bid") or densely packed decimal ("-dpd") encoding of decimal32 or decimal64.
+// They may be Decimals, strings, or big.Rats, and are always written canonically. Tagging them
+// `finite:"true"` rejects NaNs and infinities as they're read, with ErrValidation.
+//
+// net.HardwareAddr fields are 6 bytes, or 8 when tagged `size:"8"` for EUI-64.
+//
+// Uint24, Int24, Uint48, and Int48 are integers of 3 and 6 bytes, and Uint128 and Int128 of 16,
+// in the field's byte order like any other.
+//
+// NBO16, NBO32, and NBO64 are integers always in NetworkByteOrder, big endian unless it's been
+// changed, whatever the field's byte order.
+//
+// Strings are fixed size, given by a "size" tag. Trailing NULs are trimmed when read and added
+// when written, which a "trim" tag of "space" changes to spaces. `trim:"none"` keeps every byte:
+//
+//	type mno struct {
+//		Name  string `size:"16"`
+//		Label string `size:"8" trim:"space"`
+//	}
+//
+// Strings tagged `string:"gsm7,len=Field"` are GSM 03.38 text packed into 7 bits per character,
+// with Field holding the number of septets. Characters outside the alphabet error when written,
+// unless a replacement is given, as in `string:"gsm7,len=UDL,replace=?"`.
+//
+// Unsigned integers tagged `encoding:"gray"`, or `gray:"true"`, are stored as reflected binary Gray code.
+// The conversion applies to the whole value, after byte ordering, so composes with any width.
+//
+// Integers and bytes tagged `bitreverse:"true"` are stored with the bits of each byte in reverse
+// order, most significant bit last, as some serial formats send them. Bytes keep their places,
+// ordered as the field's byte order says, only the bits within them reversed.
+//
+// UUID is a [16]byte RFC 4122 UUID, formatted by its String method. UUIDs are byte sequences, so
+// any endian tag has no effect; a [16]byte tagged `encoding:"uuid"` is stored as it is.
+// One tagged `encoding:"uuid_le"` is stored with its first three fields little endian,
+// as COM and Variant-1 GUIDs are.
+//
+// Fields tagged `ssh:"string"` (a string or []byte), `ssh:"mpint"` (a *big.Int), or
+// `ssh:"namelist"` (a []string) are RFC 4251 data types, led by a big endian uint32 length
+// whatever the field's byte order. mpints are read and written in their minimal form.
+//
+// string or []byte fields tagged `compress:"gzip"` are compressed as they're written, led by the
+// compressed length as a uint32 in the field's byte order, and decompressed when read. "gzip" and
+// "zlib" are built in, and RegisterCompressor names others.
+//
+// A field tagged `presentif:"Flag"` is only there when Flag, an earlier bool field, is true.
+// Otherwise it's read as its zero value. It's written when either Flag is set or the field isn't
+// its zero value, Flag being written as set to match.
+//
+// Optional fields flag their own presence, with a leading byte, a bit of an earlier field, or by
+// simply ending the input, as their optional tag says. See Optional.
+//
+// Fields tagged `padding_before:"2"` or `padding_after:"2"` are preceded or followed by 2 bytes of
+// padding, written as zeros, or as the fill given after a comma, as in `padding_after:"1,0xFF"`.
+// Padding is discarded when read, whatever it holds, and is left out along with optional fields.
+//
+// Floats, and arrays and slices of them, tagged `norm:"unorm8"`, `norm:"unorm16"`, `norm:"snorm8"`,
+// or `norm:"snorm16"` are stored as normalized integers, as graphics vertex formats store them.
+// unorms map to [0, 1] and snorms to [-1, 1], converted as Vulkan and Direct3D do.
+//
+// int16 or float32 samples, and arrays and slices of them, tagged `pcm:"alaw"` or `pcm:"ulaw"` are
+// stored as G.711 companded bytes, expanded by table when read and compressed as the G.711
+// reference encoder does when written. float32 samples are scaled to [-1, 1).
+//
+// Maps are written as their entries, each key followed by its value, and read as slices are, sized
+// by a len, countfrom, or count tag. Their entries are written in Go's map iteration order, which
+// varies between runs, unless written by an Encoder made WithCanonical(true), which orders them by
+// their encoded keys. Nothing else depends on iteration order, so canonical Encoders write equal
+// values as identical bytes.
+//
+// Structs marked `iso8583:"true"` on a blank field are ISO 8583 messages: a bitmap follows the
+// fields without a "de" tag, such as the message type, flagging which of the fields tagged
+// `de:"N"` follow, in ascending order of N from 2 to 128. Fields are written when they aren't
+// their zero value, and zeroed when read unless flagged. A secondary bitmap, flagged by bit 1,
+// follows the primary when any element above 64 is present.
+//
+// Fields tagged `added_in:"N"` or `removed_in:"N"` are only in some versions of a protocol.
+// Decoders and Encoders skip them per the version given to their SetVersion; Read and Write, and
+// Decoders and Encoders without a version, handle every field. See VersionNegotiator.
+//
+// A []byte field tagged `raw:"Field"` takes no bytes on the wire, but is filled with the exact
+// bytes Field was read from once its struct is read; one tagged `raw:"struct"` with those of the
+// whole struct. Raw fields aren't written.
+//
+// Integers, and arrays and slices of them, tagged `encoding:"uvarint"` (unsigned) or
+// `encoding:"varint"` (signed, zigzag) use encoding/binary's variable length encodings.
+//
+// Integers tagged `bitwidth:"N"` only hold N significant bits, within a wider wire field.
+// The rest are reserved: they're dropped when read and written as zero. Writing a value needing
+// more than N bits is an error, unless also tagged `clamp:"true"`, which writes the largest N bit value.
+// Signed integers hold an N bit two's complement value, sign extended when read, and clamp to
+// the most negative N bit value too.
+//
+// Integer arrays and slices tagged `delta:"true"` store their first element followed by the
+// difference of each element from the one before. Differences must fit the element type.
+//
+// Fields may also be checked as they are read. A "const" tag gives the only acceptable value
+// (comma separated for arrays and slices), which is also what gets written regardless of the
+// field's contents. An "enum" tag gives a comma separated set of acceptable integer values:
+//
+//	type ghi struct {
+//		Magic [4]byte `const:"0x7F,0x45,0x4C,0x46"`
+//		Class uint8   `enum:"1,2"`
+//	}
+//
+// A field tagged `mirror:"Field"` stores a redundant copy of an earlier field of the same type.
+// The copy is what gets written, whatever the field holds, and reading errors unless they match.
+//
+// Forward compatible readers can give an "enumdefault" alongside an enum, which values outside the
+// enum are read as rather than erroring, as in `enum:"1,2,3" enumdefault:"0"`. The default may be
+// written too, even when outside the enum, so what's read can be written back.
+//
+// An integer field tagged `sizeof_field:"self"` is set, when written, to the encoded size of the
+// whole struct containing it, as frame headers often need. Working that out takes a dry run
+// encoding the struct before the real one, roughly doubling the cost of writing it.
+//
+// A uint16 field tagged `network_checksum:"A:C"` holds the RFC 1071 Internet checksum of the
+// encoded fields A through C, as IP, TCP, and UDP headers do. It's computed with the checksum
+// field's own bytes zeroed, filled in when written, and checked when read, failing with ErrChecksum.
+//
+// Likewise an unsigned field tagged `crc:"crc16-modbus" crcrange:"A:C"` holds a CRC of fields A
+// through C, in the field's own byte order, which `crc:"crc16-modbus,A..C"` also says. Fields
+// outside the range, such as a header, aren't covered. Any 8, 16, 32, or 64 bit CRC can be given by its
+// parameters, as in `crc:"width=16,poly=0x1021,init=0xFFFF,refin=true,refout=true,xorout=0"`, or
+// registered under a name with RegisterCRC. See CRC. `crc_seed:"0x00000000"` and `crc_poly:"1EDC6F41"`
+// tags, in hex, override the initial value and polynomial of the named CRC.
+//
+// An integer field tagged `lengthscope:"A..C"` holds the encoded size of the later fields A
+// through C, set when written. Reading bounds those fields to that many bytes, failing with
+// ErrLength should they need more, and skips any they leave, before carrying on with the rest.
+// A slice tagged `restlen:"true"` ending a scope takes whatever the fields before it leave of
+// it, for a header followed by a payload to the end of the record. What's left must be a whole
+// number of its elements, or reading fails with ErrLength, as it does for elements taking no bytes.
+//
+// Fields tagged `group:"header"`, `group:"body"`, and so on, in runs, may be read a group at a
+// time with Decoder.DecodeGroup, or passed over with Decoder.SkipGroup.
+//
+// Adjacent fixed size fields tagged with the same `overlay:"name"` share one region of the wire,
+// as the members of a C union do, sized by the largest of them. Each is read from the start of
+// the region. The member tagged `overlay:"name,primary"` is written, or without one the first
+// member that isn't its zero value, followed by zeros to fill the region:
+//
+//	type stu struct {
+//		Word uint64    `overlay:"value"`
+//		Pair [2]uint32 `overlay:"value"`
+//	}
+//
+// Fields are laid out back to back without padding. Options applying to a whole struct are set
+// with tags on a zero sized blank field; `encoding:"packed"` states this packed layout explicitly:
+//
+//	type jkl struct {
+//		_ struct{} `encoding:"packed"`
+//		A uint8
+//		B uint32
+//	}
+//
+// Alternatively `align:"8"` pads each field to a multiple of its natural alignment, capped at 8,
+// and the whole struct to a multiple of 8, even when none of its fields need that much.
+//
+// Building with the debug tag checks structs marked `encoding:"require_alignment"` as they're
+// registered with a TypeRegistry, panicking unless they match C's layout. See ValidateAlignment.
+//
+// Building with the network_order_swap tag inverts every byte order conversion in the package.
+// It exists so tests can exercise the byte swapping path of a host with the opposite endianness.
 package mixedEndian
 
 import (
+	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"reflect"
+	"strconv"
+	"strings"
 )
 
 var (
@@ -26,50 +266,210 @@ var (
 
 	// Error wrapped to specify unexpected types encountered during reflection
 	ErrUnexpectedType = fmt.Errorf("Unexpected type.")
+
+	// Error wrapped to specify values which do not fit in their wire representation
+	ErrRange = fmt.Errorf("Value out of range.")
+
+	// Error wrapped to specify bad or mismatched slice lengths
+	ErrLength = fmt.Errorf("Bad length.")
+
+	// Reading a slice with nothing to size it is almost certainly a mistake
+	errNoLength = fmt.Errorf("%w Slice has no len, countfrom, or count tag, so the number of elements to read is unknown", ErrLength)
+
+	// Error wrapped to specify malformed struct tags
+	ErrTag = fmt.Errorf("Bad tag.")
+
+	// Error wrapped to specify structs not laid out as required
+	ErrLayout = fmt.Errorf("Bad layout.")
+
+	// Error wrapped to specify malformed schemas
+	ErrSchema = fmt.Errorf("Bad schema.")
+
+	// Error wrapped when encoding passes the cap set by WithMaxOutput
+	ErrOutputLimit = fmt.Errorf("Output limit exceeded.")
+
+	// Error wrapped when a network_checksum doesn't match what was read
+	ErrChecksum = fmt.Errorf("Bad checksum.")
+
+	// Error wrapped when using a PipelinedEncoder after Close
+	ErrClosed = fmt.Errorf("Closed.")
+
+	// Error wrapped when a WithCanonical Encoder meets a value with no canonical encoding
+	ErrCanonical = fmt.Errorf("Not canonical.")
+
+	// Error wrapped to specify values rejected by a const or enum tag
+	ErrValidation = fmt.Errorf("Validation failed.")
+
+	// Error wrapped when DetectAndDecode matches no registered format
+	ErrUnknownFormat = fmt.Errorf("Unknown format.")
+
+	// Error wrapped when DetectAndDecode matches more than one registered format equally well
+	ErrAmbiguousFormat = fmt.Errorf("Ambiguous format.")
+
+	// Error wrapped when a FrameReader meets a frame that can't be unstuffed
+	ErrFrame = fmt.Errorf("Bad frame.")
 )
 
 type reader struct {
 	r io.Reader
 	o binary.ByteOrder
+
+	// ctx is checked for cancellation between fields, as set by ReadContext or WithContext
+	ctx context.Context
+
+	// alloc makes the slices read into, when set
+	alloc Allocator
+
+	// versions skips fields outside the negotiated protocol version
+	versions versioning
+
+	// trace, when set, notes where each field was read from
+	trace *tracer
+
+	// lenient skips sized fields of types that can't be read
+	lenient bool
+
+	// maxDecompressed caps what compress tagged fields decompress to, as set by WithMaxDecompressed
+	maxDecompressed int64
+
+	// overrides, when set, gives fields the byte orders of WithOrderOverrides
+	overrides *orderOverrides
+
+	// presence, when set, skips the fields WithFieldPresence rules out
+	presence *fieldPresence
+
+	// rest, within a length scope, bounds reads to what's left of it
+	rest *io.LimitedReader
 }
 
+// Read fills the value *data points to from ioReader. *data must hold a pointer, as a struct
+// held directly couldn't be changed. Should ioReader return neither bytes nor an error 100 times
+// in a row, Read fails with io.ErrNoProgress, wrapped with the field being read. Failing partway
+// through a value, the error is a *ReadError saying where.
 func Read(ioReader io.Reader, defaultEndian binary.ByteOrder, data *any) (err error) {
+	return ReadContext(context.Background(), ioReader, defaultEndian, data)
+}
 
+// ReadContext is Read, stopping with ctx's error once it's done.
+// ctx is checked before each field is read.
+func ReadContext(ctx context.Context, ioReader io.Reader, defaultEndian binary.ByteOrder, data *any) (err error) {
+	in := &countingReader{r: guardProgress(ioReader)}
 	r := reader{
-		r: ioReader,
-		o: defaultEndian,
+		r:   in,
+		o:   defaultEndian,
+		ctx: ctx,
 	}
 
-	return r.readOrdered(reflect.ValueOf(*data), defaultEndian)
+	// Anything but a pointer is a copy, so filling it would be lost
+	v := reflect.ValueOf(*data)
+	if v.Kind() != reflect.Pointer {
+		return fmt.Errorf("%w Expected pointer; Got %v", ErrUnexpectedType, reflect.TypeOf(*data))
+	}
+	err = r.readOrdered(v, defaultEndian)
+	seeker, _ := ioReader.(io.Seeker)
+	return readError(seeker, 0, in.n, err)
 }
 
 func (r *reader) readOrdered(v reflect.Value, o binary.ByteOrder) (err error) {
 	switch k := v.Kind(); k {
+	// Pointers
+	case reflect.Pointer:
+		if v.IsNil() {
+			if !v.CanSet() {
+				return fmt.Errorf("%w Got nil %s", ErrUnexpectedType, v.Type().String())
+			}
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return r.readOrdered(v.Elem(), o)
+
 	// Structs
 	case reflect.Struct:
-		t := v.Type()
-		for i := 0; i < v.NumField(); i++ {
-			// Slightly slower, but very much needed
-			if f := v.Field(i); f.CanSet() && t.Field(i).Name != "_" {
-				// Get endian tag if set
-				targetEndian := o
-				switch t.Field(i).Tag.Get("endian") {
-				case "big":
-					targetEndian = BigEndian
-				case "little":
-					targetEndian = LittleEndian
-				}
-
-				if err = r.readOrdered(f, targetEndian); err != nil {
-					return
-				}
+		// 128 bit integers are base types, only structs to Go
+		if n := typeSize(v.Type()); n > 0 {
+			var bs []byte
+			if bs, err = r.next(n); err != nil {
+				return
 			}
+			decode(v, bs, o)
+			return
+		}
+		if v.Type() == timecodeType {
+			return r.readTimecode(v, defaultTimecodeLayout, o)
 		}
 
+		// Plain structs in memory skip the tags altogether
+		if r.readPlain(v, o) {
+			return
+		}
+
+		var so structOptions
+		if so, err = optionsOf(v.Type()); err != nil {
+			return
+		} else if so.iso8583 {
+			return r.readISO8583(v, o)
+		}
+
+		// Checksummed structs are verified once read, and raw fields filled from what was read
+		cks, err := checksumsOf(v.Type())
+		if err != nil {
+			return err
+		}
+		raws, err := rawFieldsOf(v.Type())
+		if err != nil {
+			return err
+		}
+		if len(cks) > 0 || len(raws) > 0 {
+			return r.readCaptured(v, so, cks, raws, o)
+		}
+		return r.readStruct(v, so, o)
+
 	// List types
 	case reflect.Slice, reflect.Array:
+		if k == reflect.Slice && v.IsNil() {
+			return errNoLength
+		}
+
+		// Zero length arrays, used as markers, aren't on the wire at all
+		if k == reflect.Array && v.Len() == 0 {
+			return
+		}
+
+		// Bytes need no decoding, so are read straight into place
+		if v.Type().Elem().Kind() == reflect.Uint8 && (k == reflect.Slice || v.CanAddr()) {
+			_, err = io.ReadFull(r.r, v.Slice(0, v.Len()).Bytes())
+			return
+		}
+
+		// Byte arrays, such as colour table entries, are copied out of one read
+		if et := v.Type().Elem(); et.Kind() == reflect.Array && et.Elem().Kind() == reflect.Uint8 && (k == reflect.Slice || v.CanAddr()) {
+			n := et.Len()
+			var bs []byte
+			if bs, err = r.next(n * v.Len()); err != nil {
+				return
+			}
+			for i := 0; i < v.Len(); i++ {
+				reflect.Copy(v.Index(i), reflect.ValueOf(bs[i*n:(i+1)*n]))
+			}
+			return
+		}
+
+		// Fixed size elements can be read in one go
+		if n := typeSize(v.Type().Elem()); n > 0 {
+			var bs []byte
+			if bs, err = r.next(n * v.Len()); err != nil {
+				return
+			}
+			for i := 0; i < v.Len(); i++ {
+				decode(v.Index(i), bs[i*n:(i+1)*n], o)
+			}
+			return
+		}
+
 		for i := 0; i < v.Len(); i++ {
-			if err = r.readOrdered(v.Index(i), r.o); err != nil {
+			r.trace.push(i)
+			err = r.readOrdered(v.Index(i), o)
+			r.trace.pop()
+			if err != nil {
 				return
 			}
 		}
@@ -86,32 +486,13 @@ func (r *reader) readOrdered(v reflect.Value, o binary.ByteOrder) (err error) {
 		reflect.Uint32,
 		reflect.Int64,
 		reflect.Uint64:
-		bs := make([]byte, size(k))
-		if _, err = io.ReadFull(r.r, bs); err != nil {
-			return
-		}
-
-		switch k {
-		case reflect.Bool:
-			v.SetBool(bs[0] != 0)
-		case reflect.Uint8:
-			v.SetUint(uint64(bs[0]))
-		case reflect.Int8:
-			v.SetInt(int64(int8(bs[0])))
-		case reflect.Uint16:
-			v.SetUint(uint64(o.Uint16(bs)))
-		case reflect.Int16:
-			v.SetInt(int64(int16(o.Uint16(bs))))
-		case reflect.Uint32:
-			v.SetUint(uint64(o.Uint32(bs)))
-		case reflect.Int32:
-			v.SetInt(int64(int32(o.Uint32(bs))))
-		case reflect.Uint64:
-			v.SetUint(o.Uint64(bs))
-		case reflect.Int64:
-			v.SetInt(int64(o.Uint64(bs)))
+		var bs []byte
+		if bs, err = r.next(typeSize(v.Type())); err != nil {
+			return
 		}
 
+		decode(v, bs, o)
+
 	// Unknown type
 	default:
 		return fmt.Errorf("%w Expected int, uint, bool, array, slice, or struct; Got %s", ErrUnexpectedType, v.Type().String())
@@ -120,48 +501,511 @@ func (r *reader) readOrdered(v reflect.Value, o binary.ByteOrder) (err error) {
 	return
 }
 
+// readStruct reads the fields of struct v, with options so
+func (r *reader) readStruct(v reflect.Value, so structOptions, o binary.ByteOrder) (err error) {
+	if so.align > 0 || len(so.scopes) > 0 {
+		return r.readCounted(v, so, o, nil)
+	}
+
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		// Slightly slower, but very much needed
+		if f := v.Field(i); f.CanSet() && t.Field(i).Name != "_" {
+			if err = r.readFieldAt(v, so, i, o); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// readField reads field f of struct v, applying the tags of sf
+func (r *reader) readField(v reflect.Value, sf reflect.StructField, f reflect.Value, o binary.ByteOrder) (err error) {
+	if err = r.ctx.Err(); err != nil {
+		return
+	}
+
+	// Fields the WithFieldPresence predicate rules out aren't on the wire, Optionals' flags included.
+	// Raw captures and Serializables never are, so aren't asked about.
+	if r.presence != nil && !isRaw(sf) && sf.Type != serializableType {
+		present, leave := r.presence.enter(sf.Name)
+		defer leave()
+		if !present {
+			f.Set(reflect.Zero(f.Type()))
+			return nil
+		}
+	}
+
+	if isOptional(f.Type()) {
+		return r.readOptional(v, sf, f, o)
+	}
+
+	// Get endian tag if set
+	targetEndian := o
+	switch sf.Tag.Get("endian") {
+	case "big":
+		targetEndian = BigEndian
+	case "little":
+		targetEndian = LittleEndian
+	}
+	if r.overrides != nil {
+		var leave func()
+		targetEndian, leave = r.overrides.enter(sf.Name, targetEndian)
+		defer leave()
+	}
+
+	// Raw captures are filled in once the struct's read, and Serializables aren't on the wire
+	if isRaw(sf) || sf.Type == serializableType {
+		return
+	}
+
+	// Fields outside the negotiated version aren't on the wire
+	if present, err := r.versions.present(v.Type(), sf); err != nil {
+		return fmt.Errorf("%s: %w", sf.Name, err)
+	} else if !present {
+		f.Set(reflect.Zero(f.Type()))
+		return nil
+	}
+
+	// Optional fields are only there when flagged
+	if p := sf.Tag.Get("presentif"); p != "" {
+		var flag reflect.Value
+		if flag, err = presenceFlag(v, sf, p); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		} else if !flag.Bool() {
+			f.Set(reflect.Zero(f.Type()))
+			return
+		}
+	}
+
+	// Padding surrounds the field, so is only there when it is
+	if err = r.skipPad(sf, "padding_before"); err != nil {
+		return fmt.Errorf("%s: %w", sf.Name, err)
+	}
+	defer func() {
+		if err == nil {
+			if err = r.skipPad(sf, "padding_after"); err != nil {
+				err = fmt.Errorf("%s: %w", sf.Name, err)
+			}
+		}
+	}()
+
+	// Traces note where each field lies, leaving its padding to be listed as bytes between fields
+	if r.trace != nil {
+		defer r.trace.field(sf.Name, targetEndian, f)(&err)
+	}
+
+	// Lenient readers skip what they can't read, given its size
+	if r.lenient && !readable(sf) {
+		if n, ok, _ := tagSize(sf); ok {
+			if _, err = r.next(n); err != nil {
+				return fmt.Errorf("%s: %w", sf.Name, err)
+			}
+			f.Set(reflect.Zero(f.Type()))
+			return
+		}
+	}
+
+	// Run length encoded slices size themselves, checking their lengths against their max
+	if sf.Tag.Get("rle") != "" {
+		if err = r.readRLE(v, sf, f, targetEndian); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		return
+	}
+
+	// Size slices from their tags
+	sized := false
+	if sf.Tag.Get("dims") != "" {
+		var dims []int
+		if dims, err = dimsOf(v, sf); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		r.allocDims(f, dims)
+		sized = true
+	} else if f.Kind() == reflect.Slice {
+		var n int
+		if n, sized, err = r.sliceLen(v, sf, targetEndian); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		} else if sized {
+			f.Set(r.makeSlice(f.Type(), n))
+		}
+	}
+
+	// Maps are sized as slices are
+	if f.Kind() == reflect.Map {
+		if n, ok, err := r.sliceLen(v, sf, targetEndian); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		} else if !ok {
+			return fmt.Errorf("%s: %w", sf.Name, errNoLength)
+		} else if err = r.readMap(f, n, targetEndian); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		return
+	}
+
+	if sf.Tag.Get("crcblocks") != "" {
+		if err = r.readCRCBlocks(sf, f, targetEndian); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		return
+	}
+
+	if sf.Tag.Get("sparse") != "" {
+		if err = r.readSparse(sf, f, targetEndian); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		return
+	}
+
+	if sf.Tag.Get("compress") != "" {
+		if err = r.readCompressed(sf, f, targetEndian); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		return
+	}
+
+	if sf.Tag.Get("restlen") != "" {
+		if err = r.readRest(f, targetEndian); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		return
+	}
+
+	// SSH types carry their own lengths, always big endian
+	if sf.Tag.Get("ssh") != "" {
+		if err = r.readSSH(sf, f); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		return
+	}
+
+	// MACs are fixed size
+	if f.Type() == hardwareAddrType {
+		var n int
+		if n, err = hardwareAddrLen(sf); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		f.Set(r.makeSlice(f.Type(), n))
+		sized = true
+	}
+
+	// Without a length there's no telling how many elements to read
+	if f.Kind() == reflect.Slice && f.Len() == 0 && !sized {
+		return fmt.Errorf("%s: %w", sf.Name, errNoLength)
+	}
+
+	// Decimal floating point fields hold one of several types
+	if isDecimal(sf) {
+		if err = r.readDecimal(sf, f, targetEndian); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		return
+	}
+
+	// Packed text is sized by another field
+	if f.Kind() == reflect.String && sf.Tag.Get("string") != "" {
+		if err = r.readGSM7(v, sf, f); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		return
+	}
+
+	// Other strings are fixed size
+	if f.Kind() == reflect.String {
+		if err = r.readString(sf, f); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		return
+	}
+
+	// Durations are stored in other units
+	if isDuration(sf) {
+		if err = r.readDuration(sf, f, targetEndian); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		return
+	}
+
+	// Times are stored as counts since their epochs
+	if isEpoch(sf) {
+		if err = r.readEpoch(sf, f, targetEndian); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		return
+	}
+
+	// Timecodes are BCD digits, their flags where their tags say
+	if f.Type() == timecodeType {
+		var tl timecodeLayout
+		if tl, err = timecodeLayoutOf(sf); err == nil {
+			err = r.readTimecode(f, tl, targetEndian)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		return
+	}
+
+	// Normalized floats are stored as integers
+	if isNorm(sf) {
+		if err = r.readNorm(sf, f, targetEndian); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		return
+	}
+
+	// Companded samples are a byte each
+	if isPCM(sf) {
+		if err = r.readPCM(sf, f); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		return
+	}
+
+	// Column major matrices are read whole, then transposed
+	target := f
+	colMajor, err := isColMajor(sf)
+	if err != nil {
+		return fmt.Errorf("%s: %w", sf.Name, err)
+	} else if colMajor {
+		if target, err = newColumns(f); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+	}
+
+	if isVarint(sf) {
+		err = r.readVarint(target, sf.Tag.Get("encoding"))
+	} else {
+		err = r.readOrdered(target, targetEndian)
+	}
+	if errors.Is(err, io.ErrNoProgress) {
+		// Nothing else says where a stalled reader stalled
+		return fmt.Errorf("%s: %w", sf.Name, err)
+	} else if err != nil {
+		return
+	}
+	if colMajor {
+		fromColumns(f, target)
+	}
+
+	if err = afterRead(sf, f); err != nil {
+		return fmt.Errorf("%s: %w", sf.Name, err)
+	}
+
+	if err = validate(sf, f); err != nil {
+		return fmt.Errorf("%s: %w", sf.Name, err)
+	}
+
+	if m := sf.Tag.Get("mirror"); m != "" {
+		if err = checkMirror(v, sf, f, m); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+	}
+
+	return
+}
+
+// decode sets base type v from bs, which must be typeSize(v.Type()) bytes long
+func decode(v reflect.Value, bs []byte, o binary.ByteOrder) {
+	o = wireOrder(typeOrder(v.Type(), o))
+	switch v.Type() {
+	case uint24Type, uint48Type:
+		v.SetUint(getUint(bs, o))
+		return
+	case int24Type, int48Type:
+		// Shift up to the sign bit and back down to sign extend
+		shift := 64 - 8*len(bs)
+		v.SetInt(int64(getUint(bs, o)<<shift) >> shift)
+		return
+	case uint128Type, int128Type:
+		get128(v, bs, o)
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(bs[0] != 0)
+	case reflect.Uint8:
+		v.SetUint(uint64(bs[0]))
+	case reflect.Int8:
+		v.SetInt(int64(int8(bs[0])))
+	case reflect.Uint16:
+		v.SetUint(uint64(o.Uint16(bs)))
+	case reflect.Int16:
+		v.SetInt(int64(int16(o.Uint16(bs))))
+	case reflect.Uint32:
+		v.SetUint(uint64(o.Uint32(bs)))
+	case reflect.Int32:
+		v.SetInt(int64(int32(o.Uint32(bs))))
+	case reflect.Uint64:
+		v.SetUint(o.Uint64(bs))
+	case reflect.Int64:
+		v.SetInt(int64(o.Uint64(bs)))
+	}
+}
+
 type writer struct {
 	w io.Writer
 	o binary.ByteOrder
+
+	// ctx is checked for cancellation between fields, as set by WriteContext or WithContext
+	ctx context.Context
+
+	// canonical orders map entries by their encoded keys, as set by WithCanonical
+	canonical bool
+
+	// versions skips fields outside the negotiated protocol version
+	versions versioning
+
+	// scratch holds base types while they're encoded, saving an allocation per field
+	scratch [16]byte
+
+	// progress, when set, follows the path of the field being written
+	progress *writeProgress
+
+	// overrides, when set, gives fields the byte orders of WithOrderOverrides
+	overrides *orderOverrides
+
+	// limit, when set, is the WithMaxOutput cap, checked before bulk fields are buffered
+	limit *limitWriter
 }
 
+// Write writes data to ioWriter in byte order defaultEndian, except where its tags give another.
+// Should ioWriter fail, the error is a *WriteError saying how far it got. Writes ioWriter takes
+// only part of, without an error, are retried with the rest, so every field is written whole.
 func Write(ioWriter io.Writer, defaultEndian binary.ByteOrder, data any) (err error) {
+	return WriteContext(context.Background(), ioWriter, defaultEndian, data)
+}
+
+// WriteContext is Write, stopping with ctx's error once it's done.
+// ctx is checked before each field is written.
+func WriteContext(ctx context.Context, ioWriter io.Writer, defaultEndian binary.ByteOrder, data any) (err error) {
+	cw := &countingWriter{w: ioWriter}
 	w := writer{
-		w: ioWriter,
-		o: defaultEndian,
+		w:   cw,
+		o:   defaultEndian,
+		ctx: ctx,
 	}
 
-	return w.writeOrdered(reflect.ValueOf(data), defaultEndian)
+	// Should ioWriter fail, how far it got is told
+	v := reflect.ValueOf(data)
+	if err = w.writeOrdered(v, defaultEndian); err != nil && cw.err != nil {
+		return &WriteError{Written: cw.n, Path: w.pathAt(v, defaultEndian, cw.n), Err: err}
+	}
+	return
 }
 
 func (w *writer) writeOrdered(v reflect.Value, o binary.ByteOrder) (err error) {
 	switch k := v.Kind(); k {
+	// Pointers
+	case reflect.Pointer:
+		if v.IsNil() {
+			return fmt.Errorf("%w Got nil %s", ErrUnexpectedType, v.Type().String())
+		}
+		return w.writeOrdered(v.Elem(), o)
+
 	// Structs
 	case reflect.Struct:
-		t := v.Type()
-		for i := 0; i < v.NumField(); i++ {
-			// Get endian tag if set, else default
-			targetEndian := o
-			switch t.Field(i).Tag.Get("endian") {
-			case "little":
-				targetEndian = LittleEndian
-			case "big":
-				targetEndian = BigEndian
-			}
-
-			if err = w.writeOrdered(v.Field(i), targetEndian); err != nil {
+		// 128 bit integers are base types, only structs to Go
+		if n := typeSize(v.Type()); n > 0 {
+			bs := w.scratch[:n]
+			if err = encode(v, bs, o); err != nil {
 				return
 			}
+			_, err = w.w.Write(bs)
+			return
+		}
+		if v.Type() == timecodeType {
+			return w.writeTimecode(v, defaultTimecodeLayout, o)
+		}
+
+		var so structOptions
+		if so, err = optionsOf(v.Type()); err != nil {
+			return
+		} else if so.iso8583 {
+			return w.writeISO8583(v, o)
+		}
+
+		// Length scopes are sized by dry runs of their fields
+		if len(so.scopes) > 0 {
+			if v, err = w.scopeSized(v, so, o); err != nil {
+				return err
+			}
 		}
 
+		// Structs holding their own size take a dry run first
+		if i, ok, err := selfSizeField(v.Type()); err != nil {
+			return err
+		} else if ok {
+			if v, err = w.selfSized(v, i, so, o); err != nil {
+				return err
+			}
+		}
+
+		// Checksummed structs are patched once written
+		if cks, err := checksumsOf(v.Type()); err != nil {
+			return err
+		} else if len(cks) > 0 {
+			return w.writeChecksummed(v, so, cks, o)
+		}
+		return w.writeStruct(v, so, o)
+
 	// List types
 	case reflect.Slice, reflect.Array:
+		// Zero length arrays, used as markers, aren't on the wire at all
+		if k == reflect.Array && v.Len() == 0 {
+			return
+		}
+
+		// Bytes need no encoding, so are written as they are
+		if v.Type().Elem().Kind() == reflect.Uint8 && (k == reflect.Slice || v.CanAddr()) {
+			_, err = w.w.Write(v.Slice(0, v.Len()).Bytes())
+			return
+		}
+
+		// As can byte arrays
+		if et := v.Type().Elem(); et.Kind() == reflect.Array && et.Elem().Kind() == reflect.Uint8 {
+			n := et.Len()
+			if err = w.room(n * v.Len()); err != nil {
+				return
+			}
+			bs := make([]byte, n*v.Len())
+			for i := 0; i < v.Len(); i++ {
+				reflect.Copy(reflect.ValueOf(bs[i*n:(i+1)*n]), v.Index(i))
+			}
+			_, err = w.w.Write(bs)
+			return
+		}
+
+		// Fixed size elements can be written in one go
+		if n := typeSize(v.Type().Elem()); n > 0 {
+			if err = w.room(n * v.Len()); err != nil {
+				return
+			}
+			bs := make([]byte, n*v.Len())
+			for i := 0; i < v.Len(); i++ {
+				if err = encode(v.Index(i), bs[i*n:(i+1)*n], o); err != nil {
+					return
+				}
+			}
+			_, err = w.w.Write(bs)
+			return
+		}
+
 		for i := 0; i < v.Len(); i++ {
-			if err = w.writeOrdered(v.Index(i), w.o); err != nil {
+			w.progress.pushIndex(i)
+			err = w.writeOrdered(v.Index(i), o)
+			w.progress.pop()
+			if err != nil {
 				return
 			}
 		}
 
+	// Maps
+	case reflect.Map:
+		return w.writeMap(v, o)
+
 	// Base types
 	case reflect.Bool,
 		reflect.Int,
@@ -174,31 +1018,9 @@ func (w *writer) writeOrdered(v reflect.Value, o binary.ByteOrder) (err error) {
 		reflect.Uint32,
 		reflect.Int64,
 		reflect.Uint64:
-		bs := make([]byte, size(k))
-
-		switch k {
-		case reflect.Bool:
-			if v.Bool() {
-				bs[0] = 1
-			} else {
-				bs[0] = 0
-			}
-		case reflect.Uint8:
-			bs[0] = uint8(v.Uint())
-		case reflect.Int8:
-			bs[0] = uint8(int8(v.Int()))
-		case reflect.Uint16:
-			o.PutUint16(bs, uint16(v.Uint()))
-		case reflect.Int16:
-			o.PutUint16(bs, uint16(int16(v.Int())))
-		case reflect.Uint32:
-			o.PutUint32(bs, uint32(v.Uint()))
-		case reflect.Int32:
-			o.PutUint32(bs, uint32(int32(v.Int())))
-		case reflect.Uint64:
-			o.PutUint64(bs, v.Uint())
-		case reflect.Int64:
-			o.PutUint64(bs, uint64(v.Int()))
+		bs := w.scratch[:typeSize(v.Type())]
+		if err = encode(v, bs, o); err != nil {
+			return
 		}
 
 		if _, err = w.w.Write(bs); err != nil {
@@ -213,6 +1035,420 @@ func (w *writer) writeOrdered(v reflect.Value, o binary.ByteOrder) (err error) {
 	return
 }
 
+// writeStruct writes the fields of struct v, with options so
+func (w *writer) writeStruct(v reflect.Value, so structOptions, o binary.ByteOrder) (err error) {
+	if so.align > 0 {
+		return w.writeCounted(v, so, o, nil)
+	}
+
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		// Markers only carry struct options
+		if t.Field(i).Name == "_" && t.Field(i).Type.Size() == 0 {
+			continue
+		}
+
+		if err = w.writeFieldAt(v, so, i, o); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// writeField writes field f of struct v, applying the tags of sf
+func (w *writer) writeField(v reflect.Value, sf reflect.StructField, f reflect.Value, o binary.ByteOrder) (err error) {
+	if err = w.ctx.Err(); err != nil {
+		return
+	}
+	if isOptional(f.Type()) {
+		return w.writeOptional(v, sf, f, o)
+	}
+
+	// Get endian tag if set, else default
+	targetEndian := o
+	switch sf.Tag.Get("endian") {
+	case "little":
+		targetEndian = LittleEndian
+	case "big":
+		targetEndian = BigEndian
+	}
+	if w.overrides != nil {
+		var leave func()
+		targetEndian, leave = w.overrides.enter(sf.Name, targetEndian)
+		defer leave()
+	}
+
+	// Raw captures and Serializables aren't on the wire
+	if isRaw(sf) || sf.Type == serializableType {
+		return
+	}
+
+	if w.progress != nil {
+		w.progress.push(sf.Name)
+		defer w.progress.pop()
+	}
+
+	// Fields outside the negotiated version are left out
+	if present, err := w.versions.present(v.Type(), sf); err != nil {
+		return fmt.Errorf("%s: %w", sf.Name, err)
+	} else if !present {
+		return nil
+	}
+
+	// Optional fields are only written when flagged or set, and their flags to match
+	if sf.Tag.Get("presentif") != "" {
+		var present bool
+		if present, err = isPresent(v, sf, f); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		} else if !present {
+			return
+		}
+	} else if f.Kind() == reflect.Bool {
+		f = flagFor(v, sf, f)
+	}
+	if f.CanInt() || f.CanUint() {
+		f = optionalBits(v, sf, f)
+	}
+
+	if err = w.writePad(sf, "padding_before"); err != nil {
+		return fmt.Errorf("%s: %w", sf.Name, err)
+	}
+	defer func() {
+		if err == nil {
+			if err = w.writePad(sf, "padding_after"); err != nil {
+				err = fmt.Errorf("%s: %w", sf.Name, err)
+			}
+		}
+	}()
+
+	// Constants are written as tagged, whatever the field holds
+	if c := sf.Tag.Get("const"); c != "" {
+		if f, err = constValue(f.Type(), c); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+	}
+
+	// Mirrors are written as copies, whatever the field holds
+	if m := sf.Tag.Get("mirror"); m != "" {
+		if f, err = mirrorOf(v, sf, m); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+	}
+
+	// The enumdefault stands for every unknown code, so is written even outside the enum
+	if e := sf.Tag.Get("enum"); e != "" {
+		err = checkEnum(f, e)
+		if d := sf.Tag.Get("enumdefault"); d != "" && errors.Is(err, ErrValidation) {
+			if def, derr := constValue(f.Type(), d); derr != nil {
+				err = derr
+			} else if sameInt(def, f) {
+				err = nil
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+	}
+
+	// Slices must agree with their tags
+	if sf.Tag.Get("dims") != "" {
+		var dims []int
+		if dims, err = dimsOf(v, sf); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		if err = checkDims(f, dims, sf.Name); err != nil {
+			return
+		}
+	} else if f.Kind() == reflect.Slice {
+		var (
+			n  int
+			ok bool
+		)
+		if n, ok, err = sliceLen(v, sf); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		} else if _, pad, _, _ := literalCount(sf); pad && f.Len() < n {
+			// Short slices are filled out with zero values
+			padded := reflect.MakeSlice(f.Type(), n, n)
+			reflect.Copy(padded, f)
+			f = padded
+		} else if ok && n != f.Len() {
+			return fmt.Errorf("%w %s has %d elements, expected %d", ErrLength, sf.Name, f.Len(), n)
+		}
+	} else if f.Kind() == reflect.Map {
+		if n, ok, err := sliceLen(v, sf); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		} else if ok && n != f.Len() {
+			return fmt.Errorf("%w %s has %d entries, expected %d", ErrLength, sf.Name, f.Len(), n)
+		}
+	}
+
+	// Length prefixes lead their slices and maps
+	if f.Kind() == reflect.Slice || f.Kind() == reflect.Map {
+		if err = w.writeLenPrefix(sf, f, targetEndian); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+	}
+
+	if sf.Tag.Get("crcblocks") != "" {
+		if err = w.writeCRCBlocks(sf, f, targetEndian); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		return
+	}
+
+	if sf.Tag.Get("sparse") != "" {
+		if err = w.writeSparse(sf, f, targetEndian); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		return
+	}
+
+	if sf.Tag.Get("rle") != "" {
+		if err = w.writeRLE(v, sf, f, targetEndian); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		return
+	}
+
+	if sf.Tag.Get("compress") != "" {
+		if err = w.writeCompressed(sf, f, targetEndian); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		return
+	}
+
+	// SSH types carry their own lengths, always big endian
+	if sf.Tag.Get("ssh") != "" {
+		if err = w.writeSSH(sf, f); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		return
+	}
+
+	// MACs are fixed size
+	if f.Type() == hardwareAddrType {
+		var n int
+		if n, err = hardwareAddrLen(sf); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		if f.Len() != n {
+			return fmt.Errorf("%w %s is %d bytes, expected %d", ErrLength, sf.Name, f.Len(), n)
+		}
+	}
+
+	// Decimal floating point fields hold one of several types
+	if isDecimal(sf) {
+		if err = w.writeDecimal(sf, f, targetEndian); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		return
+	}
+
+	// Packed text is sized by another field
+	if f.Kind() == reflect.String && sf.Tag.Get("string") != "" {
+		if err = w.writeGSM7(v, sf, f); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		return
+	}
+
+	// Other strings are fixed size
+	if f.Kind() == reflect.String {
+		if err = w.writeString(sf, f); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		return
+	}
+
+	// Durations are stored in other units
+	if isDuration(sf) {
+		if err = w.writeDuration(sf, f, targetEndian); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		return
+	}
+
+	// Times are stored as counts since their epochs
+	if isEpoch(sf) {
+		if err = w.writeEpoch(sf, f, targetEndian); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		return
+	}
+
+	// Timecodes are BCD digits, their flags where their tags say
+	if f.Type() == timecodeType {
+		var tl timecodeLayout
+		if tl, err = timecodeLayoutOf(sf); err == nil {
+			err = w.writeTimecode(f, tl, targetEndian)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		return
+	}
+
+	// Normalized floats are stored as integers
+	if isNorm(sf) {
+		if err = w.writeNorm(sf, f, targetEndian); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		return
+	}
+
+	// Companded samples are a byte each
+	if isPCM(sf) {
+		if err = w.writePCM(sf, f); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		return
+	}
+
+	if f, err = beforeWrite(sf, f); err != nil {
+		return fmt.Errorf("%s: %w", sf.Name, err)
+	}
+
+	// Column major matrices are transposed, then written whole
+	if colMajor, err := isColMajor(sf); err != nil {
+		return fmt.Errorf("%s: %w", sf.Name, err)
+	} else if colMajor {
+		flat, err := newColumns(f)
+		if err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+		toColumns(flat, f)
+		f = flat
+	}
+
+	if isVarint(sf) {
+		return w.writeVarint(f, sf.Tag.Get("encoding"))
+	}
+	return w.writeOrdered(f, targetEndian)
+}
+
+// encode puts base type v into bs, which must be typeSize(v.Type()) bytes long
+func encode(v reflect.Value, bs []byte, o binary.ByteOrder) error {
+	o = wireOrder(typeOrder(v.Type(), o))
+	switch t := v.Type(); t {
+	case uint24Type, uint48Type:
+		if v.Uint()>>(8*len(bs)) != 0 {
+			return fmt.Errorf("%w %d does not fit in %s", ErrRange, v.Uint(), t.Name())
+		}
+		putUint(bs, v.Uint(), o)
+		return nil
+	case int24Type, int48Type:
+		// Must survive a round trip through the sign extension
+		shift := 64 - 8*len(bs)
+		if v.Int()<<shift>>shift != v.Int() {
+			return fmt.Errorf("%w %d does not fit in %s", ErrRange, v.Int(), t.Name())
+		}
+		putUint(bs, uint64(v.Int()), o)
+		return nil
+	case uint128Type, int128Type:
+		put128(bs, v, o)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			bs[0] = 1
+		} else {
+			bs[0] = 0
+		}
+	case reflect.Uint8:
+		bs[0] = uint8(v.Uint())
+	case reflect.Int8:
+		bs[0] = uint8(int8(v.Int()))
+	case reflect.Uint16:
+		o.PutUint16(bs, uint16(v.Uint()))
+	case reflect.Int16:
+		o.PutUint16(bs, uint16(int16(v.Int())))
+	case reflect.Uint32:
+		o.PutUint32(bs, uint32(v.Uint()))
+	case reflect.Int32:
+		o.PutUint32(bs, uint32(int32(v.Int())))
+	case reflect.Uint64:
+		o.PutUint64(bs, v.Uint())
+	case reflect.Int64:
+		o.PutUint64(bs, uint64(v.Int()))
+	}
+
+	return nil
+}
+
+// sliceLen resolves the element count given by a slice field's tags, reporting whether there was one.
+// "len" and its synonym "countfrom" name an earlier integer field holding the count,
+// while "count" gives it literally.
+func sliceLen(v reflect.Value, sf reflect.StructField) (n int, ok bool, err error) {
+	if ref := sf.Tag.Get("len"); ref != "" {
+		n, err = lengthOf(v, ref)
+		return n, true, err
+	}
+	if ref := sf.Tag.Get("countfrom"); ref != "" {
+		n, err = lengthOf(v, ref)
+		return n, true, err
+	}
+	n, _, ok, err = literalCount(sf)
+	return
+}
+
+// literalCount parses a field's count tag, reporting whether it was set.
+// `count:"16,pad"` also asks for short slices to be padded with zero values when written.
+func literalCount(sf reflect.StructField) (n int, pad bool, ok bool, err error) {
+	c := sf.Tag.Get("count")
+	if c == "" {
+		return 0, false, false, nil
+	}
+	c, opt, _ := strings.Cut(strings.TrimPrefix(c, "fixed:"), ",")
+	if n, err = strconv.Atoi(c); err != nil || n < 0 || (opt != "" && opt != "pad") {
+		return 0, false, true, fmt.Errorf("%w count %q is not a length", ErrTag, sf.Tag.Get("count"))
+	}
+	return n, opt == "pad", true, nil
+}
+
+// lenMax caps the lengths read from fields, and length prefixes, so corrupt or hostile counts
+// error rather than exhausting memory
+const lenMax = 1 << 28
+
+// lengthOf reads the integer field named ref from struct v for use as a slice length.
+// Lengths over lenMax error with ErrLength.
+func lengthOf(v reflect.Value, ref string) (int, error) {
+	f := v.FieldByName(ref)
+	switch f.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if f.Int() < 0 {
+			return 0, fmt.Errorf("%w %s is negative", ErrLength, ref)
+		} else if f.Int() > lenMax {
+			return 0, fmt.Errorf("%w %s of %d is over %d", ErrLength, ref, f.Int(), lenMax)
+		}
+		return int(f.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if f.Uint() > lenMax {
+			return 0, fmt.Errorf("%w %s of %d is over %d", ErrLength, ref, f.Uint(), lenMax)
+		}
+		return int(f.Uint()), nil
+	case reflect.Invalid:
+		return 0, fmt.Errorf("%w No field named %s", ErrLength, ref)
+	default:
+		return 0, fmt.Errorf("%w %s is not an integer", ErrLength, ref)
+	}
+}
+
+// typeSize is size, but aware of the odd width integer types.
+// Returns 0 for anything that isn't a fixed size base type.
+func typeSize(t reflect.Type) int {
+	switch t {
+	case uint24Type, int24Type:
+		return 3
+	case uint48Type, int48Type:
+		return 6
+	case uint128Type, int128Type:
+		return 16
+	}
+	return size(t.Kind())
+}
+
 // size is a dumb function, and should already exist as a part of reflect/value
 func size(k reflect.Kind) int {
 	switch k {
@@ -2,9 +2,14 @@ package mixedEndian
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"io"
+	"math"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -34,11 +39,6 @@ func TestRead(t *testing.T) {
 
 	reference := []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF}
 
-	Notags := NoTagStruct{}
-	tags := TaggedStruct{}
-	nested := NestedStruct{}
-	nonStruct := 0
-
 	tests := []struct {
 		name     string
 		args     args
@@ -50,10 +50,10 @@ func TestRead(t *testing.T) {
 			args: args{
 				ioReader:      bytes.NewReader(reference),
 				defaultEndian: BigEndian,
-				data:          Notags,
+				data:          &NoTagStruct{},
 			},
 			wantErr: nil,
-			wantData: NoTagStruct{
+			wantData: &NoTagStruct{
 				A: 0x01,
 				B: 0x2345,
 				C: 0x6789ABCD,
@@ -64,10 +64,10 @@ func TestRead(t *testing.T) {
 			args: args{
 				ioReader:      bytes.NewReader(reference),
 				defaultEndian: BigEndian,
-				data:          tags,
+				data:          &TaggedStruct{},
 			},
 			wantErr: nil,
-			wantData: TaggedStruct{
+			wantData: &TaggedStruct{
 				A: 0x0123,
 				B: 0x6745,
 			},
@@ -77,10 +77,10 @@ func TestRead(t *testing.T) {
 			args: args{
 				ioReader:      bytes.NewReader(reference),
 				defaultEndian: BigEndian,
-				data:          nested,
+				data:          &NestedStruct{},
 			},
 			wantErr: nil,
-			wantData: NestedStruct{
+			wantData: &NestedStruct{
 				A: 0x0123,
 				B: TaggedStruct{
 					A: 0x4567,
@@ -89,23 +89,334 @@ func TestRead(t *testing.T) {
 				C: 0xEFCD,
 			},
 		},
+		{
+			name: "non-pointer",
+			args: args{
+				ioReader:      bytes.NewReader(reference),
+				defaultEndian: BigEndian,
+				data:          NoTagStruct{},
+			},
+			wantErr: ErrUnexpectedType,
+		},
 		{
 			name: "non-struct",
 			args: args{
 				ioReader:      bytes.NewReader(reference),
 				defaultEndian: BigEndian,
-				data:          nonStruct,
+				data:          &[]string{""},
 			},
 			wantErr: ErrUnexpectedType,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := Read(tt.args.ioReader, tt.args.defaultEndian, &tt.args.data); tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+			err := Read(tt.args.ioReader, tt.args.defaultEndian, &tt.args.data)
+			if !errors.Is(err, tt.wantErr) {
 				t.Errorf("Read() error = %v, wanted %v", err, tt.wantErr)
-			} else if tt.wantData != nil {
+			} else if tt.wantData != nil && !reflect.DeepEqual(tt.args.data, tt.wantData) {
 				t.Errorf("Read() data = %v, wanted %v", tt.args.data, tt.wantData)
 			}
 		})
 	}
 }
+
+type UnsizedSliceStruct struct {
+	A    uint8
+	Data []uint16
+}
+
+func TestIntegerTypes(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  any
+		big    []byte
+		little []byte
+	}{
+		{name: "bool false", value: false, big: []byte{0x00}, little: []byte{0x00}},
+		{name: "bool true", value: true, big: []byte{0x01}, little: []byte{0x01}},
+
+		{name: "uint8 zero", value: uint8(0), big: []byte{0x00}, little: []byte{0x00}},
+		{name: "uint8 one", value: uint8(1), big: []byte{0x01}, little: []byte{0x01}},
+		{name: "uint8 max", value: uint8(math.MaxUint8), big: []byte{0xFF}, little: []byte{0xFF}},
+		{name: "int8 zero", value: int8(0), big: []byte{0x00}, little: []byte{0x00}},
+		{name: "int8 one", value: int8(1), big: []byte{0x01}, little: []byte{0x01}},
+		{name: "int8 max", value: int8(math.MaxInt8), big: []byte{0x7F}, little: []byte{0x7F}},
+		{name: "int8 min", value: int8(math.MinInt8), big: []byte{0x80}, little: []byte{0x80}},
+		{name: "int8 minus one", value: int8(-1), big: []byte{0xFF}, little: []byte{0xFF}},
+
+		{name: "uint16 zero", value: uint16(0), big: []byte{0x00, 0x00}, little: []byte{0x00, 0x00}},
+		{name: "uint16 one", value: uint16(1), big: []byte{0x00, 0x01}, little: []byte{0x01, 0x00}},
+		{name: "uint16 max", value: uint16(math.MaxUint16), big: []byte{0xFF, 0xFF}, little: []byte{0xFF, 0xFF}},
+		{name: "int16 one", value: int16(1), big: []byte{0x00, 0x01}, little: []byte{0x01, 0x00}},
+		{name: "int16 max", value: int16(math.MaxInt16), big: []byte{0x7F, 0xFF}, little: []byte{0xFF, 0x7F}},
+		{name: "int16 min", value: int16(math.MinInt16), big: []byte{0x80, 0x00}, little: []byte{0x00, 0x80}},
+		{name: "int16 minus one", value: int16(-1), big: []byte{0xFF, 0xFF}, little: []byte{0xFF, 0xFF}},
+
+		{name: "uint32 zero", value: uint32(0), big: []byte{0x00, 0x00, 0x00, 0x00}, little: []byte{0x00, 0x00, 0x00, 0x00}},
+		{name: "uint32 one", value: uint32(1), big: []byte{0x00, 0x00, 0x00, 0x01}, little: []byte{0x01, 0x00, 0x00, 0x00}},
+		{name: "uint32 max", value: uint32(math.MaxUint32), big: []byte{0xFF, 0xFF, 0xFF, 0xFF}, little: []byte{0xFF, 0xFF, 0xFF, 0xFF}},
+		{name: "int32 one", value: int32(1), big: []byte{0x00, 0x00, 0x00, 0x01}, little: []byte{0x01, 0x00, 0x00, 0x00}},
+		{name: "int32 max", value: int32(math.MaxInt32), big: []byte{0x7F, 0xFF, 0xFF, 0xFF}, little: []byte{0xFF, 0xFF, 0xFF, 0x7F}},
+		{name: "int32 min", value: int32(math.MinInt32), big: []byte{0x80, 0x00, 0x00, 0x00}, little: []byte{0x00, 0x00, 0x00, 0x80}},
+		{name: "int32 negative", value: int32(-2), big: []byte{0xFF, 0xFF, 0xFF, 0xFE}, little: []byte{0xFE, 0xFF, 0xFF, 0xFF}},
+
+		{
+			name:   "uint64 zero",
+			value:  uint64(0),
+			big:    []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			little: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		},
+		{
+			name:   "uint64 one",
+			value:  uint64(1),
+			big:    []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+			little: []byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		},
+		{
+			name:   "uint64 max",
+			value:  uint64(math.MaxUint64),
+			big:    []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF},
+			little: []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF},
+		},
+		{
+			name:   "int64 one",
+			value:  int64(1),
+			big:    []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+			little: []byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		},
+		{
+			name:   "int64 max",
+			value:  int64(math.MaxInt64),
+			big:    []byte{0x7F, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF},
+			little: []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x7F},
+		},
+		{
+			name:   "int64 min",
+			value:  int64(math.MinInt64),
+			big:    []byte{0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			little: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x80},
+		},
+		{
+			name:   "int64 negative",
+			value:  int64(-0x0102030405060708),
+			big:    []byte{0xFE, 0xFD, 0xFC, 0xFB, 0xFA, 0xF9, 0xF8, 0xF8},
+			little: []byte{0xF8, 0xF8, 0xF9, 0xFA, 0xFB, 0xFC, 0xFD, 0xFE},
+		},
+	}
+	for _, tt := range tests {
+		for _, e := range []struct {
+			o    binary.ByteOrder
+			wire []byte
+		}{{BigEndian, tt.big}, {LittleEndian, tt.little}} {
+			t.Run(fmt.Sprintf("%s %s", tt.name, e.o), func(t *testing.T) {
+				// Read starts from a value other than the one wanted, so every bit must be set
+				v := reflect.New(reflect.TypeOf(tt.value))
+				v.Elem().Set(reflect.ValueOf(tt.value))
+				flipBits(v.Elem())
+
+				var data any = v.Interface()
+				if err := Read(bytes.NewReader(e.wire), e.o, &data); err != nil {
+					t.Fatalf("Read() error = %v", err)
+				}
+				if got := v.Elem().Interface(); got != tt.value {
+					t.Errorf("Read() = %v, wanted %v", got, tt.value)
+				}
+
+				buf := &bytes.Buffer{}
+				if err := Write(buf, e.o, tt.value); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+				if !bytes.Equal(buf.Bytes(), e.wire) {
+					t.Errorf("Write() = % X, wanted % X", buf.Bytes(), e.wire)
+				}
+			})
+		}
+	}
+}
+
+// flipBits inverts every bit of v, a bool or integer
+func flipBits(v reflect.Value) {
+	switch {
+	case v.Kind() == reflect.Bool:
+		v.SetBool(!v.Bool())
+	case v.CanInt():
+		v.SetInt(^v.Int())
+	default:
+		v.SetUint(^v.Uint())
+	}
+}
+
+type ZeroArrayStruct struct {
+	A      uint8
+	Marker [0]uint32
+	Nested [0]struct{ B uint16 }
+	C      uint8
+}
+
+func TestZeroLengthArray(t *testing.T) {
+	r := bytes.NewReader([]byte{0x01, 0x02, 0x03})
+	var data any = &ZeroArrayStruct{}
+	if err := Read(r, BigEndian, &data); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if got, want := *data.(*ZeroArrayStruct), (ZeroArrayStruct{A: 0x01, C: 0x02}); got != want {
+		t.Errorf("Read() = %+v, wanted %+v", got, want)
+	}
+	if r.Len() != 1 {
+		t.Errorf("Read() left %d bytes, wanted 1", r.Len())
+	}
+
+	// Read on its own, a zero length array takes nothing
+	data = &[0]uint64{}
+	if err := Read(r, BigEndian, &data); err != nil || r.Len() != 1 {
+		t.Errorf("Read() error = %v, left %d bytes, wanted 1", err, r.Len())
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, ZeroArrayStruct{A: 0x01, C: 0x02}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if want := []byte{0x01, 0x02}; !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), want)
+	}
+	if n, err := SizeOf(ZeroArrayStruct{}); err != nil || n != 2 {
+		t.Errorf("SizeOf() = %d, %v, wanted 2", n, err)
+	}
+}
+
+func TestReadUnsizedSlice(t *testing.T) {
+	tests := []struct {
+		name string
+		data any
+	}{
+		{name: "nil field", data: &UnsizedSliceStruct{}},
+		{name: "empty field", data: &UnsizedSliceStruct{Data: []uint16{}}},
+		{name: "nil top level", data: new([]uint16)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Read(bytes.NewReader([]byte{0x01, 0x02, 0x03}), BigEndian, &tt.data)
+			if !errors.Is(err, ErrLength) || !strings.Contains(err.Error(), "number of elements to read is unknown") {
+				t.Errorf("Read() error = %v, wanted guidance on sizing the slice", err)
+			}
+		})
+	}
+
+	// Preallocated slices are still read in full
+	var data any = &UnsizedSliceStruct{Data: make([]uint16, 1)}
+	if err := Read(bytes.NewReader([]byte{0x01, 0x02, 0x03}), BigEndian, &data); err != nil {
+		t.Errorf("Read() error = %v", err)
+	}
+}
+
+type HugeCountStruct struct {
+	N    uint64
+	Data []uint16 `len:"N"`
+}
+
+type HugeGSM7Struct struct {
+	N    uint64
+	Text string `string:"gsm7,len=N"`
+}
+
+func TestReadHugeCount(t *testing.T) {
+	for _, n := range []uint64{0xFF00000000000000, 0x0F00000000000000, lenMax + 1} {
+		wire := []byte{byte(n >> 56), byte(n >> 48), byte(n >> 40), byte(n >> 32), byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)}
+		for _, data := range []any{&HugeCountStruct{}, &HugeGSM7Struct{}} {
+			if err := Read(bytes.NewReader(wire), BigEndian, &data); !errors.Is(err, ErrLength) {
+				t.Errorf("Read(%T) of %#x error = %v, wanted %v", data, n, err, ErrLength)
+			}
+		}
+	}
+}
+
+func TestContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var data any = &UnsizedSliceStruct{Data: make([]uint16, 1)}
+	if err := ReadContext(ctx, bytes.NewReader([]byte{0x01, 0x02, 0x03}), BigEndian, &data); !errors.Is(err, context.Canceled) {
+		t.Errorf("ReadContext() error = %v, wanted %v", err, context.Canceled)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := WriteContext(ctx, buf, BigEndian, data); !errors.Is(err, context.Canceled) {
+		t.Errorf("WriteContext() error = %v, wanted %v", err, context.Canceled)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("WriteContext() wrote %d bytes after cancellation", buf.Len())
+	}
+
+	// A live context changes nothing
+	if err := WriteContext(context.Background(), buf, BigEndian, data); err != nil || buf.Len() != 3 {
+		t.Errorf("WriteContext() = % X, %v", buf.Bytes(), err)
+	}
+
+	// Decoders and Encoders are given theirs as an option
+	if err := NewDecoder(bytes.NewReader([]byte{0x01, 0x02, 0x03}), BigEndian, WithContext(ctx)).Decode(data); !errors.Is(err, context.Canceled) {
+		t.Errorf("Decoder.Decode() error = %v, wanted %v", err, context.Canceled)
+	}
+	if err := NewEncoder(io.Discard, BigEndian, WithContext(ctx)).Encode(data); !errors.Is(err, context.Canceled) {
+		t.Errorf("Encoder.Encode() error = %v, wanted %v", err, context.Canceled)
+	}
+}
+
+// readCounter counts the reads made of r
+type readCounter struct {
+	r     io.Reader
+	reads int
+}
+
+func (c *readCounter) Read(bs []byte) (int, error) {
+	c.reads++
+	return c.r.Read(bs)
+}
+
+type ColorTableStruct struct {
+	Count  uint8
+	Colors [][4]byte `len:"Count"`
+	Tail   [2][4]byte
+}
+
+func TestByteArraySlice(t *testing.T) {
+	wire := []byte{
+		0x03,
+		0xFF, 0x00, 0x00, 0xFF,
+		0x00, 0xFF, 0x00, 0xFF,
+		0x00, 0x00, 0xFF, 0x80,
+		0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+	}
+	want := ColorTableStruct{
+		Count:  3,
+		Colors: [][4]byte{{0xFF, 0x00, 0x00, 0xFF}, {0x00, 0xFF, 0x00, 0xFF}, {0x00, 0x00, 0xFF, 0x80}},
+		Tail:   [2][4]byte{{0x01, 0x02, 0x03, 0x04}, {0x05, 0x06, 0x07, 0x08}},
+	}
+
+	cr := &readCounter{r: bytes.NewReader(wire)}
+	var data any = &ColorTableStruct{}
+	if err := Read(cr, LittleEndian, &data); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if got := *data.(*ColorTableStruct); !reflect.DeepEqual(got, want) {
+		t.Errorf("Read() = %+v, wanted %+v", got, want)
+	}
+
+	// One read for the count, then one for each list of arrays, rather than one per array or byte
+	if cr.reads != 3 {
+		t.Errorf("Read() made %d reads, wanted 3", cr.reads)
+	}
+
+	// Too few entries for the count
+	data = &ColorTableStruct{}
+	if err := Read(bytes.NewReader(wire[:10]), LittleEndian, &data); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Read() error = %v, wanted %v", err, io.ErrUnexpectedEOF)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, LittleEndian, want); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+}
@@ -0,0 +1,27 @@
+package mixedEndian
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"strconv"
+)
+
+var hardwareAddrType = reflect.TypeOf(net.HardwareAddr{})
+
+// hardwareAddrLen is the wire length of a net.HardwareAddr field.
+// MACs are 6 bytes unless tagged `size:"8"` for EUI-64.
+// They're byte sequences, so any endian tag has no effect.
+func hardwareAddrLen(sf reflect.StructField) (int, error) {
+	switch s := sf.Tag.Get("size"); s {
+	case "", "6":
+		return 6, nil
+	case "8":
+		return 8, nil
+	default:
+		if _, err := strconv.Atoi(s); err != nil {
+			return 0, fmt.Errorf("%w size %q is not a number", ErrTag, s)
+		}
+		return 0, fmt.Errorf("%w net.HardwareAddr must be 6 or 8 bytes, not %s", ErrTag, s)
+	}
+}
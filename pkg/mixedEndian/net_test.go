@@ -0,0 +1,96 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+)
+
+type EthernetHeader struct {
+	Dst  net.HardwareAddr
+	Src  net.HardwareAddr `endian:"little"`
+	Type uint16
+}
+
+type EUI64Struct struct {
+	Addr net.HardwareAddr `size:"8"`
+}
+
+func TestHardwareAddr(t *testing.T) {
+	tests := []struct {
+		name string
+		wire []byte
+		data any
+		want any
+	}{
+		{
+			name: "6 bytes",
+			wire: []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x08, 0x00},
+			data: &EthernetHeader{},
+			want: &EthernetHeader{
+				Dst:  net.HardwareAddr{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF},
+				Src:  net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x55},
+				Type: 0x0800,
+			},
+		},
+		{
+			name: "8 bytes",
+			wire: []byte{0x00, 0x11, 0x22, 0xFF, 0xFE, 0x33, 0x44, 0x55},
+			data: &EUI64Struct{},
+			want: &EUI64Struct{Addr: net.HardwareAddr{0x00, 0x11, 0x22, 0xFF, 0xFE, 0x33, 0x44, 0x55}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Read(bytes.NewReader(tt.wire), BigEndian, &tt.data); err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if !reflect.DeepEqual(tt.data, tt.want) {
+				t.Errorf("Read() data = %v, wanted %v", tt.data, tt.want)
+			}
+
+			buf := &bytes.Buffer{}
+			if err := Write(buf, BigEndian, tt.want); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if !bytes.Equal(buf.Bytes(), tt.wire) {
+				t.Errorf("Write() = % X, wanted % X", buf.Bytes(), tt.wire)
+			}
+		})
+	}
+}
+
+func TestHardwareAddrWrongLength(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    any
+		wantErr error
+	}{
+		{
+			name:    "short MAC",
+			data:    EthernetHeader{Dst: net.HardwareAddr{0x01, 0x02}, Src: make(net.HardwareAddr, 6)},
+			wantErr: ErrLength,
+		},
+		{
+			name:    "MAC in EUI-64 field",
+			data:    EUI64Struct{Addr: make(net.HardwareAddr, 6)},
+			wantErr: ErrLength,
+		},
+		{
+			name: "bad size",
+			data: struct {
+				Addr net.HardwareAddr `size:"7"`
+			}{Addr: make(net.HardwareAddr, 7)},
+			wantErr: ErrTag,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Write(&bytes.Buffer{}, BigEndian, tt.data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Write() error = %v, wanted %v", err, tt.wantErr)
+			}
+		})
+	}
+}
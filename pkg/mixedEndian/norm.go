@@ -0,0 +1,112 @@
+package mixedEndian
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// normWidths are the integer types a norm tag may name
+var normWidths = map[string]reflect.Type{
+	"unorm8":  reflect.TypeOf(uint8(0)),
+	"unorm16": reflect.TypeOf(uint16(0)),
+	"snorm8":  reflect.TypeOf(int8(0)),
+	"snorm16": reflect.TypeOf(int16(0)),
+}
+
+// isNorm reports whether sf is a float, or array or slice of them, stored as normalized integers
+func isNorm(sf reflect.StructField) bool {
+	return sf.Tag.Get("norm") != ""
+}
+
+// normFormat resolves the wire type of a norm field, and the largest magnitude it holds, which
+// maps to 1.0
+func normFormat(sf reflect.StructField) (t reflect.Type, max float64, err error) {
+	t, ok := normWidths[sf.Tag.Get("norm")]
+	if !ok {
+		return nil, 0, fmt.Errorf("%w Unknown norm %q", ErrTag, sf.Tag.Get("norm"))
+	}
+
+	ft := sf.Type
+	if k := ft.Kind(); k == reflect.Array || k == reflect.Slice {
+		ft = ft.Elem()
+	}
+	if k := ft.Kind(); k != reflect.Float32 && k != reflect.Float64 {
+		return nil, 0, fmt.Errorf("%w norm needs floats; Got %s", ErrUnexpectedType, sf.Type.String())
+	}
+
+	bits := 8 * typeSize(t)
+	switch t.Kind() {
+	case reflect.Int8, reflect.Int16:
+		return t, float64(int64(1)<<(bits-1) - 1), nil
+	default:
+		return t, float64(int64(1)<<bits - 1), nil
+	}
+}
+
+// normElems counts the floats of f, a float or array or slice of them, and gives each by index
+func normElems(f reflect.Value) (n int, at func(int) reflect.Value) {
+	if k := f.Kind(); k == reflect.Array || k == reflect.Slice {
+		return f.Len(), f.Index
+	}
+	return 1, func(int) reflect.Value { return f }
+}
+
+// readNorm reads float f, or each float of array or slice f, already sized, as a normalized integer:
+// unorm values map to [0, 1] and snorm to [-1, 1], the snorm minimum clamping to -1 as its
+// successor does
+func (r *reader) readNorm(sf reflect.StructField, f reflect.Value, o binary.ByteOrder) error {
+	t, max, err := normFormat(sf)
+	if err != nil {
+		return err
+	}
+
+	n, at := normElems(f)
+	wire := reflect.MakeSlice(reflect.SliceOf(t), n, n)
+	if err = r.readOrdered(wire, o); err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		var x float64
+		if t.Kind() == reflect.Int8 || t.Kind() == reflect.Int16 {
+			x = math.Max(float64(wire.Index(i).Int())/max, -1)
+		} else {
+			x = float64(wire.Index(i).Uint()) / max
+		}
+		at(i).SetFloat(x)
+	}
+	return nil
+}
+
+// writeNorm writes float f, or each float of array or slice f, as a normalized integer.
+// As graphics APIs convert them, values are clamped to range, NaN written as 0, and scaled values
+// rounded to the nearest integer, ties to even.
+func (w *writer) writeNorm(sf reflect.StructField, f reflect.Value, o binary.ByteOrder) error {
+	t, max, err := normFormat(sf)
+	if err != nil {
+		return err
+	}
+
+	signed := t.Kind() == reflect.Int8 || t.Kind() == reflect.Int16
+	lo := 0.0
+	if signed {
+		lo = -1
+	}
+
+	n, at := normElems(f)
+	wire := reflect.MakeSlice(reflect.SliceOf(t), n, n)
+	for i := 0; i < n; i++ {
+		x := at(i).Float()
+		if math.IsNaN(x) {
+			continue
+		}
+		x = math.RoundToEven(math.Min(math.Max(x, lo), 1) * max)
+		if signed {
+			wire.Index(i).SetInt(int64(x))
+		} else {
+			wire.Index(i).SetUint(uint64(x))
+		}
+	}
+	return w.writeOrdered(wire, o)
+}
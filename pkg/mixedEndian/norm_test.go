@@ -0,0 +1,144 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+type Vertex struct {
+	Color  [4]float32 `norm:"unorm8"`
+	Weight float32    `norm:"snorm16" endian:"little"`
+	Scale  float64    `norm:"unorm16"`
+}
+
+func TestNormRead(t *testing.T) {
+	tests := []struct {
+		name string
+		norm string
+		wire []byte
+		want float32
+	}{
+		{"unorm8 zero", "unorm8", []byte{0x00}, 0},
+		{"unorm8 max", "unorm8", []byte{0xFF}, 1},
+		{"unorm8 mid", "unorm8", []byte{0x80}, 128.0 / 255},
+		{"unorm16 max", "unorm16", []byte{0xFF, 0xFF}, 1},
+		{"snorm8 zero", "snorm8", []byte{0x00}, 0},
+		{"snorm8 max", "snorm8", []byte{0x7F}, 1},
+		{"snorm8 -127", "snorm8", []byte{0x81}, -1},
+		{"snorm8 -128", "snorm8", []byte{0x80}, -1},
+		{"snorm16 max", "snorm16", []byte{0x7F, 0xFF}, 1},
+		{"snorm16 -32767", "snorm16", []byte{0x80, 0x01}, -1},
+		{"snorm16 -32768", "snorm16", []byte{0x80, 0x00}, -1},
+		{"snorm16 -1", "snorm16", []byte{0xFF, 0xFF}, -1.0 / 32767},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readNormField(tt.norm, tt.wire)
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Read() = %v, wanted %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormWrite(t *testing.T) {
+	tests := []struct {
+		name string
+		norm string
+		f    float32
+		want []byte
+	}{
+		{"unorm8 zero", "unorm8", 0, []byte{0x00}},
+		{"unorm8 max", "unorm8", 1, []byte{0xFF}},
+		{"unorm8 rounds up", "unorm8", 0.5, []byte{0x80}},
+		{"unorm8 clamps above", "unorm8", 2, []byte{0xFF}},
+		{"unorm8 clamps below", "unorm8", -1, []byte{0x00}},
+		{"unorm8 NaN", "unorm8", float32(math.NaN()), []byte{0x00}},
+		{"unorm16 max", "unorm16", 1, []byte{0xFF, 0xFF}},
+		{"snorm8 max", "snorm8", 1, []byte{0x7F}},
+		{"snorm8 min", "snorm8", -1, []byte{0x81}},
+		{"snorm8 clamps below", "snorm8", -2, []byte{0x81}},
+		{"snorm8 half", "snorm8", -0.5, []byte{0xC0}},
+		{"snorm16 max", "snorm16", 1, []byte{0x7F, 0xFF}},
+		{"snorm16 min", "snorm16", -1, []byte{0x80, 0x01}},
+		{"snorm16 NaN", "snorm16", float32(math.NaN()), []byte{0x00, 0x00}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := writeNormField(tt.norm, tt.f)
+			if err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("Write() = % X, wanted % X", got, tt.want)
+			}
+		})
+	}
+}
+
+// normStruct is a struct of one float32 tagged with norm
+func normStruct(norm string) reflect.Type {
+	return reflect.StructOf([]reflect.StructField{{
+		Name: "F",
+		Type: reflect.TypeOf(float32(0)),
+		Tag:  reflect.StructTag(`norm:"` + norm + `"`),
+	}})
+}
+
+// readNormField reads a float32 tagged with norm from wire, big endian
+func readNormField(norm string, wire []byte) (float32, error) {
+	v := reflect.New(normStruct(norm))
+	var data any = v.Interface()
+	err := Read(bytes.NewReader(wire), BigEndian, &data)
+	return float32(v.Elem().Field(0).Float()), err
+}
+
+// writeNormField writes a float32 tagged with norm, big endian
+func writeNormField(norm string, f float32) ([]byte, error) {
+	v := reflect.New(normStruct(norm)).Elem()
+	v.Field(0).SetFloat(float64(f))
+	return Marshal(BigEndian, v.Interface())
+}
+
+func TestNormVertex(t *testing.T) {
+	v := Vertex{Color: [4]float32{1, 0, 0.2, 1}, Weight: -0.5, Scale: 1}
+	wire, err := Marshal(BigEndian, v)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	want := []byte{0xFF, 0x00, 0x33, 0xFF, 0x00, 0xC0, 0xFF, 0xFF}
+	if !bytes.Equal(wire, want) {
+		t.Errorf("Marshal() = % X, wanted % X", wire, want)
+	}
+
+	if sl, err := LayoutOf(reflect.TypeOf(v)); err != nil || sl.Size != len(want) {
+		t.Errorf("LayoutOf() size = %v, %v, wanted %d", sl, err, len(want))
+	}
+
+	var data any = &Vertex{}
+	if err = Read(bytes.NewReader(wire), BigEndian, &data); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	got := data.(*Vertex)
+	if got.Color != v.Color || got.Scale != v.Scale || math.Abs(float64(got.Weight-v.Weight)) > 1.0/32767 {
+		t.Errorf("Read() = %+v, wanted %+v", got, v)
+	}
+}
+
+func TestNormErrors(t *testing.T) {
+	if _, err := writeNormField("unorm32", 0); !errors.Is(err, ErrTag) {
+		t.Errorf("Write() error = %v, wanted %v", err, ErrTag)
+	}
+	type notFloat struct {
+		I uint8 `norm:"unorm8"`
+	}
+	if _, err := Marshal(BigEndian, notFloat{}); !errors.Is(err, ErrUnexpectedType) {
+		t.Errorf("Write() error = %v, wanted %v", err, ErrUnexpectedType)
+	}
+}
@@ -0,0 +1,248 @@
+package mixedEndian
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Optional is a value that may be absent, without the allocation and nil checks of a pointer.
+//
+// As a struct field, how the wire shows whether it's present is given by its optional tag:
+//
+//   - `optional:"flag"`, the default, leads the value with a presence byte, 1 if present and 0
+//     if not. Any nonzero byte reads as present.
+//   - `optional:"eof"` is present when there's anything left to read. It must be its struct's
+//     last field, and the struct the last thing read.
+//   - `optional:"bit=Flags.3"` is present when bit 3, counting from the least significant, of the
+//     earlier integer field Flags is set. Flags is written with the bit set to match.
+//
+// Absent values take no bytes beyond those, and are read as Optionals with neither Value nor
+// Present set. Other tags on the field apply to its Value. Optionals elsewhere, such as the
+// elements of slices, are read and written as plain structs.
+type Optional[T any] struct {
+	Value   T
+	Present bool
+}
+
+// Some is an Optional holding v
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{Value: v, Present: true}
+}
+
+// Get returns the value held, and whether there is one
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.Present
+}
+
+// Set makes v the value held
+func (o *Optional[T]) Set(v T) {
+	o.Value, o.Present = v, true
+}
+
+// Clear makes o absent
+func (o *Optional[T]) Clear() {
+	*o = Optional[T]{}
+}
+
+func (Optional[T]) optional() {}
+
+// optionalType is implemented by Optionals of every type
+type optionalType interface{ optional() }
+
+var optionalIface = reflect.TypeOf((*optionalType)(nil)).Elem()
+
+// isOptional reports whether t is an Optional
+func isOptional(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.Implements(optionalIface)
+}
+
+// optionalStrategy is how the presence of an Optional field is given on the wire
+type optionalStrategy struct {
+	// kind is flag, eof, or bit
+	kind string
+
+	// field and bit are the index of the field holding a bit strategy's bit, and which bit it is
+	field, bit int
+}
+
+// optionalOf parses the optional tag of sf, an Optional field of struct t
+func optionalOf(t reflect.Type, sf reflect.StructField) (s optionalStrategy, err error) {
+	tag := sf.Tag.Get("optional")
+	switch {
+	case tag == "" || tag == "flag":
+		return optionalStrategy{kind: "flag"}, nil
+	case tag == "eof":
+		return optionalStrategy{kind: "eof"}, nil
+	case !strings.HasPrefix(tag, "bit="):
+		return s, fmt.Errorf("%w Unknown optional %q", ErrTag, tag)
+	}
+
+	ref, b, _ := strings.Cut(strings.TrimPrefix(tag, "bit="), ".")
+	flags, ok := t.FieldByName(ref)
+	if !ok || len(flags.Index) != 1 || flags.Index[0] >= sf.Index[len(sf.Index)-1] {
+		return s, fmt.Errorf("%w optional %q is not a bit of an earlier field", ErrTag, tag)
+	}
+	switch flags.Type.Kind() {
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+	default:
+		return s, fmt.Errorf("%w optional %s needs a sized integer; Got %s", ErrUnexpectedType, ref, flags.Type.String())
+	}
+	s = optionalStrategy{kind: "bit", field: flags.Index[0]}
+	if s.bit, err = strconv.Atoi(b); err != nil || s.bit < 0 || s.bit >= flags.Type.Bits() {
+		return s, fmt.Errorf("%w optional %q is not a bit of %s", ErrTag, tag, flags.Type.String())
+	}
+	return s, nil
+}
+
+// checkOptional errors if Optional field sf of struct t can't give its presence as tagged
+func checkOptional(t reflect.Type, sf reflect.StructField) error {
+	s, err := optionalOf(t, sf)
+	switch {
+	case err != nil:
+		return err
+	case sf.Tag.Get("presentif") != "":
+		return fmt.Errorf("%w optional and presentif both flag the field", ErrTag)
+	case s.kind == "eof" && sf.Index[len(sf.Index)-1] != t.NumField()-1:
+		return fmt.Errorf("%w optional eof needs the struct's last field", ErrTag)
+	}
+	return nil
+}
+
+// optionalValue is sf, an Optional field, as the field of its Value, so keeps its tags
+func optionalValue(sf reflect.StructField) reflect.StructField {
+	sf.Type = sf.Type.Field(0).Type
+	return sf
+}
+
+// bitSet reports whether bit b of integer f is set
+func bitSet(f reflect.Value, b int) bool {
+	if f.CanUint() {
+		return f.Uint()>>b&1 == 1
+	}
+	return uint64(f.Int())>>b&1 == 1
+}
+
+// readOptional reads Optional field f of struct v, with default byte order o
+func (r *reader) readOptional(v reflect.Value, sf reflect.StructField, f reflect.Value, o binary.ByteOrder) error {
+	s, err := optionalOf(v.Type(), sf)
+	if err != nil {
+		return fmt.Errorf("%s: %w", sf.Name, err)
+	}
+	f.Set(reflect.Zero(f.Type()))
+
+	// Fields outside the negotiated version have no presence to read either
+	if present, err := r.versions.present(v.Type(), sf); err != nil {
+		return fmt.Errorf("%s: %w", sf.Name, err)
+	} else if !present {
+		return nil
+	}
+
+	inner, value := optionalValue(sf), f.Field(0)
+	switch s.kind {
+	case "flag":
+		bs, err := r.next(1)
+		if err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		} else if bs[0] == 0 {
+			return nil
+		}
+
+	case "bit":
+		if !bitSet(v.Field(s.field), s.bit) {
+			return nil
+		}
+
+	case "eof":
+		// Present if anything's left, so running out before a byte's read means absent
+		cr := &countingReader{r: r.r}
+		sub := *r
+		sub.r = cr
+		if err = sub.readField(v, inner, value, o); errors.Is(err, io.EOF) && cr.n == 0 {
+			value.Set(reflect.Zero(value.Type()))
+			return nil
+		} else if err != nil {
+			return err
+		}
+		f.Field(1).SetBool(true)
+		return nil
+	}
+
+	if err = r.readField(v, inner, value, o); err != nil {
+		return err
+	}
+	f.Field(1).SetBool(true)
+	return nil
+}
+
+// writeOptional writes Optional field f of struct v, with default byte order o
+func (w *writer) writeOptional(v reflect.Value, sf reflect.StructField, f reflect.Value, o binary.ByteOrder) error {
+	s, err := optionalOf(v.Type(), sf)
+	if err != nil {
+		return fmt.Errorf("%s: %w", sf.Name, err)
+	}
+	if present, err := w.versions.present(v.Type(), sf); err != nil {
+		return fmt.Errorf("%s: %w", sf.Name, err)
+	} else if !present {
+		return nil
+	}
+
+	present := f.Field(1).Bool()
+	if s.kind == "flag" {
+		flag := []byte{0}
+		if present {
+			flag[0] = 1
+		}
+		if _, err = w.w.Write(flag); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+	}
+	if !present {
+		return nil
+	}
+	return w.writeField(v, optionalValue(sf), f.Field(0), o)
+}
+
+// optionalBits returns integer field f of struct v with the bits later Optionals are flagged by
+// set or cleared to match them, so the flags written always match the fields that follow
+func optionalBits(v reflect.Value, sf reflect.StructField, f reflect.Value) reflect.Value {
+	t := v.Type()
+	copied := false
+	for i := sf.Index[len(sf.Index)-1] + 1; i < t.NumField(); i++ {
+		opt := t.Field(i)
+		if !strings.HasPrefix(opt.Tag.Get("optional"), "bit="+sf.Name+".") || !isOptional(opt.Type) {
+			continue
+		}
+		s, err := optionalOf(t, opt)
+		if err != nil {
+			// Left to fail as the Optional's written
+			continue
+		}
+
+		if !copied {
+			c := reflect.New(f.Type()).Elem()
+			c.Set(f)
+			f, copied = c, true
+		}
+		mask, present := uint64(1)<<s.bit, v.Field(i).Field(1).Bool()
+		if f.CanUint() {
+			u := f.Uint() &^ mask
+			if present {
+				u |= mask
+			}
+			f.SetUint(u)
+		} else {
+			u := uint64(f.Int()) &^ mask
+			if present {
+				u |= mask
+			}
+			f.SetInt(int64(u))
+		}
+	}
+	return f
+}
@@ -0,0 +1,173 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type OptionalFlagStruct struct {
+	A    uint8
+	Ext  Optional[uint32] `optional:"flag" endian:"little"`
+	Name Optional[string] `size:"4"`
+}
+
+type OptionalBitStruct struct {
+	Flags uint8
+	Ext   Optional[uint16]   `optional:"bit=Flags.3"`
+	Inner Optional[GenInner] `optional:"bit=Flags.0"`
+}
+
+type OptionalEOFStruct struct {
+	A     uint16
+	Trail Optional[[2]uint8] `optional:"eof"`
+}
+
+func TestOptionalRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		data any
+		wire []byte
+	}{
+		{
+			name: "flag present",
+			data: &OptionalFlagStruct{A: 1, Ext: Some[uint32](0x01020304), Name: Some("ab")},
+			wire: []byte{0x01, 0x01, 0x04, 0x03, 0x02, 0x01, 0x01, 'a', 'b', 0, 0},
+		},
+		{
+			name: "flag absent",
+			data: &OptionalFlagStruct{A: 1},
+			wire: []byte{0x01, 0x00, 0x00},
+		},
+		{
+			name: "bit present",
+			data: &OptionalBitStruct{Flags: 0x08 | 0x01 | 0x40, Ext: Some[uint16](0x0A0B), Inner: Some(GenInner{A: 1, B: [2]uint8{2, 3}})},
+			wire: []byte{0x49, 0x0A, 0x0B, 0x00, 0x01, 0x02, 0x03},
+		},
+		{
+			name: "bit absent",
+			data: &OptionalBitStruct{Flags: 0x40},
+			wire: []byte{0x40},
+		},
+		{
+			name: "eof present",
+			data: &OptionalEOFStruct{A: 0x0102, Trail: Some([2]uint8{3, 4})},
+			wire: []byte{0x01, 0x02, 0x03, 0x04},
+		},
+		{
+			name: "eof absent",
+			data: &OptionalEOFStruct{A: 0x0102},
+			wire: []byte{0x01, 0x02},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			if err := Write(buf, BigEndian, tt.data); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if !bytes.Equal(buf.Bytes(), tt.wire) {
+				t.Errorf("Write() = % X, wanted % X", buf.Bytes(), tt.wire)
+			}
+
+			got := reflect.New(reflect.TypeOf(tt.data).Elem()).Interface()
+			if err := NewDecoder(bytes.NewReader(tt.wire), BigEndian, WithStrict(true)).Decode(got); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.data) {
+				t.Errorf("Decode() = %+v, wanted %+v", got, tt.data)
+			}
+		})
+	}
+}
+
+func TestOptionalBitsMatch(t *testing.T) {
+	// Flags are written to match the Optionals, whatever they held
+	buf := &bytes.Buffer{}
+	data := OptionalBitStruct{Flags: 0x09, Ext: Some[uint16](0x0102)}
+	if err := Write(buf, BigEndian, data); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if want := []byte{0x08, 0x01, 0x02}; !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), want)
+	}
+	if data.Flags != 0x09 {
+		t.Errorf("Write() changed Flags to %#x", data.Flags)
+	}
+}
+
+func TestOptionalEOFTruncated(t *testing.T) {
+	// Running out partway through a present value is still an error
+	var got any = &OptionalEOFStruct{}
+	err := Read(bytes.NewReader([]byte{0x01, 0x02, 0x03}), BigEndian, &got)
+	if err == nil {
+		t.Errorf("Read() error = nil, wanted one")
+	}
+}
+
+func TestOptionalHelpers(t *testing.T) {
+	var o Optional[int16]
+	if _, ok := o.Get(); ok {
+		t.Errorf("Get() on zero Optional is present")
+	}
+	o.Set(-2)
+	if v, ok := o.Get(); !ok || v != -2 {
+		t.Errorf("Get() = %d, %t; wanted -2, true", v, ok)
+	}
+	o.Clear()
+	if o != (Optional[int16]{}) {
+		t.Errorf("Clear() left %+v", o)
+	}
+}
+
+func TestOptionalLayout(t *testing.T) {
+	sl, err := Describe(OptionalBitStruct{})
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	if sl.Size != -1 {
+		t.Errorf("Describe().Size = %d, wanted -1", sl.Size)
+	}
+	inner := sl.Fields[2]
+	if inner.Type != reflect.TypeOf(Optional[GenInner]{}) || inner.Size != -1 || inner.Elem == nil || inner.Elem.Size != 4 {
+		t.Errorf("Describe() Inner = %+v, wanted a variable sized Optional laid out as a GenInner", inner)
+	}
+	if _, err = SizeOf(OptionalFlagStruct{}); !errors.Is(err, ErrLength) {
+		t.Errorf("SizeOf() error = %v, wanted %v", err, ErrLength)
+	}
+}
+
+func TestOptionalBadTags(t *testing.T) {
+	tests := []struct {
+		name string
+		data any
+	}{
+		{name: "unknown", data: &struct {
+			A Optional[uint8] `optional:"maybe"`
+		}{}},
+		{name: "later bit", data: &struct {
+			A     Optional[uint8] `optional:"bit=Flags.0"`
+			Flags uint8
+		}{}},
+		{name: "wide bit", data: &struct {
+			Flags uint8
+			A     Optional[uint8] `optional:"bit=Flags.8"`
+		}{}},
+		{name: "eof not last", data: &struct {
+			A Optional[uint8] `optional:"eof"`
+			B uint8
+		}{}},
+		{name: "not Optional", data: &struct {
+			A uint8 `optional:"flag"`
+		}{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewDecoder(bytes.NewReader([]byte{0, 0}), BigEndian, WithStrict(true)).Decode(tt.data)
+			if !errors.Is(err, ErrTag) {
+				t.Errorf("Decode() error = %v, wanted %v", err, ErrTag)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package mixedEndian
+
+import (
+	"context"
+	"encoding/binary"
+)
+
+// Option configures an Encoder or Decoder, or the encoders of an EncoderPool.
+// Options that don't apply to what they're given are ignored.
+type Option func(*options)
+
+type options struct {
+	bufferSize int
+	maxOutput  int64
+	strict     bool
+	lenient    bool
+
+	recordSize int
+	resync     []byte
+
+	maxDecompressed int64
+
+	alloc Allocator
+
+	canonical bool
+
+	negotiator *VersionNegotiator
+
+	overrides map[string]binary.ByteOrder
+
+	presence func(path string) bool
+
+	ctx context.Context
+}
+
+// WithBufferSize preallocates n bytes for each encoded value
+func WithBufferSize(n int) Option {
+	return func(o *options) {
+		o.bufferSize = n
+	}
+}
+
+// WithMaxOutput caps the bytes an Encoder writes, in total, to its current io.Writer at n.
+// Encoding a value that would pass the cap fails with ErrOutputLimit, and writes nothing of it.
+// Array and slice fields too large for what's left are refused before they're encoded, so the cap
+// bounds the Encoder's buffer too. A cap of 0 or less is no cap.
+func WithMaxOutput(n int64) Option {
+	return func(o *options) {
+		o.maxOutput = n
+	}
+}
+
+// WithMaxDecompressed caps the bytes a Decoder decompresses each compress tagged field to at n,
+// rather than the default of 64 MiB, so a small compressed block can't expand to exhaust memory.
+// A field that would decompress to more fails with ErrLength. A cap of 0 or less keeps the default.
+func WithMaxDecompressed(n int64) Option {
+	return func(o *options) {
+		o.maxDecompressed = n
+	}
+}
+
+// WithStrict makes a Decoder reject any struct that doesn't fully specify its layout,
+// rather than skipping what it can't read. See Decoder.
+func WithStrict(strict bool) Option {
+	return func(o *options) {
+		o.strict = strict
+	}
+}
+
+// WithLenient makes a Decoder skip fields of types Read can't handle, such as floats, when they're
+// tagged with their size in bytes, as in `size:"8"`. The bytes are discarded and the field left as
+// its zero value, so formats can be decoded in part before all of them are modelled.
+// Strict Decoders still reject such fields.
+func WithLenient(lenient bool) Option {
+	return func(o *options) {
+		o.lenient = lenient
+	}
+}
+
+// WithRecordRecovery makes a Decoder's DecodeAll read fixed size records of recordSize bytes,
+// skipping any that fail to decode rather than giving up. See DecodeAll.
+func WithRecordRecovery(recordSize int) Option {
+	return func(o *options) {
+		o.recordSize = recordSize
+	}
+}
+
+// WithResync makes a Decoder's DecodeAll skip records that fail to decode, by searching for
+// pattern, which starts every record, and resuming there. See DecodeAll.
+func WithResync(pattern []byte) Option {
+	return func(o *options) {
+		o.resync = pattern
+	}
+}
+
+// WithAllocator makes a Decoder allocate the slices and maps it reads into with a, rather than the
+// Go heap. See Allocator.
+func WithAllocator(a Allocator) Option {
+	return func(o *options) {
+		o.alloc = a
+	}
+}
+
+// WithCanonical makes an Encoder write map entries in order of their encoded keys, so equal values
+// always encode to the same bytes. Everything else is written in a fixed order regardless.
+// Maps whose keys can't be told apart once encoded fail with ErrCanonical.
+func WithCanonical(canonical bool) Option {
+	return func(o *options) {
+		o.canonical = canonical
+	}
+}
+
+// WithVersionNegotiator gives a Decoder or Encoder the field versions of vn, used once a version is
+// set with SetVersion. See VersionNegotiator.
+func WithVersionNegotiator(vn *VersionNegotiator) Option {
+	return func(o *options) {
+		o.negotiator = vn
+	}
+}
+
+// WithContext makes a Decoder or Encoder stop with ctx's error once it's done, as ReadContext and
+// WriteContext do. ctx is checked before each field.
+func WithContext(ctx context.Context) Option {
+	return func(o *options) {
+		o.ctx = ctx
+	}
+}
+
+// context is the Context set by WithContext, or context.Background
+func (o *options) context() context.Context {
+	if o.ctx == nil {
+		return context.Background()
+	}
+	return o.ctx
+}
@@ -0,0 +1,81 @@
+package mixedEndian
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// isColMajor reports whether sf, a 2-D array or nested slice, is stored column major
+func isColMajor(sf reflect.StructField) (bool, error) {
+	switch o := sf.Tag.Get("order"); o {
+	case "", "rowmajor":
+		return false, nil
+	case "colmajor":
+	default:
+		return false, fmt.Errorf("%w Unknown order %q", ErrTag, o)
+	}
+
+	t := sf.Type
+	if k := t.Kind(); k != reflect.Array && k != reflect.Slice {
+		return false, fmt.Errorf("%w colmajor needs a 2-D array or slice; Got %s", ErrUnexpectedType, t.String())
+	}
+	if k := t.Elem().Kind(); k != reflect.Array && k != reflect.Slice {
+		return false, fmt.Errorf("%w colmajor needs a 2-D array or slice; Got %s", ErrUnexpectedType, t.String())
+	}
+	if k := t.Elem().Elem().Kind(); k == reflect.Array || k == reflect.Slice {
+		return false, fmt.Errorf("%w colmajor needs a 2-D array or slice; Got %s", ErrUnexpectedType, t.String())
+	}
+	if t.Kind() == reflect.Slice && sf.Tag.Get("dims") == "" {
+		return false, fmt.Errorf("%w colmajor slices need a dims tag", ErrTag)
+	}
+	return true, nil
+}
+
+// matrixShape is the number of rows and columns of 2-D f, which must be rectangular
+func matrixShape(f reflect.Value) (rows, cols int, err error) {
+	rows = f.Len()
+	if f.Type().Elem().Kind() == reflect.Array {
+		return rows, f.Type().Elem().Len(), nil
+	} else if rows == 0 {
+		return 0, 0, nil
+	}
+
+	cols = f.Index(0).Len()
+	for i := 1; i < rows; i++ {
+		if f.Index(i).Len() != cols {
+			return 0, 0, fmt.Errorf("%w Row %d has %d elements, expected %d", ErrLength, i, f.Index(i).Len(), cols)
+		}
+	}
+	return
+}
+
+// newColumns makes a flat slice to hold the elements of 2-D f, read or written a column at a time
+func newColumns(f reflect.Value) (reflect.Value, error) {
+	rows, cols, err := matrixShape(f)
+	if err != nil {
+		return f, err
+	}
+	return reflect.MakeSlice(reflect.SliceOf(f.Type().Elem().Elem()), rows*cols, rows*cols), nil
+}
+
+// toColumns sets flat to the elements of 2-D f in column major order
+func toColumns(flat, f reflect.Value) {
+	rows := f.Len()
+	for i := 0; i < rows; i++ {
+		row := f.Index(i)
+		for j := 0; j < row.Len(); j++ {
+			flat.Index(j*rows + i).Set(row.Index(j))
+		}
+	}
+}
+
+// fromColumns sets the elements of 2-D f, already sized, from flat in column major order
+func fromColumns(f, flat reflect.Value) {
+	rows := f.Len()
+	for i := 0; i < rows; i++ {
+		row := f.Index(i)
+		for j := 0; j < row.Len(); j++ {
+			row.Index(j).Set(flat.Index(j*rows + i))
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type MatrixStruct struct {
+	Rows   [2][3]uint16 `endian:"little"`
+	Cols   [2][3]uint16 `endian:"little" order:"colmajor"`
+	R, C   uint8
+	Sliced [][]uint8 `dims:"R,C" order:"colmajor"`
+}
+
+type BadOrderStruct struct {
+	A [4]uint8 `order:"colmajor"`
+}
+
+func TestColMajor(t *testing.T) {
+	m := [2][3]uint16{{1, 2, 3}, {4, 5, 6}}
+	want := &MatrixStruct{Rows: m, Cols: m, R: 2, C: 3, Sliced: [][]uint8{{1, 2, 3}, {4, 5, 6}}}
+	wire := []byte{
+		0x01, 0x00, 0x02, 0x00, 0x03, 0x00, 0x04, 0x00, 0x05, 0x00, 0x06, 0x00,
+		0x01, 0x00, 0x04, 0x00, 0x02, 0x00, 0x05, 0x00, 0x03, 0x00, 0x06, 0x00,
+		0x02, 0x03,
+		0x01, 0x04, 0x02, 0x05, 0x03, 0x06,
+	}
+
+	var data any = &MatrixStruct{}
+	if err := Read(bytes.NewReader(wire), BigEndian, &data); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("Read() data = %v, wanted %v", data, want)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, want); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+}
+
+func TestColMajorErrors(t *testing.T) {
+	if err := Write(&bytes.Buffer{}, BigEndian, BadOrderStruct{}); !errors.Is(err, ErrUnexpectedType) {
+		t.Errorf("Write() error = %v, wanted %v", err, ErrUnexpectedType)
+	}
+
+	ragged := MatrixStruct{R: 2, C: 3, Sliced: [][]uint8{{1, 2, 3}, {4, 5}}}
+	if err := Write(&bytes.Buffer{}, BigEndian, ragged); !errors.Is(err, ErrLength) {
+		t.Errorf("Write() error = %v, wanted %v", err, ErrLength)
+	}
+}
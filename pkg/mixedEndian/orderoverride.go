@@ -0,0 +1,154 @@
+package mixedEndian
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+)
+
+// WithOrderOverrides makes a Decoder or Encoder read and write the fields named in overrides in
+// the byte orders given, as though they were tagged with them, whatever their endian tags say.
+// Fields are named by their dotted paths from the value decoded or encoded, as a StructLayout's
+// Paths name them, such as "Header.Length", with the elements of slices and arrays sharing their
+// field's path. Overriding a struct field sets the default order of the fields within it.
+//
+// Paths naming no field fail the first value of each type with ErrTag, before anything's read or
+// written. The Decoder or Encoder's Describe gives the orders it uses.
+func WithOrderOverrides(overrides map[string]binary.ByteOrder) Option {
+	return func(o *options) {
+		o.overrides = overrides
+	}
+}
+
+// orderOverrides applies the overrides of WithOrderOverrides as fields are read or written
+type orderOverrides struct {
+	orders map[string]binary.ByteOrder
+
+	// prefix is the path of the struct whose fields are being read or written, with a trailing dot
+	prefix string
+
+	// checked are the types whose fields cover every override
+	checked map[reflect.Type]bool
+}
+
+// newOrderOverrides returns the orderOverrides of orders, or nil when there are none
+func newOrderOverrides(orders map[string]binary.ByteOrder) *orderOverrides {
+	if len(orders) == 0 {
+		return nil
+	}
+	return &orderOverrides{orders: orders, checked: map[reflect.Type]bool{}}
+}
+
+// check errors if any override names no field of t
+func (ov *orderOverrides) check(t reflect.Type) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if ov.checked[t] {
+		return nil
+	}
+
+	paths := map[string]bool{}
+	fieldPaths(t, "", paths, map[reflect.Type]bool{})
+	for path, o := range ov.orders {
+		if !paths[path] {
+			return fmt.Errorf("%w Order override %q names no field of %s", ErrTag, path, t.String())
+		} else if o == nil {
+			return fmt.Errorf("%w Order override %q has no byte order", ErrTag, path)
+		}
+	}
+	ov.checked[t] = true
+	return nil
+}
+
+// fieldPaths adds the dotted path of every field within t, prefixed by prefix, to paths.
+// Types in seen are already being walked, so recursive types end.
+func fieldPaths(t reflect.Type, prefix string, paths map[string]bool, seen map[reflect.Type]bool) {
+	for {
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Array, reflect.Slice:
+			t = t.Elem()
+			continue
+		}
+		break
+	}
+	if t.Kind() != reflect.Struct || seen[t] {
+		return
+	}
+	if isOptional(t) {
+		fieldPaths(t.Field(0).Type, prefix, paths, seen)
+		return
+	}
+
+	seen[t] = true
+	defer delete(seen, t)
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() || sf.Name == "_" {
+			continue
+		}
+		paths[prefix+sf.Name] = true
+		fieldPaths(sf.Type, prefix+sf.Name+".", paths, seen)
+	}
+}
+
+// order is the byte order of field name, in the struct being read or written, given it'd
+// otherwise be o
+func (ov *orderOverrides) order(name string, o binary.ByteOrder) binary.ByteOrder {
+	if over, ok := ov.orders[ov.prefix+name]; ok {
+		return over
+	}
+	return o
+}
+
+// enter returns the byte order of field name, given it'd otherwise be o, and moves into it until
+// the returned func is called
+func (ov *orderOverrides) enter(name string, o binary.ByteOrder) (binary.ByteOrder, func()) {
+	prefix := ov.prefix
+	o = ov.order(name, o)
+	ov.prefix += name + "."
+	return o, func() { ov.prefix = prefix }
+}
+
+// apply sets the orders of the fields of sl as overridden, those without endian tags within an
+// overridden struct taking its order, o
+func (ov *orderOverrides) apply(sl *StructLayout, o binary.ByteOrder, inherit bool) {
+	for i := range sl.Fields {
+		fl := &sl.Fields[i]
+		over, ok := ov.orders[fl.Path]
+		switch e := fl.Tag.Get("endian"); {
+		case ok:
+			fl.Order = over
+		case inherit && e != "big" && e != "little":
+			fl.Order = o
+		}
+		if fl.Elem != nil {
+			ov.apply(fl.Elem, fl.Order, inherit || ok)
+		}
+	}
+}
+
+// describe is Describe with the overrides of ov, if any
+func (ov *orderOverrides) describe(sample any) (*StructLayout, error) {
+	sl, err := Describe(sample)
+	if err != nil || ov == nil {
+		return sl, err
+	}
+	if err = ov.check(sl.Type); err != nil {
+		return nil, err
+	}
+	ov.apply(sl, nil, false)
+	return sl, nil
+}
+
+// Describe is the package's Describe, with the byte orders the Decoder reads sample's fields in
+// given any WithOrderOverrides
+func (d *Decoder) Describe(sample any) (*StructLayout, error) {
+	return d.dec.overrides.describe(sample)
+}
+
+// Describe is the package's Describe, with the byte orders the Encoder writes sample's fields in
+// given any WithOrderOverrides
+func (e *Encoder) Describe(sample any) (*StructLayout, error) {
+	return e.enc.overrides.describe(sample)
+}
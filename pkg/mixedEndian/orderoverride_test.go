@@ -0,0 +1,122 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+type OverrideInner struct {
+	X uint16
+	Y uint16 `endian:"big"`
+}
+
+type OverrideStruct struct {
+	Kind   uint16
+	Length uint32 `endian:"big"`
+	Inner  OverrideInner
+	Items  []OverrideInner `count:"1"`
+}
+
+func TestOrderOverrides(t *testing.T) {
+	wire := []byte{
+		0x01, 0x02, // Kind
+		0x00, 0x00, 0x00, 0x10, // Length
+		0x03, 0x04, 0x05, 0x06, // Inner
+		0x07, 0x08, 0x09, 0x0A, // Items
+	}
+	tests := []struct {
+		name      string
+		overrides map[string]binary.ByteOrder
+		want      OverrideStruct
+	}{
+		{
+			name: "none",
+			want: OverrideStruct{Kind: 0x0201, Length: 0x10, Inner: OverrideInner{X: 0x0403, Y: 0x0506}, Items: []OverrideInner{{X: 0x0807, Y: 0x090A}}},
+		},
+		{
+			// Vendor A's firmware writes Length little endian, against its tag
+			name:      "tagged field",
+			overrides: map[string]binary.ByteOrder{"Length": LittleEndian},
+			want:      OverrideStruct{Kind: 0x0201, Length: 0x10000000, Inner: OverrideInner{X: 0x0403, Y: 0x0506}, Items: []OverrideInner{{X: 0x0807, Y: 0x090A}}},
+		},
+		{
+			// Vendor B's writes a struct big endian, but for Y, which keeps its tag
+			name:      "struct",
+			overrides: map[string]binary.ByteOrder{"Inner": BigEndian, "Items.Y": LittleEndian},
+			want:      OverrideStruct{Kind: 0x0201, Length: 0x10, Inner: OverrideInner{X: 0x0304, Y: 0x0506}, Items: []OverrideInner{{X: 0x0807, Y: 0x0A09}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got OverrideStruct
+			dec := NewDecoder(bytes.NewReader(wire), LittleEndian, WithOrderOverrides(tt.overrides))
+			if err := dec.Decode(&got); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if got.Kind != tt.want.Kind || got.Length != tt.want.Length || got.Inner != tt.want.Inner || got.Items[0] != tt.want.Items[0] {
+				t.Errorf("Decode() = %+v, wanted %+v", got, tt.want)
+			}
+
+			// Writing with the same overrides gives the same bytes back
+			buf := &bytes.Buffer{}
+			if err := NewEncoder(buf, LittleEndian, WithOrderOverrides(tt.overrides)).Encode(&got); err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if !bytes.Equal(buf.Bytes(), wire) {
+				t.Errorf("Encode() = % X, wanted % X", buf.Bytes(), wire)
+			}
+		})
+	}
+}
+
+func TestOrderOverridesDescribe(t *testing.T) {
+	dec := NewDecoder(nil, LittleEndian, WithOrderOverrides(map[string]binary.ByteOrder{"Inner": BigEndian, "Length": LittleEndian}))
+	sl, err := dec.Describe(OverrideStruct{})
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	orders := map[string]binary.ByteOrder{}
+	for _, f := range sl.Fields {
+		orders[f.Path] = f.Order
+		if f.Elem != nil {
+			for _, e := range f.Elem.Fields {
+				orders[e.Path] = e.Order
+			}
+		}
+	}
+	want := map[string]binary.ByteOrder{
+		"Kind": nil, "Length": LittleEndian, "Inner": BigEndian, "Inner.X": BigEndian, "Inner.Y": BigEndian,
+		"Items": nil, "Items.X": nil, "Items.Y": BigEndian,
+	}
+	for path, o := range want {
+		if orders[path] != o {
+			t.Errorf("Describe() %s Order = %v, wanted %v", path, orders[path], o)
+		}
+	}
+
+	// The package's Describe is left as the tags have it
+	if sl, err = Describe(OverrideStruct{}); err != nil || sl.Fields[1].Order != BigEndian || sl.Fields[2].Elem.Fields[0].Order != nil {
+		t.Errorf("Describe() = %+v, %v; wanted the tagged orders", sl, err)
+	}
+}
+
+func TestOrderOverridesUnknown(t *testing.T) {
+	for _, overrides := range []map[string]binary.ByteOrder{
+		{"Lenght": BigEndian},
+		{"Inner.Z": BigEndian},
+		{"Kind": nil},
+	} {
+		dec := NewDecoder(bytes.NewReader(make([]byte, 14)), BigEndian, WithOrderOverrides(overrides))
+		if err := dec.Decode(&OverrideStruct{}); !errors.Is(err, ErrTag) {
+			t.Errorf("Decode(%v) error = %v, wanted %v", overrides, err, ErrTag)
+		}
+		if _, err := dec.Describe(OverrideStruct{}); !errors.Is(err, ErrTag) {
+			t.Errorf("Describe(%v) error = %v, wanted %v", overrides, err, ErrTag)
+		}
+		if err := NewEncoder(&bytes.Buffer{}, BigEndian, WithOrderOverrides(overrides)).Encode(OverrideStruct{}); !errors.Is(err, ErrTag) {
+			t.Errorf("Encode(%v) error = %v, wanted %v", overrides, err, ErrTag)
+		}
+	}
+}
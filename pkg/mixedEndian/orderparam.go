@@ -0,0 +1,54 @@
+package mixedEndian
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// OrderProvider types give a byte order from their zero values, so it can be chosen by a type
+// parameter rather than passed around. ReadAs, WriteAs, UnmarshalAs, and MarshalAs take one as
+// the default byte order:
+//
+//	type Frame[O mixedEndian.OrderProvider] struct{ ... }
+//
+//	func (f *Frame[O]) Decode(r io.Reader) error {
+//		var data any = f
+//		return mixedEndian.ReadAs[O](r, &data)
+//	}
+type OrderProvider interface {
+	ByteOrder() binary.ByteOrder
+}
+
+// BigEndianOrder is the OrderProvider for BigEndian
+type BigEndianOrder struct{}
+
+func (BigEndianOrder) ByteOrder() binary.ByteOrder { return BigEndian }
+
+// LittleEndianOrder is the OrderProvider for LittleEndian
+type LittleEndianOrder struct{}
+
+func (LittleEndianOrder) ByteOrder() binary.ByteOrder { return LittleEndian }
+
+// ReadAs is Read with O's byte order as the default
+func ReadAs[O OrderProvider](ioReader io.Reader, data *any) error {
+	var o O
+	return Read(ioReader, o.ByteOrder(), data)
+}
+
+// WriteAs is Write with O's byte order as the default
+func WriteAs[O OrderProvider](ioWriter io.Writer, data any) error {
+	var o O
+	return Write(ioWriter, o.ByteOrder(), data)
+}
+
+// UnmarshalAs is Unmarshal with O's byte order as the default
+func UnmarshalAs[O OrderProvider](bs []byte, data any) error {
+	var o O
+	return Unmarshal(o.ByteOrder(), bs, data)
+}
+
+// MarshalAs is Marshal with O's byte order as the default
+func MarshalAs[O OrderProvider](data any) ([]byte, error) {
+	var o O
+	return Marshal(o.ByteOrder(), data)
+}
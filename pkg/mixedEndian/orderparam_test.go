@@ -0,0 +1,65 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+type OrderParamStruct struct {
+	A uint16
+	B uint32 `endian:"big"`
+	C int16
+}
+
+func TestOrderParam(t *testing.T) {
+	data := OrderParamStruct{A: 0x0102, B: 0x03040506, C: -2}
+	tests := []struct {
+		name  string
+		write func(*bytes.Buffer) error
+		read  func([]byte, *any) error
+		wire  []byte
+	}{
+		{
+			name:  "big",
+			write: func(b *bytes.Buffer) error { return WriteAs[BigEndianOrder](b, data) },
+			read:  func(bs []byte, got *any) error { return ReadAs[BigEndianOrder](bytes.NewReader(bs), got) },
+			wire:  []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0xFF, 0xFE},
+		},
+		{
+			name:  "little",
+			write: func(b *bytes.Buffer) error { return WriteAs[LittleEndianOrder](b, data) },
+			read:  func(bs []byte, got *any) error { return ReadAs[LittleEndianOrder](bytes.NewReader(bs), got) },
+			wire:  []byte{0x02, 0x01, 0x03, 0x04, 0x05, 0x06, 0xFE, 0xFF},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			if err := tt.write(buf); err != nil {
+				t.Fatalf("WriteAs() error = %v", err)
+			}
+			if !bytes.Equal(buf.Bytes(), tt.wire) {
+				t.Errorf("WriteAs() = % X, wanted % X", buf.Bytes(), tt.wire)
+			}
+
+			var got any = &OrderParamStruct{}
+			if err := tt.read(tt.wire, &got); err != nil {
+				t.Fatalf("ReadAs() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, &data) {
+				t.Errorf("ReadAs() = %+v, wanted %+v", got, data)
+			}
+		})
+	}
+
+	// The in memory forms agree
+	bs, err := MarshalAs[LittleEndianOrder](data)
+	if err != nil || !bytes.Equal(bs, tests[1].wire) {
+		t.Errorf("MarshalAs() = % X, %v; wanted % X", bs, err, tests[1].wire)
+	}
+	got := OrderParamStruct{}
+	if err = UnmarshalAs[BigEndianOrder](tests[0].wire, &got); err != nil || got != data {
+		t.Errorf("UnmarshalAs() = %+v, %v; wanted %+v", got, err, data)
+	}
+}
@@ -0,0 +1,154 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// overlayGroup is a run of fields tagged with the same overlay name, sharing one region of the
+// wire as the members of a C union do
+type overlayGroup struct {
+	name string
+
+	// fields are the members' indices, in order, and primary the one written, or -1 to write
+	// the first member that isn't its zero value
+	fields  []int
+	primary int
+
+	// size is that of the largest member
+	size int
+}
+
+// parseOverlays collects the overlay groups of struct t. Members must be adjacent and fixed size.
+func parseOverlays(t reflect.Type) (groups []overlayGroup, err error) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		s := sf.Tag.Get("overlay")
+		if s == "" {
+			continue
+		}
+		name, opt, _ := strings.Cut(s, ",")
+		if name == "" || (opt != "" && opt != "primary") {
+			return nil, fmt.Errorf("%s: %w overlay %q is not a group name, optionally followed by \",primary\"", sf.Name, ErrTag, s)
+		}
+		if !sf.IsExported() {
+			return nil, fmt.Errorf("%s: %w overlay needs an exported field", sf.Name, ErrTag)
+		}
+
+		n := len(groups) - 1
+		if n < 0 || groups[n].name != name || groups[n].fields[len(groups[n].fields)-1] != i-1 {
+			for _, g := range groups {
+				if g.name == name {
+					return nil, fmt.Errorf("%s: %w overlay group %q is split by other fields", sf.Name, ErrTag, name)
+				}
+			}
+			groups = append(groups, overlayGroup{name: name, primary: -1})
+			n++
+		}
+		g := &groups[n]
+
+		if opt == "primary" {
+			if g.primary >= 0 {
+				return nil, fmt.Errorf("%s: %w overlay group %q has two primaries", sf.Name, ErrTag, name)
+			}
+			g.primary = i
+		}
+
+		fl := FieldLayout{Type: sf.Type, Tag: sf.Tag}
+		if err = describeField(&fl, sf); err != nil {
+			return nil, fmt.Errorf("%s: %w", sf.Name, err)
+		} else if fl.Size < 0 {
+			return nil, fmt.Errorf("%s: %w overlay members need a fixed size", sf.Name, ErrLength)
+		}
+		if fl.Size > g.size {
+			g.size = fl.Size
+		}
+		g.fields = append(g.fields, i)
+	}
+	return
+}
+
+// overlayOf is the overlay group field i belongs to, if any
+func (so structOptions) overlayOf(i int) (overlayGroup, bool) {
+	for _, g := range so.overlays {
+		for _, j := range g.fields {
+			if j == i {
+				return g, true
+			}
+		}
+	}
+	return overlayGroup{}, false
+}
+
+// shareSpans gives every member of each overlay group the span of the region they share
+func (so structOptions) shareSpans(spans [][2]int64) {
+	for _, g := range so.overlays {
+		for _, i := range g.fields[1:] {
+			spans[i] = spans[g.fields[0]]
+		}
+	}
+}
+
+// readFieldAt reads field i of struct v, or, should it start an overlay group, the group's
+// region and every member from it. Later members of a group are left to the first.
+func (r *reader) readFieldAt(v reflect.Value, so structOptions, i int, o binary.ByteOrder) error {
+	sf := v.Type().Field(i)
+	g, ok := so.overlayOf(i)
+	if !ok {
+		return r.readField(v, sf, v.Field(i), o)
+	} else if g.fields[0] != i {
+		return nil
+	}
+
+	bs, err := r.next(g.size)
+	if err != nil {
+		return fmt.Errorf("%s: %w", sf.Name, err)
+	}
+	sub := *r
+	sub.trace = nil
+	for _, j := range g.fields {
+		sub.r = &sliceReader{bs: bs}
+		if err = sub.readField(v, v.Type().Field(j), v.Field(j), o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFieldAt writes field i of struct v, or, should it start an overlay group, the group's
+// region as its winning member encodes it, padded with zeros. Later members of a group are left
+// to the first.
+func (w *writer) writeFieldAt(v reflect.Value, so structOptions, i int, o binary.ByteOrder) error {
+	sf := v.Type().Field(i)
+	g, ok := so.overlayOf(i)
+	if !ok {
+		return w.writeField(v, sf, v.Field(i), o)
+	} else if g.fields[0] != i {
+		return nil
+	}
+
+	winner := g.primary
+	for _, j := range g.fields {
+		if winner < 0 && !v.Field(j).IsZero() {
+			winner = j
+		}
+	}
+
+	region := make([]byte, g.size)
+	if winner >= 0 {
+		buf := &bytes.Buffer{}
+		sub := *w
+		sub.w = buf
+		if err := sub.writeField(v, v.Type().Field(winner), v.Field(winner), o); err != nil {
+			return err
+		}
+		copy(region, buf.Bytes())
+	}
+	if _, err := w.w.Write(region); err != nil {
+		return fmt.Errorf("%s: %w", sf.Name, err)
+	}
+	return nil
+}
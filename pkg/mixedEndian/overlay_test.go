@@ -0,0 +1,170 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type OverlayRecord struct {
+	Kind  uint8
+	Word  uint64    `overlay:"value"`
+	Pair  [2]uint32 `overlay:"value" endian:"little"`
+	Short uint16    `overlay:"value"`
+	Tail  uint8
+}
+
+type PrimaryOverlayRecord struct {
+	Word uint64    `overlay:"value"`
+	Pair [2]uint32 `overlay:"value,primary"`
+}
+
+func TestOverlayRead(t *testing.T) {
+	wire := []byte{0x01, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0xFF}
+	want := &OverlayRecord{
+		Kind:  0x01,
+		Word:  0x0102030405060708,
+		Pair:  [2]uint32{0x04030201, 0x08070605},
+		Short: 0x0102,
+		Tail:  0xFF,
+	}
+
+	var data any = &OverlayRecord{}
+	if err := Read(bytes.NewReader(wire), BigEndian, &data); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("Read() data = %+v, wanted %+v", data, want)
+	}
+}
+
+func TestOverlayWrite(t *testing.T) {
+	tests := []struct {
+		name string
+		data any
+		want []byte
+	}{
+		{
+			// Without a primary, the first member that isn't zero is written
+			name: "first set member",
+			data: OverlayRecord{Kind: 1, Pair: [2]uint32{0x04030201, 0x08070605}, Short: 0xEEEE, Tail: 0xFF},
+			want: []byte{0x01, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0xFF},
+		},
+		{
+			name: "short member padded",
+			data: OverlayRecord{Kind: 1, Short: 0x0102, Tail: 0xFF},
+			want: []byte{0x01, 0x01, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xFF},
+		},
+		{
+			name: "no member set",
+			data: OverlayRecord{Kind: 1, Tail: 0xFF},
+			want: []byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xFF},
+		},
+		{
+			// A primary is written whatever the others hold, even when it's zero
+			name: "primary",
+			data: PrimaryOverlayRecord{Word: 0x0102030405060708, Pair: [2]uint32{0, 0x0A0B0C0D}},
+			want: []byte{0x00, 0x00, 0x00, 0x00, 0x0A, 0x0B, 0x0C, 0x0D},
+		},
+		{
+			name: "zero primary",
+			data: PrimaryOverlayRecord{Word: 0x0102030405060708},
+			want: make([]byte, 8),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Marshal(BigEndian, tt.data)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("Marshal() = % X, wanted % X", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOverlayRoundTrip(t *testing.T) {
+	// What's read writes back as it was, the first member covering the whole region
+	wire := []byte{0x02, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99}
+	var data any = &OverlayRecord{}
+	if err := Read(bytes.NewReader(wire), BigEndian, &data); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	got, err := Marshal(BigEndian, data)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !bytes.Equal(got, wire) {
+		t.Errorf("Marshal() = % X, wanted % X", got, wire)
+	}
+}
+
+func TestOverlayLayout(t *testing.T) {
+	sl, err := Describe(OverlayRecord{})
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	if sl.Size != 10 {
+		t.Errorf("Describe() Size = %d, wanted 10", sl.Size)
+	}
+	for i, want := range []struct{ offset, size int }{{0, 1}, {1, 8}, {1, 8}, {1, 2}, {9, 1}} {
+		if f := sl.Fields[i]; f.Offset != want.offset || f.Size != want.size {
+			t.Errorf("Describe() %s at %d of %d bytes, wanted at %d of %d", f.Name, f.Offset, f.Size, want.offset, want.size)
+		}
+	}
+
+	buf := &strings.Builder{}
+	if err = ExportTemplate010(buf, OverlayRecord{}); err != nil {
+		t.Fatalf("ExportTemplate010() error = %v", err)
+	}
+	if want := "\tunion {\n"; !strings.Contains(buf.String(), want) || !strings.Contains(buf.String(), "\t} value;\n") {
+		t.Errorf("ExportTemplate010() = %s, wanted a union of value", buf.String())
+	}
+}
+
+func TestOverlayErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    any
+		wantErr error
+	}{
+		{"split", &struct {
+			A uint8 `overlay:"x"`
+			B uint8
+			C uint8 `overlay:"x"`
+		}{}, ErrTag},
+		{"two primaries", &struct {
+			A uint8 `overlay:"x,primary"`
+			B uint8 `overlay:"x,primary"`
+		}{}, ErrTag},
+		{"unknown option", &struct {
+			A uint8 `overlay:"x,first"`
+		}{}, ErrTag},
+		{"variable size", &struct {
+			N uint8
+			A []uint8 `overlay:"x" len:"N"`
+			B uint8   `overlay:"x"`
+		}{}, ErrLength},
+		{"aligned", &struct {
+			_ struct{} `align:"4"`
+			A uint8    `overlay:"x"`
+		}{}, ErrTag},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Write(&bytes.Buffer{}, BigEndian, tt.data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Write() error = %v, wanted %v", err, tt.wantErr)
+			}
+			if err := Read(bytes.NewReader(make([]byte, 8)), BigEndian, &tt.data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Read() error = %v, wanted %v", err, tt.wantErr)
+			}
+			if _, err := Describe(tt.data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Describe() error = %v, wanted %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"sync"
+)
+
+// PipelinedEncoder writes values to an io.Writer as Write does, but hands the writing to a
+// background goroutine so encoding the next value overlaps writing the last. Values are written
+// in the order they're encoded.
+//
+// A PipelinedEncoder must be closed to be sure everything's written. One that's dropped without
+// Close stops its goroutine once garbage collected, abandoning whatever's still queued.
+// It's not safe for concurrent use.
+type PipelinedEncoder struct {
+	*pipeline
+}
+
+// pipeline is the state shared with the writing goroutine, which mustn't keep the
+// PipelinedEncoder itself reachable
+type pipeline struct {
+	enc        writer
+	bufferSize int
+
+	// maxOutput caps the bytes queued, in total, as WithMaxOutput caps an Encoder's
+	maxOutput int64
+	queued    int64
+
+	queue  chan []byte
+	done   chan struct{}
+	closed bool
+
+	// err is the first write error, after which queued values are dropped
+	mu  sync.Mutex
+	err error
+}
+
+// NewPipelinedEncoder returns a PipelinedEncoder writing to w with default byte order
+// defaultEndian. Up to queueDepth encoded values wait to be written before Encode blocks.
+// It takes the same options as NewEncoder.
+func NewPipelinedEncoder(w io.Writer, defaultEndian binary.ByteOrder, queueDepth int, opts ...Option) *PipelinedEncoder {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	p := &pipeline{
+		bufferSize: o.bufferSize,
+		maxOutput:  o.maxOutput,
+		queue:      make(chan []byte, queueDepth),
+		done:       make(chan struct{}),
+	}
+	p.enc = writer{o: defaultEndian, ctx: o.context(), canonical: o.canonical}
+	p.enc.versions.vn = o.negotiator
+	p.enc.overrides = newOrderOverrides(o.overrides)
+	go p.run(w)
+
+	e := &PipelinedEncoder{p}
+	runtime.SetFinalizer(e, func(e *PipelinedEncoder) { e.stop() })
+	return e
+}
+
+// run writes queued values to w until the queue is closed
+func (p *pipeline) run(w io.Writer) {
+	defer close(p.done)
+	for bs := range p.queue {
+		if p.failed() != nil {
+			continue
+		}
+		if _, err := writeFull(w, bs); err != nil {
+			p.mu.Lock()
+			p.err = err
+			p.mu.Unlock()
+		}
+	}
+}
+
+// failed is the first write error, if there's been one
+func (p *pipeline) failed() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+// stop closes the queue, once
+func (p *pipeline) stop() {
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+}
+
+// Encode encodes data and queues it to be written, blocking while the queue is full.
+// Once a write has failed, Encode returns that error without encoding anything.
+func (e *PipelinedEncoder) Encode(data any) error {
+	if e.closed {
+		return fmt.Errorf("%w PipelinedEncoder is closed", ErrClosed)
+	} else if err := e.failed(); err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(data)
+	if e.enc.overrides != nil && v.IsValid() {
+		if err := e.enc.overrides.check(v.Type()); err != nil {
+			return err
+		}
+	}
+	buf := &bytes.Buffer{}
+	buf.Grow(e.bufferSize)
+	enc := e.enc
+	enc.w = buf
+	if e.maxOutput > 0 {
+		enc.limit = &limitWriter{w: buf, n: e.maxOutput - e.queued}
+		enc.w = enc.limit
+	}
+	if err := enc.writeOrdered(v, enc.o); err != nil {
+		return err
+	}
+
+	e.queued += int64(buf.Len())
+	e.queue <- buf.Bytes()
+	return nil
+}
+
+// SetVersion makes the PipelinedEncoder write fields as protocol version v has them, as
+// Encoder.SetVersion does
+func (e *PipelinedEncoder) SetVersion(v int) {
+	e.enc.versions.version = v
+}
+
+// Close waits for every queued value to be written, then returns the first write error.
+// The PipelinedEncoder can't be used afterwards.
+func (e *PipelinedEncoder) Close() error {
+	e.stop()
+	runtime.SetFinalizer(e, nil)
+	<-e.done
+	return e.failed()
+}
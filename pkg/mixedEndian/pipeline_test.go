@@ -0,0 +1,159 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"reflect"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// slowWriter sleeps on each write, so encoding gets ahead of writing
+type slowWriter struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (s *slowWriter) Write(bs []byte) (int, error) {
+	time.Sleep(time.Millisecond)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.Write(bs)
+}
+
+// failingWriter fails every write after the first n
+type failingWriter struct {
+	n int
+}
+
+var errWriteFailed = errors.New("write failed")
+
+func (f *failingWriter) Write(bs []byte) (int, error) {
+	if f.n == 0 {
+		return 0, errWriteFailed
+	}
+	f.n--
+	return len(bs), nil
+}
+
+func TestPipelinedEncoderOrder(t *testing.T) {
+	w := &slowWriter{}
+	e := NewPipelinedEncoder(w, BigEndian, 4)
+
+	want := &bytes.Buffer{}
+	for i := 0; i < 50; i++ {
+		data := NoTagStruct{A: uint8(i), B: int16(i), C: uint32(i)}
+		if err := e.Encode(data); err != nil {
+			t.Fatalf("Encode(%d) error = %v", i, err)
+		}
+		if err := Write(want, BigEndian, data); err != nil {
+			t.Fatalf("Write(%d) error = %v", i, err)
+		}
+	}
+	if err := e.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if !bytes.Equal(w.buf.Bytes(), want.Bytes()) {
+		t.Errorf("PipelinedEncoder wrote % X, wanted % X", w.buf.Bytes(), want.Bytes())
+	}
+
+	if err := e.Encode(NoTagStruct{}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Encode() after Close() error = %v, wanted %v", err, ErrClosed)
+	}
+}
+
+func TestPipelinedEncoderWriteError(t *testing.T) {
+	e := NewPipelinedEncoder(&failingWriter{n: 1}, BigEndian, 1)
+	if err := e.Encode(NoTagStruct{}); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	// The second write fails in the background, after which Encode fails fast
+	deadline := time.Now().Add(5 * time.Second)
+	var err error
+	for err == nil && time.Now().Before(deadline) {
+		err = e.Encode(NoTagStruct{})
+	}
+	if !errors.Is(err, errWriteFailed) {
+		t.Errorf("Encode() error = %v, wanted %v", err, errWriteFailed)
+	}
+	if err := e.Close(); !errors.Is(err, errWriteFailed) {
+		t.Errorf("Close() error = %v, wanted %v", err, errWriteFailed)
+	}
+}
+
+func TestPipelinedEncoderEncodeError(t *testing.T) {
+	w := &bytes.Buffer{}
+	e := NewPipelinedEncoder(w, BigEndian, 1)
+	if err := e.Encode(UnknownEncodingStruct{}); !errors.Is(err, ErrTag) {
+		t.Errorf("Encode() error = %v, wanted %v", err, ErrTag)
+	}
+	if err := e.Close(); err != nil || w.Len() != 0 {
+		t.Errorf("Close() error = %v, with % X written, wanted nothing", err, w.Bytes())
+	}
+}
+
+func TestPipelinedEncoderAbandoned(t *testing.T) {
+	before := runtime.NumGoroutine()
+	for i := 0; i < 10; i++ {
+		e := NewPipelinedEncoder(io.Discard, BigEndian, 1)
+		if err := e.Encode(NoTagStruct{}); err != nil {
+			t.Fatalf("Encode() error = %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > before {
+		t.Errorf("%d goroutines left running, wanted %d", n, before)
+	}
+}
+
+func TestPipelinedEncoderOptions(t *testing.T) {
+	// Each value comes out as an Encoder with the same options would write it
+	data := VersionedHello{Kind: 1, Flags: 0x0203, Legacy: 4, Checksum: 5}
+	overrides := WithOrderOverrides(map[string]binary.ByteOrder{"Flags": LittleEndian})
+	vn := WithVersionNegotiator(&VersionNegotiator{Versions: map[reflect.Type]map[string]VersionRange{
+		reflect.TypeOf(VersionedHello{}): {"Checksum": {Min: 4}},
+	}})
+
+	want := &bytes.Buffer{}
+	e := NewEncoder(want, BigEndian, overrides, vn)
+	e.SetVersion(3)
+	if err := e.Encode(data); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	got := &bytes.Buffer{}
+	p := NewPipelinedEncoder(got, BigEndian, 1, overrides, vn)
+	p.SetVersion(3)
+	if err := p.Encode(data); err != nil {
+		t.Fatalf("PipelinedEncoder.Encode() error = %v", err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if !bytes.Equal(got.Bytes(), want.Bytes()) || !bytes.Equal(got.Bytes(), []byte{1, 0x03, 0x02}) {
+		t.Errorf("PipelinedEncoder wrote % X, wanted % X", got.Bytes(), want.Bytes())
+	}
+
+	// The output cap counts every value queued
+	got.Reset()
+	p = NewPipelinedEncoder(got, BigEndian, 1, WithMaxOutput(10))
+	if err := p.Encode(NoTagStruct{}); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if err := p.Encode(NoTagStruct{}); !errors.Is(err, ErrOutputLimit) {
+		t.Errorf("Encode() past the cap error = %v, wanted %v", err, ErrOutputLimit)
+	}
+	if err := p.Close(); err != nil || got.Len() != 7 {
+		t.Errorf("Close() = %v with %d bytes written, wanted 7", err, got.Len())
+	}
+}
@@ -0,0 +1,135 @@
+package mixedEndian
+
+import (
+	"encoding/binary"
+	"io"
+	"math/bits"
+	"reflect"
+	"sync"
+)
+
+// EncoderPool hands out Encoders for reuse across goroutines,
+// so each request doesn't allocate an Encoder and its buffer.
+type EncoderPool struct {
+	free chan *PooledEncoder
+	new  func() *PooledEncoder
+}
+
+// PooledEncoder is an Encoder borrowed from an EncoderPool
+type PooledEncoder struct {
+	*Encoder
+}
+
+// WriterPool returns a pool holding up to size Encoders with default byte order defaultEndian,
+// configured by opts. The pool starts full.
+func WriterPool(size int, defaultEndian binary.ByteOrder, opts ...Option) *EncoderPool {
+	p := &EncoderPool{
+		free: make(chan *PooledEncoder, size),
+		new: func() *PooledEncoder {
+			return &PooledEncoder{NewEncoder(nil, defaultEndian, opts...)}
+		},
+	}
+	for i := 0; i < size; i++ {
+		p.free <- p.new()
+	}
+	return p
+}
+
+// Acquire takes an Encoder from the pool, allocating one if the pool is empty.
+// Point it at a writer with Reset before use.
+func (p *EncoderPool) Acquire() *PooledEncoder {
+	select {
+	case e := <-p.free:
+		return e
+	default:
+		return p.new()
+	}
+}
+
+// Release returns e to the pool, dropping it if the pool is already full.
+// e must not be used afterwards.
+func (p *EncoderPool) Release(e *PooledEncoder) {
+	e.Reset(nil)
+	select {
+	case p.free <- e:
+	default:
+	}
+}
+
+// Reset points e at w without allocating
+func (e *PooledEncoder) Reset(w io.Writer) {
+	e.Encoder.Reset(w)
+}
+
+// marshalBuffers pools the buffers of MarshalPooled by capacity, each class holding buffers of at
+// least 1<<class bytes
+var marshalBuffers [64]sync.Pool
+
+// marshalSizes caches the encoded size of each type given to MarshalPooled, -1 if variable
+var marshalSizes sync.Map
+
+// MarshalPooled is Marshal, encoding into a buffer drawn from a pool rather than allocated.
+// Structs of fixed size are given a buffer with room for them, anything else one grown as needed.
+// buf's length is exactly that of the encoding, though its capacity may be larger.
+// Call release once done with buf to return it to the pool; buf must not be used afterwards.
+// Calling release again does nothing.
+func MarshalPooled(defaultEndian binary.ByteOrder, data any) (buf []byte, release func(), err error) {
+	n := pooledSize(reflect.TypeOf(data))
+	if n < 1 {
+		// A guess, grown by the writes themselves
+		n = 64
+	}
+
+	bw := getBuffer(n)
+	if err = Write(bw, defaultEndian, data); err != nil {
+		bw.release()
+		return nil, nil, err
+	}
+	// Each release is good once, so a second call can't hand the buffer out twice
+	released := false
+	return bw.bs, func() {
+		if !released {
+			released = true
+			bw.release()
+		}
+	}, nil
+}
+
+// pooledSize is the encoded size of values of type t, or -1 if it varies
+func pooledSize(t reflect.Type) int {
+	if n, ok := marshalSizes.Load(t); ok {
+		return n.(int)
+	}
+	n := -1
+	if sl, err := LayoutOf(t); err == nil && sl.Size >= 0 {
+		n = sl.Size
+	}
+	marshalSizes.Store(t, n)
+	return n
+}
+
+// byteWriter appends writes to bs, a pooled buffer which release returns to its pool
+type byteWriter struct {
+	bs      []byte
+	release func()
+}
+
+func (b *byteWriter) Write(p []byte) (int, error) {
+	b.bs = append(b.bs, p...)
+	return len(p), nil
+}
+
+// getBuffer takes an empty buffer with room for n bytes from the pools, or allocates one
+func getBuffer(n int) *byteWriter {
+	if bw, ok := marshalBuffers[bits.Len(uint(n-1))].Get().(*byteWriter); ok {
+		return bw
+	}
+
+	bw := &byteWriter{bs: make([]byte, 0, 1<<bits.Len(uint(n-1)))}
+	bw.release = func() {
+		// Buffers grown past their class are pooled with the larger buffers
+		bw.bs = bw.bs[:0]
+		marshalBuffers[bits.Len(uint(cap(bw.bs)))-1].Put(bw)
+	}
+	return bw
+}
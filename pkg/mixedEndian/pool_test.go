@@ -0,0 +1,141 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestWriterPool(t *testing.T) {
+	p := WriterPool(2, LittleEndian, WithBufferSize(16))
+
+	a, b, c := p.Acquire(), p.Acquire(), p.Acquire()
+	if a == b || b == c || a == c {
+		t.Fatal("Acquire() returned the same encoder twice")
+	}
+
+	buf := &bytes.Buffer{}
+	a.Reset(buf)
+	if err := a.Encode(TaggedStruct{A: 0x0102, B: 0x0304}); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if want := []byte{0x01, 0x02, 0x04, 0x03}; !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("Encode() = % X, wanted % X", buf.Bytes(), want)
+	}
+
+	p.Release(a)
+	p.Release(b)
+	p.Release(c)
+	if got := p.Acquire(); got != a && got != b {
+		t.Error("Acquire() didn't reuse a released encoder")
+	}
+}
+
+var poolSample = NestedStruct{A: 1, B: TaggedStruct{A: 2, B: 3}, C: 4}
+
+// encodeConcurrently encodes poolSample from 100 goroutines at once
+func encodeConcurrently(b *testing.B, encode func()) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var wg sync.WaitGroup
+		for g := 0; g < 100; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				encode()
+			}()
+		}
+		wg.Wait()
+	}
+}
+
+func BenchmarkEncoderPerRequest(b *testing.B) {
+	encodeConcurrently(b, func() {
+		e := NewEncoder(io.Discard, BigEndian, WithBufferSize(64))
+		_ = e.Encode(poolSample)
+	})
+}
+
+func BenchmarkEncoderPool(b *testing.B) {
+	p := WriterPool(100, BigEndian, WithBufferSize(64))
+	encodeConcurrently(b, func() {
+		e := p.Acquire()
+		e.Reset(io.Discard)
+		_ = e.Encode(poolSample)
+		p.Release(e)
+	})
+}
+
+func TestMarshalPooled(t *testing.T) {
+	tests := []struct {
+		name string
+		data any
+	}{
+		{"fixed", poolSample},
+		{"pointer", &poolSample},
+		{"variable", &DNP3Struct{N: 3, Data: []byte{1, 2, 3}}},
+		{"empty", struct{}{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := Marshal(BigEndian, tt.data)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+
+			// Twice, the second time reusing the released buffer
+			for i := 0; i < 2; i++ {
+				buf, release, err := MarshalPooled(BigEndian, tt.data)
+				if err != nil {
+					t.Fatalf("MarshalPooled() error = %v", err)
+				}
+				if !bytes.Equal(buf, want) {
+					t.Errorf("MarshalPooled() = % X, wanted % X", buf, want)
+				}
+
+				data := reflect.New(reflect.Indirect(reflect.ValueOf(tt.data)).Type()).Interface()
+				if err = Read(bytes.NewReader(buf), BigEndian, &data); err != nil {
+					t.Fatalf("Read() error = %v", err)
+				}
+				if got := reflect.ValueOf(data).Elem().Interface(); !reflect.DeepEqual(got, reflect.Indirect(reflect.ValueOf(tt.data)).Interface()) {
+					t.Errorf("Read() data = %v, wanted %v", got, tt.data)
+				}
+				release()
+			}
+		})
+	}
+
+	if _, _, err := MarshalPooled(BigEndian, (*NestedStruct)(nil)); err == nil {
+		t.Error("MarshalPooled() of nil succeeded")
+	}
+
+	// Releasing twice mustn't pool the buffer twice, handing it to two callers at once
+	_, release, _ := MarshalPooled(BigEndian, poolSample)
+	release()
+	release()
+	want, _ := Marshal(BigEndian, poolSample)
+	a, releaseA, _ := MarshalPooled(BigEndian, poolSample)
+	b, releaseB, _ := MarshalPooled(BigEndian, poolSample)
+	defer releaseA()
+	defer releaseB()
+	if !bytes.Equal(a, want) || !bytes.Equal(b, want) {
+		t.Errorf("MarshalPooled() after a double release = % X and % X, wanted % X", a, b, want)
+	}
+}
+
+func BenchmarkMarshal(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = Marshal(BigEndian, &poolSample)
+	}
+}
+
+func BenchmarkMarshalPooled(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, release, _ := MarshalPooled(BigEndian, &poolSample)
+		release()
+	}
+}
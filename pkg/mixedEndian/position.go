@@ -0,0 +1,48 @@
+package mixedEndian
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ReadError is returned by Read and a Decoder when a value fails partway through being read.
+// Offset is where in the input reading stopped. Should the io.Reader also be an io.Seeker, it's
+// the absolute position in the stream, as Seek(0, io.SeekCurrent) gives it, and Absolute is set,
+// so the byte can be found in a hex editor. Otherwise it counts the bytes read through the Read
+// call or Decoder.
+//
+// Running out of input before a value's first byte is io.EOF as it is, there being nothing to
+// locate.
+type ReadError struct {
+	Offset   int64
+	Absolute bool
+	Err      error
+}
+
+func (e *ReadError) Error() string {
+	if e.Absolute {
+		return fmt.Sprintf("%v (at stream position %d)", e.Err, e.Offset)
+	}
+	return fmt.Sprintf("%v (after %d bytes)", e.Err, e.Offset)
+}
+
+func (e *ReadError) Unwrap() error {
+	return e.Err
+}
+
+// readError wraps err, met reading a value from s that started start bytes into the input and
+// stopped n bytes in, with where it stopped. s is nil for inputs that aren't io.Seekers.
+func readError(s io.Seeker, start, n int64, err error) error {
+	if err == nil || errors.Is(err, io.EOF) && n == start {
+		return err
+	}
+	e := &ReadError{Offset: n, Err: err}
+	if s != nil {
+		// Pipes and the like are Seekers that can't, so are counted as any other reader
+		if pos, serr := s.Seek(0, io.SeekCurrent); serr == nil {
+			e.Offset, e.Absolute = pos, true
+		}
+	}
+	return e
+}
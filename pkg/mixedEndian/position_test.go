@@ -0,0 +1,73 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+type PositionStruct struct {
+	A uint16
+	B uint32 `const:"2"`
+}
+
+func TestReadErrorPosition(t *testing.T) {
+	// A header the Decoder isn't given, so the stream position and bytes decoded differ
+	wire := []byte{0xAA, 0xBB, 0xCC, 0xDD, 0x00, 0x01, 0x00, 0x00, 0x00, 0x02, 0x00, 0x01, 0x00, 0x00, 0x01, 0x00}
+	tests := []struct {
+		name     string
+		r        func() io.Reader
+		offset   int64
+		absolute bool
+	}{
+		{name: "seeker", r: func() io.Reader {
+			br := bytes.NewReader(wire)
+			br.Seek(4, io.SeekStart)
+			return br
+		}, offset: 16, absolute: true},
+		{name: "reader", r: func() io.Reader { return struct{ io.Reader }{bytes.NewReader(wire[4:])} }, offset: 12},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dec := NewDecoder(tt.r(), BigEndian)
+			var good PositionStruct
+			if err := dec.Decode(&good); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			// B is 256 rather than its const 2, an error only once it's been read
+			err := dec.Decode(&PositionStruct{})
+			var re *ReadError
+			if !errors.As(err, &re) || !errors.Is(err, ErrValidation) {
+				t.Fatalf("Decode() error = %v, wanted a *ReadError wrapping %v", err, ErrValidation)
+			}
+			if re.Offset != tt.offset || re.Absolute != tt.absolute {
+				t.Errorf("Decode() error at %d, absolute %t; wanted %d, %t", re.Offset, re.Absolute, tt.offset, tt.absolute)
+			}
+			if !strings.HasPrefix(err.Error(), "B: ") {
+				t.Errorf("Decode() error = %v, wanted it to name B", err)
+			}
+
+			// The end of the input, between values, is left as io.EOF
+			if err = dec.Decode(&PositionStruct{}); err != io.EOF {
+				t.Errorf("Decode() at end error = %v, wanted %v", err, io.EOF)
+			}
+		})
+	}
+}
+
+func TestReadErrorPositionRead(t *testing.T) {
+	br := bytes.NewReader([]byte{0xAA, 0xBB, 0x00, 0x01, 0x00, 0x00})
+	br.Seek(2, io.SeekStart)
+	var data any = &PositionStruct{}
+	err := Read(br, BigEndian, &data)
+	var re *ReadError
+	if !errors.As(err, &re) || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("Read() error = %v, wanted a *ReadError wrapping %v", err, io.ErrUnexpectedEOF)
+	}
+	if !re.Absolute || re.Offset != 6 || !strings.Contains(err.Error(), "stream position 6") {
+		t.Errorf("Read() error = %v, wanted it at stream position 6", err)
+	}
+}
@@ -0,0 +1,52 @@
+package mixedEndian
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// presenceIndex is the index of the field of struct t named by sf's presentif tag, an earlier bool
+func presenceIndex(t reflect.Type, sf reflect.StructField, ref string) (int, error) {
+	flag, ok := t.FieldByName(ref)
+	if !ok || len(flag.Index) != 1 || flag.Index[0] >= sf.Index[len(sf.Index)-1] {
+		return 0, fmt.Errorf("%w presentif %q is not an earlier field", ErrTag, ref)
+	}
+	if flag.Type.Kind() != reflect.Bool {
+		return 0, fmt.Errorf("%w presentif %s needs a bool; Got %s", ErrUnexpectedType, ref, flag.Type.String())
+	}
+	return flag.Index[0], nil
+}
+
+// presenceFlag is the field of struct v named by sf's presentif tag
+func presenceFlag(v reflect.Value, sf reflect.StructField, ref string) (reflect.Value, error) {
+	i, err := presenceIndex(v.Type(), sf, ref)
+	if err != nil {
+		return v, err
+	}
+	return v.Field(i), nil
+}
+
+// isPresent reports whether field f of struct v, tagged presentif, is to be written: when its
+// flag is set or it's not its zero value
+func isPresent(v reflect.Value, sf reflect.StructField, f reflect.Value) (bool, error) {
+	flag, err := presenceFlag(v, sf, sf.Tag.Get("presentif"))
+	if err != nil {
+		return false, err
+	}
+	return flag.Bool() || !f.IsZero(), nil
+}
+
+// flagFor returns bool field f of struct v set if any later field it gates is present, so the
+// flag written always matches the fields that follow it
+func flagFor(v reflect.Value, sf reflect.StructField, f reflect.Value) reflect.Value {
+	if f.Bool() {
+		return f
+	}
+	t := v.Type()
+	for i := sf.Index[len(sf.Index)-1] + 1; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("presentif") == sf.Name && !v.Field(i).IsZero() {
+			return reflect.ValueOf(true).Convert(f.Type())
+		}
+	}
+	return f
+}
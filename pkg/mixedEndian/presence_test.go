@@ -0,0 +1,114 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type OptionalExtension struct {
+	Kind   uint8
+	HasExt bool
+	Ext    uint32 `presentif:"HasExt"`
+	Tail   uint16
+}
+
+func TestPresentIf(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  OptionalExtension
+		wire  []byte
+		wantR OptionalExtension
+	}{
+		{
+			name:  "present",
+			data:  OptionalExtension{Kind: 1, HasExt: true, Ext: 0x01020304, Tail: 0xBEEF},
+			wire:  []byte{0x01, 0x01, 0x01, 0x02, 0x03, 0x04, 0xBE, 0xEF},
+			wantR: OptionalExtension{Kind: 1, HasExt: true, Ext: 0x01020304, Tail: 0xBEEF},
+		},
+		{
+			name:  "absent",
+			data:  OptionalExtension{Kind: 1, Tail: 0xBEEF},
+			wire:  []byte{0x01, 0x00, 0xBE, 0xEF},
+			wantR: OptionalExtension{Kind: 1, Tail: 0xBEEF},
+		},
+		{
+			name:  "flag set by value",
+			data:  OptionalExtension{Kind: 1, Ext: 7, Tail: 0xBEEF},
+			wire:  []byte{0x01, 0x01, 0x00, 0x00, 0x00, 0x07, 0xBE, 0xEF},
+			wantR: OptionalExtension{Kind: 1, HasExt: true, Ext: 7, Tail: 0xBEEF},
+		},
+		{
+			name:  "explicitly present zero",
+			data:  OptionalExtension{Kind: 1, HasExt: true, Tail: 0xBEEF},
+			wire:  []byte{0x01, 0x01, 0x00, 0x00, 0x00, 0x00, 0xBE, 0xEF},
+			wantR: OptionalExtension{Kind: 1, HasExt: true, Tail: 0xBEEF},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			if err := Write(buf, BigEndian, tt.data); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if !bytes.Equal(buf.Bytes(), tt.wire) {
+				t.Errorf("Write() = % X, wanted % X", buf.Bytes(), tt.wire)
+			}
+			if n, err := CountBytes(BigEndian, tt.data); err != nil || n != len(tt.wire) {
+				t.Errorf("CountBytes() = %d, %v, wanted %d", n, err, len(tt.wire))
+			}
+
+			r := bytes.NewReader(tt.wire)
+			var data any = &OptionalExtension{Ext: 99}
+			if err := Read(r, BigEndian, &data); err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if !reflect.DeepEqual(data, &tt.wantR) {
+				t.Errorf("Read() = %+v, wanted %+v", data, tt.wantR)
+			}
+			if r.Len() != 0 {
+				t.Errorf("Read() left %d bytes", r.Len())
+			}
+		})
+	}
+}
+
+func TestPresentIfErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    any
+		wantErr error
+	}{
+		{
+			name: "later flag",
+			data: struct {
+				V    uint8 `presentif:"Flag"`
+				Flag bool
+			}{},
+			wantErr: ErrTag,
+		},
+		{
+			name: "unknown flag",
+			data: struct {
+				V uint8 `presentif:"Flag"`
+			}{},
+			wantErr: ErrTag,
+		},
+		{
+			name: "flag not bool",
+			data: struct {
+				Flag uint8
+				V    uint8 `presentif:"Flag"`
+			}{},
+			wantErr: ErrUnexpectedType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Write(&bytes.Buffer{}, BigEndian, tt.data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Write() error = %v, wanted %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package mixedEndian
+
+import "io"
+
+// maxEmptyReads is how many reads in a row may return neither bytes nor an error before
+// reading gives up, as bufio does
+const maxEmptyReads = 100
+
+// progressReader reads from r, failing with io.ErrNoProgress rather than retrying forever
+// should r keep returning neither bytes nor an error
+type progressReader struct {
+	r io.Reader
+}
+
+// guardProgress wraps r in a progressReader, unless it's one of the package's in-memory readers,
+// which always make progress
+func guardProgress(r io.Reader) io.Reader {
+	switch r.(type) {
+	case *sliceReader, progressReader:
+		return r
+	}
+	return progressReader{r: r}
+}
+
+func (p progressReader) Read(bs []byte) (n int, err error) {
+	if len(bs) == 0 {
+		return p.r.Read(bs)
+	}
+	for i := 0; i < maxEmptyReads; i++ {
+		if n, err = p.r.Read(bs); n > 0 || err != nil {
+			return
+		}
+	}
+	return 0, io.ErrNoProgress
+}
+
+// writeFull writes all of bs to w, retrying with what's left should w accept only part of it
+// without an error, as some pipes and sockets do. It's io.ReadFull for writes, failing with
+// io.ErrShortWrite should w keep accepting nothing.
+func writeFull(w io.Writer, bs []byte) (n int, err error) {
+	for empty := 0; n < len(bs) && err == nil; {
+		var m int
+		m, err = w.Write(bs[n:])
+		if n += m; m > 0 {
+			empty = 0
+		} else if empty++; empty == maxEmptyReads && err == nil {
+			err = io.ErrShortWrite
+		}
+	}
+	return
+}
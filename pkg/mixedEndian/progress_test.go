@@ -0,0 +1,155 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// stallingReader gives one byte of bs every other read, returning (0, nil) in between, then
+// (0, nil) forever once bs runs out
+type stallingReader struct {
+	bs    []byte
+	stall bool
+}
+
+func (s *stallingReader) Read(bs []byte) (int, error) {
+	if s.stall = !s.stall; s.stall || len(s.bs) == 0 || len(bs) == 0 {
+		return 0, nil
+	}
+	bs[0], s.bs = s.bs[0], s.bs[1:]
+	return 1, nil
+}
+
+type ProgressStruct struct {
+	A uint16
+	B [3]byte
+	C uint32
+}
+
+func TestNoProgress(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		wantData any
+		wantErr  error
+	}{
+		{
+			name:     "slow but progressing",
+			input:    []byte{0x00, 0x01, 'a', 'b', 'c', 0x00, 0x00, 0x00, 0x02},
+			wantData: &ProgressStruct{A: 1, B: [3]byte{'a', 'b', 'c'}, C: 2},
+		},
+		{
+			name:    "stalled within a field",
+			input:   []byte{0x00, 0x01, 'a', 'b', 'c', 0x00, 0x00},
+			wantErr: io.ErrNoProgress,
+		},
+		{
+			name:    "stalled from the start",
+			wantErr: io.ErrNoProgress,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data any = &ProgressStruct{}
+			err := Read(&stallingReader{bs: tt.input}, BigEndian, &data)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Read() error = %v, wanted %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if !reflect.DeepEqual(data, tt.wantData) {
+				t.Errorf("Read() data = %v, wanted %v", data, tt.wantData)
+			}
+		})
+	}
+}
+
+func TestNoProgressNamesField(t *testing.T) {
+	var data any = &ProgressStruct{}
+	err := Read(&stallingReader{bs: []byte{0x00, 0x01, 'a'}}, BigEndian, &data)
+	if !errors.Is(err, io.ErrNoProgress) || !strings.HasPrefix(err.Error(), "B: ") {
+		t.Errorf("Read() error = %v, wanted io.ErrNoProgress reading B", err)
+	}
+}
+
+func TestNoProgressDecoder(t *testing.T) {
+	d := NewDecoder(&stallingReader{bs: []byte{0x00, 0x01, 'a', 'b', 'c', 0x00, 0x00, 0x00, 0x02, 0x00}}, BigEndian)
+	var records []ProgressStruct
+	if _, err := d.DecodeAll(&records); !errors.Is(err, io.ErrNoProgress) {
+		t.Errorf("DecodeAll() error = %v, wanted %v", err, io.ErrNoProgress)
+	}
+
+	d = NewDecoder(&stallingReader{}, BigEndian, WithResync([]byte{0xFF}))
+	if _, err := d.DecodeAll(&records); !errors.Is(err, io.ErrNoProgress) {
+		t.Errorf("DecodeAll() with resync error = %v, wanted %v", err, io.ErrNoProgress)
+	}
+}
+
+func TestNoProgressCRCBlockReader(t *testing.T) {
+	c, err := NewCRCBlockReader(&stallingReader{bs: []byte{0x01, 0x02}}, 4, "crc16-dnp")
+	if err != nil {
+		t.Fatalf("NewCRCBlockReader() error = %v", err)
+	}
+	if _, err = io.ReadAll(c); !errors.Is(err, io.ErrNoProgress) {
+		t.Errorf("ReadAll() error = %v, wanted %v", err, io.ErrNoProgress)
+	}
+}
+
+// trickleWriter takes at most two bytes of each write, without an error, or none once stalled
+type trickleWriter struct {
+	bytes.Buffer
+	writes int
+	stall  bool
+}
+
+func (t *trickleWriter) Write(bs []byte) (int, error) {
+	t.writes++
+	if t.stall {
+		return 0, nil
+	}
+	if len(bs) > 2 {
+		bs = bs[:2]
+	}
+	return t.Buffer.Write(bs)
+}
+
+type ShortWriteStruct struct {
+	A uint64
+}
+
+func TestShortWrites(t *testing.T) {
+	data := ShortWriteStruct{A: 0x0102030405060708}
+	want := []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}
+
+	w := &trickleWriter{}
+	if err := Write(w, BigEndian, data); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(w.Bytes(), want) || w.writes != 4 {
+		t.Errorf("Write() = % X in %d writes, wanted % X in 4", w.Bytes(), w.writes, want)
+	}
+
+	w = &trickleWriter{}
+	if err := NewEncoder(w, BigEndian).Encode(data); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if !bytes.Equal(w.Bytes(), want) {
+		t.Errorf("Encode() = % X, wanted % X", w.Bytes(), want)
+	}
+
+	// Writers that never take anything fail rather than being retried forever
+	w = &trickleWriter{stall: true}
+	err := Write(w, BigEndian, data)
+	var we *WriteError
+	if !errors.Is(err, io.ErrShortWrite) || !errors.As(err, &we) || we.Path != "A" {
+		t.Errorf("Write(stalled) error = %v, wanted a WriteError at A of io.ErrShortWrite", err)
+	}
+}
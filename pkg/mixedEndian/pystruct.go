@@ -0,0 +1,208 @@
+package mixedEndian
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// pyStructCodes are the struct module's format characters for each base type
+var pyStructCodes = map[reflect.Kind]byte{
+	reflect.Bool:   '?',
+	reflect.Int8:   'b',
+	reflect.Uint8:  'B',
+	reflect.Int16:  'h',
+	reflect.Uint16: 'H',
+	reflect.Int32:  'i',
+	reflect.Uint32: 'I',
+	reflect.Int64:  'q',
+	reflect.Uint64: 'Q',
+}
+
+// ExportPyStructFormat gives the format string Python's struct module packs and unpacks sample,
+// a struct or pointer to one, with, such as ">BhI". PyStructFields names the values it unpacks.
+//
+// struct formats are flat, so nested structs and arrays of them are spelled out field by field.
+// Byte arrays, strings, and hardware addresses are unpacked as bytes, while other arrays give a
+// value per element. Padding added by an align option is skipped.
+//
+// A format has a single byte order, so fields wider than a byte may only be tagged with one.
+// Those without a tag of their own are taken to share it, or to be big endian should no field
+// give one. Fields struct can't express, such as those of a variable size, bitfields, overlays,
+// and odd width integers, fail with ErrLayout naming them.
+func ExportPyStructFormat(sample any) (string, error) {
+	format, _, err := pyStruct(sample)
+	return format, err
+}
+
+// PyStructFields names the values unpacking ExportPyStructFormat's format for sample gives, in
+// order. Nested fields are given by their paths, and array elements by their indices, such as
+// "Header.Flags" and "Samples[2]".
+func PyStructFields(sample any) ([]string, error) {
+	_, names, err := pyStruct(sample)
+	return names, err
+}
+
+// pyStruct gives sample's struct format along with the names of the values it unpacks
+func pyStruct(sample any) (format string, names []string, err error) {
+	sl, err := Describe(sample)
+	if err != nil {
+		return "", nil, err
+	}
+
+	p := &pyStructFormat{}
+	if err = p.fields(sl, "", 0); err != nil {
+		return "", nil, err
+	}
+	if sl.Size > p.size {
+		p.code(sl.Size-p.size, 'x', "")
+	}
+
+	prefix := ">"
+	if p.order != nil && !isBigEndian(p.order) {
+		prefix = "<"
+	}
+	return prefix + p.b.String(), p.names, nil
+}
+
+type pyStructFormat struct {
+	b     strings.Builder
+	names []string
+
+	// size is the number of bytes described so far
+	size int
+
+	// order is the one byte order given by a field's tags, once there's been one
+	order     binary.ByteOrder
+	orderPath string
+}
+
+// code appends n of format character c, which unpack as many values, or just one, named name,
+// for bytes. Pad bytes unpack no values.
+func (p *pyStructFormat) code(n int, c byte, name string) {
+	if n != 1 {
+		fmt.Fprintf(&p.b, "%d", n)
+	}
+	p.b.WriteByte(c)
+
+	switch c {
+	case 'x':
+	case 's':
+		p.names = append(p.names, name)
+	default:
+		if n == 1 {
+			p.names = append(p.names, name)
+			break
+		}
+		for i := 0; i < n; i++ {
+			p.names = append(p.names, fmt.Sprintf("%s[%d]", name, i))
+		}
+	}
+}
+
+// fields appends the fields of sl, which starts base bytes in, with their names prefixed by path
+func (p *pyStructFormat) fields(sl *StructLayout, path string, base int) error {
+	for _, f := range sl.Fields {
+		if err := p.field(f, path+f.Name, base); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// field appends f, named name, in a struct starting base bytes in
+func (p *pyStructFormat) field(f FieldLayout, name string, base int) error {
+	sf := reflect.StructField{Name: f.Name, Type: f.Type, Tag: f.Tag}
+	noCode := func(what string) error {
+		return fmt.Errorf("%w %s is %s, which Python's struct can't express", ErrLayout, name, what)
+	}
+
+	switch {
+	case f.Size == 0:
+		// Nothing on the wire to unpack
+		return nil
+	case f.Size < 0 || f.Offset < 0:
+		return noCode("variable sized")
+	case f.Tag.Get("bitwidth") != "":
+		return noCode("a bitfield")
+	case f.Tag.Get("overlay") != "":
+		return noCode("an overlay member")
+	case isNorm(sf) || isPCM(sf) || isDecimal(sf) || isDuration(sf) || isEpoch(sf) || f.Type == timecodeType:
+		return noCode("converted from its wire value")
+	}
+
+	// Alignment pads before fields
+	if at := base + f.Offset; at > p.size {
+		p.code(at-p.size, 'x', "")
+		p.size = at
+	}
+
+	t := f.Type
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	n, list := 1, t.Kind() == reflect.Array || t.Kind() == reflect.Slice
+	if list {
+		n, t = f.Count, t.Elem()
+	}
+
+	switch {
+	case t.Kind() == reflect.String || (list && t.Kind() == reflect.Uint8):
+		p.code(f.Size, 's', name)
+
+	case f.Elem != nil:
+		// Structs are flattened, element by element, then padded out to their size
+		var err error
+		if !list {
+			err = p.fields(f.Elem, name+".", base+f.Offset)
+		}
+		for i := 0; list && i < n && err == nil; i++ {
+			err = p.fields(f.Elem, fmt.Sprintf("%s[%d].", name, i), base+f.Offset+i*f.Size/n)
+		}
+		if err != nil {
+			return err
+		}
+		if end := base + f.Offset + f.Size; end > p.size {
+			p.code(end-p.size, 'x', "")
+			p.size = end
+		}
+		return nil
+
+	default:
+		c, ok := pyStructCodes[t.Kind()]
+		if !ok || typeSize(t) != size(t.Kind()) {
+			return noCode("a " + t.String())
+		}
+		if typeSize(t) > 1 {
+			if err := p.orderOf(f, name); err != nil {
+				return err
+			}
+		}
+		p.code(n, c, name)
+	}
+
+	p.size += f.Size
+	return nil
+}
+
+// orderOf checks the byte order f, named name, is given agrees with those before it
+func (p *pyStructFormat) orderOf(f FieldLayout, name string) error {
+	switch {
+	case f.Order == nil:
+	case p.order == nil:
+		p.order, p.orderPath = f.Order, name
+	case isBigEndian(p.order) != isBigEndian(f.Order):
+		return fmt.Errorf("%w %s is %s endian, where %s is %s endian, and a struct format has only one byte order",
+			ErrLayout, name, endianName(f.Order), p.orderPath, endianName(p.order))
+	}
+	return nil
+}
+
+// endianName names byte order o
+func endianName(o binary.ByteOrder) string {
+	if isBigEndian(o) {
+		return "big"
+	}
+	return "little"
+}
@@ -0,0 +1,142 @@
+package mixedEndian
+
+import (
+	"errors"
+	"os/exec"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestExportPyStructFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		sample     any
+		wantFormat string
+		wantFields []string
+	}{
+		{
+			name:       "NoTagStruct",
+			sample:     NoTagStruct{},
+			wantFormat: ">BhI",
+			wantFields: []string{"A", "B", "C"},
+		},
+		{
+			name: "little",
+			sample: &struct {
+				A uint8
+				B []uint16 `count:"3" endian:"little"`
+			}{},
+			wantFormat: "<B3H",
+			wantFields: []string{"A", "B[0]", "B[1]", "B[2]"},
+		},
+		{
+			name:       "ValidatedStruct",
+			sample:     ValidatedStruct{},
+			wantFormat: ">3sB",
+			wantFields: []string{"Magic", "Kind"},
+		},
+		{
+			name:       "EthernetHeader",
+			sample:     EthernetHeader{},
+			wantFormat: ">6s6sH",
+			wantFields: []string{"Dst", "Src", "Type"},
+		},
+		{
+			name:       "AlignedStruct",
+			sample:     AlignedStruct{},
+			wantFormat: ">B3sIHHBB2s2I",
+			wantFields: []string{"A", "Pad", "B", "C", "Inner.D", "Inner.E", "Inner.F", "Pad2", "G[0]", "G[1]"},
+		},
+		{
+			name:       "AlignedStruct8",
+			sample:     AlignedStruct8{},
+			wantFormat: ">B3xIH6xQB7x",
+			wantFields: []string{"A", "B", "C", "D", "E"},
+		},
+		{
+			name: "struct array",
+			sample: struct {
+				Count uint8
+				Items [2]GenInner `endian:"little"`
+			}{},
+			wantFormat: "<Bh2sh2s",
+			wantFields: []string{"Count", "Items[0].A", "Items[0].B", "Items[1].A", "Items[1].B"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := ExportPyStructFormat(tt.sample)
+			if err != nil {
+				t.Fatalf("ExportPyStructFormat() error = %v", err)
+			}
+			if format != tt.wantFormat {
+				t.Errorf("ExportPyStructFormat() = %q, wanted %q", format, tt.wantFormat)
+			}
+			fields, err := PyStructFields(tt.sample)
+			if err != nil {
+				t.Fatalf("PyStructFields() error = %v", err)
+			}
+			if !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Errorf("PyStructFields() = %q, wanted %q", fields, tt.wantFields)
+			}
+
+			size, err := SizeOf(tt.sample)
+			if err != nil {
+				t.Fatalf("SizeOf() error = %v", err)
+			}
+			checkPyCalcSize(t, format, size, len(fields))
+		})
+	}
+}
+
+// checkPyCalcSize has Python's struct module check format is size bytes, unpacking n values.
+// It's skipped without a python3 to run.
+func checkPyCalcSize(t *testing.T, format string, size, n int) {
+	t.Helper()
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("python3 not found")
+	}
+
+	script := "import struct, sys; f = sys.argv[1]; print(struct.calcsize(f), len(struct.unpack(f, bytes(struct.calcsize(f)))))"
+	out, err := exec.Command(python, "-c", script, format).Output()
+	if err != nil {
+		t.Fatalf("python3 error = %v", err)
+	}
+	got := strings.Fields(string(out))
+	if len(got) != 2 || got[0] != strconv.Itoa(size) || got[1] != strconv.Itoa(n) {
+		t.Errorf("struct.calcsize(%q), values = %q, wanted %d bytes, %d values", format, got, size, n)
+	}
+}
+
+func TestExportPyStructFormatErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		sample    any
+		wantField string
+	}{
+		{name: "mixed endian", sample: NestedStruct{}, wantField: "B.B"},
+		{name: "odd width", sample: OddWidthSliceStruct{}, wantField: "N"},
+		{name: "varint", sample: struct {
+			A uint8
+			V uint32 `encoding:"varint"`
+		}{}, wantField: "V"},
+		{name: "bitfield", sample: struct {
+			Flags uint16 `bitwidth:"12"`
+		}{}, wantField: "Flags"},
+		{name: "slice", sample: UnsizedSliceStruct{}, wantField: "Data"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ExportPyStructFormat(tt.sample)
+			if !errors.Is(err, ErrLayout) {
+				t.Fatalf("ExportPyStructFormat() error = %v, wanted %v", err, ErrLayout)
+			}
+			if !strings.Contains(err.Error(), " "+tt.wantField+" ") {
+				t.Errorf("ExportPyStructFormat() error = %v, wanted it to name %s", err, tt.wantField)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package mixedEndian
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// rawField is a []byte field tagged raw, capturing the bytes field from was read from, or those of
+// the whole struct when from is -1
+type rawField struct {
+	field, from int
+}
+
+// isRaw reports whether sf captures raw bytes, so takes none of its own on the wire
+func isRaw(sf reflect.StructField) bool {
+	return sf.Tag.Get("raw") != ""
+}
+
+// rawFieldsOf finds the fields of t tagged raw
+func rawFieldsOf(t reflect.Type) (raws []rawField, err error) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		s := sf.Tag.Get("raw")
+		if s == "" {
+			continue
+		}
+
+		if sf.Type.Kind() != reflect.Slice || sf.Type.Elem().Kind() != reflect.Uint8 {
+			return nil, fmt.Errorf("%s: %w raw needs a []byte; Got %s", sf.Name, ErrUnexpectedType, sf.Type.String())
+		}
+		if s == "struct" {
+			raws = append(raws, rawField{field: i, from: -1})
+			continue
+		}
+		from, ok := t.FieldByName(s)
+		if !ok || len(from.Index) != 1 || from.Index[0] == i || isRaw(from) {
+			return nil, fmt.Errorf("%s: %w raw %q is not a field", sf.Name, ErrTag, s)
+		}
+		raws = append(raws, rawField{field: i, from: from.Index[0]})
+	}
+	return
+}
+
+// capture sets the raw fields of struct v from bs, the bytes v was read from, its fields at spans
+func (r *reader) capture(v reflect.Value, raws []rawField, bs []byte, spans [][2]int64) {
+	for _, raw := range raws {
+		src := bs
+		if raw.from >= 0 {
+			src = bs[spans[raw.from][0]:spans[raw.from][1]]
+		}
+		if f := v.Field(raw.field); f.CanSet() {
+			dst := r.makeSlice(f.Type(), len(src))
+			copy(dst.Bytes(), src)
+			f.Set(dst)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type OpaqueRecord struct {
+	Kind    uint8
+	Len     uint8
+	Body    []byte `len:"Len"`
+	Value   uint32 `endian:"little"`
+	RawBody []byte `raw:"Body"`
+	RawVal  []byte `raw:"Value"`
+	Whole   []byte `raw:"struct"`
+}
+
+func TestRawCapture(t *testing.T) {
+	wire := []byte{0x07, 0x03, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x04}
+	var data any = &OpaqueRecord{}
+	if err := Read(bytes.NewReader(wire), BigEndian, &data); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := &OpaqueRecord{
+		Kind:    7,
+		Len:     3,
+		Body:    []byte{0xAA, 0xBB, 0xCC},
+		Value:   0x04030201,
+		RawBody: []byte{0xAA, 0xBB, 0xCC},
+		RawVal:  []byte{0x01, 0x02, 0x03, 0x04},
+		Whole:   wire,
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("Read() = %+v, wanted %+v", data, want)
+	}
+
+	// Captures are copies, and aren't written
+	wire[2] = 0
+	if data.(*OpaqueRecord).Whole[2] != 0xAA {
+		t.Errorf("Read() captured bytes share the input")
+	}
+	got, err := Marshal(BigEndian, data)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if w := []byte{0x07, 0x03, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x04}; !bytes.Equal(got, w) {
+		t.Errorf("Marshal() = % X, wanted % X", got, w)
+	}
+}
+
+func TestRawCaptureErrors(t *testing.T) {
+	type notBytes struct {
+		A uint8
+		R uint16 `raw:"A"`
+	}
+	type unknown struct {
+		A uint8
+		R []byte `raw:"B"`
+	}
+	type ofRaw struct {
+		A  uint8
+		R  []byte `raw:"A"`
+		RR []byte `raw:"R"`
+	}
+	tests := []struct {
+		name    string
+		data    any
+		wantErr error
+	}{
+		{"not bytes", &notBytes{}, ErrUnexpectedType},
+		{"unknown field", &unknown{}, ErrTag},
+		{"raw of raw", &ofRaw{}, ErrTag},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Read(bytes.NewReader([]byte{1}), BigEndian, &tt.data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Read() error = %v, wanted %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package mixedEndian
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// TypeRegistry maps type names, as used by schemas, to Go types.
+// It is safe for concurrent use.
+type TypeRegistry struct {
+	mu    sync.RWMutex
+	types map[string]reflect.Type
+}
+
+// DefaultRegistry holds the built in types, and any registered with it
+var DefaultRegistry = NewTypeRegistry()
+
+// NewTypeRegistry returns a registry holding the built in types: bool, string, uint8 through
+// uint64, int8 through int64, float32, float64, and the odd width integers uint24, int24,
+// uint48, and int48, and the 128 bit uint128 and int128. byte is an alias of uint8.
+func NewTypeRegistry() *TypeRegistry {
+	tr := &TypeRegistry{types: map[string]reflect.Type{}}
+	for _, v := range []any{
+		false, "",
+		uint8(0), uint16(0), uint32(0), uint64(0),
+		int8(0), int16(0), int32(0), int64(0),
+		float32(0), float64(0),
+	} {
+		tr.types[reflect.TypeOf(v).Name()] = reflect.TypeOf(v)
+	}
+	tr.types["byte"] = reflect.TypeOf(byte(0))
+	tr.types["uint24"] = uint24Type
+	tr.types["int24"] = int24Type
+	tr.types["uint48"] = uint48Type
+	tr.types["int48"] = int48Type
+	tr.types["uint128"] = uint128Type
+	tr.types["int128"] = int128Type
+	return tr
+}
+
+// arrayMax caps the bytes, and elements, of the arrays a schema can describe, so corrupt or
+// hostile lengths error rather than exhausting memory
+const arrayMax = 1 << 28
+
+// onRegister, if set, is called with each type as it's registered
+var onRegister func(name string, t reflect.Type)
+
+// Register makes t available as name, replacing any type previously registered as it.
+// In debug builds, structs requiring alignment are checked here, panicking if they fail.
+func (tr *TypeRegistry) Register(name string, t reflect.Type) {
+	if onRegister != nil {
+		onRegister(name, t)
+	}
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.types[name] = t
+}
+
+// Lookup finds the type registered as name
+func (tr *TypeRegistry) Lookup(name string) (t reflect.Type, ok bool) {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+	t, ok = tr.types[name]
+	return
+}
+
+// resolve finds the type named by ref, which may also be a slice "[]T" or array "[N]T"
+// of a registered type, or of a slice or array of one
+func (tr *TypeRegistry) resolve(ref string) (reflect.Type, error) {
+	if !strings.HasPrefix(ref, "[") {
+		if t, ok := tr.Lookup(ref); ok {
+			return t, nil
+		}
+		return nil, fmt.Errorf("%w No type registered as %q", ErrSchema, ref)
+	}
+
+	n, elem, ok := strings.Cut(ref[1:], "]")
+	if !ok {
+		return nil, fmt.Errorf("%w %q is not a type", ErrSchema, ref)
+	}
+	t, err := tr.resolve(elem)
+	if err != nil {
+		return nil, err
+	}
+	if n == "" {
+		return reflect.SliceOf(t), nil
+	}
+	length, err := strconv.Atoi(n)
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("%w %q has a bad array length", ErrSchema, ref)
+	}
+	if length > arrayMax || (t.Size() > 0 && uintptr(length) > arrayMax/t.Size()) {
+		return nil, fmt.Errorf("%w %q is over the %d byte array limit", ErrSchema, ref, arrayMax)
+	}
+	return reflect.ArrayOf(length, t), nil
+}
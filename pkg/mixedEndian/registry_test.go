@@ -0,0 +1,84 @@
+package mixedEndian
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestTypeRegistry(t *testing.T) {
+	tr := NewTypeRegistry()
+	for name, want := range map[string]reflect.Type{
+		"uint8":   reflect.TypeOf(uint8(0)),
+		"int64":   reflect.TypeOf(int64(0)),
+		"float32": reflect.TypeOf(float32(0)),
+		"bool":    reflect.TypeOf(false),
+		"string":  reflect.TypeOf(""),
+		"uint24":  uint24Type,
+	} {
+		if got, ok := tr.Lookup(name); !ok || got != want {
+			t.Errorf("Lookup(%s) = %v, %v, wanted %v", name, got, ok, want)
+		}
+	}
+	if _, ok := tr.Lookup("TaggedStruct"); ok {
+		t.Errorf("Lookup(TaggedStruct) found an unregistered type")
+	}
+
+	// Registration is safe alongside lookups
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			tr.Register(fmt.Sprintf("T%d", i), reflect.TypeOf(TaggedStruct{}))
+		}(i)
+		go func() {
+			defer wg.Done()
+			tr.Lookup("uint16")
+		}()
+	}
+	wg.Wait()
+	if got, ok := tr.Lookup("T7"); !ok || got != reflect.TypeOf(TaggedStruct{}) {
+		t.Errorf("Lookup(T7) = %v, %v", got, ok)
+	}
+
+	// Registries are independent
+	if _, ok := DefaultRegistry.Lookup("T7"); ok {
+		t.Errorf("Register() leaked into DefaultRegistry")
+	}
+}
+
+func TestTypeRegistryResolve(t *testing.T) {
+	tests := []struct {
+		ref     string
+		want    reflect.Type
+		wantErr error
+	}{
+		{ref: "uint16", want: reflect.TypeOf(uint16(0))},
+		{ref: "[]byte", want: reflect.TypeOf([]byte{})},
+		{ref: "[4]int32", want: reflect.TypeOf([4]int32{})},
+		{ref: "[][2]uint8", want: reflect.TypeOf([][2]uint8{})},
+		{ref: "uint128", want: uint128Type},
+		{ref: "uint256", wantErr: ErrSchema},
+		{ref: "[x]uint8", wantErr: ErrSchema},
+		{ref: "[4uint8", wantErr: ErrSchema},
+		{ref: "[9223372036854775807]uint64", wantErr: ErrSchema},
+		{ref: "[268435456][2]uint8", wantErr: ErrSchema},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := DefaultRegistry.resolve(tt.ref)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("resolve() error = %v, wanted %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("resolve() = %v, %v, wanted %v", got, err, tt.want)
+			}
+		})
+	}
+}
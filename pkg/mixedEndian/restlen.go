@@ -0,0 +1,74 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// checkRestLen errors should field i of struct t be tagged restlen but not be a slice ending
+// one of the length scopes of so
+func checkRestLen(t reflect.Type, so structOptions, i int) error {
+	sf := t.Field(i)
+	switch s := sf.Tag.Get("restlen"); {
+	case s == "":
+		return nil
+	case s != "true":
+		return fmt.Errorf("%w restlen %q is not true", ErrTag, s)
+	case sf.Type.Kind() != reflect.Slice:
+		return fmt.Errorf("%w restlen needs a slice; Got %s", ErrUnexpectedType, sf.Type.String())
+	case sf.Tag.Get("len") != "" || sf.Tag.Get("countfrom") != "" || sf.Tag.Get("count") != "":
+		return fmt.Errorf("%w restlen slices can't have a len, countfrom, or count tag too", ErrTag)
+	}
+	for _, sc := range so.scopes {
+		if sc.to == i {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w restlen needs the field to end a lengthscope", ErrTag)
+}
+
+// readRest reads slice f from whatever is left of the length scope it ends, as many elements as
+// that holds. A partial element left at the end fails with ErrLength, as the scope bounds it, and
+// so do elements read from no bytes, which could never fill it.
+func (r *reader) readRest(f reflect.Value, o binary.ByteOrder) error {
+	if r.rest == nil {
+		return fmt.Errorf("%w restlen needs the field to end a lengthscope", ErrTag)
+	}
+
+	// Bytes are read as they arrive, so a corrupt length can't force a huge allocation
+	if f.Type().Elem().Kind() == reflect.Uint8 {
+		buf := &bytes.Buffer{}
+		if _, err := io.CopyN(buf, r.r, r.rest.N); err == io.EOF {
+			return io.ErrUnexpectedEOF
+		} else if err != nil {
+			return err
+		}
+		out := r.makeSlice(f.Type(), buf.Len())
+		reflect.Copy(out, reflect.ValueOf(buf.Bytes()))
+		f.Set(out)
+		return nil
+	}
+
+	elems := reflect.MakeSlice(f.Type(), 0, 0)
+	for r.rest.N > 0 {
+		elem, left := reflect.New(f.Type().Elem()).Elem(), r.rest.N
+		if err := r.readOrdered(elem, o); err != nil {
+			return fmt.Errorf("element %d: %w", elems.Len(), err)
+		} else if r.rest.N == left {
+			return fmt.Errorf("%w Element %d of %s took no bytes, so can't fill the %d left", ErrLength, elems.Len(), f.Type().String(), left)
+		}
+		elems = reflect.Append(elems, elem)
+	}
+
+	// Elements are only counted as they're read, so the allocator gets a copy
+	if r.alloc != nil {
+		out := r.makeSlice(f.Type(), elems.Len())
+		reflect.Copy(out, elems)
+		elems = out
+	}
+	f.Set(elems)
+	return nil
+}
@@ -0,0 +1,116 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type PayloadRecord struct {
+	Type    uint8
+	Length  uint16 `lengthscope:"Flags..Payload"`
+	Flags   uint8
+	Seq     uint16
+	Payload []byte `restlen:"true"`
+	Trailer uint8
+}
+
+type SampleRecord struct {
+	Length  uint8 `lengthscope:"Rate..Samples"`
+	Rate    uint16
+	Samples []int16 `restlen:"true"`
+}
+
+func TestRestLen(t *testing.T) {
+	want := &PayloadRecord{Type: 7, Length: 7, Flags: 1, Seq: 0x0203, Payload: []byte{0xAA, 0xBB, 0xCC, 0xDD}, Trailer: 9}
+	wire := []byte{0x07, 0x00, 0x07, 0x01, 0x02, 0x03, 0xAA, 0xBB, 0xCC, 0xDD, 0x09}
+
+	unsized := *want
+	unsized.Length = 0
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, unsized); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+
+	var data any = &PayloadRecord{}
+	if err := Read(bytes.NewReader(wire), BigEndian, &data); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("Read() data = %v, wanted %v", data, want)
+	}
+
+	// The payload is the record less its header, so may be empty
+	empty := []byte{0x07, 0x00, 0x03, 0x01, 0x02, 0x03, 0x09}
+	data = &PayloadRecord{}
+	if err := Read(bytes.NewReader(empty), BigEndian, &data); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if got := data.(*PayloadRecord); len(got.Payload) != 0 || got.Trailer != 9 {
+		t.Errorf("Read() data = %v, wanted an empty payload", got)
+	}
+
+	// Wider elements fill what's left as many times as it holds
+	samples := []byte{0x06, 0x1F, 0x40, 0x00, 0x01, 0xFF, 0xFF}
+	data = &SampleRecord{}
+	if err := Read(bytes.NewReader(samples), BigEndian, &data); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if got := data.(*SampleRecord); !reflect.DeepEqual(got.Samples, []int16{1, -1}) {
+		t.Errorf("Read() samples = %v, wanted [1 -1]", got.Samples)
+	}
+}
+
+func TestRestLenErrors(t *testing.T) {
+	// A header longer than the record
+	var data any = &PayloadRecord{}
+	if err := Read(bytes.NewReader([]byte{0x07, 0x00, 0x02, 0x01, 0x02, 0x03, 0x09}), BigEndian, &data); !errors.Is(err, ErrLength) {
+		t.Errorf("Read() error = %v, wanted %v", err, ErrLength)
+	}
+
+	// What's left isn't a whole number of elements
+	data = &SampleRecord{}
+	if err := Read(bytes.NewReader([]byte{0x05, 0x1F, 0x40, 0x00, 0x01, 0xFF}), BigEndian, &data); !errors.Is(err, ErrLength) {
+		t.Errorf("Read() error = %v, wanted %v", err, ErrLength)
+	}
+
+	// A record cut short
+	data = &PayloadRecord{}
+	if err := Read(bytes.NewReader([]byte{0x07, 0x00, 0x07, 0x01, 0x02, 0x03, 0xAA}), BigEndian, &data); err == nil {
+		t.Error("Read() error = nil, wanted an error")
+	}
+
+	// Elements taking no bytes would never fill what's left
+	type empty struct {
+		Length uint8      `lengthscope:"Rest"`
+		Rest   []struct{} `restlen:"true"`
+	}
+	data = &empty{}
+	if err := Read(bytes.NewReader([]byte{0x02, 0x00, 0x00}), BigEndian, &data); !errors.Is(err, ErrLength) {
+		t.Errorf("Read() error = %v, wanted %v", err, ErrLength)
+	}
+
+	type unscoped struct {
+		Flags   uint8
+		Payload []byte `restlen:"true"`
+	}
+	type inner struct {
+		Length  uint8  `lengthscope:"Payload..Flags"`
+		Payload []byte `restlen:"true"`
+		Flags   uint8
+	}
+	type notSlice struct {
+		Length uint8  `lengthscope:"Rest"`
+		Rest   uint32 `restlen:"true"`
+	}
+	for name, v := range map[string]any{"unscoped": &unscoped{}, "inner": &inner{}, "not a slice": &notSlice{}} {
+		data = v
+		if err := Read(bytes.NewReader(make([]byte, 8)), BigEndian, &data); !errors.Is(err, ErrTag) && !errors.Is(err, ErrUnexpectedType) {
+			t.Errorf("%s: Read() error = %v, wanted %v", name, err, ErrTag)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package mixedEndian
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// WriteError is returned by Write and an Encoder when the io.Writer fails partway through a
+// value. Written is how many bytes of the value reached it, and Path the dotted path of the field
+// being written when it failed, such as "Header.Items[2]", or "" if all of them had.
+//
+// Path is found by encoding the value again. Map entries are only written in the same order twice
+// by WithCanonical Encoders, so for others Path stops at the map, rather than naming a field of
+// an entry that may not be the one that failed. Likewise, should the other end acknowledge
+// Written bytes, an Encoder can send the rest of the same value with ResumeFrom, so long as it's
+// canonical or the value holds no map of more than one entry.
+type WriteError struct {
+	Written int64
+	Path    string
+	Err     error
+}
+
+func (e *WriteError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("after %d bytes: %v", e.Written, e.Err)
+	}
+	return fmt.Sprintf("after %d bytes, in %s: %v", e.Written, e.Path, e.Err)
+}
+
+func (e *WriteError) Unwrap() error {
+	return e.Err
+}
+
+// ResumeFrom makes the Encoder leave out the first n bytes of the next value it encodes, which
+// an earlier attempt, failing with a WriteError, got across. The value must be the same. Maps
+// within it need WithCanonical to be encoded the same way twice, so without it a value holding a
+// map of more than one entry fails with ErrCanonical rather than resuming partway through a
+// different order. Call it after any Reset, which forgets it.
+func (e *Encoder) ResumeFrom(n int64) {
+	e.skip = n
+}
+
+// unordered reports whether v holds a map of more than one entry, which non-canonical writers
+// write in no fixed order
+func unordered(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return !v.IsNil() && unordered(v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() && unordered(v.Field(i)) {
+				return true
+			}
+		}
+	case reflect.Array, reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if unordered(v.Index(i)) {
+				return true
+			}
+		}
+	case reflect.Map:
+		if v.Len() > 1 {
+			return true
+		}
+		for it := v.MapRange(); it.Next(); {
+			if unordered(it.Key()) || unordered(it.Value()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// writeProgress follows the path of the field being written, to find the one written at an offset
+type writeProgress struct {
+	path []string
+
+	// at is the path once found
+	at string
+}
+
+// push notes field name is being written, and pushIndex element i of a list, until pop.
+// All do nothing on a nil writeProgress.
+func (p *writeProgress) push(name string) {
+	if p != nil {
+		p.path = append(p.path, name)
+	}
+}
+
+func (p *writeProgress) pushIndex(i int) {
+	if p != nil {
+		p.path = append(p.path, fmt.Sprintf("[%d]", i))
+	}
+}
+
+func (p *writeProgress) pop() {
+	if p != nil {
+		p.path = p.path[:len(p.path)-1]
+	}
+}
+
+// errWritten stops pathAt's writes once it reaches the offset it's looking for
+var errWritten = errors.New("written")
+
+// stopWriter takes n bytes, noting p's path when a write would pass them
+type stopWriter struct {
+	n int64
+	p *writeProgress
+}
+
+func (s *stopWriter) Write(bs []byte) (int, error) {
+	if int64(len(bs)) > s.n {
+		s.p.at = strings.ReplaceAll(strings.Join(s.p.path, "."), ".[", "[")
+		return 0, errWritten
+	}
+	s.n -= int64(len(bs))
+	return len(bs), nil
+}
+
+// pathAt is the path of the field of v, in byte order o, being written once n bytes have been.
+// v is written again to find it, which encodes as it did the first time.
+func (w *writer) pathAt(v reflect.Value, o binary.ByteOrder, n int64) string {
+	p := &writeProgress{}
+	sub := *w
+	sub.w, sub.progress = &stopWriter{n: n, p: p}, p
+	_ = sub.writeOrdered(v, o)
+	return p.at
+}
@@ -0,0 +1,162 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+var errFlaky = errors.New("connection reset")
+
+// flakyWriter takes n bytes, then fails
+type flakyWriter struct {
+	bytes.Buffer
+	n int
+}
+
+func (f *flakyWriter) Write(bs []byte) (int, error) {
+	if len(bs) > f.n {
+		f.Buffer.Write(bs[:f.n])
+		n := f.n
+		f.n = 0
+		return n, errFlaky
+	}
+	f.n -= len(bs)
+	return f.Buffer.Write(bs)
+}
+
+type ResumeItem struct {
+	A uint8
+	B uint32
+}
+
+type ResumeStruct struct {
+	Count uint8
+	Items []ResumeItem `len:"Count"`
+	Tail  uint16
+	Table map[uint8]uint16 `count:"3"`
+}
+
+var resumeValue = ResumeStruct{
+	Count: 3,
+	Items: []ResumeItem{{1, 0x01020304}, {2, 0x05060708}, {3, 0x090A0B0C}},
+	Tail:  0xBEEF,
+	Table: map[uint8]uint16{1: 10, 2: 20, 3: 30},
+}
+
+func TestDeterministicEncoding(t *testing.T) {
+	first := &bytes.Buffer{}
+	enc := NewEncoder(first, BigEndian, WithCanonical(true))
+	if err := enc.Encode(resumeValue); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		buf := &bytes.Buffer{}
+		enc.Reset(buf)
+		if err := enc.Encode(resumeValue); err != nil {
+			t.Fatalf("Encode() error = %v", err)
+		}
+		if !bytes.Equal(buf.Bytes(), first.Bytes()) {
+			t.Fatalf("Encode() = % X, then % X, wanted the same", first.Bytes(), buf.Bytes())
+		}
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	// Items are 5 bytes each, so byte 8 is in the second one's B
+	w := &flakyWriter{n: 8}
+	err := Write(w, BigEndian, resumeValue.Items)
+	var we *WriteError
+	if !errors.As(err, &we) || !errors.Is(err, errFlaky) {
+		t.Fatalf("Write() error = %v, wanted a WriteError wrapping %v", err, errFlaky)
+	}
+	if we.Written != 8 || we.Path != "[1].B" {
+		t.Errorf("Write() error Written = %d, Path = %q, wanted 8, \"[1].B\"", we.Written, we.Path)
+	}
+
+	w = &flakyWriter{n: 8}
+	err = Write(w, BigEndian, resumeValue)
+	if !errors.As(err, &we) || we.Written != 8 || we.Path != "Items[1].B" {
+		t.Errorf("Write() error = %v, wanted a WriteError after 8 bytes in Items[1].B", err)
+	}
+
+	// Entries of non-canonical maps aren't written in a fixed order, so the path stops at the map
+	entries := struct {
+		A uint8
+		M map[uint8]ResumeItem `count:"3"`
+	}{M: map[uint8]ResumeItem{1: {}, 2: {}, 3: {}}}
+	err = Write(&flakyWriter{n: 4}, BigEndian, entries)
+	if !errors.As(err, &we) || we.Path != "M" {
+		t.Errorf("Write() error = %v, wanted a WriteError in M", err)
+	}
+
+	// Encoding errors aren't the io.Writer's, so aren't WriteErrors
+	err = Write(&flakyWriter{n: 100}, BigEndian, struct{ S string }{})
+	if err == nil || errors.As(err, &we) {
+		t.Errorf("Write() error = %v, wanted a tag error", err)
+	}
+}
+
+func TestEncoderResumeFrom(t *testing.T) {
+	// Map entries are only written in a repeatable order by canonical Encoders
+	buf := &bytes.Buffer{}
+	if err := NewEncoder(buf, BigEndian, WithCanonical(true)).Encode(resumeValue); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	want := buf.Bytes()
+
+	for n := 0; n < len(want); n++ {
+		first := &flakyWriter{n: n}
+		enc := NewEncoder(first, BigEndian, WithCanonical(true))
+		err := enc.Encode(resumeValue)
+		var we *WriteError
+		if !errors.As(err, &we) || we.Written != int64(n) {
+			t.Fatalf("Encode() error = %v, wanted a WriteError after %d bytes", err, n)
+		}
+
+		rest := &bytes.Buffer{}
+		enc.Reset(rest)
+		enc.ResumeFrom(we.Written)
+		if err = enc.Encode(resumeValue); err != nil {
+			t.Fatalf("Encode() error = %v", err)
+		}
+		if got := append(first.Bytes(), rest.Bytes()...); !bytes.Equal(got, want) {
+			t.Errorf("Encode() after %d bytes = % X, wanted % X", n, got, want)
+		}
+
+		// Resuming only applies once
+		rest.Reset()
+		if err = enc.Encode(resumeValue); err != nil || !bytes.Equal(rest.Bytes(), want) {
+			t.Errorf("Encode() = % X, %v, wanted all of % X", rest.Bytes(), err, want)
+		}
+	}
+
+	enc := NewEncoder(&bytes.Buffer{}, BigEndian)
+	enc.ResumeFrom(100)
+	if err := enc.Encode(resumeValue.Items[0]); !errors.Is(err, ErrLength) {
+		t.Errorf("Encode() error = %v, wanted %v", err, ErrLength)
+	}
+
+	// Without WithCanonical, maps of several entries can't be resumed, but a lone entry can
+	enc.ResumeFrom(1)
+	if err := enc.Encode(resumeValue); !errors.Is(err, ErrCanonical) {
+		t.Errorf("Encode() error = %v, wanted %v", err, ErrCanonical)
+	}
+	single := struct {
+		A uint8
+		M map[uint8]uint16 `count:"1"`
+	}{M: map[uint8]uint16{1: 10}}
+	enc.ResumeFrom(1)
+	if err := enc.Encode(single); err != nil {
+		t.Errorf("Encode() error = %v", err)
+	}
+
+	// Reset forgets a ResumeFrom, so pooled Encoders don't carry it over to another caller
+	type pair struct{ A, B uint16 }
+	out := &bytes.Buffer{}
+	enc.ResumeFrom(3)
+	enc.Reset(out)
+	if err := enc.Encode(&pair{0x0102, 0x0304}); err != nil || !bytes.Equal(out.Bytes(), []byte{1, 2, 3, 4}) {
+		t.Errorf("Encode() after Reset = % X, %v, wanted 01 02 03 04", out.Bytes(), err)
+	}
+}
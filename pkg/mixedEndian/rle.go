@@ -0,0 +1,190 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// rleMax is the default cap on the elements a run length encoded slice sized by bytes expands to
+const rleMax = 1 << 20
+
+// rleOptions are parsed from an `rle:"count=uint8,bytes=Field,max=N"` tag
+type rleOptions struct {
+	count reflect.Type
+	bytes string
+	max   int
+}
+
+// parseRLE reads the options of an rle tag. `rle:"true"` is short for `rle:"count=uint16"`.
+func parseRLE(tag string) (opts rleOptions, err error) {
+	opts.max = rleMax
+	if tag == "true" {
+		opts.count = reflect.TypeOf(uint16(0))
+		return
+	}
+	for _, p := range strings.Split(tag, ",") {
+		key, val, _ := strings.Cut(p, "=")
+		switch key {
+		case "count":
+			if opts.count, err = tagUintType(val); err != nil {
+				return
+			}
+		case "bytes":
+			opts.bytes = val
+		case "max":
+			if opts.max, err = strconv.Atoi(val); err != nil || opts.max < 0 {
+				return opts, fmt.Errorf("%w rle max %q is not a length", ErrTag, val)
+			}
+		default:
+			return opts, fmt.Errorf("%w Unknown rle option %q", ErrTag, key)
+		}
+	}
+	if opts.count == nil {
+		return opts, fmt.Errorf("%w rle needs a count option giving the run length type", ErrTag)
+	}
+	return
+}
+
+// readRLE reads slice or array f of struct v as run length and element pairs.
+// Runs are read until the slice's length, given by its len, countfrom, or count tag,
+// or the array's, is filled. Without one, they're read until the number of bytes held by the
+// field named in the bytes option have been consumed. Either way slices expand to no more than
+// the max option's elements.
+func (r *reader) readRLE(v reflect.Value, sf reflect.StructField, f reflect.Value, o binary.ByteOrder) error {
+	opts, err := parseRLE(sf.Tag.Get("rle"))
+	if err != nil {
+		return err
+	}
+	n, sized, err := rleLen(v, sf, f)
+	if err != nil {
+		return err
+	}
+
+	src := *r
+	var budget *io.LimitedReader
+	if !sized {
+		if opts.bytes == "" {
+			return fmt.Errorf("%w rle slices need a len, countfrom, or count tag, or a bytes option", ErrTag)
+		}
+		size, err := lengthOf(v, opts.bytes)
+		if err != nil {
+			return err
+		}
+		budget = &io.LimitedReader{R: r.r, N: int64(size)}
+		src.r = budget
+		n = opts.max
+	} else if f.Kind() == reflect.Slice && n > opts.max {
+		// Lengths come off the wire, so are checked before anything's allocated for them
+		return fmt.Errorf("%w rle length %d is over its max of %d", ErrLength, n, opts.max)
+	}
+
+	// Sized slices are allocated once, and arrays filled in place
+	elems := f
+	if f.Kind() == reflect.Slice {
+		if sized {
+			elems = r.makeSlice(f.Type(), n)
+		} else {
+			elems = reflect.MakeSlice(f.Type(), 0, 0)
+		}
+	}
+	filled := 0
+	for (sized && filled < n) || (!sized && budget.N > 0) {
+		run, err := src.readUint(opts.count, o)
+		if err != nil {
+			return err
+		}
+		if run == 0 || run > uint64(n-filled) {
+			return fmt.Errorf("%w run of %d after %d elements overflows %d", ErrLength, run, filled, n)
+		}
+
+		elem := reflect.New(f.Type().Elem()).Elem()
+		if err = src.readOrdered(elem, o); err != nil {
+			return err
+		}
+		for ; run > 0; run-- {
+			if sized {
+				elems.Index(filled).Set(elem)
+			} else {
+				elems = reflect.Append(elems, elem)
+			}
+			filled++
+		}
+	}
+	if f.Kind() == reflect.Array {
+		return nil
+	}
+
+	// Runs sized by bytes are only counted as they're read, so the allocator gets a copy
+	if !sized && r.alloc != nil {
+		out := r.makeSlice(f.Type(), elems.Len())
+		reflect.Copy(out, elems)
+		elems = out
+	}
+	f.Set(elems)
+	return nil
+}
+
+// writeRLE writes slice or array f of struct v as run length and element pairs, splitting runs
+// too long for the count type. Slices sized by a bytes option must encode to that many bytes.
+func (w *writer) writeRLE(v reflect.Value, sf reflect.StructField, f reflect.Value, o binary.ByteOrder) error {
+	opts, err := parseRLE(sf.Tag.Get("rle"))
+	if err != nil {
+		return err
+	}
+	_, sized, err := rleLen(v, sf, f)
+	if err != nil {
+		return err
+	}
+
+	dst := *w
+	buf := &bytes.Buffer{}
+	if !sized {
+		dst.w = buf
+	}
+
+	maxRun := uint64(1)<<(typeSize(opts.count)*8) - 1
+	for i := 0; i < f.Len(); {
+		run := uint64(1)
+		for i+int(run) < f.Len() && run < maxRun && reflect.DeepEqual(f.Index(i).Interface(), f.Index(i+int(run)).Interface()) {
+			run++
+		}
+		if err = dst.writeUint(opts.count, run, o); err != nil {
+			return err
+		}
+		if err = dst.writeOrdered(f.Index(i), o); err != nil {
+			return err
+		}
+		i += int(run)
+	}
+
+	if sized {
+		return nil
+	}
+	size, err := lengthOf(v, opts.bytes)
+	if err != nil {
+		return err
+	}
+	if buf.Len() != size {
+		return fmt.Errorf("%w Encoded to %d bytes, but %s is %d", ErrLength, buf.Len(), opts.bytes, size)
+	}
+	_, err = w.w.Write(buf.Bytes())
+	return err
+}
+
+// rleLen is the number of elements run length encoded field f of struct v holds, if known
+// before it's read: an array's length, or a slice's from its tags
+func rleLen(v reflect.Value, sf reflect.StructField, f reflect.Value) (int, bool, error) {
+	switch f.Kind() {
+	case reflect.Array:
+		return f.Len(), true, nil
+	case reflect.Slice:
+		return sliceLen(v, sf)
+	default:
+		return 0, false, fmt.Errorf("%w rle requires a slice or array; Got %s", ErrUnexpectedType, f.Type().String())
+	}
+}
@@ -0,0 +1,168 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type RLEPixel struct {
+	R, G, B uint8
+}
+
+type RLEStruct struct {
+	N       uint16
+	Indices []uint8 `len:"N" rle:"count=uint8"`
+	Size    uint8
+	Pixels  []RLEPixel `rle:"count=uint8,bytes=Size"`
+}
+
+func TestRLERoundTrip(t *testing.T) {
+	data := RLEStruct{
+		N:       259,
+		Indices: make([]uint8, 259),
+		Size:    12,
+		Pixels:  []RLEPixel{{1, 2, 3}, {4, 5, 6}, {4, 5, 6}, {7, 8, 9}},
+	}
+	// A run split where it outgrows the count type, then shorter runs
+	data.Indices[256], data.Indices[257], data.Indices[258] = 1, 1, 2
+	wire := []byte{
+		0x01, 0x03,
+		0xFF, 0x00, 0x01, 0x00, 0x02, 0x01, 0x01, 0x02,
+		12,
+		0x01, 1, 2, 3, 0x02, 4, 5, 6, 0x01, 7, 8, 9,
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, data); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+
+	var got any = &RLEStruct{}
+	if err := Read(bytes.NewReader(wire), BigEndian, &got); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, &data) {
+		t.Errorf("Read() = %v, wanted %v", got, data)
+	}
+}
+
+type RLEMaskStruct struct {
+	N    uint8
+	Mask []bool    `len:"N" rle:"true" endian:"little"`
+	Grid [10]uint8 `rle:"true"`
+}
+
+func TestRLEBools(t *testing.T) {
+	data := RLEMaskStruct{
+		N:    9,
+		Mask: []bool{true, true, true, false, false, true, false, false, false},
+		Grid: [10]uint8{0, 0, 0, 0, 0, 0, 0, 7, 7, 0},
+	}
+	wire := []byte{
+		9,
+		0x03, 0x00, 0x01, 0x02, 0x00, 0x00, 0x01, 0x00, 0x01, 0x03, 0x00, 0x00,
+		0x00, 0x07, 0x00, 0x00, 0x02, 0x07, 0x00, 0x01, 0x00,
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, data); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+
+	var got any = &RLEMaskStruct{}
+	if err := Read(bytes.NewReader(wire), BigEndian, &got); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, &data) {
+		t.Errorf("Read() = %v, wanted %v", got, data)
+	}
+
+	// Runs past the end of an array are refused
+	wire[len(wire)-3] = 0x02
+	if err := Read(bytes.NewReader(wire), BigEndian, &got); !errors.Is(err, ErrLength) {
+		t.Errorf("Read() error = %v, wanted %v", err, ErrLength)
+	}
+}
+
+func TestRLEReadErrors(t *testing.T) {
+	type capped struct {
+		Size   uint8
+		Values []uint16 `rle:"count=uint16,bytes=Size,max=1000"`
+	}
+
+	tests := []struct {
+		name    string
+		data    any
+		wire    []byte
+		wantErr error
+	}{
+		{
+			name:    "run past count",
+			data:    &RLEStruct{},
+			wire:    []byte{0x00, 0x02, 0x03, 0x07},
+			wantErr: ErrLength,
+		},
+		{
+			name:    "zero run",
+			data:    &RLEStruct{},
+			wire:    []byte{0x00, 0x02, 0x00, 0x07},
+			wantErr: ErrLength,
+		},
+		{
+			// A corrupt count can't expand past the cap
+			name:    "expansion cap",
+			data:    &capped{},
+			wire:    []byte{4, 0xFF, 0xFF, 0x00, 0x01},
+			wantErr: ErrLength,
+		},
+		{
+			// Nor can a corrupt length, which is checked before anything's allocated for it
+			name: "length over max",
+			data: &struct {
+				N      uint32
+				Values []uint8 `len:"N" rle:"count=uint8"`
+			}{},
+			wire:    []byte{0x7F, 0xFF, 0xFF, 0xFF, 0xFF, 0x00},
+			wantErr: ErrLength,
+		},
+		{
+			name: "no size",
+			data: &struct {
+				Values []uint8 `rle:"count=uint8"`
+			}{},
+			wire:    []byte{0x01, 0x01},
+			wantErr: ErrTag,
+		},
+		{
+			name: "bad count type",
+			data: &struct {
+				Values []uint8 `count:"1" rle:"count=int8"`
+			}{},
+			wire:    []byte{0x01, 0x01},
+			wantErr: ErrTag,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data any = tt.data
+			if err := Read(bytes.NewReader(tt.wire), BigEndian, &data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Read() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRLEWriteSizeMismatch(t *testing.T) {
+	data := RLEStruct{Size: 8, Pixels: []RLEPixel{{1, 2, 3}}}
+	if err := Write(&bytes.Buffer{}, BigEndian, data); !errors.Is(err, ErrLength) {
+		t.Errorf("Write() error = %v, wantErr %v", err, ErrLength)
+	}
+}
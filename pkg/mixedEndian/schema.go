@@ -0,0 +1,95 @@
+package mixedEndian
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"reflect"
+)
+
+// Schema describes a struct defined at runtime, from JSON, rather than declared in Go.
+// Its Type is an ordinary struct type, so works with everything else in the package.
+type Schema struct {
+	Name   string
+	Fields []SchemaField
+
+	typ reflect.Type
+}
+
+// SchemaField is a single field of a Schema
+type SchemaField struct {
+	// Name of the field, which must be an exported Go identifier
+	Name string `json:"name"`
+
+	// Type names a type in the schema's TypeRegistry, or a slice "[]T" or array "[N]T" of one
+	Type string `json:"type"`
+
+	// Tag is the field's struct tag, as it would be written in Go, for example `endian:"big"`
+	Tag string `json:"tag,omitempty"`
+}
+
+// SchemaFromJSON builds a Schema from its JSON description:
+//
+//	{
+//		"name": "Header",
+//		"fields": [
+//			{"name": "Magic", "type": "[4]uint8", "tag": "const:\"0x7F,0x45,0x4C,0x46\""},
+//			{"name": "Count", "type": "uint16", "tag": "endian:\"little\""},
+//			{"name": "Items", "type": "[]Item", "tag": "len:\"Count\""}
+//		]
+//	}
+//
+// Field types are resolved with reg, or DefaultRegistry if reg is nil.
+// To use a schema's struct within another, register its Type.
+func SchemaFromJSON(data []byte, reg *TypeRegistry) (*Schema, error) {
+	var desc struct {
+		Name   string        `json:"name"`
+		Fields []SchemaField `json:"fields"`
+	}
+	if err := json.Unmarshal(data, &desc); err != nil {
+		return nil, fmt.Errorf("%w %v", ErrSchema, err)
+	}
+	return newSchema(desc.Name, desc.Fields, reg)
+}
+
+// newSchema builds the struct type of a Schema from its fields, resolved with reg
+func newSchema(name string, schemaFields []SchemaField, reg *TypeRegistry) (*Schema, error) {
+	if reg == nil {
+		reg = DefaultRegistry
+	}
+
+	s := &Schema{Name: name, Fields: schemaFields}
+	fields := make([]reflect.StructField, len(s.Fields))
+	for i, f := range s.Fields {
+		if !token.IsIdentifier(f.Name) || !token.IsExported(f.Name) {
+			return nil, fmt.Errorf("%w Field name %q is not an exported identifier", ErrSchema, f.Name)
+		}
+		t, err := reg.resolve(f.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		fields[i] = reflect.StructField{Name: f.Name, Type: t, Tag: reflect.StructTag(f.Tag)}
+	}
+
+	// reflect.StructOf panics on duplicate names, so catch them first
+	seen := map[string]bool{}
+	for _, f := range fields {
+		if seen[f.Name] {
+			return nil, fmt.Errorf("%w Duplicate field %s", ErrSchema, f.Name)
+		}
+		seen[f.Name] = true
+	}
+
+	s.typ = reflect.StructOf(fields)
+	return s, nil
+}
+
+// Type is the struct type described by s
+func (s *Schema) Type() reflect.Type {
+	return s.typ
+}
+
+// New returns a pointer to a new zero value of s's struct type, ready to Read into
+func (s *Schema) New() any {
+	return reflect.New(s.typ).Interface()
+}
@@ -0,0 +1,135 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSchemaFromJSON(t *testing.T) {
+	reg := NewTypeRegistry()
+	reg.Register("Tagged", reflect.TypeOf(TaggedStruct{}))
+
+	s, err := SchemaFromJSON([]byte(`{
+		"name": "Header",
+		"fields": [
+			{"name": "Magic", "type": "[2]uint8", "tag": "const:\"0x4D,0x45\""},
+			{"name": "Count", "type": "uint16", "tag": "endian:\"little\""},
+			{"name": "Items", "type": "[]Tagged", "tag": "len:\"Count\""}
+		]
+	}`), reg)
+	if err != nil {
+		t.Fatalf("SchemaFromJSON() error = %v", err)
+	}
+	if s.Name != "Header" || s.Type().NumField() != 3 {
+		t.Errorf("SchemaFromJSON() = %v with type %v", s, s.Type())
+	}
+
+	wire := []byte{'M', 'E', 0x02, 0x00, 0x01, 0x02, 0x04, 0x03, 0x05, 0x06, 0x08, 0x07}
+	var data any = s.New()
+	if err = Read(bytes.NewReader(wire), BigEndian, &data); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	items := reflect.ValueOf(data).Elem().FieldByName("Items").Interface()
+	want := []TaggedStruct{{A: 0x0102, B: 0x0304}, {A: 0x0506, B: 0x0708}}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("Read() Items = %v, wanted %v", items, want)
+	}
+
+	buf := &bytes.Buffer{}
+	if err = Write(buf, BigEndian, data); err != nil || !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, %v, wanted % X", buf.Bytes(), err, wire)
+	}
+}
+
+func TestSchemaFromJSONErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+	}{
+		{name: "malformed", json: `{"fields": [`},
+		{name: "unknown type", json: `{"fields": [{"name": "A", "type": "Tagged"}]}`},
+		{name: "unexported", json: `{"fields": [{"name": "a", "type": "uint8"}]}`},
+		{name: "not an identifier", json: `{"fields": [{"name": "A B", "type": "uint8"}]}`},
+		{name: "duplicate", json: `{"fields": [{"name": "A", "type": "uint8"}, {"name": "A", "type": "uint16"}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := SchemaFromJSON([]byte(tt.json), nil); !errors.Is(err, ErrSchema) {
+				t.Errorf("SchemaFromJSON() error = %v, wanted %v", err, ErrSchema)
+			}
+		})
+	}
+}
+
+func TestLoadSchema(t *testing.T) {
+	s, err := LoadSchema(strings.NewReader(`{
+		"name": "Record",
+		"fields": [
+			{"name": "Version", "kind": "uint", "width": 8},
+			{"name": "Count", "kind": "uint", "width": 16, "endian": "little"},
+			{"name": "Offsets", "kind": "int", "width": 24, "repeated": true, "tags": {"len": "Count"}},
+			{"name": "Label", "kind": "string", "size": 4, "tags": {"trim": "space"}},
+			{"name": "Flags", "kind": "bool", "count": 2},
+			{"name": "Tail", "type": "uint16", "tag": "endian:\"little\""}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("LoadSchema() error = %v", err)
+	}
+
+	wire := []byte{
+		0x01,
+		0x02, 0x00,
+		0xFF, 0xFF, 0xFE, 0x00, 0x00, 0x10,
+		'a', 'b', ' ', ' ',
+		0x01, 0x00,
+		0x34, 0x12,
+	}
+	got, err := DecodeSchema(bytes.NewReader(wire), BigEndian, s)
+	if err != nil {
+		t.Fatalf("DecodeSchema() error = %v", err)
+	}
+	want := map[string]any{
+		"Version": uint8(1),
+		"Count":   uint16(2),
+		"Offsets": []Int24{-2, 16},
+		"Label":   "ab",
+		"Flags":   [2]bool{true, false},
+		"Tail":    uint16(0x1234),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeSchema() = %v, wanted %v", got, want)
+	}
+}
+
+func TestLoadSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+	}{
+		{name: "malformed", json: `{"fields": [`},
+		{name: "no type", json: `{"fields": [{"name": "A"}]}`},
+		{name: "type and kind", json: `{"fields": [{"name": "A", "type": "uint8", "kind": "uint", "width": 8}]}`},
+		{name: "unknown kind", json: `{"fields": [{"name": "A", "kind": "float", "width": 32}]}`},
+		{name: "bad width", json: `{"fields": [{"name": "A", "kind": "uint", "width": 12}]}`},
+		{name: "unsized string", json: `{"fields": [{"name": "A", "kind": "string"}]}`},
+		{name: "huge count", json: `{"fields": [{"name": "A", "kind": "uint", "width": 64, "count": 9223372036854775807}]}`},
+		{name: "oversized array", json: `{"fields": [{"name": "A", "kind": "uint", "width": 64, "count": 268435456}]}`},
+		{name: "count and repeated", json: `{"fields": [{"name": "A", "kind": "bool", "count": 2, "repeated": true}]}`},
+		{name: "unknown endian", json: `{"fields": [{"name": "A", "kind": "uint", "width": 8, "endian": "middle"}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadSchema(strings.NewReader(tt.json)); !errors.Is(err, ErrSchema) {
+				t.Errorf("LoadSchema() error = %v, wanted %v", err, ErrSchema)
+			}
+		})
+	}
+
+	if _, err := DecodeSchema(bytes.NewReader(nil), BigEndian, Schema{}); !errors.Is(err, ErrSchema) {
+		t.Errorf("DecodeSchema() error = %v, wanted %v", err, ErrSchema)
+	}
+}
@@ -0,0 +1,168 @@
+package mixedEndian
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// loadField is a field as LoadSchema describes it
+type loadField struct {
+	Name string `json:"name"`
+
+	// Either Type, as in SchemaFromJSON, or Kind with Width or Size
+	Type  string `json:"type"`
+	Kind  string `json:"kind"`
+	Width int    `json:"width"`
+	Size  int    `json:"size"`
+
+	Endian   string            `json:"endian"`
+	Count    int               `json:"count"`
+	Repeated bool              `json:"repeated"`
+	Tags     map[string]string `json:"tags"`
+	Tag      string            `json:"tag"`
+}
+
+// LoadSchema reads a format description from r, resolving types with DefaultRegistry.
+// It takes the JSON SchemaFromJSON does, but fields may instead give their kind and width,
+// which suits descriptions written for tools rather than generated from Go:
+//
+//	{
+//		"name": "Record",
+//		"fields": [
+//			{"name": "Version", "kind": "uint", "width": 8},
+//			{"name": "Count", "kind": "uint", "width": 16, "endian": "little"},
+//			{"name": "Offsets", "kind": "int", "width": 24, "repeated": true, "tags": {"len": "Count"}},
+//			{"name": "Label", "kind": "string", "size": 8, "tags": {"trim": "space"}},
+//			{"name": "Flags", "kind": "bool", "count": 4}
+//		]
+//	}
+//
+// kind is one of "uint", "int", "bool", or "string". Integers give their width in bits, any the
+// registry knows, and strings their size in bytes. endian is "big" or "little", defaulting to the
+// byte order the schema's read with. A count makes the field an array of that many; repeated makes
+// it a slice, which its tags must size. tags are any other struct tags, by key, and a tag
+// string as SchemaFromJSON takes may be given too.
+func LoadSchema(r io.Reader) (Schema, error) {
+	var desc struct {
+		Name   string      `json:"name"`
+		Fields []loadField `json:"fields"`
+	}
+	if err := json.NewDecoder(r).Decode(&desc); err != nil {
+		return Schema{}, fmt.Errorf("%w %v", ErrSchema, err)
+	}
+
+	fields := make([]SchemaField, len(desc.Fields))
+	for i, lf := range desc.Fields {
+		sf, err := lf.schemaField()
+		if err != nil {
+			return Schema{}, fmt.Errorf("%s: %w", lf.Name, err)
+		}
+		fields[i] = sf
+	}
+
+	s, err := newSchema(desc.Name, fields, nil)
+	if err != nil {
+		return Schema{}, err
+	}
+	return *s, nil
+}
+
+// schemaField converts lf to the SchemaField it describes
+func (lf loadField) schemaField() (sf SchemaField, err error) {
+	sf = SchemaField{Name: lf.Name, Type: lf.Type}
+	tags := map[string]string{}
+	for k, v := range lf.Tags {
+		tags[k] = v
+	}
+
+	switch lf.Kind {
+	case "":
+		if lf.Type == "" {
+			return sf, fmt.Errorf("%w Fields need a type or kind", ErrSchema)
+		}
+	case "uint", "int":
+		sf.Type = lf.Kind + strconv.Itoa(lf.Width)
+	case "bool":
+		sf.Type = "bool"
+	case "string":
+		if lf.Size < 1 {
+			return sf, fmt.Errorf("%w Strings need a size", ErrSchema)
+		}
+		sf.Type = "string"
+		tags["size"] = strconv.Itoa(lf.Size)
+	default:
+		return sf, fmt.Errorf("%w Unknown kind %q", ErrSchema, lf.Kind)
+	}
+	if lf.Kind != "" && lf.Type != "" {
+		return sf, fmt.Errorf("%w Fields take a type or kind, not both", ErrSchema)
+	}
+
+	switch {
+	case lf.Count < 0:
+		return sf, fmt.Errorf("%w Negative count %d", ErrSchema, lf.Count)
+	case lf.Count > arrayMax:
+		return sf, fmt.Errorf("%w Count %d is over %d", ErrSchema, lf.Count, arrayMax)
+	case lf.Count > 0 && lf.Repeated:
+		return sf, fmt.Errorf("%w Fields take a count or repeated, not both", ErrSchema)
+	case lf.Count > 0:
+		sf.Type = "[" + strconv.Itoa(lf.Count) + "]" + sf.Type
+	case lf.Repeated:
+		sf.Type = "[]" + sf.Type
+	}
+
+	switch lf.Endian {
+	case "":
+	case "big", "little":
+		tags["endian"] = lf.Endian
+	default:
+		return sf, fmt.Errorf("%w Unknown endian %q", ErrSchema, lf.Endian)
+	}
+
+	// Sorted, so the same description always gives the same type
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var tag strings.Builder
+	for _, k := range keys {
+		if tag.Len() > 0 {
+			tag.WriteByte(' ')
+		}
+		fmt.Fprintf(&tag, "%s:%s", k, strconv.Quote(tags[k]))
+	}
+	if lf.Tag != "" {
+		if tag.Len() > 0 {
+			tag.WriteByte(' ')
+		}
+		tag.WriteString(lf.Tag)
+	}
+	sf.Tag = tag.String()
+	return sf, nil
+}
+
+// DecodeSchema reads one struct described by s from r, returning its fields by name.
+// Field values are as Read would fill them: integers, bools, strings, and slices or arrays of them.
+func DecodeSchema(r io.Reader, defaultEndian binary.ByteOrder, s Schema) (map[string]any, error) {
+	if s.typ == nil {
+		return nil, fmt.Errorf("%w Schema %q has no type; build it with LoadSchema or SchemaFromJSON", ErrSchema, s.Name)
+	}
+
+	var data any = s.New()
+	if err := Read(r, defaultEndian, &data); err != nil {
+		return nil, err
+	}
+
+	v := reflect.ValueOf(data).Elem()
+	m := make(map[string]any, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		m[v.Type().Field(i).Name] = v.Field(i).Interface()
+	}
+	return m, nil
+}
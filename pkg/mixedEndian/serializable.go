@@ -0,0 +1,62 @@
+package mixedEndian
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+var serializableType = reflect.TypeOf(Serializable{})
+
+// Serializable gives a struct embedding it Encode and Decode methods:
+//
+//	type Header struct {
+//		mixedEndian.Serializable
+//		Magic uint32
+//	}
+//
+//	h := &Header{Serializable: mixedEndian.Serializable{Order: mixedEndian.BigEndian}}
+//	h.Bind(h)
+//	err := h.Decode(r)
+//
+// Go gives an embedded struct no way to reach the struct embedding it, so it must be handed a
+// pointer to it with Bind first. Copies of the struct stay bound to the original.
+// Serializable fields are skipped by Read and Write, taking no bytes on the wire.
+type Serializable struct {
+	// Order is the default byte order of the struct's fields
+	Order binary.ByteOrder
+
+	self any
+}
+
+// Bind points s at the struct embedding it, given as a pointer
+func (s *Serializable) Bind(self any) {
+	s.self = self
+}
+
+// Encode writes the bound struct to w, as Write does
+func (s *Serializable) Encode(w io.Writer) error {
+	if err := s.bound(); err != nil {
+		return err
+	}
+	return Write(w, s.Order, s.self)
+}
+
+// Decode reads the bound struct from r, as Read does
+func (s *Serializable) Decode(r io.Reader) error {
+	if err := s.bound(); err != nil {
+		return err
+	}
+	return Read(r, s.Order, &s.self)
+}
+
+// bound errors unless s is bound to a struct pointer and has a byte order
+func (s *Serializable) bound() error {
+	if v := reflect.ValueOf(s.self); v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w Serializable is bound to %T, not a struct pointer; Call Bind", ErrUnexpectedType, s.self)
+	} else if s.Order == nil {
+		return fmt.Errorf("%w Serializable has no Order", ErrUnexpectedType)
+	}
+	return nil
+}
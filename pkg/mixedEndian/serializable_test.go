@@ -0,0 +1,59 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type SelfSerializing struct {
+	Serializable
+	Magic uint32
+	Count uint16 `endian:"little"`
+}
+
+func TestSerializable(t *testing.T) {
+	h := &SelfSerializing{Serializable: Serializable{Order: BigEndian}, Magic: 0xCAFEBABE, Count: 2}
+	h.Bind(h)
+
+	buf := &bytes.Buffer{}
+	if err := h.Encode(buf); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	want := []byte{0xCA, 0xFE, 0xBA, 0xBE, 0x02, 0x00}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("Encode() = % X, wanted % X", buf.Bytes(), want)
+	}
+
+	got := &SelfSerializing{Serializable: Serializable{Order: BigEndian}}
+	got.Bind(got)
+	if err := got.Decode(bytes.NewReader(want)); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if got.Magic != h.Magic || got.Count != h.Count {
+		t.Errorf("Decode() = %+v, wanted %+v", got, h)
+	}
+
+	// Serializables take no room in the layout either
+	if n, err := CountBytes(BigEndian, h); err != nil || n != len(want) {
+		t.Errorf("CountBytes() = %d, %v, wanted %d", n, err, len(want))
+	}
+}
+
+func TestSerializableUnbound(t *testing.T) {
+	h := &SelfSerializing{Serializable: Serializable{Order: BigEndian}}
+	if err := h.Encode(&bytes.Buffer{}); !errors.Is(err, ErrUnexpectedType) {
+		t.Errorf("Encode() error = %v, wanted %v", err, ErrUnexpectedType)
+	}
+
+	h.Bind(*h)
+	if err := h.Decode(bytes.NewReader(make([]byte, 6))); !errors.Is(err, ErrUnexpectedType) {
+		t.Errorf("Decode() error = %v, wanted %v", err, ErrUnexpectedType)
+	}
+
+	h.Bind(h)
+	h.Order = nil
+	if err := h.Encode(&bytes.Buffer{}); !errors.Is(err, ErrUnexpectedType) {
+		t.Errorf("Encode() error = %v, wanted %v", err, ErrUnexpectedType)
+	}
+}
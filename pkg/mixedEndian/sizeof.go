@@ -0,0 +1,84 @@
+package mixedEndian
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// selfSizeRounds caps the dry runs sizing a struct whose size field changes its own size
+const selfSizeRounds = 8
+
+// CountBytes is the number of bytes Write would write for data, found by encoding it to nowhere
+func CountBytes(defaultEndian binary.ByteOrder, data any) (int, error) {
+	cw := &countingWriter{w: io.Discard}
+	if err := Write(cw, defaultEndian, data); err != nil {
+		return 0, err
+	}
+	return int(cw.n), nil
+}
+
+// selfSizeField is the index of t's field tagged `sizeof_field:"self"`, if it has one
+func selfSizeField(t reflect.Type) (i int, ok bool, err error) {
+	for j := 0; j < t.NumField(); j++ {
+		switch s := t.Field(j).Tag.Get("sizeof_field"); s {
+		case "":
+			continue
+		case "self":
+		default:
+			return 0, false, fmt.Errorf("%s: %w sizeof_field %q is not self", t.Field(j).Name, ErrTag, s)
+		}
+
+		if ok {
+			return 0, false, fmt.Errorf("%s: %w %s already has a sizeof_field", t.Field(j).Name, ErrTag, t.String())
+		}
+		i, ok = j, true
+	}
+	return
+}
+
+// selfSized returns a copy of struct v with its field i set to the struct's encoded size.
+// It takes a dry run of writing the struct, or more if i is a varint whose size changes.
+func (w *writer) selfSized(v reflect.Value, i int, so structOptions, o binary.ByteOrder) (reflect.Value, error) {
+	sized := reflect.New(v.Type()).Elem()
+	sized.Set(v)
+	f := sized.Field(i)
+	if !f.CanSet() {
+		return v, fmt.Errorf("%s: %w sizeof_field needs an exported field", v.Type().Field(i).Name, ErrTag)
+	}
+
+	dry := *w
+	for round := 0; round < selfSizeRounds; round++ {
+		cw := &countingWriter{w: io.Discard}
+		dry.w = cw
+		if err := dry.writeStruct(sized, so, o); err != nil {
+			return v, err
+		}
+
+		switch f.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if round > 0 && f.Int() == cw.n {
+				return sized, nil
+			} else if f.OverflowInt(cw.n) {
+				return v, fmt.Errorf("%s: %w %d bytes don't fit %s", v.Type().Field(i).Name, ErrRange, cw.n, f.Type().String())
+			}
+			f.SetInt(cw.n)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if round > 0 && f.Uint() == uint64(cw.n) {
+				return sized, nil
+			} else if f.OverflowUint(uint64(cw.n)) {
+				return v, fmt.Errorf("%s: %w %d bytes don't fit %s", v.Type().Field(i).Name, ErrRange, cw.n, f.Type().String())
+			}
+			f.SetUint(uint64(cw.n))
+		default:
+			return v, fmt.Errorf("%s: %w sizeof_field needs an integer; Got %s", v.Type().Field(i).Name, ErrUnexpectedType, f.Type().String())
+		}
+
+		// Only varints change size with their value, so everything else is done in one run
+		if !isVarint(v.Type().Field(i)) {
+			return sized, nil
+		}
+	}
+	return v, fmt.Errorf("%w %s's size never settles", ErrLength, v.Type().String())
+}
@@ -0,0 +1,89 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type FramedStruct struct {
+	Length  uint16 `sizeof_field:"self"`
+	Kind    uint8
+	N       uint8
+	Payload []byte `len:"N"`
+}
+
+type VarintFramedStruct struct {
+	Length  uint64 `sizeof_field:"self" encoding:"uvarint"`
+	N       uint16
+	Payload []byte `len:"N"`
+}
+
+type SmallFramedStruct struct {
+	Length  uint8 `sizeof_field:"self"`
+	N       uint16
+	Payload []byte `len:"N"`
+}
+
+type BadFramedStruct struct {
+	Length uint8 `sizeof_field:"Kind"`
+	Kind   uint8
+}
+
+func TestSizeofSelf(t *testing.T) {
+	data := FramedStruct{Length: 0xFFFF, Kind: 0x01, N: 3, Payload: []byte{0xAA, 0xBB, 0xCC}}
+	wire := []byte{0x00, 0x07, 0x01, 0x03, 0xAA, 0xBB, 0xCC}
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, &data); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+	if data.Length != 0xFFFF {
+		t.Errorf("Write() changed Length to %d", data.Length)
+	}
+
+	if n, err := CountBytes(BigEndian, data); err != nil || n != len(wire) {
+		t.Errorf("CountBytes() = %d, %v, wanted %d", n, err, len(wire))
+	}
+
+	fe, err := NewFieldEncoder(BigEndian, data)
+	if err != nil {
+		t.Fatalf("NewFieldEncoder() error = %v", err)
+	}
+	if bs, _, err := fe.Next(); err != nil || !bytes.Equal(bs, wire[:2]) {
+		t.Errorf("Next() = % X, %v, wanted % X", bs, err, wire[:2])
+	}
+}
+
+func TestSizeofSelfVarint(t *testing.T) {
+	// 127 bytes of N and payload make 128 with a 1 byte length, which then needs 2 bytes
+	data := VarintFramedStruct{N: 125, Payload: make([]byte, 125)}
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, data); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if got := buf.Bytes()[:2]; buf.Len() != 129 || !bytes.Equal(got, []byte{0x81, 0x01}) {
+		t.Errorf("Write() = %d bytes starting % X, wanted 129 starting 81 01", buf.Len(), got)
+	}
+}
+
+func TestSizeofSelfErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    any
+		wantErr error
+	}{
+		{"too big", SmallFramedStruct{N: 300, Payload: make([]byte, 300)}, ErrRange},
+		{"bad tag", BadFramedStruct{}, ErrTag},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Write(&bytes.Buffer{}, BigEndian, tt.data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Write() error = %v, wanted %v", err, tt.wantErr)
+			}
+		})
+	}
+}
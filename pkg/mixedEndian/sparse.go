@@ -0,0 +1,113 @@
+package mixedEndian
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+)
+
+// uintTypes are the integer types which may be named in tags
+var uintTypes = map[string]reflect.Type{
+	"uint8":  reflect.TypeOf(uint8(0)),
+	"uint16": reflect.TypeOf(uint16(0)),
+	"uint32": reflect.TypeOf(uint32(0)),
+	"uint64": reflect.TypeOf(uint64(0)),
+}
+
+// tagUintType looks up the unsigned integer type named in a tag
+func tagUintType(name string) (reflect.Type, error) {
+	t, ok := uintTypes[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q is not an unsigned integer type", ErrTag, name)
+	}
+	return t, nil
+}
+
+// readUint reads an unsigned integer of type t
+func (r *reader) readUint(t reflect.Type, o binary.ByteOrder) (uint64, error) {
+	v := reflect.New(t).Elem()
+	if err := r.readOrdered(v, o); err != nil {
+		return 0, err
+	}
+	return v.Uint(), nil
+}
+
+// writeUint writes u as an unsigned integer of type t
+func (w *writer) writeUint(t reflect.Type, u uint64, o binary.ByteOrder) error {
+	v := reflect.New(t).Elem()
+	if v.OverflowUint(u) {
+		return fmt.Errorf("%w %d does not fit in %s", ErrRange, u, t.String())
+	}
+	v.SetUint(u)
+	return w.writeOrdered(v, o)
+}
+
+// readSparse reads the nonzero elements of sized slice f as a count then index and value pairs
+func (r *reader) readSparse(sf reflect.StructField, f reflect.Value, o binary.ByteOrder) error {
+	it, err := tagUintType(sf.Tag.Get("sparse"))
+	if err != nil {
+		return err
+	}
+	if f.Kind() != reflect.Slice {
+		return fmt.Errorf("%w sparse requires a slice; Got %s", ErrUnexpectedType, f.Type().String())
+	}
+	if f.IsNil() {
+		return fmt.Errorf("%w sparse slices need a len, countfrom, or count tag", ErrTag)
+	}
+
+	count, err := r.readUint(it, o)
+	if err != nil {
+		return err
+	}
+	if count > uint64(f.Len()) {
+		return fmt.Errorf("%w %d sparse elements in a slice of %d", ErrLength, count, f.Len())
+	}
+
+	for ; count > 0; count-- {
+		i, err := r.readUint(it, o)
+		if err != nil {
+			return err
+		}
+		if i >= uint64(f.Len()) {
+			return fmt.Errorf("%w index %d in a slice of %d", ErrRange, i, f.Len())
+		}
+		if err = r.readOrdered(f.Index(int(i)), o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeSparse writes the nonzero elements of slice f as a count then index and value pairs
+func (w *writer) writeSparse(sf reflect.StructField, f reflect.Value, o binary.ByteOrder) error {
+	it, err := tagUintType(sf.Tag.Get("sparse"))
+	if err != nil {
+		return err
+	}
+	if f.Kind() != reflect.Slice {
+		return fmt.Errorf("%w sparse requires a slice; Got %s", ErrUnexpectedType, f.Type().String())
+	}
+
+	var count uint64
+	for i := 0; i < f.Len(); i++ {
+		if !f.Index(i).IsZero() {
+			count++
+		}
+	}
+	if err = w.writeUint(it, count, o); err != nil {
+		return err
+	}
+
+	for i := 0; i < f.Len(); i++ {
+		if f.Index(i).IsZero() {
+			continue
+		}
+		if err = w.writeUint(it, uint64(i), o); err != nil {
+			return err
+		}
+		if err = w.writeOrdered(f.Index(i), o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
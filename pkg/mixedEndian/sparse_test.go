@@ -0,0 +1,89 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type SparseStruct struct {
+	N      uint8
+	Values []uint32 `countfrom:"N" sparse:"uint16" endian:"little"`
+}
+
+type SparseCountStruct struct {
+	Values []uint32 `count:"6" sparse:"uint8"`
+}
+
+func TestSparse(t *testing.T) {
+	tests := []struct {
+		name string
+		data any
+		read any
+		wire []byte
+	}{
+		{
+			name: "countfrom",
+			data: &SparseStruct{N: 5, Values: []uint32{0, 0x01020304, 0, 0, 0x0A0B0C0D}},
+			read: &SparseStruct{},
+			wire: []byte{
+				0x05,
+				0x02, 0x00,
+				0x01, 0x00, 0x04, 0x03, 0x02, 0x01,
+				0x04, 0x00, 0x0D, 0x0C, 0x0B, 0x0A,
+			},
+		},
+		{
+			name: "count",
+			data: &SparseCountStruct{Values: []uint32{7, 0, 0, 0, 0, 0}},
+			read: &SparseCountStruct{},
+			wire: []byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x07},
+		},
+		{
+			name: "all zero",
+			data: &SparseCountStruct{Values: make([]uint32, 6)},
+			read: &SparseCountStruct{},
+			wire: []byte{0x00},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			if err := Write(buf, BigEndian, tt.data); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if !bytes.Equal(buf.Bytes(), tt.wire) {
+				t.Errorf("Write() = % X, wanted % X", buf.Bytes(), tt.wire)
+			}
+
+			if err := Read(bytes.NewReader(tt.wire), BigEndian, &tt.read); err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if !reflect.DeepEqual(tt.read, tt.data) {
+				t.Errorf("Read() = %v, wanted %v", tt.read, tt.data)
+			}
+		})
+	}
+}
+
+func TestSparseErrors(t *testing.T) {
+	var data any = &SparseCountStruct{}
+	if err := Read(bytes.NewReader([]byte{0x01, 0x06, 0, 0, 0, 1}), BigEndian, &data); !errors.Is(err, ErrRange) {
+		t.Errorf("Read() error = %v, wanted %v", err, ErrRange)
+	}
+	if err := Read(bytes.NewReader([]byte{0x07}), BigEndian, &data); !errors.Is(err, ErrLength) {
+		t.Errorf("Read() error = %v, wanted %v", err, ErrLength)
+	}
+
+	data = &struct {
+		Values []uint32 `sparse:"uint8"`
+	}{}
+	if err := Read(bytes.NewReader([]byte{0x00}), BigEndian, &data); !errors.Is(err, ErrTag) {
+		t.Errorf("Read() error = %v, wanted %v", err, ErrTag)
+	}
+
+	if err := Write(&bytes.Buffer{}, BigEndian, SparseCountStruct{Values: []uint32{1}}); !errors.Is(err, ErrLength) {
+		t.Errorf("Write() error = %v, wanted %v", err, ErrLength)
+	}
+}
@@ -0,0 +1,224 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+var bigIntPtrType = reflect.TypeOf((*big.Int)(nil))
+
+// sshFormat is the RFC 4251 data type named by sf's ssh tag, checked against sf's type
+func sshFormat(sf reflect.StructField) (string, error) {
+	t := sf.Type
+	switch s := sf.Tag.Get("ssh"); s {
+	case "":
+		return "", nil
+	case "string":
+		if t.Kind() == reflect.String || (t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8) {
+			return s, nil
+		}
+		return "", fmt.Errorf("%w ssh string needs a string or []byte; Got %s", ErrUnexpectedType, t.String())
+	case "mpint":
+		if t == bigIntPtrType {
+			return s, nil
+		}
+		return "", fmt.Errorf("%w ssh mpint needs a *big.Int; Got %s", ErrUnexpectedType, t.String())
+	case "namelist":
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.String {
+			return s, nil
+		}
+		return "", fmt.Errorf("%w ssh namelist needs a []string; Got %s", ErrUnexpectedType, t.String())
+	default:
+		return "", fmt.Errorf("%w Unknown ssh type %q", ErrTag, s)
+	}
+}
+
+// readSSHString reads an RFC 4251 string: a big endian uint32 length, then that many bytes.
+// The bytes are read as they arrive, so a corrupt length can't force a huge allocation.
+func (r *reader) readSSHString() ([]byte, error) {
+	bs, err := r.next(4)
+	if err != nil {
+		return nil, err
+	}
+	n := int64(binary.BigEndian.Uint32(bs))
+
+	buf := &bytes.Buffer{}
+	if m, err := io.CopyN(buf, r.r, n); err == io.EOF {
+		return nil, fmt.Errorf("%w ssh string of %d bytes ends after %d", io.ErrUnexpectedEOF, n, m)
+	} else if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeSSHString writes bs as an RFC 4251 string
+func (w *writer) writeSSHString(bs []byte) error {
+	if uint64(len(bs)) > 1<<32-1 {
+		return fmt.Errorf("%w ssh string of %d bytes is too long", ErrLength, len(bs))
+	}
+	binary.BigEndian.PutUint32(w.scratch[:4], uint32(len(bs)))
+	if _, err := w.w.Write(w.scratch[:4]); err != nil {
+		return err
+	}
+	_, err := w.w.Write(bs)
+	return err
+}
+
+// readSSH reads f as the RFC 4251 data type named by sf's ssh tag
+func (r *reader) readSSH(sf reflect.StructField, f reflect.Value) error {
+	format, err := sshFormat(sf)
+	if err != nil {
+		return err
+	}
+	bs, err := r.readSSHString()
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "string":
+		if f.Kind() == reflect.String {
+			f.SetString(string(bs))
+			return nil
+		}
+		s := r.makeSlice(f.Type(), len(bs))
+		copy(s.Bytes(), bs)
+		f.Set(s)
+	case "mpint":
+		i, err := parseMPInt(bs)
+		if err != nil {
+			return err
+		}
+		f.Set(reflect.ValueOf(i))
+	case "namelist":
+		names, err := parseNameList(bs)
+		if err != nil {
+			return err
+		}
+		s := r.makeSlice(f.Type(), len(names))
+		for i, name := range names {
+			s.Index(i).SetString(name)
+		}
+		f.Set(s)
+	}
+	return nil
+}
+
+// writeSSH writes f as the RFC 4251 data type named by sf's ssh tag
+func (w *writer) writeSSH(sf reflect.StructField, f reflect.Value) error {
+	format, err := sshFormat(sf)
+	if err != nil {
+		return err
+	}
+
+	var bs []byte
+	switch format {
+	case "string":
+		if f.Kind() == reflect.String {
+			bs = []byte(f.String())
+		} else {
+			bs = f.Bytes()
+		}
+	case "mpint":
+		if f.IsNil() {
+			return fmt.Errorf("%w ssh mpint is nil", ErrRange)
+		}
+		bs = appendMPInt(nil, f.Interface().(*big.Int))
+	case "namelist":
+		names := make([]string, f.Len())
+		for i := range names {
+			names[i] = f.Index(i).String()
+		}
+		if bs, err = formatNameList(names); err != nil {
+			return err
+		}
+	}
+	return w.writeSSHString(bs)
+}
+
+// parseMPInt decodes the two's complement body of an RFC 4251 mpint, which must be minimal
+func parseMPInt(bs []byte) (*big.Int, error) {
+	i := new(big.Int)
+	switch {
+	case len(bs) == 0:
+		return i, nil
+	case len(bs) == 1 && bs[0] == 0,
+		len(bs) > 1 && bs[0] == 0x00 && bs[1]&0x80 == 0,
+		len(bs) > 1 && bs[0] == 0xFF && bs[1]&0x80 != 0:
+		return nil, fmt.Errorf("%w mpint % X has a redundant leading byte", ErrRange, bs)
+	}
+
+	i.SetBytes(bs)
+	if bs[0]&0x80 != 0 {
+		// Negative, so take away 2^(8*len)
+		i.Sub(i, new(big.Int).Lsh(big.NewInt(1), uint(8*len(bs))))
+	}
+	return i, nil
+}
+
+// appendMPInt appends the minimal two's complement body of an RFC 4251 mpint holding i
+func appendMPInt(bs []byte, i *big.Int) []byte {
+	switch i.Sign() {
+	case 0:
+		return bs
+	case 1:
+		mag := i.Bytes()
+		if mag[0]&0x80 != 0 {
+			bs = append(bs, 0x00)
+		}
+		return append(bs, mag...)
+	}
+
+	// -i-1 is the bitwise complement of i's two's complement
+	mag := new(big.Int).Not(i).Bytes()
+	for j := range mag {
+		mag[j] = ^mag[j]
+	}
+	if len(mag) == 0 || mag[0]&0x80 == 0 {
+		bs = append(bs, 0xFF)
+	}
+	return append(bs, mag...)
+}
+
+// parseNameList splits the body of an RFC 4251 name-list, whose names mustn't be empty
+func parseNameList(bs []byte) ([]string, error) {
+	if len(bs) == 0 {
+		return []string{}, nil
+	}
+	names := strings.Split(string(bs), ",")
+	for _, name := range names {
+		if err := checkName(name); err != nil {
+			return nil, err
+		}
+	}
+	return names, nil
+}
+
+// formatNameList joins names into the body of an RFC 4251 name-list
+func formatNameList(names []string) ([]byte, error) {
+	for _, name := range names {
+		if err := checkName(name); err != nil {
+			return nil, err
+		}
+	}
+	return []byte(strings.Join(names, ",")), nil
+}
+
+// checkName errors unless name is fit for a name-list: non-empty, printable US-ASCII, and
+// without commas
+func checkName(name string) error {
+	if name == "" {
+		return fmt.Errorf("%w namelist holds an empty name", ErrRange)
+	}
+	for i := 0; i < len(name); i++ {
+		if c := name[i]; c <= ' ' || c > '~' || c == ',' {
+			return fmt.Errorf("%w namelist name %q holds %q", ErrRange, name, c)
+		}
+	}
+	return nil
+}
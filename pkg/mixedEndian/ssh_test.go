@@ -0,0 +1,160 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+type SSHMessage struct {
+	Type  uint8
+	Name  string   `ssh:"string"`
+	Blob  []byte   `ssh:"string"`
+	E     *big.Int `ssh:"mpint"`
+	Algos []string `ssh:"namelist"`
+}
+
+type MPIntStruct struct {
+	I *big.Int `ssh:"mpint"`
+}
+
+type NameListStruct struct {
+	Names []string `ssh:"namelist"`
+}
+
+func bigHex(s string) *big.Int {
+	i, _ := new(big.Int).SetString(s, 16)
+	return i
+}
+
+func TestMPInt(t *testing.T) {
+	// The examples of RFC 4251 section 5
+	tests := []struct {
+		i    *big.Int
+		wire []byte
+	}{
+		{big.NewInt(0), []byte{0x00, 0x00, 0x00, 0x00}},
+		{bigHex("9a378f9b2e332a7"), []byte{0x00, 0x00, 0x00, 0x08, 0x09, 0xa3, 0x78, 0xf9, 0xb2, 0xe3, 0x32, 0xa7}},
+		{bigHex("80"), []byte{0x00, 0x00, 0x00, 0x02, 0x00, 0x80}},
+		{bigHex("-1234"), []byte{0x00, 0x00, 0x00, 0x02, 0xed, 0xcc}},
+		{bigHex("-deadbeef"), []byte{0x00, 0x00, 0x00, 0x05, 0xff, 0x21, 0x52, 0x41, 0x11}},
+		{big.NewInt(-1), []byte{0x00, 0x00, 0x00, 0x01, 0xff}},
+		{big.NewInt(-128), []byte{0x00, 0x00, 0x00, 0x01, 0x80}},
+		{big.NewInt(-129), []byte{0x00, 0x00, 0x00, 0x02, 0xff, 0x7f}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.i.String(), func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			if err := Write(buf, LittleEndian, MPIntStruct{I: tt.i}); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if !bytes.Equal(buf.Bytes(), tt.wire) {
+				t.Errorf("Write() = % x, wanted % x", buf.Bytes(), tt.wire)
+			}
+
+			var data any = &MPIntStruct{}
+			if err := Read(bytes.NewReader(tt.wire), LittleEndian, &data); err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if got := data.(*MPIntStruct).I; got.Cmp(tt.i) != 0 {
+				t.Errorf("Read() = %v, wanted %v", got, tt.i)
+			}
+		})
+	}
+}
+
+func TestMPIntNonMinimal(t *testing.T) {
+	for _, wire := range [][]byte{
+		{0x00, 0x00, 0x00, 0x01, 0x00},
+		{0x00, 0x00, 0x00, 0x02, 0x00, 0x7f},
+		{0x00, 0x00, 0x00, 0x02, 0xff, 0x80},
+	} {
+		var data any = &MPIntStruct{}
+		if err := Read(bytes.NewReader(wire), BigEndian, &data); !errors.Is(err, ErrRange) {
+			t.Errorf("Read(% x) error = %v, wanted %v", wire, err, ErrRange)
+		}
+	}
+}
+
+func TestNameList(t *testing.T) {
+	// The examples of RFC 4251 section 5
+	tests := []struct {
+		names []string
+		wire  []byte
+	}{
+		{[]string{}, []byte{0x00, 0x00, 0x00, 0x00}},
+		{[]string{"zlib"}, []byte{0x00, 0x00, 0x00, 0x04, 'z', 'l', 'i', 'b'}},
+		{[]string{"zlib", "none"}, []byte{0x00, 0x00, 0x00, 0x09, 'z', 'l', 'i', 'b', ',', 'n', 'o', 'n', 'e'}},
+	}
+	for _, tt := range tests {
+		buf := &bytes.Buffer{}
+		if err := Write(buf, BigEndian, NameListStruct{Names: tt.names}); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		if !bytes.Equal(buf.Bytes(), tt.wire) {
+			t.Errorf("Write() = % x, wanted % x", buf.Bytes(), tt.wire)
+		}
+
+		var data any = &NameListStruct{}
+		if err := Read(bytes.NewReader(tt.wire), BigEndian, &data); err != nil {
+			t.Fatalf("Read() error = %v", err)
+		}
+		if got := data.(*NameListStruct).Names; !reflect.DeepEqual(got, tt.names) {
+			t.Errorf("Read() = %q, wanted %q", got, tt.names)
+		}
+	}
+
+	for _, names := range [][]string{{""}, {"a,b"}, {"a b"}, {"zlib", ""}} {
+		if err := Write(&bytes.Buffer{}, BigEndian, NameListStruct{Names: names}); !errors.Is(err, ErrRange) {
+			t.Errorf("Write(%q) error = %v, wanted %v", names, err, ErrRange)
+		}
+	}
+	var data any = &NameListStruct{}
+	if err := Read(bytes.NewReader([]byte{0x00, 0x00, 0x00, 0x01, ','}), BigEndian, &data); !errors.Is(err, ErrRange) {
+		t.Errorf("Read() error = %v, wanted %v", err, ErrRange)
+	}
+}
+
+func TestSSHMessage(t *testing.T) {
+	msg := SSHMessage{Type: 20, Name: "ssh-rsa", Blob: []byte{1, 2}, E: big.NewInt(65537), Algos: []string{"aes128-ctr", "none"}}
+	buf := &bytes.Buffer{}
+	if err := Write(buf, LittleEndian, msg); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	want := []byte{20, 0, 0, 0, 7, 's', 's', 'h', '-', 'r', 's', 'a', 0, 0, 0, 2, 1, 2, 0, 0, 0, 3, 0x01, 0x00, 0x01, 0, 0, 0, 15}
+	want = append(want, "aes128-ctr,none"...)
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("Write() = % x, wanted % x", buf.Bytes(), want)
+	}
+
+	var data any = &SSHMessage{}
+	if err := Read(bytes.NewReader(want), LittleEndian, &data); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if got := data.(*SSHMessage); !reflect.DeepEqual(got, &msg) {
+		t.Errorf("Read() = %+v, wanted %+v", got, msg)
+	}
+
+	if err := Read(bytes.NewReader(want[:10]), LittleEndian, &data); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Read() error = %v, wanted %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestSSHTagErrors(t *testing.T) {
+	type bad struct {
+		I int64 `ssh:"mpint"`
+	}
+	if err := Write(&bytes.Buffer{}, BigEndian, bad{}); !errors.Is(err, ErrUnexpectedType) {
+		t.Errorf("Write() error = %v, wanted %v", err, ErrUnexpectedType)
+	}
+	type unknown struct {
+		S string `ssh:"uint32"`
+	}
+	if err := Write(&bytes.Buffer{}, BigEndian, unknown{}); !errors.Is(err, ErrTag) {
+		t.Errorf("Write() error = %v, wanted %v", err, ErrTag)
+	}
+}
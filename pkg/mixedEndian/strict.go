@@ -0,0 +1,251 @@
+package mixedEndian
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// checkStrict errors if t, or any type within it, can't be read with every field accounted for
+func checkStrict(t reflect.Type) error {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Array, reflect.Slice:
+		return checkStrict(t.Elem())
+
+	case reflect.Map:
+		if err := checkStrict(t.Key()); err != nil {
+			return err
+		}
+		return checkStrict(t.Elem())
+
+	case reflect.Struct:
+		if so, err := optionsOf(t); err != nil {
+			return err
+		} else if so.iso8583 {
+			if _, err = dataElements(t); err != nil {
+				return err
+			}
+		}
+		if _, err := rawFieldsOf(t); err != nil {
+			return err
+		}
+		if _, err := checksumsOf(t); err != nil {
+			return err
+		}
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if sf.Name == "_" && sf.Type.Size() == 0 {
+				// Markers only carry struct options
+				continue
+			}
+			if !sf.IsExported() {
+				return fmt.Errorf("%s: %w Unexported fields aren't read", sf.Name, ErrUnexpectedType)
+			}
+			if isOptional(sf.Type) {
+				// Optionals' other tags are their values'
+				if err := checkOptional(t, sf); err != nil {
+					return fmt.Errorf("%s: %w", sf.Name, err)
+				}
+				sf = optionalValue(sf)
+			} else if sf.Tag.Get("optional") != "" {
+				return fmt.Errorf("%s: %w optional needs an Optional field", sf.Name, ErrTag)
+			}
+			if err := checkStrictField(t, sf); err != nil {
+				return fmt.Errorf("%s: %w", sf.Name, err)
+			}
+			if sf.Type == serializableType {
+				// Serializables aren't on the wire
+				continue
+			}
+			if isDecimal(sf) || isEpoch(sf) || sf.Tag.Get("ssh") != "" || sf.Tag.Get("compress") != "" {
+				// Decimals, times, SSH types, and compressed blocks are read whole, whatever their Go type
+				continue
+			}
+			if err := checkStrict(sf.Type); err != nil {
+				return fmt.Errorf("%s: %w", sf.Name, err)
+			}
+		}
+		return nil
+
+	default:
+		if t == hardwareAddrType || t.Kind() == reflect.String || typeSize(t) > 0 {
+			return nil
+		}
+		return fmt.Errorf("%w Unsupported type %s", ErrUnexpectedType, t.String())
+	}
+}
+
+// checkStrictField errors if any tag of sf, a field of struct t, has a value Read doesn't recognise
+func checkStrictField(t reflect.Type, sf reflect.StructField) (err error) {
+	switch e := sf.Tag.Get("endian"); e {
+	case "", "big", "little":
+	default:
+		return fmt.Errorf("%w Unknown endian %q", ErrTag, e)
+	}
+
+	switch e := sf.Tag.Get("encoding"); e {
+	case "", "gray", "varint", "uvarint", "uuid", "uuid_le":
+	default:
+		return fmt.Errorf("%w Unknown encoding %q", ErrTag, e)
+	}
+	if _, _, err = isUUID(sf); err != nil {
+		return
+	}
+
+	switch g := sf.Tag.Get("gray"); g {
+	case "", "true":
+	default:
+		return fmt.Errorf("%w Unknown gray %q", ErrTag, g)
+	}
+	switch b := sf.Tag.Get("bitreverse"); b {
+	case "", "true":
+	default:
+		return fmt.Errorf("%w Unknown bitreverse %q", ErrTag, b)
+	}
+	switch c := sf.Tag.Get("clamp"); c {
+	case "", "true":
+	default:
+		return fmt.Errorf("%w Unknown clamp %q", ErrTag, c)
+	}
+	if _, _, _, err = bitwidthMask(sf, sf.Type); err != nil {
+		return
+	}
+
+	switch d := sf.Tag.Get("delta"); d {
+	case "", "true":
+	default:
+		return fmt.Errorf("%w Unknown delta %q", ErrTag, d)
+	}
+	switch f := sf.Tag.Get("finite"); f {
+	case "", "true":
+	default:
+		return fmt.Errorf("%w Unknown finite %q", ErrTag, f)
+	}
+
+	for _, key := range []string{"len", "countfrom", "dims"} {
+		if refs := sf.Tag.Get(key); refs != "" {
+			for _, ref := range strings.Split(refs, ",") {
+				if _, ok := t.FieldByName(strings.TrimSpace(ref)); !ok {
+					return fmt.Errorf("%w %s names no field %s", ErrTag, key, ref)
+				}
+			}
+		}
+	}
+	if _, _, _, err = literalCount(sf); err != nil {
+		return
+	}
+	if _, _, err = lenPrefixOf(sf); err != nil {
+		return
+	}
+
+	if s := sf.Tag.Get("sparse"); s != "" {
+		if _, err = tagUintType(s); err != nil {
+			return
+		}
+	}
+	if isDecimal(sf) {
+		if _, err = decimalFormatOf(sf); err != nil {
+			return
+		}
+	}
+	if isDuration(sf) {
+		if _, _, err = durationFormat(sf); err != nil {
+			return
+		}
+	}
+	if isEpoch(sf) {
+		if _, _, _, err = epochFormat(sf); err != nil {
+			return
+		}
+	} else if sf.Tag.Get("epochunit") != "" {
+		return fmt.Errorf("%w epochunit needs a time.Time tagged epoch", ErrTag)
+	}
+	if _, err = timecodeLayoutOf(sf); err != nil {
+		return
+	}
+	if isNorm(sf) {
+		if _, _, err = normFormat(sf); err != nil {
+			return
+		}
+	}
+	if isPCM(sf) {
+		if _, err = pcmFormat(sf); err != nil {
+			return
+		}
+	}
+	if _, err = sshFormat(sf); err != nil {
+		return
+	}
+	if _, err = versionTags(sf); err != nil {
+		return
+	}
+	if p := sf.Tag.Get("presentif"); p != "" {
+		if _, err = presenceIndex(t, sf, p); err != nil {
+			return
+		}
+	}
+	if sf.Tag.Get("de") != "" {
+		if so, _ := optionsOf(t); !so.iso8583 {
+			return fmt.Errorf("%w de needs a struct marked iso8583", ErrTag)
+		}
+	}
+	if s := sf.Tag.Get("crcblocks"); s != "" {
+		if _, _, err = parseCRCBlocks(s); err != nil {
+			return
+		}
+	}
+	if s := sf.Tag.Get("rle"); s != "" {
+		if _, err = parseRLE(s); err != nil {
+			return
+		}
+	}
+	if sf.Tag.Get("compress") != "" {
+		if _, err = compressorOf(sf); err != nil {
+			return
+		}
+	}
+	if s := sf.Tag.Get("string"); s != "" {
+		if _, err = parseGSM7(s); err != nil {
+			return
+		}
+	}
+	if sf.Type == hardwareAddrType {
+		if _, err = hardwareAddrLen(sf); err != nil {
+			return
+		}
+	} else if _, _, err = tagSize(sf); err != nil {
+		return
+	}
+	if _, _, err = stringPad(sf); err != nil {
+		return
+	}
+	if _, _, err = fieldPads(sf); err != nil {
+		return
+	}
+
+	if _, err = isColMajor(sf); err != nil {
+		return
+	}
+	if s := sf.Tag.Get("sizeof_field"); s != "" && s != "self" {
+		return fmt.Errorf("%w sizeof_field %q is not self", ErrTag, s)
+	}
+
+	if c := sf.Tag.Get("const"); c != "" {
+		if _, err = constValue(sf.Type, c); err != nil {
+			return
+		}
+	}
+	if d := sf.Tag.Get("enumdefault"); d != "" {
+		if err = parseInto(reflect.New(sf.Type).Elem(), d); err != nil {
+			return
+		}
+	}
+	if e := sf.Tag.Get("enum"); e != "" {
+		err = checkEnum(reflect.New(sf.Type).Elem(), e)
+		if errors.Is(err, ErrValidation) {
+			err = nil
+		}
+	}
+	return
+}
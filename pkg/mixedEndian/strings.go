@@ -0,0 +1,83 @@
+package mixedEndian
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// tagSize parses a field's size tag, reporting whether it was set
+func tagSize(sf reflect.StructField) (n int, ok bool, err error) {
+	s := sf.Tag.Get("size")
+	if s == "" {
+		return 0, false, nil
+	}
+	if n, err = strconv.Atoi(s); err != nil || n < 0 {
+		return 0, true, fmt.Errorf("%w size %q is not a length", ErrTag, s)
+	}
+	return n, true, nil
+}
+
+// stringPad is the padding trimmed from, and added to, a fixed size string field.
+// Fields are NUL padded unless tagged `trim:"space"` or `trim:"none"`.
+// The last keeps every byte read, and pads with NULs when written.
+func stringPad(sf reflect.StructField) (trim string, pad byte, err error) {
+	switch t := sf.Tag.Get("trim"); t {
+	case "", "null":
+		return "\x00", 0x00, nil
+	case "space":
+		return " ", ' ', nil
+	case "none":
+		return "", 0x00, nil
+	default:
+		return "", 0, fmt.Errorf("%w Unknown trim %q", ErrTag, t)
+	}
+}
+
+// readString reads a string field of the size given by its tag
+func (r *reader) readString(sf reflect.StructField, f reflect.Value) error {
+	n, ok, err := tagSize(sf)
+	if err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("%w string fields need a size tag", ErrTag)
+	}
+	trim, _, err := stringPad(sf)
+	if err != nil {
+		return err
+	}
+
+	bs := make([]byte, n)
+	if _, err = io.ReadFull(r.r, bs); err != nil {
+		return err
+	}
+	f.SetString(strings.TrimRight(string(bs), trim))
+	return nil
+}
+
+// writeString writes a string field padded out to the size given by its tag
+func (w *writer) writeString(sf reflect.StructField, f reflect.Value) error {
+	n, ok, err := tagSize(sf)
+	if err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("%w string fields need a size tag", ErrTag)
+	}
+	_, pad, err := stringPad(sf)
+	if err != nil {
+		return err
+	}
+
+	if f.Len() > n {
+		return fmt.Errorf("%w %q is longer than %d bytes", ErrLength, f.String(), n)
+	}
+	bs := make([]byte, n)
+	copy(bs, f.String())
+	for i := f.Len(); i < n; i++ {
+		bs[i] = pad
+	}
+	_, err = w.w.Write(bs)
+	return err
+}
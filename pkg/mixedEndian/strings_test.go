@@ -0,0 +1,104 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type NullTrimStruct struct {
+	S string `size:"8"`
+}
+
+type SpaceTrimStruct struct {
+	S string `size:"8" trim:"space"`
+}
+
+type NoTrimStruct struct {
+	S string `size:"8" trim:"none"`
+}
+
+func TestStringTrim(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     []byte
+		data      any
+		want      string
+		wantWrite []byte
+	}{
+		{
+			name:      "null",
+			input:     []byte{'a', 0x00, 'b', ' ', 0x00, 0x00, 0x00, 0x00},
+			data:      &NullTrimStruct{},
+			want:      "a\x00b ",
+			wantWrite: []byte{'a', 0x00, 'b', ' ', 0x00, 0x00, 0x00, 0x00},
+		},
+		{
+			name:      "space",
+			input:     []byte{'a', ' ', 'b', 0x00, ' ', ' ', ' ', ' '},
+			data:      &SpaceTrimStruct{},
+			want:      "a b\x00",
+			wantWrite: []byte{'a', ' ', 'b', 0x00, ' ', ' ', ' ', ' '},
+		},
+		{
+			name:      "none",
+			input:     []byte{'a', 0x00, 'b', ' ', 0x00, ' ', 0x00, 0x00},
+			data:      &NoTrimStruct{},
+			want:      "a\x00b \x00 \x00\x00",
+			wantWrite: []byte{'a', 0x00, 'b', ' ', 0x00, ' ', 0x00, 0x00},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Read(bytes.NewReader(tt.input), BigEndian, &tt.data); err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+
+			var got string
+			switch d := tt.data.(type) {
+			case *NullTrimStruct:
+				got = d.S
+			case *SpaceTrimStruct:
+				got = d.S
+			case *NoTrimStruct:
+				got = d.S
+			}
+			if got != tt.want {
+				t.Errorf("Read() = %q, wanted %q", got, tt.want)
+			}
+
+			buf := &bytes.Buffer{}
+			if err := Write(buf, BigEndian, tt.data); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if !bytes.Equal(buf.Bytes(), tt.wantWrite) {
+				t.Errorf("Write() = % X, wanted % X", buf.Bytes(), tt.wantWrite)
+			}
+		})
+	}
+}
+
+func TestStringErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    any
+		wantErr error
+	}{
+		{name: "too long", data: NullTrimStruct{S: "123456789"}, wantErr: ErrLength},
+		{name: "no size", data: struct{ S string }{}, wantErr: ErrTag},
+		{
+			name: "bad trim",
+			data: struct {
+				S string `size:"2" trim:"tabs"`
+			}{},
+			wantErr: ErrTag,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Write(&bytes.Buffer{}, BigEndian, tt.data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Write() error = %v, wanted %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package mixedEndian
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+// structOptions apply to a struct as a whole. They're set with tags on a blank,
+// zero sized marker field, conventionally the first:
+//
+//	type abc struct {
+//		_ struct{} `encoding:"packed"`
+//		a uint8
+//		b uint32
+//	}
+//
+// `align:"8"` pads each field to a multiple of its natural alignment, capped at 8, and the struct
+// to a multiple of 8. Unlike C's #pragma pack(8), the struct is padded to 8 even when none of its
+// fields need that much.
+type structOptions struct {
+	// packed suppresses all padding between fields, as with C's __attribute__((packed)).
+	// This is how fields are laid out unless alignment is requested.
+	packed bool
+
+	// align, when set, pads fields to their natural alignment up to align, and the struct to a
+	// multiple of align
+	align int
+
+	// requireAlignment asks debug builds to check the struct is laid out as C would lay it out.
+	// See ValidateAlignment.
+	requireAlignment bool
+
+	// iso8583 reads and writes fields tagged de only when a leading bitmap flags them, as ISO 8583
+	// messages do
+	iso8583 bool
+
+	// scopes are the field ranges whose encoded size a length field gives
+	scopes []lengthScope
+
+	// overlays are the groups of fields sharing a region of the wire
+	overlays []overlayGroup
+
+	// groups are the runs of fields DecodeGroup reads apart
+	groups []fieldGroup
+}
+
+// optionsOf collects the struct level options of t
+func optionsOf(t reflect.Type) (so structOptions, err error) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if s := sf.Tag.Get("lengthscope"); s != "" {
+			sc, err := parseLengthScope(t, i, s)
+			if err != nil {
+				return so, fmt.Errorf("%s: %w", sf.Name, err)
+			}
+			so.scopes = append(so.scopes, sc)
+		}
+		if sf.Name != "_" || sf.Type.Size() != 0 {
+			continue
+		}
+
+		switch e := sf.Tag.Get("encoding"); e {
+		case "":
+		case "packed":
+			so.packed = true
+		case "require_alignment":
+			so.requireAlignment = true
+		default:
+			return so, fmt.Errorf("%w Unknown struct encoding %q on %s", ErrTag, e, t.String())
+		}
+
+		if a := sf.Tag.Get("align"); a != "" {
+			n, err := strconv.Atoi(a)
+			if err != nil || n < 1 || n&(n-1) != 0 {
+				return so, fmt.Errorf("%w align %q on %s is not a power of 2", ErrTag, a, t.String())
+			}
+			so.align = n
+		}
+
+		switch m := sf.Tag.Get("iso8583"); m {
+		case "", "false":
+		case "true":
+			so.iso8583 = true
+		default:
+			return so, fmt.Errorf("%w iso8583 %q on %s is not a bool", ErrTag, m, t.String())
+		}
+	}
+
+	if so.packed && so.align > 0 {
+		return so, fmt.Errorf("%w %s can't be both packed and aligned", ErrTag, t.String())
+	}
+	if so.iso8583 && so.align > 0 {
+		return so, fmt.Errorf("%w %s can't be both ISO 8583 and aligned", ErrTag, t.String())
+	}
+	if len(so.scopes) > 0 && (so.align > 0 || so.iso8583) {
+		return so, fmt.Errorf("%w %s can't have length scopes and be aligned or ISO 8583", ErrTag, t.String())
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if err = checkRestLen(t, so, i); err != nil {
+			return so, fmt.Errorf("%s: %w", t.Field(i).Name, err)
+		}
+	}
+
+	if so.overlays, err = parseOverlays(t); err != nil {
+		return
+	}
+	if len(so.overlays) > 0 && (so.align > 0 || so.iso8583) {
+		return so, fmt.Errorf("%w %s can't have overlays and be aligned or ISO 8583", ErrTag, t.String())
+	}
+	if so.groups, err = parseGroups(t, so); err != nil {
+		return
+	}
+	return
+}
+
+// padding is the number of bytes from off to the next multiple of align
+func padding(off int64, align int) int64 {
+	return (int64(align) - off%int64(align)) % int64(align)
+}
+
+// fieldAlign is the alignment of sf within a struct aligned to align
+func fieldAlign(sf reflect.StructField, align int) int {
+	if a := naturalAlign(sf); a < align {
+		return a
+	}
+	return align
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(bs []byte) (int, error) {
+	n, err := c.r.Read(bs)
+	c.n += int64(n)
+	return n, err
+}
+
+// countingWriter counts the bytes written through it, noting the first error w gives. Writes
+// are retried until they're whole, so fields aren't cut short by a writer taking part of them.
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(bs []byte) (int, error) {
+	n, err := writeFull(c.w, bs)
+	c.n += int64(n)
+	if c.err == nil {
+		c.err = err
+	}
+	return n, err
+}
+
+// readCounted reads struct v counting bytes, to skip the padding an align option calls for,
+// to bound the fields of length scopes, and to note in spans, when given, where each field starts
+// and ends
+func (r *reader) readCounted(v reflect.Value, so structOptions, o binary.ByteOrder, spans [][2]int64) (err error) {
+	cr := &countingReader{r: r.r}
+	sub := *r
+	sub.r = cr
+
+	t := v.Type()
+	readAt := func(rd *reader, i int) (err error) {
+		start := cr.n
+		if f := v.Field(i); f.CanSet() && t.Field(i).Name != "_" {
+			if so.align > 0 {
+				if _, err = io.CopyN(io.Discard, rd.r, padding(cr.n, fieldAlign(t.Field(i), so.align))); err != nil {
+					return
+				}
+				start = cr.n
+			}
+			if err = rd.readFieldAt(v, so, i, o); err != nil {
+				return
+			}
+		}
+		if spans != nil {
+			spans[i] = [2]int64{start, cr.n}
+		}
+		return
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		sc, ok := so.scopeAt(i)
+		if !ok {
+			if err = readAt(&sub, i); err != nil {
+				return
+			}
+			continue
+		}
+
+		// Scoped fields can't read past the scope, and whatever they leave of it is skipped
+		var n int
+		if n, err = lengthOf(v, t.Field(sc.field).Name); err != nil {
+			return fmt.Errorf("%s: %w", t.Field(sc.field).Name, err)
+		}
+		lr := &io.LimitedReader{R: cr, N: int64(n)}
+		inner := sub
+		inner.r, inner.rest = lr, lr
+		for ; i <= sc.to; i++ {
+			if err = readAt(&inner, i); err != nil {
+				if lr.N == 0 {
+					return fmt.Errorf("%w %s's scope of %d bytes ends within %s: %v", ErrLength, t.Field(sc.field).Name, n, t.Field(i).Name, err)
+				}
+				return
+			}
+		}
+		i--
+		if _, err = io.CopyN(io.Discard, lr, lr.N); err == io.EOF {
+			return io.ErrUnexpectedEOF
+		} else if err != nil {
+			return
+		}
+	}
+
+	if spans != nil {
+		so.shareSpans(spans)
+	}
+	if so.align > 0 {
+		_, err = io.CopyN(io.Discard, cr, padding(cr.n, so.align))
+	}
+	return
+}
+
+// writeCounted writes struct v counting bytes, to write zeros for the padding an align option
+// calls for, and to note in spans, when given, where each field starts and ends
+func (w *writer) writeCounted(v reflect.Value, so structOptions, o binary.ByteOrder, spans [][2]int64) (err error) {
+	cw := &countingWriter{w: w.w}
+	sub := *w
+	sub.w = cw
+
+	zeros := make([]byte, so.align)
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		// Markers only carry struct options
+		if t.Field(i).Name == "_" && t.Field(i).Type.Size() == 0 {
+			if spans != nil {
+				spans[i] = [2]int64{cw.n, cw.n}
+			}
+			continue
+		}
+
+		if so.align > 0 {
+			if _, err = cw.Write(zeros[:padding(cw.n, fieldAlign(t.Field(i), so.align))]); err != nil {
+				return
+			}
+		}
+		start := cw.n
+		if err = sub.writeFieldAt(v, so, i, o); err != nil {
+			return
+		}
+		if spans != nil {
+			spans[i] = [2]int64{start, cw.n}
+		}
+	}
+
+	if spans != nil {
+		so.shareSpans(spans)
+	}
+	if so.align > 0 {
+		_, err = cw.Write(zeros[:padding(cw.n, so.align)])
+	}
+	return
+}
@@ -0,0 +1,168 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type PackedStruct struct {
+	_ struct{} `encoding:"packed"`
+	A uint8
+	B uint32
+	C uint16
+	D uint64
+}
+
+type UnknownEncodingStruct struct {
+	_ struct{} `encoding:"sparse"`
+	A uint8
+}
+
+func TestPacked(t *testing.T) {
+	wire := []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F}
+	want := &PackedStruct{A: 0x01, B: 0x02030405, C: 0x0607, D: 0x08090A0B0C0D0E0F}
+
+	var data any = &PackedStruct{}
+	if err := Read(bytes.NewReader(wire), BigEndian, &data); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("Read() data = %v, wanted %v", data, want)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, want); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+}
+
+func TestUnknownStructEncoding(t *testing.T) {
+	var data any = &UnknownEncodingStruct{}
+	if err := Read(bytes.NewReader([]byte{0x01}), BigEndian, &data); !errors.Is(err, ErrTag) {
+		t.Errorf("Read() error = %v, wanted %v", err, ErrTag)
+	}
+	if err := Write(&bytes.Buffer{}, BigEndian, UnknownEncodingStruct{}); !errors.Is(err, ErrTag) {
+		t.Errorf("Write() error = %v, wanted %v", err, ErrTag)
+	}
+}
+
+// AlignedStruct8 matches C's natural layout of
+//
+//	struct { uint8_t a; uint32_t b; uint16_t c; uint64_t d; uint8_t e; };
+//
+// with b at 4, c at 8, d at 16, e at 24, and 32 bytes in all
+type AlignedStruct8 struct {
+	_ struct{} `align:"8"`
+	A uint8
+	B uint32
+	C uint16
+	D uint64
+	E uint8
+}
+
+type PackedAlignedStruct struct {
+	_ struct{} `encoding:"packed" align:"8"`
+	A uint8
+}
+
+func TestAlign(t *testing.T) {
+	wire := []byte{
+		0x01, 0x00, 0x00, 0x00, 0x02, 0x03, 0x04, 0x05,
+		0x06, 0x07, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F,
+		0x10, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	}
+	want := &AlignedStruct8{A: 0x01, B: 0x02030405, C: 0x0607, D: 0x08090A0B0C0D0E0F, E: 0x10}
+
+	var data any = &AlignedStruct8{}
+	r := bytes.NewReader(append(wire, 0xFF))
+	if err := Read(r, BigEndian, &data); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("Read() data = %v, wanted %v", data, want)
+	}
+	if r.Len() != 1 {
+		t.Errorf("Read() left %d bytes, wanted 1", r.Len())
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, want); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+
+	sl, err := Describe(want)
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	var offsets []int
+	for _, fl := range sl.Fields {
+		offsets = append(offsets, fl.Offset)
+	}
+	if wantOffsets := []int{0, 4, 8, 16, 24}; sl.Size != len(wire) || !reflect.DeepEqual(offsets, wantOffsets) {
+		t.Errorf("Describe() = %d bytes at %v, wanted %d at %v", sl.Size, offsets, len(wire), wantOffsets)
+	}
+	if err := ValidateAlignment(reflect.TypeOf(want)); err != nil {
+		t.Errorf("ValidateAlignment() error = %v", err)
+	}
+
+	fe, err := NewFieldEncoder(BigEndian, want)
+	if err != nil {
+		t.Fatalf("NewFieldEncoder() error = %v", err)
+	}
+	var got []byte
+	for {
+		bs, ok, err := fe.Next()
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		} else if !ok {
+			break
+		}
+		got = append(got, bs...)
+	}
+	if !bytes.Equal(got, wire) {
+		t.Errorf("Next() = % X, wanted % X", got, wire)
+	}
+
+	if err := Write(&bytes.Buffer{}, BigEndian, PackedAlignedStruct{}); !errors.Is(err, ErrTag) {
+		t.Errorf("Write() error = %v, wanted %v", err, ErrTag)
+	}
+}
+
+func TestAlignCap(t *testing.T) {
+	// Fields are aligned no further than the option, and the struct to it, whatever its fields
+	capped := struct {
+		_ struct{} `align:"4"`
+		A uint8
+		B uint64
+	}{A: 0x01, B: 0x0203040506070809}
+	small := struct {
+		_ struct{} `align:"8"`
+		A uint8
+	}{A: 0x01}
+
+	tests := []struct {
+		name string
+		data any
+		want []byte
+	}{
+		{name: "field capped", data: capped, want: []byte{0x01, 0x00, 0x00, 0x00, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09}},
+		{name: "struct padded", data: small, want: []byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Marshal(BigEndian, tt.data)
+			if err != nil || !bytes.Equal(got, tt.want) {
+				t.Errorf("Marshal() = % X, %v, wanted % X", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package mixedEndian
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// StructGen generates a random T from seed, the same for the same seed, for property based tests
+// and fuzz corpora. Integers are random within their type's bounds, or those of `minvalue` and
+// `maxvalue` tags, which only StructGen reads; floats, bools, and strings are random; pointers are
+// never nil; slices and maps hold 0 to 10 elements. Unexported fields are left zero.
+//
+// Fields follow their tags where that's needed for T to round trip through Write and Read: const
+// and mirror fields match what's written, enums take one of their values, bitwidth fields fit,
+// strings fit their size, counted slices have their count, and len fields hold their slice's
+// length. Optional fields are zero unless flagged. Slices without a length tag, which Read can't
+// size, are nil when empty. Tags changing how a value's stored, such as dur or norm, may lose
+// precision the value was generated with.
+//
+// StructGen panics if T's tags are malformed.
+func StructGen[T any](seed int64) T {
+	var v T
+	g := generator{rand.New(rand.NewSource(seed))}
+	g.fill(reflect.ValueOf(&v).Elem(), reflect.StructField{})
+	return v
+}
+
+// generator fills values for StructGen
+type generator struct {
+	r *rand.Rand
+}
+
+// genMaxLen is the most elements StructGen puts in a slice or map, or characters in a string
+const genMaxLen = 10
+
+// genChars are those strings are made of, surviving any trim and packed encoding
+const genChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+// fill sets v to a random value, following the tags of sf, the field holding it
+func (g generator) fill(v reflect.Value, sf reflect.StructField) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		v.Set(reflect.New(v.Type().Elem()))
+		g.fill(v.Elem(), sf)
+
+	case reflect.Bool:
+		v.SetBool(g.r.Intn(2) == 1)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		g.integer(v, sf)
+
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(g.r.NormFloat64() * 1e6)
+
+	case reflect.String:
+		n := g.r.Intn(genMaxLen + 1)
+		if size, ok, err := tagSize(sf); err != nil {
+			panic(err)
+		} else if ok && sf.Tag.Get("trim") == "none" {
+			// Untrimmed strings read back their padding too
+			n = size
+		} else if ok {
+			n = g.r.Intn(size + 1)
+		}
+		bs := make([]byte, n)
+		for i := range bs {
+			bs[i] = genChars[g.r.Intn(len(genChars))]
+		}
+		v.SetString(string(bs))
+
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			g.fill(v.Index(i), sf)
+		}
+
+	case reflect.Slice:
+		n, sized := g.length(sf)
+		if v.Type() == hardwareAddrType {
+			var err error
+			if n, err = hardwareAddrLen(sf); err != nil {
+				panic(err)
+			}
+			sized = true
+		}
+		if n == 0 && !sized {
+			return
+		}
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+		for i := 0; i < n; i++ {
+			g.fill(v.Index(i), sf)
+		}
+
+	case reflect.Map:
+		n, _ := g.length(sf)
+		v.Set(reflect.MakeMapWithSize(v.Type(), n))
+		for i := 0; i < n; i++ {
+			k, e := reflect.New(v.Type().Key()).Elem(), reflect.New(v.Type().Elem()).Elem()
+			g.fill(k, reflect.StructField{})
+			g.fill(e, sf)
+			v.SetMapIndex(k, e)
+		}
+
+	case reflect.Struct:
+		g.fillStruct(v)
+	}
+}
+
+// fillStruct fills the exported fields of struct v, then brings them in line with each other
+func (g generator) fillStruct(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() || sf.Name == "_" {
+			continue
+		}
+		f := v.Field(i)
+
+		switch {
+		case sf.Tag.Get("const") != "":
+			c, err := constValue(sf.Type, sf.Tag.Get("const"))
+			if err != nil {
+				panic(err)
+			}
+			f.Set(c)
+		case sf.Tag.Get("mirror") != "":
+			src, err := mirrorOf(v, sf, sf.Tag.Get("mirror"))
+			if err != nil {
+				panic(err)
+			}
+			f.Set(src)
+		default:
+			g.fill(f, sf)
+		}
+
+		// Counts are what Read sizes by, so must match what's generated
+		ref := sf.Tag.Get("len")
+		if ref == "" {
+			ref = sf.Tag.Get("countfrom")
+		}
+		if ref != "" && (f.Kind() == reflect.Slice || f.Kind() == reflect.Map) {
+			switch n := v.FieldByName(ref); n.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				n.SetInt(int64(f.Len()))
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				n.SetUint(uint64(f.Len()))
+			}
+		}
+
+		// Unflagged fields that aren't zero would be written, setting their flag
+		if p := sf.Tag.Get("presentif"); p != "" {
+			flag, err := presenceFlag(v, sf, p)
+			if err != nil {
+				panic(err)
+			}
+			if !flag.Bool() {
+				f.Set(reflect.Zero(f.Type()))
+			}
+		}
+	}
+}
+
+// length is the number of elements to put in a slice or map tagged as sf, reporting whether Read
+// could size it
+func (g generator) length(sf reflect.StructField) (int, bool) {
+	if n, _, ok, err := literalCount(sf); err != nil {
+		panic(err)
+	} else if ok {
+		return n, true
+	}
+	return g.r.Intn(genMaxLen + 1), sf.Tag.Get("len") != "" || sf.Tag.Get("countfrom") != "" || sf.Tag.Get("lenprefix") != ""
+}
+
+// integer sets integer v to a random value fitting its type, bitwidth, enum, minvalue, and
+// maxvalue tags
+func (g generator) integer(v reflect.Value, sf reflect.StructField) {
+	if enum := sf.Tag.Get("enum"); enum != "" {
+		options := strings.Split(enum, ",")
+		if err := parseInto(v, options[g.r.Intn(len(options))]); err != nil {
+			panic(err)
+		}
+		return
+	}
+
+	bits := 8 * typeSize(v.Type())
+	if bits == 0 {
+		bits = v.Type().Bits()
+	}
+	if s := sf.Tag.Get("bitwidth"); s != "" {
+		var err error
+		if bits, err = strconv.Atoi(s); err != nil {
+			panic(err)
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		hi := int64(uint64(1)<<(bits-1) - 1)
+		lo := -hi - 1
+		if b, ok := genBound(v, sf, "minvalue"); ok {
+			lo = b.Int()
+		}
+		if b, ok := genBound(v, sf, "maxvalue"); ok {
+			hi = b.Int()
+		}
+		if lo > hi {
+			panic(fmt.Errorf("%w minvalue %d is above maxvalue %d", ErrTag, lo, hi))
+		}
+		v.SetInt(lo + int64(g.span(uint64(hi-lo))))
+	default:
+		lo, hi := uint64(0), uint64(1)<<(bits-1)<<1-1
+		if b, ok := genBound(v, sf, "minvalue"); ok {
+			lo = b.Uint()
+		}
+		if b, ok := genBound(v, sf, "maxvalue"); ok {
+			hi = b.Uint()
+		}
+		if lo > hi {
+			panic(fmt.Errorf("%w minvalue %d is above maxvalue %d", ErrTag, lo, hi))
+		}
+		v.SetUint(lo + g.span(hi-lo))
+	}
+}
+
+// span is a random number from 0 to n
+func (g generator) span(n uint64) uint64 {
+	if n == 1<<64-1 {
+		return g.r.Uint64()
+	}
+	return g.r.Uint64() % (n + 1)
+}
+
+// genBound parses the value of sf's key tag as the type of integer v, reporting whether it's set
+func genBound(v reflect.Value, sf reflect.StructField, key string) (reflect.Value, bool) {
+	s := sf.Tag.Get(key)
+	if s == "" {
+		return v, false
+	}
+	b := reflect.New(v.Type()).Elem()
+	if err := parseInto(b, s); err != nil {
+		panic(err)
+	}
+	return b, true
+}
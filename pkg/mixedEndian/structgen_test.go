@@ -0,0 +1,108 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+type GenInner struct {
+	A int16
+	B [2]uint8 `endian:"little"`
+}
+
+type GenStruct struct {
+	Magic   [2]byte `const:"0x4D,0x45"`
+	Kind    uint8   `enum:"1,2,7"`
+	Percent uint8   `minvalue:"0" maxvalue:"100"`
+	Offset  int32   `minvalue:"-5" maxvalue:"5"`
+	Flags   uint16  `bitwidth:"12"`
+	Odd     Int24
+	Wide    Uint128
+	Ok      bool
+	Name    string `size:"8"`
+	Pad     string `size:"4" trim:"none"`
+	Count   uint8
+	Items   []GenInner `len:"Count"`
+	Fixed   []uint32   `count:"3"`
+	Inner   *GenInner
+	HasExt  bool
+	Ext     uint32 `presentif:"HasExt"`
+	Copy    uint8  `mirror:"Kind"`
+	Entries uint16
+	Table   map[uint8]int16 `len:"Entries"`
+}
+
+func TestStructGenRoundTrip(t *testing.T) {
+	for seed := int64(0); seed < 200; seed++ {
+		want := StructGen[GenStruct](seed)
+
+		bs, err := Marshal(BigEndian, want)
+		if err != nil {
+			t.Fatalf("seed %d: Marshal() error = %v", seed, err)
+		}
+		var data any = &GenStruct{}
+		if err = Read(bytes.NewReader(bs), BigEndian, &data); err != nil {
+			t.Fatalf("seed %d: Read() error = %v", seed, err)
+		}
+		if got := *data.(*GenStruct); !reflect.DeepEqual(got, want) {
+			t.Errorf("seed %d: Read() = %+v, wanted %+v", seed, got, want)
+		}
+	}
+}
+
+func TestStructGenTags(t *testing.T) {
+	kinds := map[uint8]bool{}
+	for seed := int64(0); seed < 200; seed++ {
+		v := StructGen[GenStruct](seed)
+		kinds[v.Kind] = true
+		if v.Kind != 1 && v.Kind != 2 && v.Kind != 7 {
+			t.Errorf("seed %d: Kind = %d, wanted one of its enum", seed, v.Kind)
+		}
+		if v.Percent > 100 {
+			t.Errorf("seed %d: Percent = %d, wanted at most 100", seed, v.Percent)
+		}
+		if v.Offset < -5 || v.Offset > 5 {
+			t.Errorf("seed %d: Offset = %d, wanted -5 to 5", seed, v.Offset)
+		}
+		if v.Flags >= 1<<12 {
+			t.Errorf("seed %d: Flags = %#x, wanted 12 bits", seed, v.Flags)
+		}
+		if len(v.Name) > 8 || len(v.Pad) != 4 {
+			t.Errorf("seed %d: Name = %q, Pad = %q, wanted them to fit their sizes", seed, v.Name, v.Pad)
+		}
+		if int(v.Count) != len(v.Items) || len(v.Items) > 10 || len(v.Fixed) != 3 || int(v.Entries) != len(v.Table) {
+			t.Errorf("seed %d: Count = %d, Items = %d, Fixed = %d, Entries = %d, Table = %d, wanted matching lengths",
+				seed, v.Count, len(v.Items), len(v.Fixed), v.Entries, len(v.Table))
+		}
+		if v.Inner == nil {
+			t.Errorf("seed %d: Inner = nil, wanted a pointer", seed)
+		}
+		if !v.HasExt && v.Ext != 0 {
+			t.Errorf("seed %d: Ext = %d, wanted zero as it isn't flagged", seed, v.Ext)
+		}
+	}
+	if len(kinds) != 3 {
+		t.Errorf("StructGen() Kinds = %v, wanted every enum value", kinds)
+	}
+}
+
+func TestStructGenSeed(t *testing.T) {
+	if a, b := StructGen[GenStruct](42), StructGen[GenStruct](42); !reflect.DeepEqual(a, b) {
+		t.Errorf("StructGen(42) = %+v then %+v, wanted the same", a, b)
+	}
+	if a, b := StructGen[GenStruct](1), StructGen[GenStruct](2); reflect.DeepEqual(a, b) {
+		t.Errorf("StructGen(1) = StructGen(2) = %+v, wanted them to differ", a)
+	}
+}
+
+func TestStructGenBadTag(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("StructGen() didn't panic, wanted it to with minvalue above maxvalue")
+		}
+	}()
+	StructGen[struct {
+		A uint8 `minvalue:"9" maxvalue:"3"`
+	}](0)
+}
@@ -0,0 +1,6 @@
+//go:build !network_order_swap
+
+package mixedEndian
+
+// orderSwapped is set by the network_order_swap build tag
+const orderSwapped = false
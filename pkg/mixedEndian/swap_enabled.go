@@ -0,0 +1,10 @@
+//go:build network_order_swap
+
+package mixedEndian
+
+// orderSwapped is set by the network_order_swap build tag.
+//
+// With it, every byte order conversion in the package is inverted, big for little and little for
+// big, so code tested on a host of one endianness exercises the byte swapping it would need on the
+// other. This is for testing only; data written under the tag is not readable without it.
+const orderSwapped = true
@@ -0,0 +1,51 @@
+//go:build network_order_swap
+
+package mixedEndian
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func TestNetworkOrderSwap(t *testing.T) {
+	data := struct {
+		A uint16 `endian:"big"`
+		B uint32 `endian:"little"`
+		C Int24
+	}{A: 0x0102, B: 0x03040506, C: -2}
+	wire := []byte{0x02, 0x01, 0x03, 0x04, 0x05, 0x06, 0xFE, 0xFF, 0xFF}
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, data); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+
+	var got any = &struct {
+		A uint16 `endian:"big"`
+		B uint32 `endian:"little"`
+		C Int24
+	}{}
+	if err := Read(bytes.NewReader(wire), BigEndian, &got); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(reflect.ValueOf(got).Elem().Interface(), data) {
+		t.Errorf("Read() data = %v, wanted %v", got, data)
+	}
+}
+
+func TestNetworkOrderSwapCompressed(t *testing.T) {
+	// Compressed blocks' lengths are swapped with everything else
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, CompressedStruct{Body: []byte("abc")}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	body, _ := gzipCompressor{}.Compress([]byte("abc"))
+	if n := binary.LittleEndian.Uint32(buf.Bytes()[1:]); int(n) != len(body) {
+		t.Errorf("Write() Body length = %d, wanted %d", n, len(body))
+	}
+}
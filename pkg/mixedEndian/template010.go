@@ -0,0 +1,293 @@
+package mixedEndian
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ExportTemplate010 writes an 010 Editor binary template (.bt) mirroring the layout of sample.
+//
+// Each struct type becomes a typedef, fields switch byte order with BigEndian() or LittleEndian()
+// where tagged, and arrays use their count or len field. Untagged fields use whatever byte order
+// is in effect when the template runs, matching the default order given to Read.
+// Constructs the template can't express are left as commented placeholders.
+func ExportTemplate010(w io.Writer, sample any) error {
+	sl, err := Describe(sample)
+	if err != nil {
+		return err
+	}
+
+	t := &template010{
+		w:     bufio.NewWriter(w),
+		names: map[reflect.Type]string{},
+	}
+	fmt.Fprintf(t.w, "// 010 Editor binary template for %s, generated by mixedEndian\n\n", sl.Type.String())
+	fmt.Fprintf(t.w, "void SetEndian(int big) {\n\tif (big) BigEndian(); else LittleEndian();\n}\n\n")
+
+	name := t.declare(sl)
+	fmt.Fprintf(t.w, "%s file;\n", name)
+
+	return t.w.Flush()
+}
+
+type template010 struct {
+	w     *bufio.Writer
+	names map[reflect.Type]string
+}
+
+// declare writes typedefs for sl and the structs it contains, returning its template name
+func (t *template010) declare(sl *StructLayout) string {
+	if name, ok := t.names[sl.Type]; ok {
+		return name
+	}
+
+	name := sl.Type.Name()
+	if name == "" {
+		name = fmt.Sprintf("anon%d", len(t.names))
+	}
+	t.names[sl.Type] = name
+
+	// Nested structs are declared first
+	for _, f := range sl.Fields {
+		if f.Elem != nil {
+			t.declare(f.Elem)
+		}
+	}
+
+	fmt.Fprintf(t.w, "typedef struct {\n")
+	fmt.Fprintf(t.w, "\tlocal int defaultBig = IsBigEndian();\n")
+
+	// ISO 8583 bitmaps precede the first data element
+	so, _ := optionsOf(sl.Type)
+	bitmap := !so.iso8583
+
+	// Overlay groups are unions, whose members are indented within them
+	indent := "\t"
+	overlay := func(j int) string {
+		if j < 0 || j >= len(sl.Fields) {
+			return ""
+		}
+		g, _, _ := strings.Cut(sl.Fields[j].Tag.Get("overlay"), ",")
+		return g
+	}
+
+	// Track the byte order in effect to only switch when needed
+	current := "default"
+	switchTo := func(want string) {
+		if want == current {
+			return
+		}
+		switch want {
+		case "big":
+			fmt.Fprintf(t.w, "%sBigEndian();\n", indent)
+		case "little":
+			fmt.Fprintf(t.w, "%sLittleEndian();\n", indent)
+		default:
+			fmt.Fprintf(t.w, "%sSetEndian(defaultBig);\n", indent)
+		}
+		current = want
+	}
+
+	for j, f := range sl.Fields {
+		if g := overlay(j); g != "" && g != overlay(j-1) {
+			fmt.Fprintf(t.w, "\tunion {\n")
+			indent = "\t\t"
+		}
+
+		de := f.Tag.Get("de")
+		if de != "" && !bitmap {
+			fmt.Fprintf(t.w, "\tubyte Bitmap[(ReadUByte(FTell()) & 0x80) ? 16 : 8];\n")
+			bitmap = true
+		}
+
+		// Only tags on the field itself count, as typedefs are shared between uses
+		e := f.Tag.Get("endian")
+		if e != "big" && e != "little" {
+			e = "default"
+		}
+
+		// Length prefixes are declared as fields of their own, in their own byte order
+		if p, ok, _ := lenPrefixOf(reflect.StructField{Type: f.Type, Tag: f.Tag}); ok && f.Type.Kind() == reflect.Slice {
+			_, order, _ := strings.Cut(f.Tag.Get("lenprefix"), ",")
+			if order == "" {
+				order = e
+			}
+			switchTo(order)
+			name, _ := type010(p.t)
+			fmt.Fprintf(t.w, "%s%s %sLen; // length prefix of %s\n", indent, name, f.Name, f.Name)
+		}
+
+		if e != "default" || f.Elem != nil || f.Size != 1 {
+			switchTo(e)
+		}
+
+		line := t.field(sl, f)
+		cond := ""
+		if p := f.Tag.Get("presentif"); p != "" && !strings.HasPrefix(line, "//") {
+			cond = "if (" + p + ") "
+		}
+		if n, err := strconv.Atoi(de); err == nil && so.iso8583 && !strings.HasPrefix(line, "//") {
+			cond = fmt.Sprintf("if (Bitmap[%d] & 0x%02X) %s", (n-1)/8, 0x80>>((n-1)%8), cond)
+		}
+
+		// Padding tags skip bytes either side of the field, whenever it's there
+		sf := reflect.StructField{Type: f.Type, Tag: f.Tag}
+		if n, _, _ := fieldPad(sf, "padding_before"); n > 0 {
+			fmt.Fprintf(t.w, "%s%sFSkip(%d); // padding before %s\n", indent, cond, n, f.Name)
+		}
+		fmt.Fprintf(t.w, "%s%s%s\n", indent, cond, line)
+		if n, _, _ := fieldPad(sf, "padding_after"); n > 0 {
+			fmt.Fprintf(t.w, "%s%sFSkip(%d); // padding after %s\n", indent, cond, n, f.Name)
+		}
+
+		if g := overlay(j); g != "" && g != overlay(j+1) {
+			fmt.Fprintf(t.w, "\t} %s;\n", g)
+			indent = "\t"
+		}
+	}
+	if current != "default" {
+		fmt.Fprintf(t.w, "\tSetEndian(defaultBig);\n")
+	}
+	fmt.Fprintf(t.w, "} %s;\n\n", name)
+
+	return name
+}
+
+// field renders a single field declaration
+func (t *template010) field(sl *StructLayout, f FieldLayout) string {
+	path := sl.Type.Name() + "." + f.Name
+	if sl.Type.Name() == "" {
+		path = f.Path
+	}
+
+	typ := f.Type
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if f.Tag.Get("rle") != "" {
+		return fmt.Sprintf("// %s %s: run length encoded, not expressible", f.Name, typ.String())
+	}
+	if f.Type == serializableType {
+		return fmt.Sprintf("// %s: not on the wire", f.Name)
+	}
+	if f.Tag.Get("raw") != "" {
+		return fmt.Sprintf("// %s %s: raw bytes of %s, not on the wire", f.Name, typ.String(), f.Tag.Get("raw"))
+	}
+	if typ.Kind() == reflect.Map {
+		return fmt.Sprintf("// %s %s: map, not expressible", f.Name, typ.String())
+	}
+	if s := f.Tag.Get("ssh"); s != "" {
+		return fmt.Sprintf("// %s %s: ssh %s, not expressible", f.Name, typ.String(), s)
+	}
+	if s := f.Tag.Get("compress"); s != "" {
+		return fmt.Sprintf("// %s %s: %s compressed, not expressible", f.Name, typ.String(), s)
+	}
+	if isVarint(reflect.StructField{Tag: f.Tag}) {
+		return fmt.Sprintf("// %s %s: %s encoded, not expressible", f.Name, typ.String(), f.Tag.Get("encoding"))
+	}
+
+	elem := typ
+	count := ""
+	if sf := (reflect.StructField{Type: f.Type, Tag: f.Tag}); isDuration(sf) {
+		// Durations are stored as plain integers
+		_, elem, _ = durationFormat(sf)
+	} else if isEpoch(sf) {
+		// Times are stored as plain integers
+		_, _, elem, _ = epochFormat(sf)
+	} else if isDecimal(sf) {
+		// Decimals have no native type, so are shown as their bits
+		df, _ := decimalFormatOf(sf)
+		elem = uintTypes[fmt.Sprintf("uint%d", df.bits)]
+		path += ", " + sf.Tag.Get("floatfmt")
+	} else if typ == hardwareAddrType || typ.Kind() == reflect.String {
+		elem = typ
+		count = fmt.Sprintf("[%d]", f.Count)
+	} else if typ.Kind() == reflect.Array || typ.Kind() == reflect.Slice {
+		elem = typ.Elem()
+		switch {
+		case f.Count >= 0:
+			count = fmt.Sprintf("[%d]", f.Count)
+		case f.Tag.Get("lenprefix") != "":
+			count = fmt.Sprintf("[%sLen]", f.Name)
+		case f.CountRef != "":
+			for _, ref := range sl.Fields {
+				if _, raw := type010(ref.Type); ref.Name == f.CountRef && raw > 0 {
+					return fmt.Sprintf("// %s %s: counted by %s, which has no native type", f.Name, typ.String(), f.CountRef)
+				}
+			}
+			count = fmt.Sprintf("[%s]", f.CountRef)
+		default:
+			return fmt.Sprintf("// %s %s: variable length, not expressible", f.Name, typ.String())
+		}
+
+		// 010 Editor arrays have a single dimension, so nested arrays are flattened into it
+		for ; elem.Kind() == reflect.Array; elem = elem.Elem() {
+			count = fmt.Sprintf("%s*%d]", strings.TrimSuffix(count, "]"), elem.Len())
+		}
+	}
+
+	if sf := (reflect.StructField{Type: f.Type, Tag: f.Tag}); isNorm(sf) {
+		// Normalized floats are stored as plain integers
+		elem, _, _ = normFormat(sf)
+		path += ", " + sf.Tag.Get("norm")
+	} else if isPCM(sf) {
+		// Companded samples are shown as their codes
+		elem = reflect.TypeOf(uint8(0))
+		path += ", " + sf.Tag.Get("pcm")
+	} else if elem == timecodeType {
+		// Timecodes are shown as the integer holding their BCD digits
+		elem = reflect.TypeOf(uint32(0))
+		path += ", BCD timecode"
+	}
+
+	name, raw := "", 0
+	if f.Elem != nil {
+		name = t.names[f.Elem.Type]
+	} else if name, raw = type010(elem); raw > 0 {
+		// No native type, so lay elements out as raw bytes
+		if count == "" {
+			count = fmt.Sprintf("[%d]", raw)
+		} else {
+			count = fmt.Sprintf("%s*%d]", strings.TrimSuffix(count, "]"), raw)
+		}
+		path += ", " + elem.Name() + " as raw bytes"
+	}
+
+	return fmt.Sprintf("%s %s%s; // %s", name, f.Name, count, path)
+}
+
+// type010 maps a base type to its 010 Editor equivalent.
+// Types without one are given as ubyte along with their width in bytes.
+func type010(t reflect.Type) (name string, raw int) {
+	switch t {
+	case uint24Type, int24Type, uint48Type, int48Type, uint128Type, int128Type:
+		return "ubyte", typeSize(t)
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "char", 0
+	case reflect.Int8:
+		return "byte", 0
+	case reflect.Int16:
+		return "int16", 0
+	case reflect.Uint16:
+		return "uint16", 0
+	case reflect.Int32:
+		return "int32", 0
+	case reflect.Uint32:
+		return "uint32", 0
+	case reflect.Int64:
+		return "int64", 0
+	case reflect.Uint64:
+		return "uint64", 0
+	default:
+		// bool and uint8
+		return "ubyte", 0
+	}
+}
@@ -0,0 +1,61 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// checkGolden compares got against testdata/name, rewriting it under -update
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	golden := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output =\n%s\nwanted\n%s", got, want)
+	}
+}
+
+func TestExportTemplate010(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample any
+	}{
+		{name: "NoTagStruct", sample: NoTagStruct{}},
+		{name: "NestedStruct", sample: NestedStruct{}},
+		{name: "OddWidthStruct", sample: &OddWidthStruct{}},
+		{name: "OddWidthSliceStruct", sample: OddWidthSliceStruct{}},
+		{name: "EthernetHeader", sample: EthernetHeader{}},
+		{name: "ValidatedStruct", sample: ValidatedStruct{}},
+		{name: "PaddedStruct", sample: PaddedStruct{}},
+		{name: "NestedArrayStruct", sample: NestedArrayStruct{}},
+		{
+			name: "Unbounded",
+			sample: struct {
+				A uint8
+				B []uint16
+			}{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			if err := ExportTemplate010(buf, tt.sample); err != nil {
+				t.Fatalf("ExportTemplate010() error = %v", err)
+			}
+			checkGolden(t, tt.name+".bt", buf.Bytes())
+		})
+	}
+}
@@ -0,0 +1,18 @@
+// C declarations for AlignedStruct8, generated by mixedEndian
+
+#include <stdint.h>
+
+#pragma pack(push, 1)
+
+typedef struct AlignedStruct8 {
+	uint8_t A;
+	uint8_t _pad0[3];
+	uint32_t B; // default byte order
+	uint16_t C; // default byte order
+	uint8_t _pad1[6];
+	uint64_t D; // default byte order
+	uint8_t E;
+	uint8_t _pad2[7];
+} AlignedStruct8;
+
+#pragma pack(pop)
@@ -0,0 +1,18 @@
+// C declarations for EthernetHeader, ValidatedStruct, generated by mixedEndian
+
+#include <stdint.h>
+
+#pragma pack(push, 1)
+
+typedef struct EthernetHeader {
+	uint8_t Dst[6];
+	uint8_t Src[6];
+	uint16_t Type; // default byte order
+} EthernetHeader;
+
+typedef struct ValidatedStruct {
+	uint8_t Magic[3];
+	uint8_t Kind;
+} ValidatedStruct;
+
+#pragma pack(pop)
@@ -0,0 +1,19 @@
+// C declarations for anon0, generated by mixedEndian
+
+#include <stdint.h>
+
+#pragma pack(push, 1)
+
+typedef struct GenInner {
+	int16_t A; // default byte order
+	uint8_t B[2];
+} GenInner;
+
+typedef struct anon0 {
+	uint8_t Count;
+	uint16_t Pad[3]; // little endian
+	uint16_t ItemsLen; // length prefix of Items, big endian
+	GenInner Items[];
+} anon0;
+
+#pragma pack(pop)
@@ -0,0 +1,18 @@
+// C declarations for NestedArrayStruct, generated by mixedEndian
+
+#include <stdint.h>
+
+#pragma pack(push, 1)
+
+typedef struct GenInner {
+	int16_t A; // default byte order
+	uint8_t B[2];
+} GenInner;
+
+typedef struct NestedArrayStruct {
+	uint16_t Grid[2][3]; // default byte order
+	uint8_t Colors[2][4];
+	GenInner Cells[2][2];
+} NestedArrayStruct;
+
+#pragma pack(pop)
@@ -0,0 +1,18 @@
+// C declarations for NestedStruct, generated by mixedEndian
+
+#include <stdint.h>
+
+#pragma pack(push, 1)
+
+typedef struct TaggedStruct {
+	uint16_t A; // big endian
+	uint16_t B; // little endian
+} TaggedStruct;
+
+typedef struct NestedStruct {
+	uint16_t A; // big endian
+	TaggedStruct B;
+	uint16_t C; // little endian
+} NestedStruct;
+
+#pragma pack(pop)
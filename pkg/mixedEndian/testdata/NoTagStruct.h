@@ -0,0 +1,13 @@
+// C declarations for NoTagStruct, generated by mixedEndian
+
+#include <stdint.h>
+
+#pragma pack(push, 1)
+
+typedef struct NoTagStruct {
+	uint8_t A;
+	int16_t B; // default byte order
+	uint32_t C; // default byte order
+} NoTagStruct;
+
+#pragma pack(pop)
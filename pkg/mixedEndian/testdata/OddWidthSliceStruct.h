@@ -0,0 +1,12 @@
+// C declarations for OddWidthSliceStruct, generated by mixedEndian
+
+#include <stdint.h>
+
+#pragma pack(push, 1)
+
+typedef struct OddWidthSliceStruct {
+	uint8_t N[3]; // default byte order, Uint24 as raw bytes
+	uint8_t Data[][3]; // count in N, default byte order, Int24 as raw bytes
+} OddWidthSliceStruct;
+
+#pragma pack(pop)
@@ -0,0 +1,14 @@
+// C declarations for OddWidthStruct, generated by mixedEndian
+
+#include <stdint.h>
+
+#pragma pack(push, 1)
+
+typedef struct OddWidthStruct {
+	uint8_t A[3]; // default byte order, Uint24 as raw bytes
+	uint8_t B[3]; // little endian, Int24 as raw bytes
+	uint8_t C[6]; // default byte order, Uint48 as raw bytes
+	uint8_t D[6]; // little endian, Int48 as raw bytes
+} OddWidthStruct;
+
+#pragma pack(pop)
@@ -0,0 +1,17 @@
+// C declarations for OverlayRecord, generated by mixedEndian
+
+#include <stdint.h>
+
+#pragma pack(push, 1)
+
+typedef struct OverlayRecord {
+	uint8_t Kind;
+	union {
+		uint64_t Word; // default byte order
+		uint32_t Pair[2]; // little endian
+		uint16_t Short; // default byte order
+	} value;
+	uint8_t Tail;
+} OverlayRecord;
+
+#pragma pack(pop)
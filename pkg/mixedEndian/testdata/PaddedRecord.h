@@ -0,0 +1,14 @@
+// C declarations for anon0, generated by mixedEndian
+
+#include <stdint.h>
+
+#pragma pack(push, 1)
+
+typedef struct anon0 {
+	uint8_t Kind;
+	uint8_t _pad0[3];
+	uint16_t Length; // default byte order
+	uint8_t _pad1[2];
+} anon0;
+
+#pragma pack(pop)
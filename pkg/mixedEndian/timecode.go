@@ -0,0 +1,207 @@
+package mixedEndian
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Timecode is an SMPTE 12M timecode, of hours, minutes, seconds, and frames.
+//
+// On the wire it's the 32 bits of time SMPTE 12M's LTC and VITC carry, without their user bits:
+// a uint32, in the field's byte order, of BCD digits HHMMSSFF, as 0x01020304 holds 01:02:03:04.
+// The bits the digits' tens leave spare hold flags. By default the drop frame flag is bit 6, the
+// colour frame flag bit 7, and the field mark bit 15, as SMPTE 12M has them for 30 frame
+// timecode, so little endian fields are laid out as SMPTE 331M's packed timecode is. A tag such
+// as `timecode:"df=6,cf=7,fm=23"` moves them, 25 frame timecode keeping its field mark in bit 23,
+// and `timecode:"cf=-"` leaves a flag out, its bit always 0. Flags may only take bits 6, 7, 15,
+// 23, 30, and 31, and spare bits no flag takes are ignored when read and written as 0. Arrays
+// and slices of Timecodes take the default layout.
+//
+// Digits out of range, such as a seconds tens of 6 or a nibble over 9, fail to read with
+// ErrValidation, and to write with ErrRange.
+type Timecode struct {
+	Hours, Minutes, Seconds, Frames uint8
+
+	// DropFrame marks drop frame counting, as NTSC's 29.97 frame timecode uses
+	DropFrame bool
+
+	// ColorFrame marks colour framed timecode, and FieldMark the second field of a frame
+	ColorFrame bool
+	FieldMark  bool
+}
+
+var timecodeType = reflect.TypeOf(Timecode{})
+
+// timecodeLayout is where a Timecode's flags lie in its uint32, -1 where it has none
+type timecodeLayout struct {
+	df, cf, fm int
+}
+
+var defaultTimecodeLayout = timecodeLayout{df: 6, cf: 7, fm: 15}
+
+// timecodeSpare are the bits a Timecode's digits leave spare for its flags
+var timecodeSpare = map[int]bool{6: true, 7: true, 15: true, 23: true, 30: true, 31: true}
+
+// timecodeLayoutOf parses sf's timecode tag
+func timecodeLayoutOf(sf reflect.StructField) (tl timecodeLayout, err error) {
+	tl = defaultTimecodeLayout
+	s := sf.Tag.Get("timecode")
+	if s == "" {
+		return
+	}
+	if sf.Type != timecodeType {
+		return tl, fmt.Errorf("%w timecode needs a Timecode; Got %s", ErrUnexpectedType, sf.Type.String())
+	}
+
+	for _, opt := range strings.Split(s, ",") {
+		key, val, _ := strings.Cut(opt, "=")
+		var bit *int
+		switch key {
+		case "df":
+			bit = &tl.df
+		case "cf":
+			bit = &tl.cf
+		case "fm":
+			bit = &tl.fm
+		default:
+			return tl, fmt.Errorf("%w Unknown timecode option %q", ErrTag, opt)
+		}
+		if val == "-" {
+			*bit = -1
+		} else if n, err := strconv.Atoi(val); err != nil || !timecodeSpare[n] {
+			return tl, fmt.Errorf("%w timecode %s=%s is not a spare bit, or -", ErrTag, key, val)
+		} else {
+			*bit = n
+		}
+	}
+	if (tl.df >= 0 && (tl.df == tl.cf || tl.df == tl.fm)) || (tl.cf >= 0 && tl.cf == tl.fm) {
+		return tl, fmt.Errorf("%w timecode %q gives two flags one bit", ErrTag, s)
+	}
+	return tl, nil
+}
+
+// timecodeDigits are the digits of a Timecode's uint32, from the least significant: their
+// shift, width in bits, and largest value
+var timecodeDigits = [8]struct {
+	shift, bits int
+	max         uint32
+}{
+	{0, 4, 9}, {4, 2, 3}, // frames
+	{8, 4, 9}, {12, 3, 5}, // seconds
+	{16, 4, 9}, {20, 3, 5}, // minutes
+	{24, 4, 9}, {28, 2, 2}, // hours
+}
+
+// decode sets tc from u, laid out as tl says
+func (tl timecodeLayout) decode(u uint32) (tc Timecode, err error) {
+	var digits [8]uint32
+	for i, d := range timecodeDigits {
+		if digits[i] = u >> d.shift & (1<<d.bits - 1); digits[i] > d.max {
+			return tc, fmt.Errorf("%w Timecode %08X has a bad digit", ErrValidation, u)
+		}
+	}
+	tc.Frames = uint8(digits[1]*10 + digits[0])
+	tc.Seconds = uint8(digits[3]*10 + digits[2])
+	tc.Minutes = uint8(digits[5]*10 + digits[4])
+	tc.Hours = uint8(digits[7]*10 + digits[6])
+	if tc.Hours > 23 {
+		return tc, fmt.Errorf("%w Timecode %08X has hour %d", ErrValidation, u, tc.Hours)
+	}
+
+	flag := func(bit int) bool { return bit >= 0 && u>>bit&1 == 1 }
+	tc.DropFrame, tc.ColorFrame, tc.FieldMark = flag(tl.df), flag(tl.cf), flag(tl.fm)
+	return tc, nil
+}
+
+// encode lays tc out as tl says
+func (tl timecodeLayout) encode(tc Timecode) (u uint32, err error) {
+	if err = tc.check(); err != nil {
+		return 0, err
+	}
+	for i, n := range []uint8{tc.Frames, tc.Seconds, tc.Minutes, tc.Hours} {
+		u |= uint32(n%10)<<timecodeDigits[2*i].shift | uint32(n/10)<<timecodeDigits[2*i+1].shift
+	}
+
+	flag := func(bit int, set bool) {
+		if bit >= 0 && set {
+			u |= 1 << bit
+		}
+	}
+	flag(tl.df, tc.DropFrame)
+	flag(tl.cf, tc.ColorFrame)
+	flag(tl.fm, tc.FieldMark)
+	return u, nil
+}
+
+// check errors should any of tc's counts not fit its digits
+func (tc Timecode) check() error {
+	if tc.Hours > 23 || tc.Minutes > 59 || tc.Seconds > 59 || tc.Frames > 39 {
+		return fmt.Errorf("%w Timecode %s is out of range", ErrRange, tc)
+	}
+	return nil
+}
+
+// readTimecode reads Timecode f as a uint32 in byte order o, laid out as tl says
+func (r *reader) readTimecode(f reflect.Value, tl timecodeLayout, o binary.ByteOrder) error {
+	bs, err := r.next(4)
+	if err != nil {
+		return err
+	}
+	tc, err := tl.decode(wireOrder(o).Uint32(bs))
+	if err != nil {
+		return err
+	}
+	f.Set(reflect.ValueOf(tc))
+	return nil
+}
+
+// writeTimecode writes Timecode f as a uint32 in byte order o, laid out as tl says
+func (w *writer) writeTimecode(f reflect.Value, tl timecodeLayout, o binary.ByteOrder) error {
+	u, err := tl.encode(f.Interface().(Timecode))
+	if err != nil {
+		return err
+	}
+	wireOrder(o).PutUint32(w.scratch[:4], u)
+	_, err = w.w.Write(w.scratch[:4])
+	return err
+}
+
+// String formats tc as HH:MM:SS:FF, or HH:MM:SS;FF for drop frame timecode
+func (tc Timecode) String() string {
+	sep := ':'
+	if tc.DropFrame {
+		sep = ';'
+	}
+	return fmt.Sprintf("%02d:%02d:%02d%c%02d", tc.Hours, tc.Minutes, tc.Seconds, sep, tc.Frames)
+}
+
+// ParseTimecode parses s as formatted by Timecode.String, a semicolon, or a period or comma as
+// some tools write, before the frames marking drop frame timecode
+func ParseTimecode(s string) (tc Timecode, err error) {
+	bad := fmt.Errorf("%w %q is not a timecode", ErrRange, s)
+	if len(s) != 11 || s[2] != ':' || s[5] != ':' {
+		return tc, bad
+	}
+	switch s[8] {
+	case ':':
+	case ';', '.', ',':
+		tc.DropFrame = true
+	default:
+		return tc, bad
+	}
+
+	for i, n := range []*uint8{&tc.Hours, &tc.Minutes, &tc.Seconds, &tc.Frames} {
+		d := s[3*i : 3*i+2]
+		if d[0] < '0' || d[0] > '9' || d[1] < '0' || d[1] > '9' {
+			return tc, bad
+		}
+		*n = (d[0]-'0')*10 + d[1] - '0'
+	}
+	if tc.check() != nil {
+		return tc, bad
+	}
+	return tc, nil
+}
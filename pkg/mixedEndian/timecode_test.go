@@ -0,0 +1,163 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+type TimecodeStruct struct {
+	// SMPTE 331M packed, frames first
+	Packed Timecode `endian:"little"`
+	// 25 frame timecode, its field mark in bit 23
+	PAL Timecode `endian:"little" timecode:"fm=23"`
+	// DPX, hours first
+	DPX  Timecode `endian:"big"`
+	List [2]Timecode
+}
+
+func TestTimecodeRoundTrip(t *testing.T) {
+	data := TimecodeStruct{
+		Packed: Timecode{Hours: 1, Minutes: 2, Seconds: 3, Frames: 4, DropFrame: true},
+		PAL:    Timecode{Hours: 10, Minutes: 20, Seconds: 30, Frames: 24, FieldMark: true},
+		DPX:    Timecode{Hours: 23, Minutes: 59, Seconds: 59, Frames: 29, ColorFrame: true},
+		List:   [2]Timecode{{Frames: 1}, {Hours: 12, FieldMark: true}},
+	}
+	wire := []byte{
+		0x44, 0x03, 0x02, 0x01,
+		0x24, 0x30, 0xA0, 0x10,
+		0x23, 0x59, 0x59, 0xA9,
+		0x00, 0x00, 0x00, 0x01,
+		0x12, 0x00, 0x80, 0x00,
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, data); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+
+	var got any = &TimecodeStruct{}
+	if err := Read(bytes.NewReader(wire), BigEndian, &got); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, &data) {
+		t.Errorf("Read() = %v, wanted %v", got, data)
+	}
+
+	sl, err := Describe(data)
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	if sl.Size != len(wire) {
+		t.Errorf("Describe().Size = %d, wanted %d", sl.Size, len(wire))
+	}
+}
+
+type TimecodeFlagless struct {
+	TC Timecode `timecode:"df=-,cf=-,fm=-"`
+}
+
+func TestTimecodeSpareBits(t *testing.T) {
+	// Spare bits no flag takes are ignored
+	var got any = &TimecodeFlagless{}
+	if err := Read(bytes.NewReader([]byte{0xC1, 0x80, 0x80, 0xC1}), BigEndian, &got); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := &TimecodeFlagless{Timecode{Hours: 1, Minutes: 0, Seconds: 0, Frames: 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Read() = %v, wanted %v", got, want)
+	}
+
+	buf := &bytes.Buffer{}
+	data := TimecodeFlagless{Timecode{Frames: 1, DropFrame: true, ColorFrame: true, FieldMark: true}}
+	if err := Write(buf, BigEndian, data); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if want := []byte{0, 0, 0, 1}; !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), want)
+	}
+}
+
+type TimecodeBadBit struct {
+	TC Timecode `timecode:"df=8"`
+}
+
+type TimecodeSharedBit struct {
+	TC Timecode `timecode:"df=6,cf=6"`
+}
+
+type TimecodeNotTimecode struct {
+	TC uint32 `timecode:"fm=23"`
+}
+
+func TestTimecodeErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		wire []byte
+		want error
+	}{
+		{"frame units over 9", []byte{0x00, 0x00, 0x00, 0x0A}, ErrValidation},
+		{"seconds tens over 5", []byte{0x00, 0x00, 0x60, 0x00}, ErrValidation},
+		{"hour 24", []byte{0x24, 0x00, 0x00, 0x00}, ErrValidation},
+		{"short", []byte{0x01, 0x02}, io.ErrUnexpectedEOF},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var got any = &TimecodeFlagless{}
+			err := Read(bytes.NewReader(tt.wire), BigEndian, &got)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Read() error = %v, wanted %v", err, tt.want)
+			}
+		})
+	}
+
+	bad := []Timecode{{Hours: 24}, {Minutes: 60}, {Seconds: 60}, {Frames: 40}}
+	for _, tc := range bad {
+		if err := Write(&bytes.Buffer{}, BigEndian, TimecodeFlagless{tc}); !errors.Is(err, ErrRange) {
+			t.Errorf("Write(%s) error = %v, wanted ErrRange", tc, err)
+		}
+	}
+
+	for _, v := range []any{&TimecodeBadBit{}, &TimecodeSharedBit{}} {
+		if err := Read(bytes.NewReader(make([]byte, 4)), BigEndian, &v); !errors.Is(err, ErrTag) {
+			t.Errorf("Read(%T) error = %v, wanted ErrTag", v, err)
+		}
+	}
+	if err := NewDecoder(bytes.NewReader(make([]byte, 4)), BigEndian, WithStrict(true)).Decode(&TimecodeNotTimecode{}); !errors.Is(err, ErrUnexpectedType) {
+		t.Errorf("Decode(TimecodeNotTimecode) error = %v, wanted ErrUnexpectedType", err)
+	}
+}
+
+func TestTimecodeString(t *testing.T) {
+	for _, tt := range []struct {
+		s    string
+		tc   Timecode
+		want string
+	}{
+		{"01:02:03:04", Timecode{Hours: 1, Minutes: 2, Seconds: 3, Frames: 4}, "01:02:03:04"},
+		{"01:02:03;04", Timecode{Hours: 1, Minutes: 2, Seconds: 3, Frames: 4, DropFrame: true}, "01:02:03;04"},
+		{"23:59:59.29", Timecode{Hours: 23, Minutes: 59, Seconds: 59, Frames: 29, DropFrame: true}, "23:59:59;29"},
+	} {
+		tc, err := ParseTimecode(tt.s)
+		if err != nil {
+			t.Errorf("ParseTimecode(%q) error = %v", tt.s, err)
+			continue
+		}
+		if tc != tt.tc {
+			t.Errorf("ParseTimecode(%q) = %+v, wanted %+v", tt.s, tc, tt.tc)
+		}
+		if s := tc.String(); s != tt.want {
+			t.Errorf("String() = %q, wanted %q", s, tt.want)
+		}
+	}
+
+	for _, s := range []string{"", "1:02:03:04", "01:02:03-04", "24:00:00:00", "00:60:00:00", "00:00:00:4x"} {
+		if _, err := ParseTimecode(s); !errors.Is(err, ErrRange) {
+			t.Errorf("ParseTimecode(%q) error = %v, wanted ErrRange", s, err)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+// traceEntry is a span of the input accounted for by a decode trace: a field, or bytes read
+// between fields, such as padding, whose path ends in "_"
+type traceEntry struct {
+	Path     string `json:"path"`
+	Offset   int64  `json:"offset"`
+	Length   int64  `json:"length"`
+	Endian   string `json:"endian,omitempty"`
+	HexBytes string `json:"hexBytes"`
+	Value    any    `json:"value"`
+}
+
+// tracer collects a traceEntry for each field read, in the order they're read
+type tracer struct {
+	// read holds every byte read so far
+	read bytes.Buffer
+
+	path    []string
+	entries []traceEntry
+
+	// end is where the last entry ends
+	end int64
+}
+
+// TraceJSON reads sample from r as Read would, returning a JSON array describing every byte
+// read, in order: an object for each field with its dotted path, offset, length, byte order,
+// bytes in hex, and value. Only fields holding no others are listed. Bytes read between fields,
+// such as padding, are listed under the path of their struct followed by "_", with no value.
+//
+// sample is the pointer to read into, or a value of the type to read. On error the trace of
+// what was read so far is returned alongside it.
+func TraceJSON(r io.Reader, order binary.ByteOrder, sample any) ([]byte, error) {
+	v := reflect.ValueOf(sample)
+	if v.Kind() != reflect.Pointer {
+		if !v.IsValid() {
+			return nil, fmt.Errorf("%w Got nil", ErrUnexpectedType)
+		}
+		v = reflect.New(v.Type())
+	}
+
+	t := &tracer{}
+	rd := reader{r: io.TeeReader(guardProgress(r), &t.read), o: order, ctx: context.Background(), trace: t}
+	err := rd.readOrdered(v, order)
+	t.gap()
+
+	out, jerr := json.MarshalIndent(t.entries, "", "\t")
+	if err == nil {
+		err = jerr
+	}
+	return out, err
+}
+
+// field starts tracing a field read into f in byte order o, returning the func to call, with
+// the read's error, once it's read
+func (t *tracer) field(name string, o binary.ByteOrder, f reflect.Value) func(*error) {
+	t.path = append(t.path, name)
+	start, n := int64(t.read.Len()), len(t.entries)
+	return func(err *error) {
+		path := t.pathString()
+		t.path = t.path[:len(t.path)-1]
+
+		// Fields holding traced fields, and fields taking no bytes, aren't listed themselves
+		end := int64(t.read.Len())
+		if *err != nil || len(t.entries) > n || end == start {
+			return
+		}
+
+		t.gapTo(start)
+		endian := "little"
+		if isBigEndian(o) {
+			endian = "big"
+		}
+		t.add(traceEntry{Path: path, Offset: start, Length: end - start, Endian: endian, Value: traceValue(f)})
+	}
+}
+
+// push notes element i of a list is being read, until pop. Both do nothing on a nil tracer.
+func (t *tracer) push(i int) {
+	if t != nil {
+		t.path = append(t.path, fmt.Sprintf("[%d]", i))
+	}
+}
+
+func (t *tracer) pop() {
+	if t != nil {
+		t.path = t.path[:len(t.path)-1]
+	}
+}
+
+// pathString is the dotted path of the field being read
+func (t *tracer) pathString() string {
+	return strings.ReplaceAll(strings.Join(t.path, "."), ".[", "[")
+}
+
+// gap lists any bytes read since the last entry
+func (t *tracer) gap() {
+	t.gapTo(int64(t.read.Len()))
+}
+
+// gapTo lists any bytes from the last entry to off
+func (t *tracer) gapTo(off int64) {
+	if off <= t.end {
+		return
+	}
+	path := "_"
+	if p := t.pathString(); p != "" {
+		path = p + "._"
+	}
+	t.add(traceEntry{Path: path, Offset: t.end, Length: off - t.end})
+}
+
+// add appends e, filling in its bytes
+func (t *tracer) add(e traceEntry) {
+	e.HexBytes = hex.EncodeToString(t.read.Bytes()[e.Offset : e.Offset+e.Length])
+	t.entries = append(t.entries, e)
+	t.end = e.Offset + e.Length
+}
+
+// traceValue is f as it should appear in JSON. Integers beyond the 53 bits a JSON number holds
+// exactly are strings, as are floats JSON can't represent. Bytes are hex.
+func traceValue(f reflect.Value) any {
+	switch f.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if f.IsNil() {
+			return nil
+		}
+		if f.Type() == bigIntPtrType {
+			return f.Interface().(*big.Int).String()
+		}
+		return traceValue(f.Elem())
+
+	case reflect.Bool:
+		return f.Bool()
+	case reflect.String:
+		return f.String()
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i := f.Int(); i > 1<<53 || i < -1<<53 {
+			return fmt.Sprint(i)
+		}
+		return f.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u := f.Uint(); u > 1<<53 {
+			return fmt.Sprint(u)
+		}
+		return f.Uint()
+
+	case reflect.Float32, reflect.Float64:
+		if x := f.Float(); math.IsNaN(x) || math.IsInf(x, 0) {
+			return fmt.Sprint(x)
+		}
+		return f.Float()
+
+	case reflect.Array, reflect.Slice:
+		if s, ok := traceString(f); ok {
+			return s
+		}
+		if f.Type().Elem().Kind() == reflect.Uint8 {
+			bs := make([]byte, f.Len())
+			reflect.Copy(reflect.ValueOf(bs), f)
+			return hex.EncodeToString(bs)
+		}
+		vs := make([]any, f.Len())
+		for i := range vs {
+			vs[i] = traceValue(f.Index(i))
+		}
+		return vs
+
+	case reflect.Map:
+		m := make(map[string]any, f.Len())
+		iter := f.MapRange()
+		for iter.Next() {
+			m[fmt.Sprint(traceValue(iter.Key()))] = traceValue(iter.Value())
+		}
+		return m
+
+	case reflect.Struct:
+		switch f.Type() {
+		case uint128Type, int128Type:
+			n := new(big.Int).SetUint64(f.Field(0).Uint())
+			n.Lsh(n, 64).Or(n, new(big.Int).SetUint64(f.Field(1).Uint()))
+			if f.Type() == int128Type && n.Bit(127) == 1 {
+				n.Sub(n, new(big.Int).Lsh(big.NewInt(1), 128))
+			}
+			return n.String()
+		}
+		if s, ok := traceString(f); ok {
+			return s
+		}
+		m := make(map[string]any, f.NumField())
+		for i := 0; i < f.NumField(); i++ {
+			if sf := f.Type().Field(i); sf.IsExported() && sf.Name != "_" {
+				m[sf.Name] = traceValue(f.Field(i))
+			}
+		}
+		return m
+	}
+	return fmt.Sprint(f)
+}
+
+// traceString is f's String, should it be a composite type, such as a UUID or Decimal, with one
+func traceString(f reflect.Value) (string, bool) {
+	if f.CanInterface() {
+		if s, ok := f.Interface().(fmt.Stringer); ok {
+			return s.String(), true
+		}
+	}
+	return "", false
+}
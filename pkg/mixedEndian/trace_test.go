@@ -0,0 +1,75 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+)
+
+type TracePoint struct {
+	X, Y int16
+}
+
+type TraceStruct struct {
+	_      struct{} `align:"4"`
+	Kind   uint8
+	Big    uint64 `endian:"little"`
+	Inner  NestedStruct
+	Points [2]TracePoint
+	Name   string `size:"5"`
+	ID     UUID
+}
+
+func TestTraceJSON(t *testing.T) {
+	data := TraceStruct{
+		Kind:   7,
+		Big:    1<<63 + 1,
+		Inner:  NestedStruct{A: 1, B: TaggedStruct{A: 2, B: 3}, C: 4},
+		Points: [2]TracePoint{{-1, 2}, {3, -4}},
+		Name:   "abc",
+		ID:     UUID{0x12, 0x34, 0x56, 0x78, 0x9A, 0xBC, 0xDE, 0xF0, 0x12, 0x34, 0x56, 0x78, 0x9A, 0xBC, 0xDE, 0xF0},
+	}
+	wire, err := Marshal(BigEndian, data)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	got, err := TraceJSON(bytes.NewReader(wire), BigEndian, TraceStruct{})
+	if err != nil {
+		t.Fatalf("TraceJSON() error = %v", err)
+	}
+	checkGolden(t, "TraceStruct.json", append(got, '\n'))
+
+	// Every byte is accounted for, once
+	var entries []traceEntry
+	if err = json.Unmarshal(got, &entries); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	var off int64
+	for _, e := range entries {
+		if e.Offset != off {
+			t.Errorf("%s at %d, wanted %d", e.Path, e.Offset, off)
+		}
+		off += e.Length
+	}
+	if off != int64(len(wire)) {
+		t.Errorf("TraceJSON() covered %d bytes, wanted %d", off, len(wire))
+	}
+}
+
+func TestTraceJSONShort(t *testing.T) {
+	// What was read before the input ran out is still traced
+	got, err := TraceJSON(bytes.NewReader([]byte{0x00, 0x01, 0x00}), BigEndian, &NestedStruct{})
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("TraceJSON() error = %v, wanted %v", err, io.ErrUnexpectedEOF)
+	}
+	var entries []traceEntry
+	if err = json.Unmarshal(got, &entries); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if len(entries) == 0 || entries[0].Path != "A" {
+		t.Errorf("TraceJSON() = %s, wanted A traced", got)
+	}
+}
@@ -0,0 +1,320 @@
+package mixedEndian
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// transcodeChunk is about how many bytes Transcode reads and writes at a time
+const transcodeChunk = 64 << 10
+
+// Transcode streams records of sample's type, a struct or pointer to one, from src in default
+// byte order from to dst in default byte order to, until src ends between records. Fields with
+// an endian tag keep the order the format fixes for them. It returns the number of bytes written,
+// and the output is what decoding each record and encoding it again would give.
+//
+// Fixed size records whose fields all survive a byte swap unchanged are transcoded without
+// decoding them at all: the compiled layout is applied to large chunks of src, swapping each
+// field's bytes in place, with no reflection or allocation per record. Padding is rewritten as
+// encoding would write it, and bools as 0 or 1. Records with variable sized fields, or with fields whose
+// encoding depends on their value, such as checksums, constants, validated or converted values,
+// and bitfields, are decoded and encoded one at a time instead.
+//
+// Ending partway through a record is io.ErrUnexpectedEOF.
+func Transcode(dst io.Writer, src io.Reader, sample any, from, to binary.ByteOrder) (int64, error) {
+	t := reflect.TypeOf(sample)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return 0, fmt.Errorf("%w Expected struct; Got %v", ErrUnexpectedType, t)
+	}
+	return transcodeType(dst, src, t, t.String(), from, to)
+}
+
+// TranscodeSchema is Transcode for records described by schema rather than declared in Go. It's
+// the kernel of a gateway or proxy between peers of different byte orders: fixed size schemas
+// are transcoded field by field, without decoding, and others a record at a time.
+func TranscodeSchema(src io.Reader, srcOrder binary.ByteOrder, dst io.Writer, dstOrder binary.ByteOrder, schema *Schema) error {
+	if schema.typ == nil {
+		return fmt.Errorf("%w Schema %q has no type; build it with LoadSchema or SchemaFromJSON", ErrSchema, schema.Name)
+	}
+	_, err := transcodeType(dst, src, schema.typ, schema.Name, srcOrder, dstOrder)
+	return err
+}
+
+// transcodeType is Transcode for records of struct type t, named name in errors
+func transcodeType(dst io.Writer, src io.Reader, t reflect.Type, name string, from, to binary.ByteOrder) (int64, error) {
+	// Layouts only describe what's fixed, so anything else is left to the decoder
+	sl, err := LayoutOf(t)
+	if err != nil || sl.Size <= 0 {
+		return recodeRecords(dst, src, t, name, from, to)
+	}
+	tp, ok := planTranscode(sl, isBigEndian(from) != isBigEndian(to))
+	if !ok {
+		return recodeRecords(dst, src, t, name, from, to)
+	}
+
+	// Bytes no field covers, padding, are as the zero value encodes them
+	if tp.template, err = Marshal(to, reflect.New(t).Interface()); err != nil || len(tp.template) != sl.Size {
+		return recodeRecords(dst, src, t, name, from, to)
+	}
+	return tp.stream(dst, src)
+}
+
+// recodeRecords reads records of type t, named name, from src in byte order from, writing each
+// to dst in byte order to, until src ends between records. It returns the number of bytes written.
+func recodeRecords(dst io.Writer, src io.Reader, t reflect.Type, name string, from, to binary.ByteOrder) (int64, error) {
+	dec := NewDecoder(src, from)
+	enc := NewEncoder(dst, to)
+	for {
+		start := dec.in.n
+		v := reflect.New(t)
+		if err := dec.dec.readOrdered(v, from); errors.Is(err, io.EOF) && dec.in.n == start {
+			return enc.written, nil
+		} else if errors.Is(err, io.EOF) {
+			return enc.written, io.ErrUnexpectedEOF
+		} else if err != nil {
+			return enc.written, err
+		} else if dec.in.n == start {
+			return enc.written, fmt.Errorf("%w %s takes no bytes, so there's no end of them", ErrLength, name)
+		}
+
+		if err := enc.Encode(v.Interface()); err != nil {
+			return enc.written, err
+		}
+	}
+}
+
+// transcodeOp is how a transcodeStep rewrites its bytes
+type transcodeOp uint8
+
+const (
+	// opCopy leaves bytes as they are
+	opCopy transcodeOp = iota
+
+	// opSwap reverses the bytes of each element
+	opSwap
+
+	// opBool writes each byte as 0 or 1
+	opBool
+
+	// opFill writes the plan's template in place of the input
+	opFill
+)
+
+// transcodeStep rewrites count elements of width bytes, at offset off in each record
+type transcodeStep struct {
+	off, width, count int
+	op                transcodeOp
+}
+
+// transcodePlan is the steps transcoding a fixed size record takes
+type transcodePlan struct {
+	steps    []transcodeStep
+	swap     bool
+	size     int
+	template []byte
+}
+
+// transcodeRecodeTags are tag keys whose fields' encodings aren't simply their bytes, swapped
+var transcodeRecodeTags = []string{
+	"bitwidth", "clamp", "compress", "const", "crc", "crcblocks", "crcrange", "dur", "enum", "enumdefault",
+	"epoch", "finite", "floatfmt", "lengthscope", "maxvalue", "minvalue", "mirror", "network_checksum",
+	"norm", "overlay", "pcm", "rle", "sizeof_field", "sparse", "ssh", "string",
+}
+
+// planTranscode compiles the steps transcoding a record laid out as sl takes, swapping fields in
+// the default byte order when swap is set. It isn't ok should any field need decoding.
+func planTranscode(sl *StructLayout, swap bool) (*transcodePlan, bool) {
+	tp := &transcodePlan{swap: swap, size: sl.Size}
+	if !tp.plan(sl, 0) {
+		return nil, false
+	}
+	tp.fill()
+	return tp, true
+}
+
+// plan adds the steps for the fields of sl, which starts base bytes into the record
+func (tp *transcodePlan) plan(sl *StructLayout, base int) bool {
+	for _, f := range sl.Fields {
+		if f.Size == 0 {
+			continue
+		} else if f.Size < 0 || f.Offset < 0 {
+			return false
+		}
+		for _, key := range transcodeRecodeTags {
+			if f.Tag.Get(key) != "" {
+				return false
+			}
+		}
+		switch e := f.Tag.Get("encoding"); e {
+		case "", "gray", "uuid", "uuid_le":
+		default:
+			return false
+		}
+
+		at := base + f.Offset
+		t := f.Type
+		if t.Kind() == reflect.Pointer {
+			// Nil pointers don't encode as their zero values do
+			return false
+		}
+
+		count := 1
+		switch {
+		case t == hardwareAddrType || t.Kind() == reflect.String:
+			tp.add(transcodeStep{off: at, width: 1, count: f.Size, op: opCopy})
+			continue
+		case t.Kind() == reflect.Array || t.Kind() == reflect.Slice:
+			if f.Count < 0 {
+				return false
+			}
+			t, count = t.Elem(), f.Count
+		}
+
+		switch {
+		case f.Elem != nil:
+			// Struct elements follow each other, each its layout's size
+			for i := 0; i < count; i++ {
+				if !tp.plan(f.Elem, at+i*f.Elem.Size) {
+					return false
+				}
+			}
+		case t.Kind() == reflect.Bool:
+			tp.add(transcodeStep{off: at, width: 1, count: count, op: opBool})
+		case typeSize(t) == 1:
+			tp.add(transcodeStep{off: at, width: 1, count: count, op: opCopy})
+		case typeSize(t) > 1:
+			op := opCopy
+			if tp.swap && f.Order == nil {
+				op = opSwap
+			}
+			tp.add(transcodeStep{off: at, width: typeSize(t), count: count, op: op})
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// add appends s, merging it into the last step should s carry on where that left off the same way
+func (tp *transcodePlan) add(s transcodeStep) {
+	if s.op == opCopy {
+		s.count, s.width = s.count*s.width, 1
+	}
+	if n := len(tp.steps); n > 0 {
+		last := &tp.steps[n-1]
+		if last.op == s.op && last.width == s.width && last.off+last.width*last.count == s.off {
+			last.count += s.count
+			return
+		}
+	}
+	tp.steps = append(tp.steps, s)
+}
+
+// fill adds steps writing the template's bytes wherever no field lies, such as padding
+func (tp *transcodePlan) fill() {
+	covered := make([]bool, tp.size)
+	for _, s := range tp.steps {
+		for i := s.off; i < s.off+s.width*s.count; i++ {
+			covered[i] = true
+		}
+	}
+	for i := 0; i < tp.size; i++ {
+		if !covered[i] {
+			tp.steps = append(tp.steps, transcodeStep{off: i, width: 1, count: 1, op: opFill})
+			for i+1 < tp.size && !covered[i+1] {
+				tp.steps[len(tp.steps)-1].count++
+				i++
+			}
+		}
+	}
+}
+
+// chunk transcodes the whole records in bs in place. Each step is taken for every record in
+// turn, keeping the loops tight, and bytes copied as they are aren't touched at all.
+func (tp *transcodePlan) chunk(bs []byte) {
+	size := tp.size
+	for _, s := range tp.steps {
+		n := s.width * s.count
+		switch {
+		case s.op == opCopy:
+		case s.op == opFill:
+			// Padding is short, so not worth a call to copy
+			fill := tp.template[s.off : s.off+n]
+			for r := s.off; r < len(bs); r += size {
+				for i, b := range fill {
+					bs[r+i] = b
+				}
+			}
+		case s.op == opBool:
+			for r := s.off; r < len(bs); r += size {
+				for i, b := range bs[r : r+n] {
+					if b != 0 {
+						bs[r+i] = 1
+					}
+				}
+			}
+		case s.width == 2:
+			for r := s.off; r < len(bs); r += size {
+				for i := r; i < r+n; i += 2 {
+					bs[i], bs[i+1] = bs[i+1], bs[i]
+				}
+			}
+		case s.width == 4:
+			for r := s.off; r < len(bs); r += size {
+				for i := r; i < r+n; i += 4 {
+					binary.LittleEndian.PutUint32(bs[i:], binary.BigEndian.Uint32(bs[i:]))
+				}
+			}
+		case s.width == 8:
+			for r := s.off; r < len(bs); r += size {
+				for i := r; i < r+n; i += 8 {
+					binary.LittleEndian.PutUint64(bs[i:], binary.BigEndian.Uint64(bs[i:]))
+				}
+			}
+		default:
+			for r := s.off; r < len(bs); r += size {
+				for i := r; i < r+n; i += s.width {
+					for a, b := i, i+s.width-1; a < b; a, b = a+1, b-1 {
+						bs[a], bs[b] = bs[b], bs[a]
+					}
+				}
+			}
+		}
+	}
+}
+
+// stream transcodes records from src to dst a chunk at a time
+func (tp *transcodePlan) stream(dst io.Writer, src io.Reader) (written int64, err error) {
+	n := transcodeChunk / tp.size
+	if n < 1 {
+		n = 1
+	}
+	buf := make([]byte, n*tp.size)
+
+	for {
+		read, rerr := io.ReadFull(src, buf)
+		whole := read - read%tp.size
+		tp.chunk(buf[:whole])
+
+		w, err := dst.Write(buf[:whole])
+		written += int64(w)
+		switch {
+		case err != nil:
+			return written, err
+		case rerr == io.EOF:
+			return written, nil
+		case rerr == io.ErrUnexpectedEOF && whole == read:
+			return written, nil
+		case rerr == io.ErrUnexpectedEOF:
+			return written, io.ErrUnexpectedEOF
+		case rerr != nil:
+			return written, rerr
+		}
+	}
+}
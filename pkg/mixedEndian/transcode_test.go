@@ -0,0 +1,199 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+type TranscodeRecord struct {
+	A     uint16
+	B     uint32 `endian:"little"`
+	C     [3]int16
+	D     bool
+	E     Uint128
+	F     [4]byte
+	Name  string `size:"5"`
+	Inner struct {
+		X uint64
+		Y Uint24
+	} `padding_before:"2"`
+	Pairs [2]struct {
+		P int32
+		Q uint8
+	} `endian:"big"`
+	Gray uint16 `gray:"true"`
+	Tail uint8  `padding_after:"1,0xFF"`
+}
+
+type TranscodeCounted struct {
+	N    uint8
+	Data []uint16 `len:"N"`
+	Kind uint16
+}
+
+// naiveTranscode decodes each record of t from src in byte order from, and encodes it in to
+func naiveTranscode(t *testing.T, src []byte, typ reflect.Type, from, to binary.ByteOrder) []byte {
+	t.Helper()
+	r := bytes.NewReader(src)
+	out := &bytes.Buffer{}
+	for r.Len() > 0 {
+		var v any = reflect.New(typ).Interface()
+		if err := Read(r, from, &v); err != nil {
+			t.Fatalf("Read() error = %v", err)
+		}
+		if err := Write(out, to, v); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	return out.Bytes()
+}
+
+func TestTranscode(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	random := func(n int) []byte {
+		bs := make([]byte, n)
+		rng.Read(bs)
+		return bs
+	}
+	counted := &bytes.Buffer{}
+	for i := 0; i < 50; i++ {
+		data := TranscodeCounted{N: uint8(i % 7), Kind: uint16(i)}
+		for j := 0; j < int(data.N); j++ {
+			data.Data = append(data.Data, uint16(rng.Intn(1<<16)))
+		}
+		if err := Write(counted, BigEndian, data); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	size, err := SizeOf(TranscodeRecord{})
+	if err != nil {
+		t.Fatalf("SizeOf() error = %v", err)
+	}
+	tests := []struct {
+		name   string
+		sample any
+		src    []byte
+		fast   bool
+	}{
+		// Enough records to span several chunks, the last only partly filled
+		{name: "swapped", sample: TranscodeRecord{}, src: random(size * (transcodeChunk/size*3 + 5)), fast: true},
+		{name: "aligned", sample: &AlignedStruct8{}, src: random(32 * 100), fast: true},
+		{name: "recoded", sample: TranscodeCounted{}, src: counted.Bytes()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typ := reflect.TypeOf(tt.sample)
+			for typ.Kind() == reflect.Pointer {
+				typ = typ.Elem()
+			}
+			sl, err := LayoutOf(typ)
+			if err != nil {
+				t.Fatalf("LayoutOf() error = %v", err)
+			}
+			if _, ok := planTranscode(sl, true); ok != tt.fast {
+				t.Errorf("planTranscode() ok = %t, wanted %t", ok, tt.fast)
+			}
+
+			for _, orders := range [][2]binary.ByteOrder{{BigEndian, LittleEndian}, {LittleEndian, BigEndian}, {BigEndian, BigEndian}} {
+				want := naiveTranscode(t, tt.src, typ, orders[0], orders[1])
+				got := &bytes.Buffer{}
+				n, err := Transcode(got, bytes.NewReader(tt.src), tt.sample, orders[0], orders[1])
+				if err != nil {
+					t.Fatalf("Transcode(%v, %v) error = %v", orders[0], orders[1], err)
+				}
+				if n != int64(got.Len()) || !bytes.Equal(got.Bytes(), want) {
+					t.Errorf("Transcode(%v, %v) wrote %d bytes differing from decoding and encoding", orders[0], orders[1], n)
+				}
+			}
+		})
+	}
+}
+
+func TestTranscodeTruncated(t *testing.T) {
+	for _, sample := range []any{AlignedStruct8{}, TranscodeCounted{}} {
+		src := make([]byte, 32*3+5)
+		if _, ok := sample.(TranscodeCounted); ok {
+			src = []byte{0x02, 0x00, 0x01, 0x00, 0x02, 0x01, 0x02, 0x03, 0x00}
+		}
+
+		got := &bytes.Buffer{}
+		n, err := Transcode(got, bytes.NewReader(src), sample, BigEndian, LittleEndian)
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("Transcode(%T) error = %v, wanted %v", sample, err, io.ErrUnexpectedEOF)
+		}
+		if n != int64(got.Len()) || n == 0 {
+			t.Errorf("Transcode(%T) = %d, having written %d bytes of the whole records", sample, n, got.Len())
+		}
+	}
+
+	if _, err := Transcode(io.Discard, bytes.NewReader(nil), 5, BigEndian, LittleEndian); !errors.Is(err, ErrUnexpectedType) {
+		t.Errorf("Transcode() error = %v, wanted %v", err, ErrUnexpectedType)
+	}
+}
+
+func TestTranscodeSchema(t *testing.T) {
+	fixed, err := SchemaFromJSON([]byte(`{
+		"name": "Sample",
+		"fields": [
+			{"name": "Magic", "type": "uint16", "tag": "endian:\"big\""},
+			{"name": "Channel", "type": "uint8"},
+			{"name": "Values", "type": "[3]int32"},
+			{"name": "Stamp", "type": "uint64", "tag": "padding_before:\"1\""}
+		]
+	}`), nil)
+	if err != nil {
+		t.Fatalf("SchemaFromJSON() error = %v", err)
+	}
+	counted, err := SchemaFromJSON([]byte(`{
+		"name": "Batch",
+		"fields": [
+			{"name": "Count", "type": "uint8"},
+			{"name": "Values", "type": "[]uint32", "tag": "len:\"Count\""}
+		]
+	}`), nil)
+	if err != nil {
+		t.Fatalf("SchemaFromJSON() error = %v", err)
+	}
+
+	rng := rand.New(rand.NewSource(2))
+	sample := make([]byte, 24*1000)
+	rng.Read(sample)
+	tests := []struct {
+		schema *Schema
+		src    []byte
+		fast   bool
+	}{
+		{schema: fixed, src: sample, fast: true},
+		{schema: counted, src: []byte{0x02, 0, 0, 0, 1, 0, 0, 0, 2, 0x00, 0x01, 1, 2, 3, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.schema.Name, func(t *testing.T) {
+			sl, err := LayoutOf(tt.schema.Type())
+			if err != nil {
+				t.Fatalf("LayoutOf() error = %v", err)
+			}
+			if _, ok := planTranscode(sl, true); ok != tt.fast {
+				t.Errorf("planTranscode() ok = %t, wanted %t", ok, tt.fast)
+			}
+
+			want := naiveTranscode(t, tt.src, tt.schema.Type(), BigEndian, LittleEndian)
+			got := &bytes.Buffer{}
+			if err := TranscodeSchema(bytes.NewReader(tt.src), BigEndian, got, LittleEndian, tt.schema); err != nil {
+				t.Fatalf("TranscodeSchema() error = %v", err)
+			}
+			if !bytes.Equal(got.Bytes(), want) {
+				t.Errorf("TranscodeSchema() wrote % X, wanted % X", got.Bytes(), want)
+			}
+		})
+	}
+
+	if err := TranscodeSchema(bytes.NewReader(nil), BigEndian, io.Discard, LittleEndian, &Schema{Name: "Empty"}); !errors.Is(err, ErrSchema) {
+		t.Errorf("TranscodeSchema() error = %v, wanted %v", err, ErrSchema)
+	}
+}
@@ -0,0 +1,269 @@
+package mixedEndian
+
+import (
+	"fmt"
+	"math/bits"
+	"reflect"
+	"strconv"
+)
+
+// isGray reports whether sf is stored as Gray code, by `encoding:"gray"` or its alias `gray:"true"`
+func isGray(sf reflect.StructField) bool {
+	return sf.Tag.Get("encoding") == "gray" || sf.Tag.Get("gray") == "true"
+}
+
+// afterRead applies the value transforms named by sf's tags to freshly read f.
+// They're undone in the reverse of the order beforeWrite applies them.
+func afterRead(sf reflect.StructField, f reflect.Value) (err error) {
+	// Bits are reversed within their bytes on the wire, so are put right before any other transform's undone
+	if sf.Tag.Get("bitreverse") == "true" {
+		if err = mapBitReversed(f, f); err != nil {
+			return
+		}
+	}
+
+	if sf.Tag.Get("delta") == "true" {
+		if err = deltaDecode(f); err != nil {
+			return
+		}
+	}
+
+	switch e := sf.Tag.Get("encoding"); e {
+	case "", "gray", "varint", "uvarint", "uuid", "uuid_le":
+	default:
+		return fmt.Errorf("%w Unknown encoding %q", ErrTag, e)
+	}
+	if isGray(sf) {
+		if err = mapUints(f, f, grayToBinary); err != nil {
+			return
+		}
+	}
+	if _, le, err := isUUID(sf); err != nil {
+		return err
+	} else if le {
+		dst := blankCopy(f)
+		swapUUID(dst, f)
+		f.Set(dst)
+	}
+
+	// Bits beyond the bitwidth are reserved, so dropped, and signed values sign extended from it
+	mask, signed, ok, err := bitwidthMask(sf, f.Type())
+	if ok && signed {
+		shift := bits.LeadingZeros64(mask)
+		err = mapInts(f, f, func(i int64) int64 { return i << shift >> shift })
+	} else if ok {
+		err = mapUints(f, f, func(u uint64) uint64 { return u & mask })
+	}
+	return
+}
+
+// beforeWrite returns f with the value transforms named by sf's tags applied, ready to be written.
+// f itself is left untouched.
+func beforeWrite(sf reflect.StructField, f reflect.Value) (_ reflect.Value, err error) {
+	mask, signed, ok, err := bitwidthMask(sf, f.Type())
+	if err != nil {
+		return f, err
+	} else if ok && signed {
+		// Signed values are written in two's complement, within the bitwidth
+		clamp := sf.Tag.Get("clamp") == "true"
+		hi := int64(mask >> 1)
+		dst, over, overflow := blankCopy(f), int64(0), false
+		err = mapInts(dst, f, func(i int64) int64 {
+			switch {
+			case i > hi && clamp:
+				i = hi
+			case i < -hi-1 && clamp:
+				i = -hi - 1
+			case i > hi || i < -hi-1:
+				over, overflow = i, true
+			}
+			return int64(uint64(i) & mask)
+		})
+		if err != nil {
+			return f, err
+		} else if overflow {
+			return f, fmt.Errorf("%w %d needs more than %s bits", ErrRange, over, sf.Tag.Get("bitwidth"))
+		}
+		f = dst
+	} else if ok {
+		clamp := sf.Tag.Get("clamp") == "true"
+		dst, over := blankCopy(f), uint64(0)
+		err = mapUints(dst, f, func(u uint64) uint64 {
+			if u <= mask {
+				return u
+			} else if !clamp {
+				over = u
+			}
+			return mask
+		})
+		if err != nil {
+			return f, err
+		} else if over != 0 {
+			return f, fmt.Errorf("%w %d needs more than %s bits", ErrRange, over, sf.Tag.Get("bitwidth"))
+		}
+		f = dst
+	}
+
+	switch e := sf.Tag.Get("encoding"); e {
+	case "", "gray", "varint", "uvarint", "uuid", "uuid_le":
+	default:
+		return f, fmt.Errorf("%w Unknown encoding %q", ErrTag, e)
+	}
+	if isGray(sf) {
+		dst := blankCopy(f)
+		if err = mapUints(dst, f, binaryToGray); err != nil {
+			return
+		}
+		f = dst
+	}
+	if _, le, err := isUUID(sf); err != nil {
+		return f, err
+	} else if le {
+		dst := blankCopy(f)
+		swapUUID(dst, f)
+		f = dst
+	}
+
+	if sf.Tag.Get("delta") == "true" {
+		if f, err = deltaEncode(f); err != nil {
+			return
+		}
+	}
+
+	if sf.Tag.Get("bitreverse") == "true" {
+		dst := blankCopy(f)
+		if err = mapBitReversed(dst, f); err != nil {
+			return
+		}
+		f = dst
+	}
+	return f, nil
+}
+
+// blankCopy returns a zero value of f's type, with room for as many elements if f is a slice
+func blankCopy(f reflect.Value) reflect.Value {
+	dst := reflect.New(f.Type()).Elem()
+	if f.Kind() == reflect.Slice {
+		dst.Set(reflect.MakeSlice(f.Type(), f.Len(), f.Len()))
+	}
+	return dst
+}
+
+// mapUints sets unsigned integer dst, or each element of array or slice dst, to fn of the same in src
+func mapUints(dst, src reflect.Value, fn func(uint64) uint64) error {
+	switch src.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		dst.SetUint(fn(src.Uint()))
+	case reflect.Array, reflect.Slice:
+		for i := 0; i < src.Len(); i++ {
+			if err := mapUints(dst.Index(i), src.Index(i), fn); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("%w Expected unsigned integer; Got %s", ErrUnexpectedType, src.Type().String())
+	}
+	return nil
+}
+
+// mapBitReversed sets integer dst, or each element of array or slice dst, to the same in src
+// with the order of the bits within each of its bytes reversed. Its bytes stay where they are.
+// Integers are reversed within the bytes they take on the wire, 3 for an Int24, so must fit them,
+// or it fails with ErrRange.
+func mapBitReversed(dst, src reflect.Value) error {
+	bits := 8 * typeSize(src.Type())
+	if bits == 0 {
+		bits = 8 * int(src.Type().Size())
+	}
+
+	switch src.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u := src.Uint()
+		if bits < 64 && u >= 1<<bits {
+			return fmt.Errorf("%w %d does not fit in %s", ErrRange, u, src.Type().String())
+		}
+		dst.SetUint(reverseBitsInBytes(u))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := src.Int()
+		if bits < 64 && (i < -1<<(bits-1) || i >= 1<<(bits-1)) {
+			return fmt.Errorf("%w %d does not fit in %s", ErrRange, i, src.Type().String())
+		}
+
+		// Only the integer's own bytes are reversed, then it's sign extended again from the top of them
+		shift := 64 - bits
+		u := reverseBitsInBytes(uint64(i) << shift >> shift)
+		dst.SetInt(int64(u<<shift) >> shift)
+	case reflect.Array, reflect.Slice:
+		for i := 0; i < src.Len(); i++ {
+			if err := mapBitReversed(dst.Index(i), src.Index(i)); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("%w bitreverse needs integers; Got %s", ErrUnexpectedType, src.Type().String())
+	}
+	return nil
+}
+
+// reverseBitsInBytes reverses the order of the bits within each byte of u
+func reverseBitsInBytes(u uint64) uint64 {
+	return bits.ReverseBytes64(bits.Reverse64(u))
+}
+
+// mapInts sets signed integer dst, or each element of array or slice dst, to fn of the same in src
+func mapInts(dst, src reflect.Value, fn func(int64) int64) error {
+	switch src.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		dst.SetInt(fn(src.Int()))
+	case reflect.Array, reflect.Slice:
+		for i := 0; i < src.Len(); i++ {
+			if err := mapInts(dst.Index(i), src.Index(i), fn); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("%w Expected signed integer; Got %s", ErrUnexpectedType, src.Type().String())
+	}
+	return nil
+}
+
+// bitwidthMask is the mask of the significant bits given by a field's bitwidth tag, and whether
+// they hold a signed value, reporting whether it was set. t is the field's type, or its array or
+// slice type.
+func bitwidthMask(sf reflect.StructField, t reflect.Type) (mask uint64, signed, ok bool, err error) {
+	s := sf.Tag.Get("bitwidth")
+	if s == "" {
+		return 0, false, false, nil
+	}
+	if t.Kind() == reflect.Array || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	bits := typeSize(t) * 8
+	switch t.Kind() {
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		signed = true
+	default:
+		return 0, false, true, fmt.Errorf("%w bitwidth needs sized integers; Got %s", ErrUnexpectedType, t.String())
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 || n > bits {
+		return 0, signed, true, fmt.Errorf("%w bitwidth %q doesn't fit %s", ErrTag, s, t.String())
+	}
+	return 1<<n - 1, signed, true, nil
+}
+
+// binaryToGray converts b to reflected binary Gray code
+func binaryToGray(b uint64) uint64 {
+	return b ^ b>>1
+}
+
+// grayToBinary converts reflected binary Gray code g back to binary
+func grayToBinary(g uint64) uint64 {
+	for shift := 1; shift < 64; shift <<= 1 {
+		g ^= g >> shift
+	}
+	return g
+}
@@ -0,0 +1,318 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"math/bits"
+	"reflect"
+	"testing"
+)
+
+type GrayStruct struct {
+	A uint8  `encoding:"gray"`
+	B uint16 `encoding:"gray" endian:"little"`
+	C Uint24 `encoding:"gray"`
+}
+
+func TestGrayRoundTrip8(t *testing.T) {
+	prev := []byte(nil)
+	for i := 0; i < 256; i++ {
+		data := struct {
+			A uint8 `encoding:"gray"`
+		}{A: uint8(i)}
+
+		buf := &bytes.Buffer{}
+		if err := Write(buf, BigEndian, data); err != nil {
+			t.Fatalf("Write(%d) error = %v", i, err)
+		}
+		wire := buf.Bytes()
+
+		// Consecutive values differ in exactly one bit on the wire
+		if prev != nil && bits.OnesCount8(prev[0]^wire[0]) != 1 {
+			t.Errorf("Write(%d) = %08b, Write(%d) = %08b differ in more than one bit", i-1, prev[0], i, wire[0])
+		}
+		prev = wire
+
+		got := &struct {
+			A uint8 `encoding:"gray"`
+		}{}
+		var d any = got
+		if err := Read(bytes.NewReader(wire), BigEndian, &d); err != nil {
+			t.Fatalf("Read(%d) error = %v", i, err)
+		}
+		if got.A != uint8(i) {
+			t.Errorf("Read(Write(%d)) = %d", i, got.A)
+		}
+	}
+}
+
+func TestGrayWide(t *testing.T) {
+	data := GrayStruct{A: 0x80, B: 0x1234, C: 0xFFFFFF}
+	// Gray codes: 0x80 -> 0xC0, 0x1234 -> 0x1B2E, 0xFFFFFF -> 0x800000
+	wire := []byte{0xC0, 0x2E, 0x1B, 0x80, 0x00, 0x00}
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, data); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+
+	var got any = &GrayStruct{}
+	if err := Read(bytes.NewReader(wire), BigEndian, &got); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, &data) {
+		t.Errorf("Read() = %v, wanted %v", got, data)
+	}
+}
+
+type GrayTagStruct struct {
+	A uint8  `gray:"true"`
+	B uint16 `gray:"true"`
+}
+
+func TestGrayTag(t *testing.T) {
+	tests := []struct {
+		data GrayTagStruct
+		wire []byte
+	}{
+		{GrayTagStruct{A: 0x00, B: 0x0000}, []byte{0x00, 0x00, 0x00}},
+		{GrayTagStruct{A: 0x01, B: 0x0002}, []byte{0x01, 0x00, 0x03}},
+		{GrayTagStruct{A: 0x07, B: 0x00FF}, []byte{0x04, 0x00, 0x80}},
+		{GrayTagStruct{A: 0xFF, B: 0xFFFF}, []byte{0x80, 0x80, 0x00}},
+	}
+	for _, tt := range tests {
+		buf := &bytes.Buffer{}
+		if err := Write(buf, BigEndian, tt.data); err != nil {
+			t.Fatalf("Write(%v) error = %v", tt.data, err)
+		}
+		if !bytes.Equal(buf.Bytes(), tt.wire) {
+			t.Errorf("Write(%v) = % X, wanted % X", tt.data, buf.Bytes(), tt.wire)
+		}
+
+		var got any = &GrayTagStruct{}
+		if err := Read(bytes.NewReader(tt.wire), BigEndian, &got); err != nil {
+			t.Fatalf("Read() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, &tt.data) {
+			t.Errorf("Read() = %v, wanted %v", got, tt.data)
+		}
+	}
+}
+
+func TestGrayErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    any
+		wantErr error
+	}{
+		{
+			name: "signed",
+			data: struct {
+				A int16 `encoding:"gray"`
+			}{},
+			wantErr: ErrUnexpectedType,
+		},
+		{
+			name: "unknown encoding",
+			data: struct {
+				A uint16 `encoding:"excess3"`
+			}{},
+			wantErr: ErrTag,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Write(&bytes.Buffer{}, BigEndian, tt.data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Write() error = %v, wanted %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+type BitwidthStruct struct {
+	ADC     uint16    `bitwidth:"12"`
+	Clamped uint8     `bitwidth:"4" clamp:"true"`
+	List    [2]uint16 `bitwidth:"10" endian:"little"`
+}
+
+func TestBitwidth(t *testing.T) {
+	// Reserved bits are dropped when read
+	var got any = &BitwidthStruct{}
+	if err := Read(bytes.NewReader([]byte{0xFA, 0xBC, 0xFF, 0xFF, 0xFF, 0x01, 0x04}), BigEndian, &got); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := &BitwidthStruct{ADC: 0x0ABC, Clamped: 0x0F, List: [2]uint16{0x03FF, 0x0001}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Read() = %v, wanted %v", got, want)
+	}
+
+	// Values over the bitwidth are clamped when asked
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, BitwidthStruct{ADC: 0x0FFF, Clamped: 200, List: [2]uint16{1, 0x03FF}}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	wire := []byte{0x0F, 0xFF, 0x0F, 0x01, 0x00, 0xFF, 0x03}
+	if !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+}
+
+type SignedBitwidthStruct struct {
+	A int16    `bitwidth:"12"`
+	B int8     `bitwidth:"4" clamp:"true"`
+	C [2]Int24 `bitwidth:"20"`
+}
+
+func TestSignedBitwidth(t *testing.T) {
+	// Signed values are sign extended from their bitwidth, reserved bits dropped
+	var got any = &SignedBitwidthStruct{}
+	wire := []byte{0xFF, 0xFF, 0x07, 0x08, 0x00, 0x00, 0xFF, 0xFF, 0xF7}
+	if err := Read(bytes.NewReader(wire), BigEndian, &got); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := &SignedBitwidthStruct{A: -1, B: 7, C: [2]Int24{-0x80000, -9}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Read() = %v, wanted %v", got, want)
+	}
+
+	// and written in two's complement within it, clamped when asked
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, SignedBitwidthStruct{A: -2048, B: -100, C: [2]Int24{0x7FFFF, -1}}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if wire := []byte{0x08, 0x00, 0x08, 0x07, 0xFF, 0xFF, 0x0F, 0xFF, 0xFF}; !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+}
+
+func TestBitwidthErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    any
+		wantErr error
+	}{
+		{
+			name:    "overflow",
+			data:    BitwidthStruct{ADC: 0x1000},
+			wantErr: ErrRange,
+		},
+		{
+			name:    "element overflow",
+			data:    BitwidthStruct{List: [2]uint16{0, 0x0400}},
+			wantErr: ErrRange,
+		},
+		{
+			name: "wider than field",
+			data: struct {
+				A Uint24 `bitwidth:"25"`
+			}{},
+			wantErr: ErrTag,
+		},
+		{
+			name:    "signed overflow",
+			data:    SignedBitwidthStruct{A: -2049},
+			wantErr: ErrRange,
+		},
+		{
+			name: "float",
+			data: struct {
+				A float32 `bitwidth:"12"`
+			}{},
+			wantErr: ErrUnexpectedType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Write(&bytes.Buffer{}, BigEndian, tt.data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Write() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+type BitReverseStruct struct {
+	A uint8   `bitreverse:"true"`
+	B uint16  `bitreverse:"true" endian:"little"`
+	C int16   `bitreverse:"true" endian:"big"`
+	D [2]byte `bitreverse:"true"`
+}
+
+func TestBitReverse(t *testing.T) {
+	data := BitReverseStruct{A: 0x01, B: 0x1234, C: -2, D: [2]byte{0x0F, 0xA0}}
+	// Little endian 0x1234 is 34 12, reversed 2C 48; -2 is FF FE, reversed FF 7F
+	wire := []byte{0x80, 0x2C, 0x48, 0xFF, 0x7F, 0xF0, 0x05}
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, data); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+
+	var got any = &BitReverseStruct{}
+	if err := Read(bytes.NewReader(wire), BigEndian, &got); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, &data) {
+		t.Errorf("Read() = %+v, wanted %+v", got, data)
+	}
+}
+
+func TestBitReverseOddWidth(t *testing.T) {
+	// Reversed within their 3 and 6 bytes, so top bytes reversing into, or out of, the sign bit
+	// still fit
+	type oddWidth struct {
+		A Int24  `bitreverse:"true"`
+		B Int24  `bitreverse:"true"`
+		C Uint24 `bitreverse:"true"`
+		D Int48  `bitreverse:"true" endian:"little"`
+	}
+	data := oddWidth{A: 65536, B: -8388608, C: 0x010203, D: -2}
+	wire := []byte{
+		0x80, 0x00, 0x00,
+		0x01, 0x00, 0x00,
+		0x80, 0x40, 0xC0,
+		0x7F, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF,
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, data); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+
+	var got oddWidth
+	if err := Unmarshal(BigEndian, wire, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if got != data {
+		t.Errorf("Unmarshal() = %+v, wanted %+v", got, data)
+	}
+
+	// What doesn't fit its wire bytes can't be reversed within them
+	if err := Write(buf, BigEndian, oddWidth{A: 1 << 23}); !errors.Is(err, ErrRange) {
+		t.Errorf("Write() error = %v, wanted %v", err, ErrRange)
+	}
+}
+
+func TestBitReverseErrors(t *testing.T) {
+	var float any = &struct {
+		F float32 `bitreverse:"true"`
+	}{}
+	if err := Read(bytes.NewReader(make([]byte, 4)), BigEndian, &float); !errors.Is(err, ErrUnexpectedType) {
+		t.Errorf("Read(float32) error = %v, wanted ErrUnexpectedType", err)
+	}
+
+	unknown := &struct {
+		U uint8 `bitreverse:"yes"`
+	}{}
+	if err := NewDecoder(bytes.NewReader(make([]byte, 1)), BigEndian, WithStrict(true)).Decode(unknown); !errors.Is(err, ErrTag) {
+		t.Errorf("Decode() error = %v, wanted ErrTag", err)
+	}
+}
@@ -0,0 +1,154 @@
+package mixedEndian
+
+import (
+	"encoding/binary"
+	"reflect"
+)
+
+// Odd width integers, encoded in exactly 3 or 6 bytes in the field's byte order.
+// They convert to and from their underlying Go types as usual, for example Uint24(x) or uint32(y).
+type (
+	Uint24 uint32
+	Int24  int32
+	Uint48 uint64
+	Int48  int64
+)
+
+// 128 bit integers, encoded in 16 bytes in the field's byte order. Hi holds the most significant
+// 64 bits and Lo the least. Int128 is two's complement, negative when Hi's top bit is set.
+type (
+	Uint128 struct{ Hi, Lo uint64 }
+	Int128  struct{ Hi, Lo uint64 }
+)
+
+// Int128FromInt64 sign extends i to 128 bits
+func Int128FromInt64(i int64) Int128 {
+	return Int128{Hi: uint64(i >> 63), Lo: uint64(i)}
+}
+
+// Sign is -1 if i is negative, 0 if it's zero, and 1 if it's positive
+func (i Int128) Sign() int {
+	switch {
+	case int64(i.Hi) < 0:
+		return -1
+	case i.Hi == 0 && i.Lo == 0:
+		return 0
+	default:
+		return 1
+	}
+}
+
+// Network byte order integers, always encoded in NetworkByteOrder whatever the byte order of the
+// field or call, as htons and htonl would leave them
+type (
+	NBO16 uint16
+	NBO32 uint32
+	NBO64 uint64
+)
+
+// NetworkByteOrder is the byte order of the NBO types, big endian unless changed. Protocols whose
+// specifications call little endian their network order can set it to LittleEndian.
+//
+// It's process-global, applying to every NBO field encoded or decoded anywhere, and is read
+// without synchronization: set it in an init function, before anything is encoded or decoded,
+// and never change it after.
+var NetworkByteOrder binary.ByteOrder = binary.BigEndian
+
+// Limits of the odd width integers
+const (
+	MaxUint24 = 1<<24 - 1
+	MaxInt24  = 1<<23 - 1
+	MinInt24  = -1 << 23
+	MaxUint48 = 1<<48 - 1
+	MaxInt48  = 1<<47 - 1
+	MinInt48  = -1 << 47
+)
+
+var (
+	uint24Type = reflect.TypeOf(Uint24(0))
+	int24Type  = reflect.TypeOf(Int24(0))
+	uint48Type = reflect.TypeOf(Uint48(0))
+	int48Type  = reflect.TypeOf(Int48(0))
+
+	uint128Type = reflect.TypeOf(Uint128{})
+	int128Type  = reflect.TypeOf(Int128{})
+
+	nbo16Type = reflect.TypeOf(NBO16(0))
+	nbo32Type = reflect.TypeOf(NBO32(0))
+	nbo64Type = reflect.TypeOf(NBO64(0))
+)
+
+// typeOrder is the byte order values of type t are encoded in, given o for the field holding them:
+// NetworkByteOrder for the NBO types, o for anything else
+func typeOrder(t reflect.Type, o binary.ByteOrder) binary.ByteOrder {
+	switch t {
+	case nbo16Type, nbo32Type, nbo64Type:
+		return NetworkByteOrder
+	}
+	return o
+}
+
+// isBigEndian reports whether o puts the most significant byte first
+func isBigEndian(o binary.ByteOrder) bool {
+	return o.Uint16([]byte{0x00, 0x01}) == 0x0001
+}
+
+// getUint reads an unsigned integer as wide as bs
+func getUint(bs []byte, o binary.ByteOrder) (u uint64) {
+	if isBigEndian(o) {
+		for _, b := range bs {
+			u = u<<8 | uint64(b)
+		}
+	} else {
+		for i := len(bs) - 1; i >= 0; i-- {
+			u = u<<8 | uint64(bs[i])
+		}
+	}
+	return
+}
+
+// putUint writes the low len(bs) bytes of u
+func putUint(bs []byte, u uint64, o binary.ByteOrder) {
+	if isBigEndian(o) {
+		for i := len(bs) - 1; i >= 0; i-- {
+			bs[i] = byte(u)
+			u >>= 8
+		}
+	} else {
+		for i := range bs {
+			bs[i] = byte(u)
+			u >>= 8
+		}
+	}
+}
+
+// get128 reads a 128 bit integer into the Hi and Lo fields of v
+func get128(v reflect.Value, bs []byte, o binary.ByteOrder) {
+	hi, lo := bs[:8], bs[8:]
+	if !isBigEndian(o) {
+		hi, lo = lo, hi
+	}
+	v.Field(0).SetUint(o.Uint64(hi))
+	v.Field(1).SetUint(o.Uint64(lo))
+}
+
+// put128 writes the Hi and Lo fields of 128 bit integer v
+func put128(bs []byte, v reflect.Value, o binary.ByteOrder) {
+	hi, lo := bs[:8], bs[8:]
+	if !isBigEndian(o) {
+		hi, lo = lo, hi
+	}
+	o.PutUint64(hi, v.Field(0).Uint())
+	o.PutUint64(lo, v.Field(1).Uint())
+}
+
+// wireOrder is the byte order conversions actually use, o unless built with network_order_swap
+func wireOrder(o binary.ByteOrder) binary.ByteOrder {
+	if !orderSwapped {
+		return o
+	}
+	if isBigEndian(o) {
+		return binary.LittleEndian
+	}
+	return binary.BigEndian
+}
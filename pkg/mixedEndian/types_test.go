@@ -0,0 +1,278 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type OddWidthStruct struct {
+	A Uint24
+	B Int24 `endian:"little"`
+	C Uint48
+	D Int48 `endian:"little"`
+}
+
+type OddWidthSliceStruct struct {
+	N    Uint24
+	Data []Int24 `len:"N"`
+}
+
+func TestOddWidthRead(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         []byte
+		defaultEndian binary.ByteOrder
+		data          any
+		wantData      any
+		wantErr       error
+	}{
+		{
+			name:          "positive values",
+			input:         []byte{0x01, 0x02, 0x03, 0x03, 0x02, 0x01, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01},
+			defaultEndian: BigEndian,
+			data:          &OddWidthStruct{},
+			wantData:      &OddWidthStruct{A: 0x010203, B: 0x010203, C: 0x010203040506, D: 0x010203040506},
+		},
+		{
+			name:          "negative Int24 big endian",
+			input:         []byte{0xFF, 0xFF, 0xFE},
+			defaultEndian: BigEndian,
+			data:          new(Int24),
+			wantData:      func() *Int24 { i := Int24(-2); return &i }(),
+		},
+		{
+			name:          "negative Int24 little endian",
+			input:         []byte{0xFE, 0xFF, 0xFF},
+			defaultEndian: LittleEndian,
+			data:          new(Int24),
+			wantData:      func() *Int24 { i := Int24(-2); return &i }(),
+		},
+		{
+			name:          "minimum Int24 tagged little endian",
+			input:         []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x80},
+			defaultEndian: BigEndian,
+			data:          &OddWidthStruct{},
+			wantData:      &OddWidthStruct{B: MinInt24, D: MinInt48},
+		},
+		{
+			name:          "slice sized by Uint24",
+			input:         []byte{0x00, 0x00, 0x02, 0xFF, 0xFF, 0xFF, 0x00, 0x00, 0x01},
+			defaultEndian: BigEndian,
+			data:          &OddWidthSliceStruct{},
+			wantData:      &OddWidthSliceStruct{N: 2, Data: []Int24{-1, 1}},
+		},
+		{
+			name:          "array of Int24",
+			input:         []byte{0xFF, 0xFF, 0x7F, 0x00, 0x00, 0x80},
+			defaultEndian: LittleEndian,
+			data:          &[2]Int24{},
+			wantData:      &[2]Int24{0x7FFFFF, MinInt24},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Read(bytes.NewReader(tt.input), tt.defaultEndian, &tt.data)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Read() error = %v, wanted %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if !reflect.DeepEqual(tt.data, tt.wantData) {
+				t.Errorf("Read() data = %v, wanted %v", tt.data, tt.wantData)
+			}
+		})
+	}
+}
+
+func TestOddWidthWrite(t *testing.T) {
+	tests := []struct {
+		name          string
+		defaultEndian binary.ByteOrder
+		data          any
+		want          []byte
+		wantErr       error
+	}{
+		{
+			name:          "mixed endian",
+			defaultEndian: BigEndian,
+			data:          OddWidthStruct{A: 0x010203, B: -2, C: 0x010203040506, D: -2},
+			want:          []byte{0x01, 0x02, 0x03, 0xFE, 0xFF, 0xFF, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0xFE, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF},
+		},
+		{
+			name:          "negative Int24 big endian",
+			defaultEndian: BigEndian,
+			data:          Int24(-2),
+			want:          []byte{0xFF, 0xFF, 0xFE},
+		},
+		{
+			name:          "negative Int24 little endian",
+			defaultEndian: LittleEndian,
+			data:          Int24(-2),
+			want:          []byte{0xFE, 0xFF, 0xFF},
+		},
+		{
+			name:          "slice of Int24",
+			defaultEndian: LittleEndian,
+			data:          []Int24{MaxInt24, MinInt24},
+			want:          []byte{0xFF, 0xFF, 0x7F, 0x00, 0x00, 0x80},
+		},
+		{
+			name:          "Uint24 too large",
+			defaultEndian: BigEndian,
+			data:          Uint24(MaxUint24 + 1),
+			wantErr:       ErrRange,
+		},
+		{
+			name:          "Int24 too small",
+			defaultEndian: BigEndian,
+			data:          Int24(MinInt24 - 1),
+			wantErr:       ErrRange,
+		},
+		{
+			name:          "Int48 too large",
+			defaultEndian: BigEndian,
+			data:          Int48(MaxInt48 + 1),
+			wantErr:       ErrRange,
+		},
+		{
+			name:          "length mismatch",
+			defaultEndian: BigEndian,
+			data:          OddWidthSliceStruct{N: 3, Data: []Int24{1}},
+			wantErr:       ErrLength,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			err := Write(buf, tt.defaultEndian, tt.data)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Write() error = %v, wanted %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if !bytes.Equal(buf.Bytes(), tt.want) {
+				t.Errorf("Write() = % X, wanted % X", buf.Bytes(), tt.want)
+			}
+		})
+	}
+}
+
+type WideStruct struct {
+	U Uint128
+	I Int128 `endian:"little"`
+}
+
+func TestWideReadWrite(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         []byte
+		defaultEndian binary.ByteOrder
+		data          any
+		wantData      any
+	}{
+		{
+			name: "mixed endian",
+			input: []byte{
+				0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F, 0x10,
+				0xFE, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF,
+			},
+			defaultEndian: BigEndian,
+			data:          &WideStruct{},
+			wantData: &WideStruct{
+				U: Uint128{Hi: 0x0102030405060708, Lo: 0x090A0B0C0D0E0F10},
+				I: Int128FromInt64(-2),
+			},
+		},
+		{
+			name:          "Uint128 little endian",
+			input:         []byte{0x10, 0x0F, 0x0E, 0x0D, 0x0C, 0x0B, 0x0A, 0x09, 0x08, 0x07, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01},
+			defaultEndian: LittleEndian,
+			data:          new(Uint128),
+			wantData:      &Uint128{Hi: 0x0102030405060708, Lo: 0x090A0B0C0D0E0F10},
+		},
+		{
+			name:          "array of Int128",
+			input:         append(bytes.Repeat([]byte{0xFF}, 16), append([]byte{0x80}, make([]byte, 15)...)...),
+			defaultEndian: BigEndian,
+			data:          &[2]Int128{},
+			wantData:      &[2]Int128{Int128FromInt64(-1), {Hi: 1 << 63}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Read(bytes.NewReader(tt.input), tt.defaultEndian, &tt.data); err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if !reflect.DeepEqual(tt.data, tt.wantData) {
+				t.Errorf("Read() data = %v, wanted %v", tt.data, tt.wantData)
+			}
+
+			buf := &bytes.Buffer{}
+			if err := Write(buf, tt.defaultEndian, tt.wantData); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if !bytes.Equal(buf.Bytes(), tt.input) {
+				t.Errorf("Write() = %x, wanted %x", buf.Bytes(), tt.input)
+			}
+		})
+	}
+}
+
+func TestInt128Sign(t *testing.T) {
+	for i, want := range map[int64]int{-1 << 63: -1, -1: -1, 0: 0, 1: 1, 1<<63 - 1: 1} {
+		if got := Int128FromInt64(i).Sign(); got != want {
+			t.Errorf("Int128FromInt64(%d).Sign() = %d, wanted %d", i, got, want)
+		}
+	}
+}
+
+type NBOStruct struct {
+	A NBO16
+	B NBO32 `endian:"little"`
+	C NBO64
+	D uint16
+}
+
+func TestNetworkByteOrder(t *testing.T) {
+	data := NBOStruct{A: 0x0102, B: 0x03040506, C: 0x0708090A0B0C0D0E, D: 0x0F10}
+	tests := []struct {
+		name  string
+		order binary.ByteOrder
+		want  []byte
+	}{
+		{"big", BigEndian, []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 0x10, 0x0F}},
+		{"little", LittleEndian, []byte{2, 1, 6, 5, 4, 3, 14, 13, 12, 11, 10, 9, 8, 7, 0x10, 0x0F}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func(o binary.ByteOrder) { NetworkByteOrder = o }(NetworkByteOrder)
+			NetworkByteOrder = tt.order
+
+			got, err := Marshal(LittleEndian, data)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("Marshal() = % x, wanted % x", got, tt.want)
+			}
+			var back NBOStruct
+			if err = Unmarshal(LittleEndian, got, &back); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if back != data {
+				t.Errorf("Unmarshal() = %+v, wanted %+v", back, data)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package mixedEndian
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// Unmarshal reads data, which must be a pointer, from bs as Read would from a bytes.Reader.
+// Bytes beyond the value are ignored.
+//
+// Fixed size fields are decoded straight from bs, without the copying and interface calls of an
+// io.Reader, and structs holding nothing else, tagged with no more than a byte order, are laid
+// out once per type so their tags aren't looked at again. That makes Unmarshal the faster choice
+// for small structs already in memory.
+func Unmarshal(defaultEndian binary.ByteOrder, bs []byte, data any) error {
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Pointer {
+		return fmt.Errorf("%w Expected pointer; Got %T", ErrUnexpectedType, data)
+	}
+
+	sr := &sliceReader{bs: bs}
+	r := reader{r: sr, o: defaultEndian, ctx: context.Background()}
+	err := r.readOrdered(v, defaultEndian)
+	return readError(sr, 0, int64(sr.off), err)
+}
+
+// sliceReader reads from bs, and lets a reader take fixed size fields from it without copying
+type sliceReader struct {
+	bs  []byte
+	off int
+}
+
+func (s *sliceReader) Read(bs []byte) (int, error) {
+	if s.off >= len(s.bs) {
+		return 0, io.EOF
+	}
+	n := copy(bs, s.bs[s.off:])
+	s.off += n
+	return n, nil
+}
+
+func (s *sliceReader) ReadByte() (byte, error) {
+	if s.off >= len(s.bs) {
+		return 0, io.EOF
+	}
+	s.off++
+	return s.bs[s.off-1], nil
+}
+
+// Seek moves to offset in the slice, so positions in errors are those in it, as with a bytes.Reader
+func (s *sliceReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += int64(s.off)
+	case io.SeekEnd:
+		offset += int64(len(s.bs))
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("%w Seeking to %d", ErrLength, offset)
+	}
+	s.off = int(offset)
+	return offset, nil
+}
+
+// next returns the next n bytes, erroring as io.ReadFull does when there aren't enough.
+// Reading from a sliceReader, they're taken from its slice, so mustn't be modified.
+func (r *reader) next(n int) ([]byte, error) {
+	if s, ok := r.r.(*sliceReader); ok {
+		switch rest := len(s.bs) - s.off; {
+		case rest >= n:
+			s.off += n
+			return s.bs[s.off-n : s.off], nil
+		case rest == 0 && n > 0:
+			return nil, io.EOF
+		default:
+			s.off = len(s.bs)
+			return nil, io.ErrUnexpectedEOF
+		}
+	}
+
+	bs := make([]byte, n)
+	if _, err := io.ReadFull(r.r, bs); err != nil {
+		return nil, err
+	}
+	return bs, nil
+}
+
+// fieldPlan is how a struct's fields lie on the wire, worked out once per type. Structs whose
+// fields are all fixed size, and untagged but for their byte order, are plain, so can be decoded
+// straight from a slice without looking at their tags again.
+type fieldPlan struct {
+	plain  bool
+	size   int
+	fields []plannedField
+}
+
+// plannedField is a field of a plain struct
+type plannedField struct {
+	index int
+	size  int
+
+	// order is the field's byte order, or nil for the struct's
+	order binary.ByteOrder
+
+	// elems is the length of an array field, and sub the plan of a struct field
+	elems int
+	sub   *fieldPlan
+}
+
+// fieldPlans holds the plans of the structs planOf has seen
+var fieldPlans sync.Map
+
+// planOf is the fieldPlan of struct type t
+func planOf(t reflect.Type) *fieldPlan {
+	if p, ok := fieldPlans.Load(t); ok {
+		return p.(*fieldPlan)
+	}
+
+	p := &fieldPlan{plain: true}
+	for i := 0; i < t.NumField() && p.plain; i++ {
+		sf := t.Field(i)
+		// Unexported fields aren't on the wire, and blank ones carry struct options
+		if sf.Name == "_" {
+			p.plain = false
+			break
+		} else if !sf.IsExported() {
+			continue
+		}
+
+		pf := plannedField{index: i}
+		switch sf.Tag {
+		case "":
+		case `endian:"big"`:
+			pf.order = BigEndian
+		case `endian:"little"`:
+			pf.order = LittleEndian
+		default:
+			p.plain = false
+		}
+
+		ft := sf.Type
+		switch {
+		case typeSize(ft) > 0:
+			pf.size = typeSize(ft)
+		case ft.Kind() == reflect.Array && ft.Len() > 0 && typeSize(ft.Elem()) > 0:
+			pf.elems, pf.size = ft.Len(), ft.Len()*typeSize(ft.Elem())
+		case ft.Kind() == reflect.Struct && ft != timecodeType && ft != serializableType && !isOptional(ft):
+			if pf.sub = planOf(ft); pf.sub.plain {
+				pf.size = pf.sub.size
+			} else {
+				p.plain = false
+			}
+		default:
+			p.plain = false
+		}
+		p.size += pf.size
+		p.fields = append(p.fields, pf)
+	}
+
+	fieldPlans.Store(t, p)
+	return p
+}
+
+// decode sets the fields of plain struct v from bs, which holds the whole of it
+func (p *fieldPlan) decode(v reflect.Value, bs []byte, o binary.ByteOrder) {
+	for _, pf := range p.fields {
+		fo := o
+		if pf.order != nil {
+			fo = pf.order
+		}
+		f, part := v.Field(pf.index), bs[:pf.size]
+		bs = bs[pf.size:]
+
+		switch {
+		case pf.sub != nil:
+			pf.sub.decode(f, part, fo)
+		case pf.elems > 0:
+			n := pf.size / pf.elems
+			for i := 0; i < pf.elems; i++ {
+				decode(f.Index(i), part[i*n:(i+1)*n], fo)
+			}
+		default:
+			decode(f, part, fo)
+		}
+	}
+}
+
+// readPlain reads struct v straight from a sliceReader, if it's plain and there's the whole of it
+// there, reporting whether it did. Options which could leave fields out, or reorder them, rule it
+// out.
+func (r *reader) readPlain(v reflect.Value, o binary.ByteOrder) bool {
+	s, ok := r.r.(*sliceReader)
+	if !ok || !v.CanSet() || r.trace != nil || r.overrides != nil || r.presence != nil || r.versions.version != 0 || r.ctx.Err() != nil {
+		return false
+	}
+	p := planOf(v.Type())
+	if !p.plain || len(s.bs)-s.off < p.size {
+		return false
+	}
+	p.decode(v, s.bs[s.off:s.off+p.size], o)
+	s.off += p.size
+	return true
+}
@@ -0,0 +1,121 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+type UnmarshalStruct struct {
+	A NoTagStruct
+	B NestedStruct
+	N uint8
+	C []Int24 `len:"N" endian:"little"`
+	D uint32  `encoding:"uvarint"`
+	E string  `size:"3"`
+	F [2]bool
+}
+
+func TestUnmarshal(t *testing.T) {
+	wire := []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD}
+	got := &NoTagStruct{}
+	if err := Unmarshal(BigEndian, wire, got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	want := &NoTagStruct{A: 0x01, B: 0x2345, C: 0x6789ABCD}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unmarshal() = %v, wanted %v", got, want)
+	}
+}
+
+// Unmarshal must decode exactly as Read from a bytes.Reader does, errors included
+func TestUnmarshalMatchesRead(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		bs := make([]byte, rng.Intn(48))
+		rng.Read(bs)
+		// Keep C short, so plenty of inputs hold the whole struct
+		if len(bs) > 13 {
+			bs[13] %= 4
+		}
+
+		var read any = &UnmarshalStruct{}
+		readErr := Read(bytes.NewReader(bs), BigEndian, &read)
+		unmarshaled := &UnmarshalStruct{}
+		unmarshalErr := Unmarshal(BigEndian, bs, unmarshaled)
+
+		if fmt.Sprint(readErr) != fmt.Sprint(unmarshalErr) {
+			t.Fatalf("% X: Read() error = %v, Unmarshal() error = %v", bs, readErr, unmarshalErr)
+		}
+		if !reflect.DeepEqual(read, unmarshaled) {
+			t.Fatalf("% X: Read() = %v, Unmarshal() = %v", bs, read, unmarshaled)
+		}
+	}
+}
+
+// PlainStruct is laid out once, its unexported field left off the wire as Read leaves it
+type PlainStruct struct {
+	A      NestedStruct
+	B      [3]Int24 `endian:"little"`
+	hidden uint8
+	C      Uint128
+	D      bool
+}
+
+func TestUnmarshalPlan(t *testing.T) {
+	tests := []struct {
+		data  any
+		plain bool
+		size  int
+	}{
+		{data: PlainStruct{}, plain: true, size: 8 + 9 + 16 + 1},
+		{data: NoTagStruct{}, plain: true, size: 7},
+		{data: UnmarshalStruct{}},
+		{data: AlignedStruct8{}},
+		{data: struct{ A int }{}},
+	}
+	for _, tt := range tests {
+		if p := planOf(reflect.TypeOf(tt.data)); p.plain != tt.plain || (p.plain && p.size != tt.size) {
+			t.Errorf("planOf(%T) = %v of %d bytes, wanted %v of %d", tt.data, p.plain, p.size, tt.plain, tt.size)
+		}
+	}
+
+	wire := make([]byte, 34)
+	for i := range wire {
+		wire[i] = byte(i * 37)
+	}
+	var read any = &PlainStruct{hidden: 9}
+	if err := Read(bytes.NewReader(wire), BigEndian, &read); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	got := &PlainStruct{hidden: 9}
+	if err := Unmarshal(BigEndian, wire, got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(read, got) {
+		t.Errorf("Unmarshal() = %+v, Read() = %+v", got, read)
+	}
+}
+
+func BenchmarkReadSmall(b *testing.B) {
+	wire := []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var data any = &NestedStruct{}
+		if err := Read(bytes.NewReader(wire), BigEndian, &data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalSmall(b *testing.B) {
+	wire := []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := Unmarshal(BigEndian, wire, &NestedStruct{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
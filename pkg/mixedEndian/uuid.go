@@ -0,0 +1,65 @@
+package mixedEndian
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+)
+
+// UUID is an RFC 4122 UUID, its bytes in the RFC's order
+type UUID [16]byte
+
+// String formats u as xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx
+func (u UUID) String() string {
+	var bs [36]byte
+	hex.Encode(bs[0:8], u[0:4])
+	hex.Encode(bs[9:13], u[4:6])
+	hex.Encode(bs[14:18], u[6:8])
+	hex.Encode(bs[19:23], u[8:10])
+	hex.Encode(bs[24:36], u[10:16])
+	bs[8], bs[13], bs[18], bs[23] = '-', '-', '-', '-'
+	return string(bs[:])
+}
+
+// ParseUUID parses s as formatted by UUID.String, in either case
+func ParseUUID(s string) (u UUID, err error) {
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return u, fmt.Errorf("%w %q is not a UUID", ErrRange, s)
+	}
+	bs := []byte(s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:36])
+	if _, err = hex.Decode(u[:], bs); err != nil {
+		return u, fmt.Errorf("%w %q is not a UUID", ErrRange, s)
+	}
+	return
+}
+
+// isUUID reports whether sf is tagged as a UUID, and whether it's stored with its first three
+// fields little endian, as COM and Variant-1 GUIDs are
+func isUUID(sf reflect.StructField) (ok, le bool, err error) {
+	switch sf.Tag.Get("encoding") {
+	case "uuid":
+	case "uuid_le":
+		le = true
+	default:
+		return false, false, nil
+	}
+
+	t := sf.Type
+	if t.Kind() != reflect.Array || t.Len() != 16 || t.Elem().Kind() != reflect.Uint8 {
+		return false, false, fmt.Errorf("%w %s needs a [16]byte; Got %s", ErrUnexpectedType, sf.Tag.Get("encoding"), t.String())
+	}
+	return true, le, nil
+}
+
+// swapUUID sets dst to UUID src with its first three fields, of 4, 2, and 2 bytes, reversed.
+// It's its own inverse, converting either way between RFC 4122 and little endian GUID order.
+func swapUUID(dst, src reflect.Value) {
+	for _, span := range [][2]int{{0, 4}, {4, 6}, {6, 8}} {
+		for i, j := span[0], span[1]-1; i < span[1]; i, j = i+1, j-1 {
+			dst.Index(i).SetUint(src.Index(j).Uint())
+		}
+	}
+	for i := 8; i < 16; i++ {
+		dst.Index(i).SetUint(src.Index(i).Uint())
+	}
+}
@@ -0,0 +1,82 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type UUIDStruct struct {
+	ID   UUID     `encoding:"uuid"`
+	GUID UUID     `encoding:"uuid_le"`
+	Raw  [16]byte `encoding:"uuid_le"`
+}
+
+type BadUUIDStruct struct {
+	ID [8]byte `encoding:"uuid"`
+}
+
+// uuidBytes is 00112233-4455-6677-8899-aabbccddeeff in RFC 4122 order
+var uuidBytes = UUID{0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99, 0xAA, 0xBB, 0xCC, 0xDD, 0xEE, 0xFF}
+
+func TestUUIDString(t *testing.T) {
+	want := "00112233-4455-6677-8899-aabbccddeeff"
+	if got := uuidBytes.String(); got != want {
+		t.Errorf("String() = %s, wanted %s", got, want)
+	}
+
+	for _, s := range []string{want, "00112233-4455-6677-8899-AABBCCDDEEFF"} {
+		u, err := ParseUUID(s)
+		if err != nil {
+			t.Fatalf("ParseUUID(%q) error = %v", s, err)
+		}
+		if u != uuidBytes {
+			t.Errorf("ParseUUID(%q) = %v, wanted %v", s, u, uuidBytes)
+		}
+	}
+
+	for _, s := range []string{"", "00112233445566778899aabbccddeeff", "0011223-34455-6677-8899-aabbccddeeff", "0011223g-4455-6677-8899-aabbccddeeff"} {
+		if _, err := ParseUUID(s); !errors.Is(err, ErrRange) {
+			t.Errorf("ParseUUID(%q) error = %v, wanted %v", s, err, ErrRange)
+		}
+	}
+}
+
+func TestUUIDReadWrite(t *testing.T) {
+	le := []byte{0x33, 0x22, 0x11, 0x00, 0x55, 0x44, 0x77, 0x66, 0x88, 0x99, 0xAA, 0xBB, 0xCC, 0xDD, 0xEE, 0xFF}
+	input := append(append(append([]byte{}, uuidBytes[:]...), le...), le...)
+	want := UUIDStruct{ID: uuidBytes, GUID: uuidBytes, Raw: uuidBytes}
+
+	// Endianness doesn't come into it
+	for _, o := range []binary.ByteOrder{BigEndian, LittleEndian} {
+		t.Run(o.String(), func(t *testing.T) {
+			var got any = &UUIDStruct{}
+			if err := Read(bytes.NewReader(input), o, &got); err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, &want) {
+				t.Errorf("Read() = %v, wanted %v", got, want)
+			}
+
+			buf := &bytes.Buffer{}
+			if err := Write(buf, o, want); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if !bytes.Equal(buf.Bytes(), input) {
+				t.Errorf("Write() = %x, wanted %x", buf.Bytes(), input)
+			}
+		})
+	}
+}
+
+func TestUUIDErrors(t *testing.T) {
+	var bad any = &BadUUIDStruct{}
+	if err := Read(bytes.NewReader(make([]byte, 8)), BigEndian, &bad); !errors.Is(err, ErrUnexpectedType) {
+		t.Errorf("Read() error = %v, wanted %v", err, ErrUnexpectedType)
+	}
+	if err := Write(&bytes.Buffer{}, BigEndian, bad); !errors.Is(err, ErrUnexpectedType) {
+		t.Errorf("Write() error = %v, wanted %v", err, ErrUnexpectedType)
+	}
+}
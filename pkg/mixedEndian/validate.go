@@ -0,0 +1,136 @@
+package mixedEndian
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// parseInto sets integer v from s, which may be in any base strconv understands
+func parseInto(v reflect.Value, s string) error {
+	s = strings.TrimSpace(s)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 0, 64)
+		if err != nil || v.OverflowInt(i) {
+			return fmt.Errorf("%w Can't use %q as %s", ErrTag, s, v.Type().String())
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 0, 64)
+		if err != nil || v.OverflowUint(u) {
+			return fmt.Errorf("%w Can't use %q as %s", ErrTag, s, v.Type().String())
+		}
+		v.SetUint(u)
+	default:
+		return fmt.Errorf("%w Expected integer; Got %s", ErrUnexpectedType, v.Type().String())
+	}
+	return nil
+}
+
+// constValue builds the value described by a const tag.
+// Integers take a single value, arrays and slices of integers a comma separated list.
+func constValue(t reflect.Type, c string) (v reflect.Value, err error) {
+	v = reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.Array, reflect.Slice:
+		elems := strings.Split(c, ",")
+		if t.Kind() == reflect.Slice {
+			v.Set(reflect.MakeSlice(t, len(elems), len(elems)))
+		} else if len(elems) != t.Len() {
+			return v, fmt.Errorf("%w const has %d elements, %s holds %d", ErrTag, len(elems), t.String(), t.Len())
+		}
+		for i, e := range elems {
+			if err = parseInto(v.Index(i), e); err != nil {
+				return
+			}
+		}
+	default:
+		err = parseInto(v, c)
+	}
+	return
+}
+
+// checkConst errors if v doesn't hold the value of its const tag
+func checkConst(v reflect.Value, c string) error {
+	want, err := constValue(v.Type(), c)
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(v.Interface(), want.Interface()) {
+		return fmt.Errorf("%w Got %v, expected const %v", ErrValidation, v.Interface(), want.Interface())
+	}
+	return nil
+}
+
+// mirrorOf finds the field of struct v named by ref, which mirror field sf copies.
+// It must come before sf, so it's read first, and be of the same type.
+func mirrorOf(v reflect.Value, sf reflect.StructField, ref string) (reflect.Value, error) {
+	src, ok := v.Type().FieldByName(ref)
+	if !ok || len(src.Index) != 1 || src.Index[0] >= sf.Index[len(sf.Index)-1] {
+		return v, fmt.Errorf("%w mirror %q is not an earlier field", ErrTag, ref)
+	}
+	if src.Type != sf.Type {
+		return v, fmt.Errorf("%w mirror of %s %s needs the same type; Got %s", ErrUnexpectedType, src.Type.String(), ref, sf.Type.String())
+	}
+	return v.Field(src.Index[0]), nil
+}
+
+// checkMirror errors if f, field sf of struct v, doesn't match the field it mirrors
+func checkMirror(v reflect.Value, sf reflect.StructField, f reflect.Value, ref string) error {
+	src, err := mirrorOf(v, sf, ref)
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(f.Interface(), src.Interface()) {
+		return fmt.Errorf("%w Got %v, expected %v mirroring %s", ErrValidation, f.Interface(), src.Interface(), ref)
+	}
+	return nil
+}
+
+// sameInt reports whether integers a and b, of the same type, hold the same value
+func sameInt(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == b.Int()
+	default:
+		return a.Uint() == b.Uint()
+	}
+}
+
+// checkEnum errors if integer v isn't one of the comma separated values of its enum tag
+func checkEnum(v reflect.Value, enum string) error {
+	option := reflect.New(v.Type()).Elem()
+	for _, e := range strings.Split(enum, ",") {
+		if err := parseInto(option, e); err != nil {
+			return err
+		}
+		if sameInt(option, v) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w %v is not one of %s", ErrValidation, v, enum)
+}
+
+// validate applies a field's const and enum tags to freshly read v.
+// Values outside an enum are replaced by its enumdefault tag, if given.
+func validate(sf reflect.StructField, v reflect.Value) error {
+	if c := sf.Tag.Get("const"); c != "" {
+		if err := checkConst(v, c); err != nil {
+			return err
+		}
+	}
+	if e := sf.Tag.Get("enum"); e != "" {
+		err := checkEnum(v, e)
+		if d := sf.Tag.Get("enumdefault"); d != "" && errors.Is(err, ErrValidation) {
+			// Unknown codes stand for the default instead
+			err = parseInto(v, d)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type ValidatedStruct struct {
+	Magic [3]byte `const:"0x47,0x49,0x46"`
+	Kind  uint8   `enum:"1,2,0x10"`
+}
+
+func TestValidateRead(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		wantData any
+		wantErr  error
+	}{
+		{
+			name:     "valid",
+			input:    []byte{'G', 'I', 'F', 0x10},
+			wantData: &ValidatedStruct{Magic: [3]byte{'G', 'I', 'F'}, Kind: 0x10},
+		},
+		{
+			name:    "bad const",
+			input:   []byte{'G', 'I', 'X', 0x01},
+			wantErr: ErrValidation,
+		},
+		{
+			name:    "bad enum",
+			input:   []byte{'G', 'I', 'F', 0x03},
+			wantErr: ErrValidation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data any = &ValidatedStruct{}
+			err := Read(bytes.NewReader(tt.input), BigEndian, &data)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Read() error = %v, wanted %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if !reflect.DeepEqual(data, tt.wantData) {
+				t.Errorf("Read() data = %v, wanted %v", data, tt.wantData)
+			}
+		})
+	}
+}
+
+func TestValidateWrite(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    any
+		want    []byte
+		wantErr error
+	}{
+		{
+			name: "const written regardless",
+			data: ValidatedStruct{Kind: 2},
+			want: []byte{'G', 'I', 'F', 0x02},
+		},
+		{
+			name:    "bad enum",
+			data:    ValidatedStruct{Kind: 3},
+			wantErr: ErrValidation,
+		},
+		{
+			name: "malformed tag",
+			data: struct {
+				A uint8 `const:"256"`
+			}{},
+			wantErr: ErrTag,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			err := Write(buf, BigEndian, tt.data)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Write() error = %v, wanted %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if !bytes.Equal(buf.Bytes(), tt.want) {
+				t.Errorf("Write() = % X, wanted % X", buf.Bytes(), tt.want)
+			}
+		})
+	}
+}
+
+func TestEnumDefault(t *testing.T) {
+	type message struct {
+		Type  uint8 `enum:"1,2,3" enumdefault:"0"`
+		Value uint8
+	}
+
+	var data any = &message{}
+	if err := Read(bytes.NewReader([]byte{0x07, 0x2A, 0x02, 0x2B}), BigEndian, &data); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if want := (&message{Type: 0, Value: 0x2A}); !reflect.DeepEqual(data, want) {
+		t.Errorf("Read() data = %v, wanted %v", data, want)
+	}
+
+	// Known codes are untouched
+	data = &message{}
+	if err := Read(bytes.NewReader([]byte{0x02, 0x2B}), BigEndian, &data); err != nil || data.(*message).Type != 2 {
+		t.Errorf("Read() data = %v, %v, wanted Type 2", data, err)
+	}
+
+	// What's read as the default can be written back, though it's not in the enum
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, message{Type: 0, Value: 0x2A}); err != nil || !bytes.Equal(buf.Bytes(), []byte{0x00, 0x2A}) {
+		t.Errorf("Write() = % X, %v, wanted 00 2A", buf.Bytes(), err)
+	}
+	if err := Write(buf, BigEndian, message{Type: 7}); !errors.Is(err, ErrValidation) {
+		t.Errorf("Write() error = %v, wanted %v", err, ErrValidation)
+	}
+
+	var bad any = &struct {
+		Type uint8 `enum:"1,2" enumdefault:"x"`
+	}{}
+	if err := Read(bytes.NewReader([]byte{0x07}), BigEndian, &bad); !errors.Is(err, ErrTag) {
+		t.Errorf("Read() error = %v, wanted %v", err, ErrTag)
+	}
+}
+
+type MirrorStruct struct {
+	Length uint16
+	Name   [2]byte
+	Copy   uint16 `mirror:"Length" endian:"little"`
+}
+
+func TestMirror(t *testing.T) {
+	wire := []byte{0x01, 0x02, 'h', 'i', 0x02, 0x01}
+	want := &MirrorStruct{Length: 0x0102, Name: [2]byte{'h', 'i'}, Copy: 0x0102}
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, BigEndian, MirrorStruct{Length: 0x0102, Name: [2]byte{'h', 'i'}, Copy: 0xFFFF}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), wire) {
+		t.Errorf("Write() = % X, wanted % X", buf.Bytes(), wire)
+	}
+
+	var data any = &MirrorStruct{}
+	if err := Read(bytes.NewReader(wire), BigEndian, &data); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("Read() data = %v, wanted %v", data, want)
+	}
+
+	corrupt := []byte{0x01, 0x02, 'h', 'i', 0x02, 0x00}
+	data = &MirrorStruct{}
+	if err := Read(bytes.NewReader(corrupt), BigEndian, &data); !errors.Is(err, ErrValidation) {
+		t.Errorf("Read() error = %v, wanted %v", err, ErrValidation)
+	}
+}
+
+func TestMirrorErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    any
+		wantErr error
+	}{
+		{"later field", struct {
+			A uint8 `mirror:"B"`
+			B uint8
+		}{}, ErrTag},
+		{"missing field", struct {
+			A uint8 `mirror:"Z"`
+		}{}, ErrTag},
+		{"different type", struct {
+			A uint8
+			B uint16 `mirror:"A"`
+		}{}, ErrUnexpectedType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Write(&bytes.Buffer{}, BigEndian, tt.data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Write() error = %v, wanted %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package mixedEndian
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// isVarint reports whether sf is tagged with a variable length integer encoding.
+// `encoding:"uvarint"` applies to unsigned integers and `encoding:"varint"`, zigzag encoded,
+// to signed ones. Both are the LEB128 style encodings of encoding/binary, to which byte order
+// doesn't apply.
+func isVarint(sf reflect.StructField) bool {
+	e := sf.Tag.Get("encoding")
+	return e == "varint" || e == "uvarint"
+}
+
+// byteReader reads single bytes for encoding/binary's varint functions
+type byteReader struct {
+	r  io.Reader
+	bs [1]byte
+}
+
+func (b *byteReader) ReadByte() (byte, error) {
+	_, err := io.ReadFull(b.r, b.bs[:])
+	return b.bs[0], err
+}
+
+// byteReader is r's io.Reader as an io.ByteReader, a sliceReader being one already
+func (r *reader) byteReader() io.ByteReader {
+	if s, ok := r.r.(*sliceReader); ok {
+		return s
+	}
+	return &byteReader{r: r.r}
+}
+
+// readVarint reads integer f, or each element of array or slice f, as a varint
+func (r *reader) readVarint(f reflect.Value, e string) error {
+	switch f.Kind() {
+	case reflect.Array, reflect.Slice:
+		if f.Kind() == reflect.Slice && f.IsNil() {
+			return errNoLength
+		}
+		for i := 0; i < f.Len(); i++ {
+			if err := r.readVarint(f.Index(i), e); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if e != "varint" {
+			return fmt.Errorf("%w %s needs a signed integer; Got %s", ErrUnexpectedType, e, f.Type().String())
+		}
+		i, err := binary.ReadVarint(r.byteReader())
+		if err != nil {
+			return err
+		}
+		if f.OverflowInt(i) {
+			return fmt.Errorf("%w %d does not fit in %s", ErrRange, i, f.Type().String())
+		}
+		f.SetInt(i)
+		return nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if e != "uvarint" {
+			return fmt.Errorf("%w %s needs an unsigned integer; Got %s", ErrUnexpectedType, e, f.Type().String())
+		}
+		u, err := binary.ReadUvarint(r.byteReader())
+		if err != nil {
+			return err
+		}
+		if f.OverflowUint(u) {
+			return fmt.Errorf("%w %d does not fit in %s", ErrRange, u, f.Type().String())
+		}
+		f.SetUint(u)
+		return nil
+
+	default:
+		return fmt.Errorf("%w %s needs integers; Got %s", ErrUnexpectedType, e, f.Type().String())
+	}
+}
+
+// writeVarint writes integer f, or each element of array or slice f, as a varint
+func (w *writer) writeVarint(f reflect.Value, e string) error {
+	switch f.Kind() {
+	case reflect.Array, reflect.Slice:
+		for i := 0; i < f.Len(); i++ {
+			if err := w.writeVarint(f.Index(i), e); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if e != "varint" {
+			return fmt.Errorf("%w %s needs a signed integer; Got %s", ErrUnexpectedType, e, f.Type().String())
+		}
+		var bs [binary.MaxVarintLen64]byte
+		_, err := w.w.Write(bs[:binary.PutVarint(bs[:], f.Int())])
+		return err
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if e != "uvarint" {
+			return fmt.Errorf("%w %s needs an unsigned integer; Got %s", ErrUnexpectedType, e, f.Type().String())
+		}
+		var bs [binary.MaxVarintLen64]byte
+		_, err := w.w.Write(bs[:binary.PutUvarint(bs[:], f.Uint())])
+		return err
+
+	default:
+		return fmt.Errorf("%w %s needs integers; Got %s", ErrUnexpectedType, e, f.Type().String())
+	}
+}
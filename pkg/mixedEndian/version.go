@@ -0,0 +1,94 @@
+package mixedEndian
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// VersionRange is the protocol versions from Min up to and including Max.
+// A Max of 0 has no upper bound.
+type VersionRange struct {
+	Min, Max int
+}
+
+// Contains reports whether version v is in the range
+func (vr VersionRange) Contains(v int) bool {
+	return v >= vr.Min && (vr.Max == 0 || v <= vr.Max)
+}
+
+// VersionNegotiator settles the protocol version a Decoder or Encoder handles fields for.
+//
+// Supported is the versions this end speaks. Versions gives the versions a field is present in,
+// keyed by its struct type then its name, for fields that can't carry added_in and removed_in
+// tags, such as those of generated types. An entry there takes the place of any tags. Keying by
+// type keeps same named types of different packages, or of different functions, apart.
+type VersionNegotiator struct {
+	Supported VersionRange
+	Versions  map[reflect.Type]map[string]VersionRange
+}
+
+// Negotiate is the highest version supported by both this end and a peer speaking peer
+func (vn *VersionNegotiator) Negotiate(peer VersionRange) (int, error) {
+	v := vn.Supported.Max
+	if v == 0 || (peer.Max != 0 && peer.Max < v) {
+		v = peer.Max
+	}
+	if v == 0 {
+		return 0, fmt.Errorf("%w Neither end caps the version", ErrRange)
+	}
+	if !vn.Supported.Contains(v) || !peer.Contains(v) {
+		return 0, fmt.Errorf("%w No version common to %v and %v", ErrRange, vn.Supported, peer)
+	}
+	return v, nil
+}
+
+// versioning is the negotiated version, 0 until set, and the negotiator giving field versions
+type versioning struct {
+	version int
+	vn      *VersionNegotiator
+}
+
+// present reports whether field sf of struct t is in the negotiated version. Until a version is
+// set, every field is.
+func (vs versioning) present(t reflect.Type, sf reflect.StructField) (bool, error) {
+	if vs.version == 0 {
+		return true, nil
+	}
+	vr, err := vs.fieldVersions(t, sf)
+	return err != nil || vr.Contains(vs.version), err
+}
+
+// fieldVersions is the versions field sf of struct t is present in, from the negotiator's
+// Versions if it's there, or else its added_in and removed_in tags
+func (vs versioning) fieldVersions(t reflect.Type, sf reflect.StructField) (VersionRange, error) {
+	if vs.vn != nil {
+		if vr, ok := vs.vn.Versions[t][sf.Name]; ok {
+			return vr, nil
+		}
+	}
+	return versionTags(sf)
+}
+
+// versionTags is the versions given by sf's added_in and removed_in tags. A field removed in
+// version N is last present in N-1.
+func versionTags(sf reflect.StructField) (vr VersionRange, err error) {
+	if s := sf.Tag.Get("added_in"); s != "" {
+		if vr.Min, err = strconv.Atoi(s); err != nil || vr.Min < 1 {
+			return vr, fmt.Errorf("%w added_in %q is not a version", ErrTag, s)
+		}
+	}
+	if s := sf.Tag.Get("removed_in"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= vr.Min || n < 2 {
+			return vr, fmt.Errorf("%w removed_in %q is not a version after added_in", ErrTag, s)
+		}
+		vr.Max = n - 1
+	}
+	return
+}
+
+// isVersioned reports whether sf is only present in some versions
+func isVersioned(sf reflect.StructField) bool {
+	return sf.Tag.Get("added_in") != "" || sf.Tag.Get("removed_in") != ""
+}
@@ -0,0 +1,114 @@
+package mixedEndian
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type VersionedHello struct {
+	Kind     uint8
+	Flags    uint16 `added_in:"2"`
+	Legacy   uint8  `removed_in:"3"`
+	Checksum uint8
+}
+
+func TestVersionedDecode(t *testing.T) {
+	tests := []struct {
+		version int
+		wire    []byte
+		want    VersionedHello
+	}{
+		{0, []byte{1, 0x02, 0x03, 4, 5}, VersionedHello{Kind: 1, Flags: 0x0203, Legacy: 4, Checksum: 5}},
+		{1, []byte{1, 4, 5}, VersionedHello{Kind: 1, Legacy: 4, Checksum: 5}},
+		{2, []byte{1, 0x02, 0x03, 4, 5}, VersionedHello{Kind: 1, Flags: 0x0203, Legacy: 4, Checksum: 5}},
+		{3, []byte{1, 0x02, 0x03, 5}, VersionedHello{Kind: 1, Flags: 0x0203, Checksum: 5}},
+	}
+	for _, tt := range tests {
+		d := NewDecoder(bytes.NewReader(tt.wire), BigEndian)
+		d.SetVersion(tt.version)
+		got := VersionedHello{Flags: 0xFFFF, Legacy: 0xFF}
+		if err := d.Decode(&got); err != nil {
+			t.Fatalf("Decode() version %d error = %v", tt.version, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Decode() version %d = %+v, wanted %+v", tt.version, got, tt.want)
+		}
+
+		buf := &bytes.Buffer{}
+		e := NewEncoder(buf, BigEndian)
+		e.SetVersion(tt.version)
+		if err := e.Encode(tt.want); err != nil {
+			t.Fatalf("Encode() version %d error = %v", tt.version, err)
+		}
+		if !bytes.Equal(buf.Bytes(), tt.wire) {
+			t.Errorf("Encode() version %d = % X, wanted % X", tt.version, buf.Bytes(), tt.wire)
+		}
+	}
+}
+
+func TestVersionNegotiator(t *testing.T) {
+	vn := &VersionNegotiator{
+		Supported: VersionRange{Min: 1, Max: 4},
+		Versions: map[reflect.Type]map[string]VersionRange{
+			reflect.TypeOf(VersionedHello{}): {"Checksum": {Min: 4}},
+		},
+	}
+
+	tests := []struct {
+		peer    VersionRange
+		want    int
+		wantErr error
+	}{
+		{peer: VersionRange{Min: 1, Max: 3}, want: 3},
+		{peer: VersionRange{Min: 2}, want: 4},
+		{peer: VersionRange{Min: 5, Max: 6}, wantErr: ErrRange},
+	}
+	for _, tt := range tests {
+		got, err := vn.Negotiate(tt.peer)
+		if !errors.Is(err, tt.wantErr) || got != tt.want {
+			t.Errorf("Negotiate(%v) = %d, %v, wanted %d, %v", tt.peer, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	// Versions take the place of tags
+	d := NewDecoder(bytes.NewReader([]byte{1, 0x02, 0x03}), BigEndian, WithVersionNegotiator(vn))
+	d.SetVersion(3)
+	var got VersionedHello
+	if err := d.Decode(&got); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if want := (VersionedHello{Kind: 1, Flags: 0x0203}); got != want {
+		t.Errorf("Decode() = %+v, wanted %+v", got, want)
+	}
+
+	// Only the type the entry's for, not any other of the same name
+	type VersionedHello struct {
+		Kind     uint8
+		Checksum uint8
+	}
+	d = NewDecoder(bytes.NewReader([]byte{1, 5}), BigEndian, WithVersionNegotiator(vn))
+	d.SetVersion(3)
+	var other VersionedHello
+	if err := d.Decode(&other); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if want := (VersionedHello{Kind: 1, Checksum: 5}); other != want {
+		t.Errorf("Decode() of a same named type = %+v, wanted %+v", other, want)
+	}
+}
+
+func TestVersionTagErrors(t *testing.T) {
+	type bad struct {
+		A uint8 `added_in:"3" removed_in:"3"`
+	}
+	e := NewEncoder(&bytes.Buffer{}, BigEndian)
+	e.SetVersion(1)
+	if err := e.Encode(bad{}); !errors.Is(err, ErrTag) {
+		t.Errorf("Encode() error = %v, wanted %v", err, ErrTag)
+	}
+	if err := NewDecoder(bytes.NewReader([]byte{1}), BigEndian, WithStrict(true)).Decode(&bad{}); !errors.Is(err, ErrTag) {
+		t.Errorf("Decode() error = %v, wanted %v", err, ErrTag)
+	}
+}
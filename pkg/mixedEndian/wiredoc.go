@@ -0,0 +1,197 @@
+package mixedEndian
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// WireDoc renders the wire layout of data, a struct or pointer to one, as a Markdown table for
+// protocol specifications. Each field is a row giving its offset and size in bytes, name, Go
+// type, byte order, and the description in its doc tag. The fields of nested structs follow
+// theirs, indented, at offsets from the start of data; those of a struct array or slice are
+// of its first element. Offsets following a variable sized field are given from it, as in
+// "after Payload + 2", and the sizes of such fields are "variable".
+//
+// Fields without their own byte order use defaultEndian. Like StructGen, WireDoc is meant for
+// types known to be well formed, so it panics should data's tags be malformed.
+func WireDoc(data any, defaultEndian binary.ByteOrder) string {
+	sl, err := Describe(data)
+	if err != nil {
+		panic(err)
+	}
+	rows, err := wireDocRows(nil, sl, docPos{}, defaultEndian, 0, "", true)
+	if err != nil {
+		panic(err)
+	}
+
+	b := &strings.Builder{}
+	writeWireDocTable(b, rows, func(f FieldLayout) string { return "`" + f.Type.String() + "`" }, nil)
+	return b.String()
+}
+
+// docPos is where a field starts: rel bytes after the end of the variable sized field after, or
+// from the start should after be "". aligned is where padding to an alignment follows after, so
+// rel isn't known.
+type docPos struct {
+	after   string
+	rel     int
+	aligned bool
+}
+
+func (p docPos) String() string {
+	switch {
+	case p.after == "":
+		return strconv.Itoa(p.rel)
+	case p.aligned:
+		return "after " + p.after + ", aligned"
+	case p.rel == 0:
+		return "after " + p.after
+	default:
+		return fmt.Sprintf("after %s + %d", p.after, p.rel)
+	}
+}
+
+// wireDocRow is a field as WireDoc and ExportMarkdown list it: where it starts, its byte order,
+// nil for the default, and how many structs deep it's nested
+type wireDocRow struct {
+	FieldLayout
+	at    docPos
+	order binary.ByteOrder
+	depth int
+}
+
+// wireDocRows appends the rows of the fields of sl, starting at base, to rows. o is the byte
+// order of fields without their own, and depth how many structs deep sl is, its fields' names
+// following path in the offsets of those after them. With flatten, the fields of nested structs
+// follow theirs.
+func wireDocRows(rows []wireDocRow, sl *StructLayout, base docPos, o binary.ByteOrder, depth int, path string, flatten bool) ([]wireDocRow, error) {
+	so, err := optionsOf(sl.Type)
+	if err != nil {
+		return rows, err
+	}
+
+	// pos is where the field after the last one starts, and groupAt where the overlay group does
+	pos, groupAt := base, docPos{}
+	for _, f := range sl.Fields {
+		sf, _ := sl.Type.FieldByName(f.Name)
+		i := sf.Index[0]
+		before, pad, _ := fieldPads(sf)
+
+		size := f.Size
+		if size >= 0 {
+			size += before + pad
+		}
+		at := pos
+		g, inGroup := so.overlayOf(i)
+		switch {
+		case inGroup && g.fields[0] != i:
+			// Overlay members all start where their group does, which takes the largest's size
+			at, size = groupAt, 0
+		case f.Offset >= 0:
+			at.after, at.rel, at.aligned = base.after, base.rel+f.Offset, base.aligned
+		case so.align > 0:
+			// Padding to alignment depends on where the variable sized field ended
+			at.aligned = true
+		default:
+			at.rel += before
+		}
+		if inGroup && g.fields[0] == i {
+			groupAt, size = at, g.size
+		}
+
+		order := o
+		if f.Order != nil {
+			order = f.Order
+		}
+		rows = append(rows, wireDocRow{FieldLayout: f, at: at, order: order, depth: depth})
+		if flatten && f.Elem != nil {
+			if rows, err = wireDocRows(rows, f.Elem, at, order, depth+1, path+f.Name+".", true); err != nil {
+				return rows, err
+			}
+		}
+
+		if size < 0 || so.endsScope(i) {
+			pos = docPos{after: path + f.Name}
+		} else {
+			pos.rel += size
+		}
+	}
+	return rows, nil
+}
+
+// endsScope is whether field i ends a length scope, which may hold more than its fields, so
+// places what follows only once read
+func (so structOptions) endsScope(i int) bool {
+	for _, sc := range so.scopes {
+		if sc.to == i {
+			return true
+		}
+	}
+	return false
+}
+
+// writeWireDocTable writes rows as a Markdown table, their types as typeName gives them. Given
+// constraints, a column of what it gives each row precedes their descriptions.
+func writeWireDocTable(b *strings.Builder, rows []wireDocRow, typeName func(FieldLayout) string, constraints func(FieldLayout) []string) {
+	if constraints != nil {
+		fmt.Fprintf(b, "| Offset | Size | Field | Type | Endian | Constraints | Description |\n")
+		fmt.Fprintf(b, "| ---: | ---: | --- | --- | --- | --- | --- |\n")
+	} else {
+		fmt.Fprintf(b, "| Offset | Size | Field | Type | Endian | Description |\n")
+		fmt.Fprintf(b, "| ---: | ---: | --- | --- | --- | --- |\n")
+	}
+
+	for _, row := range rows {
+		fmt.Fprintf(b, "| %s | %s | %s%s | %s | %s |",
+			row.at,
+			wireDocBytes(row.Size),
+			strings.Repeat("&emsp;", row.depth), row.Name,
+			typeName(row.FieldLayout),
+			wireDocOrder(row.FieldLayout, row.order),
+		)
+		if constraints != nil {
+			fmt.Fprintf(b, " %s |", markdownEscape(strings.Join(constraints(row.FieldLayout), "; ")))
+		}
+		fmt.Fprintf(b, " %s |\n", markdownEscape(row.Tag.Get("doc")))
+	}
+}
+
+// wireDocBytes is n, or "variable" when it's -1
+func wireDocBytes(n int) string {
+	if n < 0 {
+		return "variable"
+	}
+	return fmt.Sprint(n)
+}
+
+// wireDocOrder names the byte order o of field f, which is left blank where order doesn't
+// matter: for bytes and text, and for structs, whose fields give their own, unless the struct's
+// own tag sets one for every field within it
+func wireDocOrder(f FieldLayout, o binary.ByteOrder) string {
+	t := f.Type
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Array || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if (f.Elem != nil && f.Order == nil) || t.Kind() == reflect.String || typeSize(t) == 1 {
+		return ""
+	}
+
+	switch o {
+	case nil:
+		return "default"
+	case BigEndian:
+		return "big"
+	case LittleEndian:
+		return "little"
+	default:
+		return o.String()
+	}
+}
+
+// markdownEscape keeps s within its table cell
+func markdownEscape(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
@@ -0,0 +1,29 @@
+package mixedEndian
+
+import "testing"
+
+type DocumentedPacket struct {
+	Magic  [2]byte      `const:"0x4D,0x45" doc:"Always \"ME\""`
+	Length uint16       `doc:"Bytes of payload"`
+	Header TaggedStruct `doc:"Routing header"`
+	Flags  uint8        `doc:"Bit 0: compressed | bit 1: signed"`
+	Name   string       `size:"8" doc:"Sender, NUL padded"`
+	Data   []uint16     `len:"Length" endian:"little"`
+	Check  uint32       `doc:"Checksum of the above"`
+}
+
+func TestWireDoc(t *testing.T) {
+	checkGolden(t, "DocumentedPacket.md", []byte(WireDoc(&DocumentedPacket{}, BigEndian)))
+	checkGolden(t, "NestedStruct.md", []byte(WireDoc(NestedStruct{}, nil)))
+	checkGolden(t, "SpecPacketWire.md", []byte(WireDoc(SpecPacket{}, BigEndian)))
+	checkGolden(t, "NestedArrayStruct.md", []byte(WireDoc(NestedArrayStruct{}, BigEndian)))
+}
+
+func TestWireDocBadTag(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("WireDoc() didn't panic, wanted it to with a string missing its size")
+		}
+	}()
+	WireDoc(struct{ S string }{}, BigEndian)
+}
@@ -0,0 +1,282 @@
+package mixedendiancheck
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// VetAnalyzer checks calls into the mixedEndian package for the pointer mistakes its signatures
+// allow: Write given a pointer to a pointer, which it can't encode, and Read given an any holding
+// something other than a pointer to a concrete type, which it can't fill. Each report suggests
+// the fix where it's clear.
+var VetAnalyzer = &analysis.Analyzer{
+	Name:     "mixedendianvet",
+	Doc:      "check the values passed to mixedEndian's Read and Write",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runVet,
+}
+
+const mixedEndianPath = "github.com/AV-IO/mixedEndian/pkg/mixedEndian"
+
+// writeFuncs and readFuncs are the functions taking the value to encode or decode last
+var (
+	writeFuncs = []string{"Write", "WriteContext", "WriteAs", "Marshal", "MarshalAs"}
+	readFuncs  = []string{"Read", "ReadContext", "ReadAs"}
+)
+
+func runVet(pass *analysis.Pass) (any, error) {
+	ins := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	ins.WithStack([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		call := n.(*ast.CallExpr)
+		fn := callee(pass, call)
+		if fn == nil {
+			return true
+		}
+		data := call.Args[len(call.Args)-1]
+
+		switch {
+		case contains(writeFuncs, fn.Name()):
+			checkWriteArg(pass, fn.Name(), data)
+		case contains(readFuncs, fn.Name()):
+			checkReadArg(pass, fn.Name(), data, stack)
+		}
+		return true
+	})
+	return nil, nil
+}
+
+// callee is the mixedEndian function call makes, taking the value last, or nil for any other call
+func callee(pass *analysis.Pass, call *ast.CallExpr) *types.Func {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != mixedEndianPath || len(call.Args) == 0 {
+		return nil
+	}
+	if sig := fn.Type().(*types.Signature); sig.Recv() != nil {
+		return nil
+	}
+	return fn
+}
+
+// checkWriteArg reports data, given to write function name, should it be a pointer to a pointer
+func checkWriteArg(pass *analysis.Pass, name string, data ast.Expr) {
+	p, ok := pass.TypesInfo.TypeOf(data).(*types.Pointer)
+	if !ok {
+		return
+	}
+	if _, ok = p.Elem().Underlying().(*types.Pointer); !ok {
+		return
+	}
+
+	d := analysis.Diagnostic{
+		Pos:     data.Pos(),
+		End:     data.End(),
+		Message: name + " is passed a " + p.String() + ", a pointer to a pointer, which it can't encode; pass the pointer itself",
+	}
+	if u, ok := data.(*ast.UnaryExpr); ok && u.Op == token.AND {
+		d.SuggestedFixes = []analysis.SuggestedFix{{
+			Message:   "Remove the &",
+			TextEdits: []analysis.TextEdit{{Pos: u.Pos(), End: u.X.Pos()}},
+		}}
+	}
+	pass.Report(d)
+}
+
+// checkReadArg reports the value assigned to the any whose address data, given to read function
+// name, takes, should it not be a pointer to a concrete type. stack is the path to the call.
+// Only a value sure to be the one it holds is checked: the last assigned before the call, with
+// no branch or loop between that could assign it another.
+func checkReadArg(pass *analysis.Pass, name string, data ast.Expr, stack []ast.Node) {
+	u, ok := data.(*ast.UnaryExpr)
+	if !ok || u.Op != token.AND {
+		return
+	}
+	id, ok := u.X.(*ast.Ident)
+	if !ok {
+		return
+	}
+	v := reaching(pass, pass.TypesInfo.ObjectOf(id), stack)
+	if v == nil {
+		return
+	}
+
+	t := pass.TypesInfo.TypeOf(v)
+	if t == nil || types.IsInterface(t) {
+		// Whatever it holds is only known once run
+		return
+	}
+	if b, ok := t.(*types.Basic); ok && b.Kind() == types.UntypedNil {
+		// Read reports nil itself, and there's no pointer to suggest
+		return
+	}
+	if p, ok := t.Underlying().(*types.Pointer); ok {
+		if e := p.Elem().Underlying(); !types.IsInterface(e) {
+			if _, ok = e.(*types.Pointer); !ok {
+				return
+			}
+		}
+		pass.Reportf(data.Pos(), "%s is passed &%s, which holds a %s, not a pointer to a concrete type it can fill", name, id.Name, t)
+		return
+	}
+
+	d := analysis.Diagnostic{
+		Pos:     data.Pos(),
+		End:     data.End(),
+		Message: name + " is passed &" + id.Name + ", which holds a " + t.String() + " rather than a pointer to one, so can't be filled; assign it a pointer",
+	}
+	if addressable(pass, v) {
+		d.SuggestedFixes = []analysis.SuggestedFix{{
+			Message:   "Assign a pointer",
+			TextEdits: []analysis.TextEdit{{Pos: v.Pos(), End: v.Pos(), NewText: []byte("&")}},
+		}}
+	}
+	pass.Report(d)
+}
+
+// addressable reports whether v can be given an & to make a pointer of it
+func addressable(pass *analysis.Pass, v ast.Expr) bool {
+	switch v := v.(type) {
+	case *ast.CompositeLit:
+		return true
+	case *ast.Ident:
+		_, ok := pass.TypesInfo.ObjectOf(v).(*types.Var)
+		return ok
+	case *ast.SelectorExpr:
+		_, ok := pass.TypesInfo.ObjectOf(v.Sel).(*types.Var)
+		return ok
+	case *ast.IndexExpr:
+		_, isMap := pass.TypesInfo.TypeOf(v.X).Underlying().(*types.Map)
+		return !isMap
+	}
+	return false
+}
+
+// reaching is the value last assigned to obj before the call at the end of stack, or nil should
+// several reach it, through branches or loops, or the one that does be out of sight: a parameter,
+// a tuple assignment, a capture by a closure, or an address taken elsewhere.
+func reaching(pass *analysis.Pass, obj types.Object, stack []ast.Node) ast.Expr {
+	for i := len(stack) - 1; i > 0; i-- {
+		var stmts []ast.Stmt
+		switch p := stack[i-1].(type) {
+		case *ast.BlockStmt:
+			stmts = p.List
+		case *ast.CaseClause:
+			stmts = p.Body
+		case *ast.CommClause:
+			stmts = p.Body
+		case *ast.ForStmt, *ast.RangeStmt:
+			// Assignments anywhere in a loop reach the next time round
+			if assigns(pass, p, obj) {
+				return nil
+			}
+		case *ast.IfStmt:
+			if p.Init != nil && assigns(pass, p.Init, obj) {
+				return nil
+			}
+		case *ast.SwitchStmt:
+			if p.Init != nil && assigns(pass, p.Init, obj) {
+				return nil
+			}
+		case *ast.TypeSwitchStmt:
+			if p.Init != nil && assigns(pass, p.Init, obj) {
+				return nil
+			}
+		case *ast.FuncLit, *ast.FuncDecl:
+			return nil
+		}
+
+		at := -1
+		for j, stmt := range stmts {
+			if ast.Node(stmt) == stack[i] {
+				at = j
+			}
+		}
+		for j := at - 1; j >= 0; j-- {
+			if v, ok := assignment(pass, stmts[j], obj); ok {
+				return v
+			} else if assigns(pass, stmts[j], obj) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// assignment is the value stmt assigns obj, reporting whether it's an assignment or declaration
+// of obj. The value is nil if it's not a single one, or there's none.
+func assignment(pass *analysis.Pass, stmt ast.Stmt, obj types.Object) (ast.Expr, bool) {
+	switch stmt := stmt.(type) {
+	case *ast.AssignStmt:
+		for i, lhs := range stmt.Lhs {
+			if id, ok := lhs.(*ast.Ident); ok && pass.TypesInfo.ObjectOf(id) == obj {
+				if len(stmt.Lhs) != len(stmt.Rhs) || stmt.Tok != token.ASSIGN && stmt.Tok != token.DEFINE {
+					return nil, true
+				}
+				return stmt.Rhs[i], true
+			}
+		}
+	case *ast.DeclStmt:
+		gd, ok := stmt.Decl.(*ast.GenDecl)
+		if !ok {
+			break
+		}
+		for _, spec := range gd.Specs {
+			vs, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for i, name := range vs.Names {
+				if pass.TypesInfo.ObjectOf(name) == obj {
+					if len(vs.Names) != len(vs.Values) {
+						return nil, true
+					}
+					return vs.Values[i], true
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+// assigns reports whether obj may be assigned anywhere within n, by assignment, declaration,
+// range, or having its address taken other than by a read function
+func assigns(pass *analysis.Pass, n ast.Node, obj types.Object) (found bool) {
+	is := func(e ast.Expr) bool {
+		id, ok := e.(*ast.Ident)
+		return ok && pass.TypesInfo.ObjectOf(id) == obj
+	}
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				found = found || is(lhs)
+			}
+		case *ast.ValueSpec:
+			for _, name := range n.Names {
+				found = found || is(name)
+			}
+		case *ast.RangeStmt:
+			found = found || n.Key != nil && is(n.Key) || n.Value != nil && is(n.Value)
+		case *ast.UnaryExpr:
+			found = found || n.Op == token.AND && is(n.X)
+		case *ast.CallExpr:
+			// Reads fill what the any points to, leaving the any itself as it was
+			if fn := callee(pass, n); fn != nil && contains(readFuncs, fn.Name()) {
+				for _, arg := range n.Args[:len(n.Args)-1] {
+					found = found || assigns(pass, arg, obj)
+				}
+				return false
+			}
+		}
+		return !found
+	})
+	return
+}
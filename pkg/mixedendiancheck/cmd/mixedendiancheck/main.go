@@ -0,0 +1,21 @@
+// mixedendiancheck checks the struct tags read by the mixedEndian package, reporting misspelled
+// values, references to missing or later fields, tags on fields they can't apply to, unexported
+// fields of tagged structs, and platform sized integers. Alongside, mixedendianvet checks calls
+// to Read and Write for values they can't fill or encode. See package mixedendiancheck.
+//
+// Usage:
+//
+//	go vet -vettool=$(which mixedendiancheck) ./...
+//
+// or run it directly, as mixedendiancheck ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/multichecker"
+
+	"github.com/AV-IO/mixedEndian/pkg/mixedendiancheck"
+)
+
+func main() {
+	multichecker.Main(mixedendiancheck.Analyzer, mixedendiancheck.VetAnalyzer)
+}
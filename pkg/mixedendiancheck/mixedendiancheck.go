@@ -0,0 +1,415 @@
+// Package mixedendiancheck defines an Analyzer checking the struct tags read by the mixedEndian
+// package, reporting at build time the mistakes Read and Write would otherwise only report once
+// run: misspelled tag values, references to fields that don't exist or come too late, and tags on
+// fields of kinds they can't apply to. It also flags unexported fields of tagged structs, which
+// Read skips, and int, uint, and uintptr fields, whose size depends on the platform.
+//
+// Structs are checked when any of their fields carries a tag key mixedEndian reads.
+//
+// VetAnalyzer, registered as mixedendianvet, checks calls to Read and Write instead, for the
+// pointers their signatures can't rule out.
+package mixedendiancheck
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "mixedendiancheck",
+	Doc:      "check struct tags read by the mixedEndian package",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// tagKeys are the struct tag keys mixedEndian reads
+var tagKeys = []string{
+	"added_in", "align", "bitreverse", "bitwidth", "clamp", "compress", "const", "count", "countfrom", "crc", "crc_poly",
+	"crc_seed", "crcblocks", "crcrange", "de", "delta", "dims", "dur", "encoding", "endian", "enum", "enumdefault",
+	"epoch", "epochunit", "finite", "floatfmt", "gray", "group", "iso8583", "len", "lengthscope", "lenprefix", "maxvalue", "minvalue", "mirror",
+	"network_checksum", "norm", "optional", "order", "overlay", "padding_after", "padding_before", "pcm", "presentif", "raw", "removed_in", "restlen", "rle", "size", "sizeof_field", "sparse",
+	"ssh", "string", "timecode", "trim", "width",
+}
+
+// tagValues are the values tags naming one of a fixed set may take
+var tagValues = map[string][]string{
+	"endian":       {"big", "little"},
+	"encoding":     {"gray", "varint", "uvarint", "uuid", "uuid_le"},
+	"gray":         {"true"},
+	"clamp":        {"true"},
+	"delta":        {"true"},
+	"bitreverse":   {"true"},
+	"restlen":      {"true"},
+	"finite":       {"true"},
+	"iso8583":      {"true", "false"},
+	"order":        {"rowmajor", "colmajor"},
+	"trim":         {"null", "space", "none"},
+	"sizeof_field": {"self"},
+	"floatfmt":     {"decimal32-bid", "decimal32-dpd", "decimal64-bid", "decimal64-dpd"},
+	"dur":          {"ns", "us", "ms", "s"},
+	"width":        {"int8", "int16", "int32", "int64", "uint8", "uint16", "uint32", "uint64"},
+	"norm":         {"unorm8", "unorm16", "snorm8", "snorm16"},
+	"pcm":          {"alaw", "ulaw"},
+	"ssh":          {"string", "mpint", "namelist"},
+}
+
+// markerEncodings are the encodings a blank marker field may give its whole struct
+var markerEncodings = []string{"packed", "require_alignment"}
+
+// field is a field of a struct being checked
+type field struct {
+	v   *types.Var
+	tag reflect.StructTag
+	pos ast.Node
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	ins := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	ins.Preorder([]ast.Node{(*ast.StructType)(nil)}, func(n ast.Node) {
+		st := n.(*ast.StructType)
+		if s, ok := pass.TypesInfo.TypeOf(st).(*types.Struct); ok {
+			checkStruct(pass, st, s)
+		}
+	})
+	return nil, nil
+}
+
+// checkStruct checks the fields of st, of type s, should any carry a mixedEndian tag
+func checkStruct(pass *analysis.Pass, st *ast.StructType, s *types.Struct) {
+	// Fields declared together share their ast.Field
+	var fields []field
+	for _, af := range st.Fields.List {
+		n := len(af.Names)
+		if n == 0 {
+			n = 1
+		}
+		for j := 0; j < n; j++ {
+			i := len(fields)
+			var pos ast.Node = af
+			if len(af.Names) > 0 {
+				pos = af.Names[j]
+			}
+			fields = append(fields, field{v: s.Field(i), tag: reflect.StructTag(s.Tag(i)), pos: pos})
+		}
+	}
+
+	tagged := false
+	for _, f := range fields {
+		for _, key := range tagKeys {
+			if _, ok := f.tag.Lookup(key); ok {
+				tagged = true
+			}
+		}
+	}
+	if !tagged {
+		return
+	}
+
+	for i, f := range fields {
+		checkField(pass, fields, i, f)
+	}
+}
+
+// checkField checks the tags of f, the ith of fields
+func checkField(pass *analysis.Pass, fields []field, i int, f field) {
+	name := f.v.Name()
+	report := func(format string, args ...any) {
+		pass.Reportf(f.pos.Pos(), "%s: "+format, append([]any{name}, args...)...)
+	}
+
+	if name == "_" {
+		if e := f.tag.Get("encoding"); e != "" && !contains(markerEncodings, e) {
+			report("unknown struct encoding %q", e)
+		}
+		if a := f.tag.Get("align"); a != "" {
+			if n, err := strconv.Atoi(a); err != nil || n < 1 || n&(n-1) != 0 {
+				report("align %q is not a power of 2", a)
+			}
+		}
+		if m := f.tag.Get("iso8583"); m != "" && !contains(tagValues["iso8583"], m) {
+			report("iso8583 %q is not a bool", m)
+		}
+		return
+	}
+	if !f.v.Exported() {
+		report("unexported field is skipped by Read, so won't round trip")
+	}
+	if k, ok := platformSized(f.v.Type()); ok && !isVarint(f.tag) {
+		report("%s has a platform dependent size; use a sized integer type", k)
+	}
+
+	for _, key := range tagKeys {
+		if values, ok := tagValues[key]; ok && key != "iso8583" {
+			if s := f.tag.Get(key); s != "" && !contains(values, s) {
+				report("unknown %s %q", key, s)
+			}
+		}
+	}
+
+	t := f.v.Type().Underlying()
+	elem := t
+	switch u := t.(type) {
+	case *types.Array:
+		elem = u.Elem().Underlying()
+	case *types.Slice:
+		elem = u.Elem().Underlying()
+	}
+	isList := elem != t
+
+	// Sizes and counts
+	if s := f.tag.Get("size"); s != "" {
+		if n, err := strconv.Atoi(s); err != nil || n < 0 {
+			report("size %q is not a length", s)
+		} else if !isKind(t, types.IsString|types.IsFloat|types.IsComplex) && !isNamed(f.v.Type(), "net", "HardwareAddr") {
+			// Floats and complex numbers aren't read, but a lenient Decoder skips them by size
+			report("size on %s, which is not a string", f.v.Type())
+		}
+	}
+	if s := f.tag.Get("count"); s != "" {
+		c, opt, _ := strings.Cut(strings.TrimPrefix(s, "fixed:"), ",")
+		if n, err := strconv.Atoi(c); err != nil || n < 0 || (opt != "" && opt != "pad") {
+			report("count %q is not a length", s)
+		}
+	}
+	for _, key := range []string{"padding_before", "padding_after"} {
+		s := f.tag.Get(key)
+		if s == "" {
+			continue
+		}
+		c, fill, hasFill := strings.Cut(s, ",")
+		if n, err := strconv.Atoi(c); err != nil || n < 0 {
+			report("%s %q is not a length", key, s)
+		} else if _, err := strconv.ParseUint(fill, 0, 8); hasFill && err != nil {
+			report("%s %q has a fill that is not a byte", key, s)
+		}
+	}
+	for _, key := range []string{"len", "countfrom", "count"} {
+		if f.tag.Get(key) == "" {
+			continue
+		}
+		switch t.(type) {
+		case *types.Slice, *types.Map:
+		default:
+			report("%s on %s, which is not a slice or map", key, f.v.Type())
+		}
+	}
+	if s := f.tag.Get("overlay"); s != "" {
+		if name, opt, _ := strings.Cut(s, ","); name == "" || (opt != "" && opt != "primary") {
+			report("overlay %q is not a group name, optionally followed by \",primary\"", s)
+		}
+	}
+	if s := f.tag.Get("lenprefix"); s != "" {
+		name, order, _ := strings.Cut(s, ",")
+		switch t.(type) {
+		case *types.Slice, *types.Map:
+		default:
+			report("lenprefix on %s, which is not a slice or map", f.v.Type())
+		}
+		if !contains([]string{"uint8", "uint16", "uint32", "uint64"}, name) || (order != "" && !contains(tagValues["endian"], order)) {
+			report("lenprefix %q is not an unsigned integer type and optional endian", s)
+		}
+		for _, key := range []string{"len", "countfrom", "count", "dims"} {
+			if f.tag.Get(key) != "" {
+				report("lenprefix and %s both count the field", key)
+			}
+		}
+	}
+	if s := f.tag.Get("bitwidth"); s != "" {
+		if b, ok := elem.(*types.Basic); !ok || b.Info()&types.IsInteger == 0 || b.Kind() == types.Int || b.Kind() == types.Uint || b.Kind() == types.Uintptr {
+			report("bitwidth on %s, which is not a sized integer", f.v.Type())
+		} else if n, err := strconv.Atoi(s); err != nil || n < 1 || int64(n) > 8*pass.TypesSizes.Sizeof(b) {
+			report("bitwidth %q doesn't fit %s", s, b)
+		}
+	}
+	if f.tag.Get("bitreverse") != "" && !isKind(elem, types.IsInteger) {
+		report("bitreverse on %s, which is not an integer", f.v.Type())
+	}
+	if f.tag.Get("delta") != "" && (!isList || !isKind(elem, types.IsInteger)) {
+		report("delta on %s, which is not an array or slice of integers", f.v.Type())
+	}
+	if f.tag.Get("finite") != "" && f.tag.Get("floatfmt") == "" {
+		report("finite without floatfmt, so there are no NaNs or infinities to reject")
+	}
+	for _, key := range []string{"minvalue", "maxvalue"} {
+		if s := f.tag.Get(key); s == "" {
+			continue
+		} else if !isKind(elem, types.IsInteger) {
+			report("%s on %s, which is not an integer", key, f.v.Type())
+		} else if _, err := strconv.ParseInt(s, 0, 64); err != nil {
+			if _, err = strconv.ParseUint(s, 0, 64); err != nil {
+				report("%s %q is not an integer", key, s)
+			}
+		}
+	}
+	if s := f.tag.Get("de"); s != "" {
+		if n, err := strconv.Atoi(s); err != nil || n < 2 || n > 128 {
+			report("de %q is not a data element from 2 to 128", s)
+		}
+	}
+
+	// Versions
+	added := 0
+	if s := f.tag.Get("added_in"); s != "" {
+		if n, err := strconv.Atoi(s); err != nil || n < 1 {
+			report("added_in %q is not a version", s)
+		} else {
+			added = n
+		}
+	}
+	if s := f.tag.Get("removed_in"); s != "" {
+		if n, err := strconv.Atoi(s); err != nil || n <= added || n < 2 {
+			report("removed_in %q is not a version after added_in", s)
+		}
+	}
+
+	// Fields read before this one
+	for _, key := range []string{"len", "countfrom", "dims", "presentif", "mirror"} {
+		refs := f.tag.Get(key)
+		if refs == "" {
+			continue
+		}
+		for _, ref := range strings.Split(refs, ",") {
+			ref = strings.TrimSpace(ref)
+			j := indexOf(fields, ref)
+			switch {
+			case j < 0:
+				report("%s names no field %s", key, ref)
+			case j >= i:
+				report("%s names %s, which isn't read until after it", key, ref)
+			case key == "presentif" && !isKind(fields[j].v.Type(), types.IsBoolean):
+				report("presentif names %s, which is not a bool", ref)
+			case (key == "len" || key == "countfrom" || key == "dims") && !isKind(fields[j].v.Type(), types.IsInteger):
+				report("%s names %s, which is not an integer", key, ref)
+			case key == "mirror" && !types.Identical(fields[j].v.Type(), f.v.Type()):
+				report("mirror names %s, which is a %s", ref, fields[j].v.Type())
+			}
+		}
+	}
+
+	if s := f.tag.Get("optional"); s != "" && s != "flag" && s != "eof" {
+		ref, b, _ := strings.Cut(strings.TrimPrefix(s, "bit="), ".")
+		j := indexOf(fields, ref)
+		if n, err := strconv.Atoi(b); !strings.HasPrefix(s, "bit=") || err != nil || n < 0 {
+			report("optional %q is not flag, eof, or bit=Field.N", s)
+		} else if j < 0 || j >= i || !isKind(fields[j].v.Type(), types.IsInteger) {
+			report("optional %q is not a bit of an earlier integer field", s)
+		}
+	}
+
+	// Fields anywhere in the struct
+	if s := f.tag.Get("raw"); s != "" && s != "struct" {
+		if j := indexOf(fields, s); j < 0 || j == i {
+			report("raw names no field %s", s)
+		}
+	}
+	crcRange := f.tag.Get("crcrange")
+	if c := f.tag.Get("crc"); strings.Contains(c[strings.LastIndexByte(c, ',')+1:], "..") {
+		if crcRange != "" {
+			report("crc gives a range as well as crcrange")
+		}
+		crcRange = strings.Replace(c[strings.LastIndexByte(c, ',')+1:], "..", ":", 1)
+	}
+	for _, key := range []string{"network_checksum", "crcrange"} {
+		s := f.tag.Get(key)
+		if key == "crcrange" {
+			s = crcRange
+		}
+		if s == "" {
+			continue
+		}
+		from, to, ok := strings.Cut(s, ":")
+		if a, c := indexOf(fields, from), indexOf(fields, to); !ok || a < 0 || c < 0 || a > c {
+			report("%s %q is not a range of fields", key, s)
+		}
+	}
+	if s := f.tag.Get("lengthscope"); s != "" {
+		from, to, ok := strings.Cut(s, "..")
+		if !ok {
+			to = from
+		}
+		if a, c := indexOf(fields, from), indexOf(fields, to); a <= i || a > c {
+			report("lengthscope %q is not a range of later fields", s)
+		}
+	}
+	if f.tag.Get("crc") != "" && crcRange == "" {
+		report("crc needs a range of fields")
+	}
+	for _, key := range []string{"crc_seed", "crc_poly"} {
+		s := f.tag.Get(key)
+		if s == "" {
+			continue
+		}
+		if f.tag.Get("crc") == "" {
+			report("%s needs a crc", key)
+		}
+		hex := strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+		if _, err := strconv.ParseUint(hex, 16, 64); err != nil {
+			report("%s %q is not a hex number", key, s)
+		}
+	}
+}
+
+// platformSized reports whether t is, or is a list of, int, uint, or uintptr
+func platformSized(t types.Type) (string, bool) {
+	for {
+		switch u := t.Underlying().(type) {
+		case *types.Array:
+			t = u.Elem()
+		case *types.Slice:
+			t = u.Elem()
+		case *types.Basic:
+			switch u.Kind() {
+			case types.Int, types.Uint, types.Uintptr:
+				return u.Name(), true
+			}
+			return "", false
+		default:
+			return "", false
+		}
+	}
+}
+
+// isVarint reports whether tag encodes its field as a varint, whose width doesn't depend on the
+// platform's
+func isVarint(tag reflect.StructTag) bool {
+	e := tag.Get("encoding")
+	return e == "varint" || e == "uvarint"
+}
+
+// isKind reports whether t is a basic type with info
+func isKind(t types.Type, info types.BasicInfo) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&info != 0
+}
+
+// isNamed reports whether t is the type pkg.name
+func isNamed(t types.Type, pkg, name string) bool {
+	n, ok := t.(*types.Named)
+	return ok && n.Obj().Pkg() != nil && n.Obj().Pkg().Path() == pkg && n.Obj().Name() == name
+}
+
+// indexOf is the index of the field called name, or -1
+func indexOf(fields []field, name string) int {
+	for i, f := range fields {
+		if f.v.Name() == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
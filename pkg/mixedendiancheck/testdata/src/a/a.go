@@ -0,0 +1,138 @@
+package a
+
+import (
+	"net"
+	"time"
+)
+
+// Passing fixtures, each tag used as mixedEndian expects
+
+type Header struct {
+	_       struct{} `encoding:"packed"`
+	Magic   [4]byte  `const:"0x7F,0x45,0x4C,0x46"`
+	Flags   uint16   `endian:"little" bitwidth:"12" clamp:"true"`
+	HasExt  bool
+	Ext     uint32 `presentif:"HasExt"`
+	N       uint8
+	Items   []uint16 `len:"N" delta:"true"`
+	Fixed   []uint8  `count:"4,pad"`
+	Name    string   `size:"8" trim:"space"`
+	MAC     net.HardwareAddr
+	Wait    time.Duration `dur:"ms" width:"uint32"`
+	Copy    uint16        `mirror:"Flags"`
+	Raw     []byte        `raw:"Items"`
+	Sum     uint16        `network_checksum:"Magic:Name"`
+	CRC     uint32        `crc:"crc32" crcrange:"Magic:Name" crc_seed:"0" crc_poly:"0x1EDC6F41"`
+	CRC16   uint16        `crc:"crc16,Flags..Name"`
+	Late    uint8         `added_in:"2" removed_in:"4"`
+	Scope   uint16        `lengthscope:"Rows..Cols"`
+	Ext16   Optional      `optional:"bit=Flags.3"`
+	Rows    uint8
+	Cols    uint8
+	Grid    [][]int16 `dims:"Rows,Cols" order:"colmajor"`
+	Level   int8      `minvalue:"-3" maxvalue:"0x10"`
+	Trim    int16     `bitwidth:"12"`
+	Samples []int16   `lenprefix:"uint16,big" endian:"little"`
+	Word    uint64    `overlay:"value"`
+	Pair    [2]uint32 `overlay:"value,primary"`
+	Untyped int32     `json:"untyped"`
+	Ratio   float64   `size:"8"`
+	Price   string    `floatfmt:"decimal64-bid" finite:"true"`
+	Spaced  uint16    `padding_before:"2" padding_after:"1,0xFF"`
+	Blob    []byte    `compress:"gzip"`
+	Record  uint16    `lengthscope:"Kind..Body"`
+	Kind    uint8
+	Body    []byte    `restlen:"true"`
+	Stamp   time.Time `epoch:"1601" epochunit:"100ns" width:"uint64"`
+	TC      Timecode  `timecode:"fm=23"`
+	Serial  [2]int16  `bitreverse:"true" endian:"little"`
+	Delta   int       `encoding:"varint"`
+	Total   uint      `encoding:"uvarint"`
+}
+
+type Staged struct {
+	Kind   uint8  `group:"header"`
+	Length uint16 `group:"header"`
+	Body   []byte `group:"body" len:"Length"`
+}
+
+type Message struct {
+	_   struct{} `iso8583:"true"`
+	MTI [4]byte
+	PAN [8]byte `de:"2"`
+}
+
+// Timecode stands in for a mixedEndian.Timecode
+type Timecode struct {
+	Hours, Minutes, Seconds, Frames  uint8
+	DropFrame, ColorFrame, FieldMark bool
+}
+
+// Optional stands in for a mixedEndian.Optional
+type Optional struct {
+	Value   uint16
+	Present bool
+}
+
+// Untagged structs aren't checked at all
+type Plain struct {
+	n int
+}
+
+// Failing fixtures
+
+type BadValues struct {
+	A uint16   `endian:"middle"`                                // want `A: unknown endian "middle"`
+	B uint16   `encoding:"zigzag"`                              // want `B: unknown encoding "zigzag"`
+	C uint16   `gray:"yes"`                                     // want `C: unknown gray "yes"`
+	D string   `size:"4" trim:"both"`                           // want `D: unknown trim "both"`
+	E uint16   `bitwidth:"17"`                                  // want `E: bitwidth "17" doesn't fit uint16`
+	F float32  `bitwidth:"4"`                                   // want `F: bitwidth on float32, which is not a sized integer`
+	G []byte   `count:"many"`                                   // want `G: count "many" is not a length`
+	H uint8    `added_in:"3" removed_in:"2"`                    // want `H: removed_in "2" is not a version after added_in`
+	I uint8    `minvalue:"low"`                                 // want `I: minvalue "low" is not an integer`
+	J bool     `maxvalue:"1"`                                   // want `J: maxvalue on bool, which is not an integer`
+	K []byte   `lenprefix:"int16"`                              // want `K: lenprefix "int16" is not an unsigned integer type and optional endian`
+	L []byte   `lenprefix:"uint8" count:"2"`                    // want `L: lenprefix and count both count the field`
+	M uint16   `crc_seed:"FFFF"`                                // want `M: crc_seed needs a crc`
+	O uint16   `overlay:"v,first"`                              // want `O: overlay "v,first" is not a group name, optionally followed by ",primary"`
+	N uint16   `crc:"crc16-arc" crcrange:"A:A" crc_poly:"0xZZ"` // want `N: crc_poly "0xZZ" is not a hex number`
+	P string   `floatfmt:"decimal32-bid" finite:"yes"`          // want `P: unknown finite "yes"`
+	Q uint8    `padding_before:"-2"`                            // want `Q: padding_before "-2" is not a length`
+	R uint8    `padding_after:"1,0x1FF"`                        // want `R: padding_after "1,0x1FF" has a fill that is not a byte`
+	S Optional `optional:"sometimes"`                           // want `S: optional "sometimes" is not flag, eof, or bit=Field.N`
+	T float32  `bitreverse:"true"`                              // want `T: bitreverse on float32, which is not an integer`
+}
+
+type BadMarker struct {
+	_ struct{} `encoding:"loose" align:"3"` // want `_: unknown struct encoding "loose"` `_: align "3" is not a power of 2`
+	A uint8
+}
+
+type BadRefs struct {
+	Data  []byte   `len:"Count"`           // want `Data: len names Count, which isn't read until after it`
+	Opt   Optional `optional:"bit=Name.1"` // want `Opt: optional "bit=Name.1" is not a bit of an earlier integer field`
+	Count uint8
+	More  []byte `len:"Total"` // want `More: len names no field Total`
+	Name  string `size:"4"`
+	Str   []byte `len:"Name"`                    // want `Str: len names Name, which is not an integer`
+	Ext   uint8  `presentif:"Count"`             // want `Ext: presentif names Count, which is not a bool`
+	Copy  uint16 `mirror:"Count"`                // want `Copy: mirror names Count, which is a uint8`
+	Raw   []byte `raw:"Missing"`                 // want `Raw: raw names no field Missing`
+	Sum   uint16 `network_checksum:"Name:Count"` // want `Sum: network_checksum "Name:Count" is not a range of fields`
+	CRC   uint16 `crc:"crc16-arc"`               // want `CRC: crc needs a range of fields`
+	CRC2  uint16 `crc:"crc16,Name..Count"`       // want `CRC2: crcrange "Name:Count" is not a range of fields`
+	Scope uint8  `lengthscope:"Name..Raw"`       // want `Scope: lengthscope "Name..Raw" is not a range of later fields`
+}
+
+type BadKinds struct {
+	A uint32 `size:"4"` // want `A: size on uint32, which is not a string`
+	N uint8
+	B [4]byte `len:"N"`         // want `B: len on \[4\]byte, which is not a slice or map`
+	C uint16  `delta:"true"`    // want `C: delta on uint16, which is not an array or slice of integers`
+	D int     `endian:"big"`    // want `D: int has a platform dependent size; use a sized integer type`
+	E []uint  `count:"2"`       // want `E: uint has a platform dependent size; use a sized integer type`
+	f uint16  `endian:"little"` // want `f: unexported field is skipped by Read, so won't round trip`
+	G [8]byte `de:"1"`          // want `G: de "1" is not a data element from 2 to 128`
+	H uint32  `finite:"true"`   // want `H: finite without floatfmt, so there are no NaNs or infinities to reject`
+}
@@ -0,0 +1,72 @@
+package b
+
+import (
+	"bytes"
+
+	"github.com/AV-IO/mixedEndian/pkg/mixedEndian"
+)
+
+type Header struct {
+	A uint16
+	B uint32
+}
+
+func good(buf *bytes.Buffer, h Header, p *Header, v any) {
+	mixedEndian.Write(buf, mixedEndian.BigEndian, h)
+	mixedEndian.Write(buf, mixedEndian.BigEndian, &h)
+	mixedEndian.Write(buf, mixedEndian.BigEndian, p)
+
+	var got any = &Header{}
+	mixedEndian.Read(buf, mixedEndian.BigEndian, &got)
+	got = p
+	mixedEndian.Read(buf, mixedEndian.BigEndian, &got)
+
+	// What an any given from elsewhere holds isn't known
+	mixedEndian.Read(buf, mixedEndian.BigEndian, &v)
+
+	// Nor is what it holds when branches or loops could assign it something else
+	var maybe any = Header{}
+	if h.A != 0 {
+		maybe = &h
+	}
+	mixedEndian.Read(buf, mixedEndian.BigEndian, &maybe)
+
+	var looped any = &h
+	for i := 0; i < 2; i++ {
+		mixedEndian.Read(buf, mixedEndian.BigEndian, &looped)
+		looped = h
+	}
+
+	// Only what's assigned before the call reaches it
+	var after any = &h
+	mixedEndian.Read(buf, mixedEndian.BigEndian, &after)
+	after = h
+	_ = after
+
+	// Read reports nil itself, and there's no pointer to suggest
+	var none any = nil
+	mixedEndian.Read(buf, mixedEndian.BigEndian, &none)
+}
+
+func bad(buf *bytes.Buffer, h Header, p *Header) {
+	mixedEndian.Write(buf, mixedEndian.BigEndian, &p) // want `Write is passed a \*\*b.Header, a pointer to a pointer, which it can't encode; pass the pointer itself`
+	mixedEndian.Marshal(mixedEndian.BigEndian, &p)    // want `Marshal is passed a \*\*b.Header`
+
+	var got any = Header{}
+	mixedEndian.Read(buf, mixedEndian.BigEndian, &got) // want `Read is passed &got, which holds a b.Header rather than a pointer to one, so can't be filled; assign it a pointer`
+
+	held := any(h)
+	held = h
+	mixedEndian.Read(buf, mixedEndian.BigEndian, &held) // want `Read is passed &held, which holds a b.Header rather than a pointer to one`
+
+	var pp any = &p
+	mixedEndian.Read(buf, mixedEndian.BigEndian, &pp) // want `Read is passed &pp, which holds a \*\*b.Header, not a pointer to a concrete type it can fill`
+
+	// The assignment that reaches the call is the one checked
+	var last any = &h
+	if h.A != 0 {
+		last = &p
+	}
+	last = h
+	mixedEndian.Read(buf, mixedEndian.BigEndian, &last) // want `Read is passed &last, which holds a b.Header rather than a pointer to one`
+}
@@ -0,0 +1,20 @@
+// Package mixedEndian stands in for the real package, declaring what the checks look for
+package mixedEndian
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+)
+
+var BigEndian = binary.BigEndian
+
+func Read(r io.Reader, defaultEndian binary.ByteOrder, data *any) error { return nil }
+
+func ReadContext(ctx context.Context, r io.Reader, defaultEndian binary.ByteOrder, data *any) error {
+	return nil
+}
+
+func Write(w io.Writer, defaultEndian binary.ByteOrder, data any) error { return nil }
+
+func Marshal(defaultEndian binary.ByteOrder, data any) ([]byte, error) { return nil, nil }